@@ -0,0 +1,72 @@
+package jaws
+
+import (
+	"strings"
+)
+
+// Label is a parameter that gives an Element an accessible name using the
+// "aria-label" attribute, for inputs and widgets that have no visible <label>.
+type Label string
+
+// interactiveTags are the HTML elements that browsers make keyboard operable.
+var interactiveTags = map[string]struct{}{
+	"a":        {},
+	"button":   {},
+	"input":    {},
+	"option":   {},
+	"select":   {},
+	"summary":  {},
+	"textarea": {},
+}
+
+func hasAttr(attrs []string, names ...string) bool {
+	for _, attr := range attrs {
+		for _, name := range names {
+			if attr == name || strings.HasPrefix(attr, name+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isClickable returns true if Jaws.KeyboardClicks is set and tag or any
+// of the Element's event handlers is a ClickHandler.
+func (e *Element) isClickable(tag interface{}) bool {
+	if !e.Request.Jaws.KeyboardClicks {
+		return false
+	}
+	if _, ok := tag.(ClickHandler); ok {
+		return true
+	}
+	for _, h := range e.handlers {
+		if _, ok := h.(clickHandlerWapper); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// clickableAttrs returns attrs with "role" and "tabindex" added if needed to make
+// an element with the given HTML tag that handles clicks operable using the keyboard.
+func clickableAttrs(htmltag string, clickable bool, attrs []string) []string {
+	if _, ok := interactiveTags[htmltag]; clickable && !ok {
+		if !hasAttr(attrs, "role") {
+			attrs = append(attrs, `role="button"`)
+		}
+		if !hasAttr(attrs, "tabindex") {
+			attrs = append(attrs, `tabindex="0"`)
+		}
+	}
+	return attrs
+}
+
+// auditLabel adds the "data-jaws-audit" attribute to the attributes for an input
+// if Jaws.Audit is set and it has no accessible name, which makes the browser
+// warn if it isn't labelled by a <label> either.
+func (e *Element) auditLabel(attrs []string) []string {
+	if e.Request.Jaws.Audit && !hasAttr(attrs, "aria-label", "aria-labelledby", "title") {
+		attrs = append(attrs, "data-jaws-audit")
+	}
+	return attrs
+}
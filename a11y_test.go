@@ -0,0 +1,51 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestLabel(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	th.NoErr(rq.Text("", Label(`Name "nick"`)))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" aria-label="Name &#34;nick&#34;">`)
+}
+
+func Test_clickableAttrs(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(clickableAttrs("div", false, nil), []string(nil))
+	th.Equal(clickableAttrs("button", true, nil), []string(nil))
+	th.Equal(clickableAttrs("span", true, []string{"hidden"}), []string{"hidden", `role="button"`, `tabindex="0"`})
+	th.Equal(clickableAttrs("li", true, []string{`role="tab"`, `tabindex="-1"`}), []string{`role="tab"`, `tabindex="-1"`})
+}
+
+func TestJaws_KeyboardClicks(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	tj.KeyboardClicks = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+	tjc := &testJawsClick{clickCh: make(chan string)}
+	th.NoErr(rq.Div("inner", tjc))
+	th.NoErr(rq.Button("ok", tjc))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" role="button" tabindex="0">inner</div>`+
+		`<button id="Jid.2" type="button">ok</button>`)
+}
+
+func TestJaws_Audit(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	tj.Audit = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+	th.NoErr(rq.Text(""))
+	th.NoErr(rq.Checkbox(false, Label("Remember me")))
+	th.NoErr(rq.Textarea("", `title="Comment"`))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" data-jaws-audit>`+
+		`<input id="Jid.2" type="checkbox" aria-label="Remember me">`+
+		`<textarea id="Jid.3" title="Comment"></textarea>`)
+}
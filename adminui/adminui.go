@@ -0,0 +1,212 @@
+// Package adminui is an optional status page for operators, showing live
+// runtime statistics, GC metrics, expvar variables and Jaws introspection
+// data. It is built using JaWS itself.
+package adminui
+
+import (
+	"expvar"
+	"html"
+	"html/template"
+	"io"
+	"net/http"
+	"runtime"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/linkdata/jaws"
+)
+
+// DefaultInterval is the default interval between statistics updates.
+const DefaultInterval = time.Second
+
+// DefaultPprofURL is the default URL of the pprof index, as registered by
+// importing net/http/pprof.
+const DefaultPprofURL = "/debug/pprof/"
+
+// Page is a http.Handler serving the status page.
+//
+//	http.Handle("/admin/", adminui.New(jw, 0))
+//
+// The page is not protected in any way, so take care to only serve it to operators.
+type Page struct {
+	Title    string // page title, defaults to "Status"
+	PprofURL string // if not empty, the page links to the pprof index here, defaults to DefaultPprofURL
+	jw       *jaws.Jaws
+	started  time.Time
+	mu       sync.RWMutex // protects following
+	mem      runtime.MemStats
+	routines int
+}
+
+// New returns a Page for the given Jaws, updating the statistics shown every
+// interval until the Jaws is closed. If interval is not positive, DefaultInterval is used.
+func New(jw *jaws.Jaws, interval time.Duration) (p *Page) {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	p = &Page{
+		Title:    "Status",
+		PprofURL: DefaultPprofURL,
+		jw:       jw,
+		started:  time.Now(),
+	}
+	p.refresh()
+	go p.run(interval)
+	return
+}
+
+func (p *Page) run(interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-p.jw.Done():
+			return
+		case <-t.C:
+			p.refresh()
+			p.jw.Dirty(p)
+		}
+	}
+}
+
+func (p *Page) refresh() {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	routines := runtime.NumGoroutine()
+	p.mu.Lock()
+	p.mem = mem
+	p.routines = routines
+	p.mu.Unlock()
+}
+
+// stat is a HtmlGetter showing a statistic of the Page, and updated with it.
+type stat struct {
+	p  *Page
+	fn func(p *Page) string
+}
+
+func (s stat) JawsGetHtml(e *jaws.Element) template.HTML {
+	s.p.mu.RLock()
+	v := s.fn(s.p)
+	s.p.mu.RUnlock()
+	return template.HTML(html.EscapeString(v)) // #nosec G203
+}
+
+func (s stat) JawsGetTag(rq *jaws.Request) interface{} {
+	return s.p
+}
+
+func bytesString(n uint64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatUint(n, 10) + " B"
+	}
+	div, exp := uint64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return strconv.FormatFloat(float64(n)/float64(div), 'f', 1, 64) + " " + "KMGTPE"[exp:exp+1] + "iB"
+}
+
+type row struct {
+	name string
+	fn   func(p *Page) string
+}
+
+var runtimeRows = []row{
+	{"Uptime", func(p *Page) string { return time.Since(p.started).Truncate(time.Second).String() }},
+	{"Go version", func(p *Page) string { return runtime.Version() }},
+	{"GOMAXPROCS", func(p *Page) string { return strconv.Itoa(runtime.GOMAXPROCS(0)) }},
+	{"Goroutines", func(p *Page) string { return strconv.Itoa(p.routines) }},
+	{"Heap in use", func(p *Page) string { return bytesString(p.mem.HeapInuse) }},
+	{"Heap objects", func(p *Page) string { return strconv.FormatUint(p.mem.HeapObjects, 10) }},
+	{"Total allocated", func(p *Page) string { return bytesString(p.mem.TotalAlloc) }},
+	{"System memory", func(p *Page) string { return bytesString(p.mem.Sys) }},
+}
+
+var gcRows = []row{
+	{"GC cycles", func(p *Page) string { return strconv.FormatUint(uint64(p.mem.NumGC), 10) }},
+	{"Next GC at", func(p *Page) string { return bytesString(p.mem.NextGC) }},
+	{"Last pause", func(p *Page) string {
+		return time.Duration(p.mem.PauseNs[(p.mem.NumGC+255)%256]).String()
+	}},
+	{"Total pause", func(p *Page) string { return time.Duration(p.mem.PauseTotalNs).String() }},
+	{"GC CPU fraction", func(p *Page) string { return strconv.FormatFloat(p.mem.GCCPUFraction*100, 'f', 3, 64) + "%" }},
+}
+
+var jawsRows = []row{
+	{"Requests", func(p *Page) string { return strconv.Itoa(p.jw.RequestCount()) }},
+	{"Pending requests", func(p *Page) string { return strconv.Itoa(p.jw.Pending()) }},
+	{"Sessions", func(p *Page) string { return strconv.Itoa(p.jw.SessionCount()) }},
+	{"Session values rejected", func(p *Page) string { return strconv.FormatUint(p.jw.SessionQuotaStats().Rejected, 10) }},
+	{"Session values evicted", func(p *Page) string { return strconv.FormatUint(p.jw.SessionQuotaStats().Evicted, 10) }},
+	{"Hottest tag", func(p *Page) string {
+		if stats := p.jw.TagStats(); len(stats) > 0 {
+			return stats[0].String()
+		}
+		return "-"
+	}},
+	{"Slowest component", func(p *Page) string {
+		if stats := p.jw.RenderTypeStats(); len(stats) > 0 {
+			return stats[0].String()
+		}
+		return "-"
+	}},
+}
+
+func (p *Page) table(caption string, rows []row) *jaws.Node {
+	args := []interface{}{jaws.Class("jaws-admin"), jaws.H("caption", caption)}
+	for _, r := range rows {
+		args = append(args, jaws.H("tr", jaws.H("th", r.name), jaws.NewUiTd(stat{p, r.fn})))
+	}
+	return jaws.H("table", args...)
+}
+
+// expvarRows returns rows for the published expvar variables, except
+// "memstats" and "cmdline" which are large and shown elsewhere.
+func expvarRows() (rows []row) {
+	expvar.Do(func(kv expvar.KeyValue) {
+		if kv.Key != "memstats" && kv.Key != "cmdline" {
+			v := kv.Value
+			rows = append(rows, row{kv.Key, func(p *Page) string { return v.String() }})
+		}
+	})
+	return
+}
+
+// ServeHTTP renders the status page.
+func (p *Page) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rq := p.jw.NewRequest(r)
+	rw := rq.Writer(w)
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	_, err := io.WriteString(w, "<!DOCTYPE html>\n<html><head><title>"+html.EscapeString(p.Title)+"</title>")
+	if err == nil {
+		err = rw.HeadHTML()
+	}
+	if err == nil {
+		_, err = io.WriteString(w, "</head><body>")
+	}
+	if err == nil {
+		args := []interface{}{
+			jaws.H("h1", p.Title),
+			p.table("Runtime", runtimeRows),
+			p.table("Garbage collector", gcRows),
+			p.table("JaWS", jawsRows),
+		}
+		if rows := expvarRows(); len(rows) > 0 {
+			args = append(args, p.table("Variables", rows))
+		}
+		if p.PprofURL != "" {
+			args = append(args, jaws.H("p", jaws.H("a", jaws.A("href", p.PprofURL), "Profiles")))
+		}
+		err = rw.H("main", args...)
+	}
+	if err == nil {
+		_, err = io.WriteString(w, "</body></html>\n")
+	}
+	if err != nil {
+		_ = p.jw.Log(err)
+	}
+}
@@ -0,0 +1,55 @@
+package adminui_test
+
+import (
+	"expvar"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/adminui"
+)
+
+func TestPage_ServeHTTP(t *testing.T) {
+	jw := jaws.New()
+	defer jw.Close()
+	go jw.Serve()
+	expvar.NewString("adminui_test").Set("<hello>")
+
+	p := adminui.New(jw, time.Millisecond)
+	p.Title = "Ops & status"
+	rr := httptest.NewRecorder()
+	p.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/admin/", nil))
+
+	if rr.Code != http.StatusOK {
+		t.Error(rr.Code)
+	}
+	if ct := rr.Header().Get("Content-Type"); ct != "text/html; charset=utf-8" {
+		t.Error(ct)
+	}
+	txt := rr.Body.String()
+	for _, want := range []string{
+		"<title>Ops &amp; status</title>",
+		jaws.JavascriptPath,
+		"<th", ">Goroutines</th>",
+		">GC cycles</th>",
+		">Requests</th><td id=\"Jid.",
+		">Hottest tag</th>",
+		">Slowest component</th>",
+		">adminui_test</th>",
+		`&#34;\u003chello\u003e&#34;`,
+		`href="` + adminui.DefaultPprofURL + `"`,
+	} {
+		if !strings.Contains(txt, want) {
+			t.Errorf("missing %q in %q", want, txt)
+		}
+	}
+	if strings.Contains(txt, ">memstats</th>") {
+		t.Error("memstats shown")
+	}
+	if n := jw.RequestCount(); n != 1 {
+		t.Error(n)
+	}
+}
@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultAlertHistory is the default number of alerts a Request retains.
+const DefaultAlertHistory = 50
+
+// AlertEntry is an alert in a Request's alert history.
+type AlertEntry struct {
+	First   time.Time     // when the alert was first shown
+	Last    time.Time     // when the alert was last raised
+	Level   string        // alert level, e.g. "danger"
+	Message template.HTML // alert message
+	Count   int           // number of times the alert was raised, including suppressed ones
+}
+
+// alertLogTag is the tag used for the UiAlertLog Elements of a Request.
+type alertLogTag struct{ rq *Request }
+
+func (jw *Jaws) alertHistory() int {
+	if jw.AlertHistory > 0 {
+		return jw.AlertHistory
+	}
+	return DefaultAlertHistory
+}
+
+// recordAlert adds the alert to the history, and returns false if an
+// identical alert was shown within Jaws.AlertDedup and it should be suppressed.
+func (rq *Request) recordAlert(lvl, msg string) (show bool) {
+	now := time.Now()
+	show = true
+	rq.mu.Lock()
+	for i := len(rq.alerts) - 1; i >= 0; i-- {
+		if a := &rq.alerts[i]; a.Level == lvl && string(a.Message) == msg {
+			if show = now.Sub(a.First) >= rq.Jaws.AlertDedup; !show {
+				a.Last = now
+				a.Count++
+			}
+			break
+		}
+	}
+	if show {
+		rq.alerts = append(rq.alerts, AlertEntry{
+			First:   now,
+			Last:    now,
+			Level:   lvl,
+			Message: template.HTML(msg), // #nosec G203
+			Count:   1,
+		})
+		if n := len(rq.alerts) - rq.Jaws.alertHistory(); n > 0 {
+			rq.alerts = append(rq.alerts[:0], rq.alerts[n:]...)
+		}
+	}
+	rq.mu.Unlock()
+	rq.Dirty(alertLogTag{rq})
+	return
+}
+
+// Alerts returns a copy of the Request's alert history, oldest first.
+func (rq *Request) Alerts() (alerts []AlertEntry) {
+	rq.mu.RLock()
+	alerts = append(alerts, rq.alerts...)
+	rq.mu.RUnlock()
+	return
+}
+
+// ClearAlerts discards the Request's alert history.
+func (rq *Request) ClearAlerts() {
+	rq.mu.Lock()
+	rq.alerts = rq.alerts[:0]
+	rq.mu.Unlock()
+	rq.Dirty(alertLogTag{rq})
+}
+
+// UiAlertLog renders the alert history of the Request, newest first.
+type UiAlertLog struct {
+	UiHtml
+}
+
+func NewUiAlertLog() *UiAlertLog {
+	return &UiAlertLog{}
+}
+
+func (ui *UiAlertLog) innerHTML(rq *Request) template.HTML {
+	alerts := rq.Alerts()
+	var b strings.Builder
+	for i := len(alerts) - 1; i >= 0; i-- {
+		a := &alerts[i]
+		b.WriteString(`<li class="jaws-alert-log-`)
+		b.WriteString(html.EscapeString(a.Level))
+		b.WriteString(`"><time datetime="`)
+		b.WriteString(a.Last.UTC().Format(time.RFC3339))
+		b.WriteString(`">`)
+		b.WriteString(a.Last.Format(time.TimeOnly))
+		b.WriteString(`</time> `)
+		b.WriteString(string(a.Message))
+		if a.Count > 1 {
+			b.WriteString(` <small>&times;`)
+			b.WriteString(strconv.Itoa(a.Count))
+			b.WriteString(`</small>`)
+		}
+		b.WriteString(`</li>`)
+	}
+	return template.HTML(b.String()) // #nosec G203
+}
+
+func (ui *UiAlertLog) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	e.Tag(alertLogTag{e.Request})
+	attrs := append(ui.parseParams(e, params), `class="jaws-alert-log"`)
+	return WriteHtmlInner(w, e.Jid(), "ul", "", ui.innerHTML(e.Request), attrs...)
+}
+
+func (ui *UiAlertLog) JawsUpdate(e *Element) {
+	e.SetInner(ui.innerHTML(e.Request))
+}
+
+// AlertLog renders a HTML ul element listing the alerts shown to the Request,
+// newest first. Repeated alerts suppressed by Jaws.AlertDedup are counted.
+func (rq RequestWriter) AlertLog(params ...interface{}) error {
+	return rq.UI(NewUiAlertLog(), params...)
+}
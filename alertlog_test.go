@@ -0,0 +1,107 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_AlertDedup(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.AlertDedup = time.Hour
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	th.NoErr(rq.AlertLog())
+	th.Equal(rq.BodyString(), `<ul id="Jid.1" class="jaws-alert-log"></ul>`)
+
+	rq.Alert("danger", "<b>oops</b>")
+	rq.Alert("danger", "<b>oops</b>")
+	rq.Alert("info", "<b>oops</b>")
+
+	var got string
+	for strings.Count(got, "Alert\t") < 2 || !strings.Contains(got, "&times;2") {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			got += s
+		}
+	}
+	th.Equal(strings.Count(got, "Alert\t\t\"danger\\n<b>oops</b>\""), 1)
+	th.Equal(strings.Count(got, "Alert\t\t\"info\\n<b>oops</b>\""), 1)
+
+	alerts := rq.Alerts()
+	th.Equal(len(alerts), 2)
+	th.Equal(alerts[0].Level, "danger")
+	th.Equal(alerts[0].Count, 2)
+	th.True(!alerts[0].Last.Before(alerts[0].First))
+	th.Equal(alerts[1].Level, "info")
+	th.Equal(alerts[1].Count, 1)
+
+	log := NewUiAlertLog().innerHTML(rq.Request)
+	th.True(strings.Index(string(log), "jaws-alert-log-info") < strings.Index(string(log), "jaws-alert-log-danger"))
+
+	rq.ClearAlerts()
+	th.Equal(len(rq.Alerts()), 0)
+	rq.Alert("danger", "<b>oops</b>")
+	th.Equal(len(rq.Alerts()), 1)
+}
+
+func TestRequest_AlertHistory(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.AlertHistory = 2
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	rq.Alert("info", "1")
+	rq.Alert("info", "2")
+	rq.Alert("info", "2")
+	rq.Alert("info", "3")
+	alerts := rq.Alerts()
+	th.Equal(len(alerts), 2)
+	th.Equal(alerts[0].Message, template.HTML("2"))
+	th.Equal(alerts[0].Count, 1)
+	th.Equal(alerts[1].Message, template.HTML("3"))
+}
+
+func TestRequest_AlertDedupEventError(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.AlertDedup = time.Hour
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	th.NoErr(rq.Button("x", func(e *Element, wht what.What, val string) error {
+		return errors.New("<fail>")
+	}))
+	rq.inCh <- wsMsg{Jid: 1, What: what.Click, Data: "x"}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Alert\t\t\"danger\\n&lt;fail&gt;\"\n")
+	}
+	rq.inCh <- wsMsg{Jid: 1, What: what.Click, Data: "x"}
+	for len(rq.Alerts()) != 1 || rq.Alerts()[0].Count != 2 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			t.Errorf("unexpected %q", s)
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package jaws
+
+import (
+	"fmt"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// RenderedAlert is an alert rendered by an AlertRenderer.
+type RenderedAlert struct {
+	Container string        // HTML ID of the element to add the alert to
+	HTML      template.HTML // the alert's HTML
+	Limit     int           // if positive, the oldest alerts are removed from the container to keep at most this many
+	Prepend   bool          // if true, newer alerts are placed first in the container
+}
+
+// AlertRenderer renders alerts on the server, replacing the default
+// Bootstrap alerts added to the "jaws-alerts" element by the browser.
+type AlertRenderer interface {
+	// JawsRenderAlert renders an alert for the Request with the given
+	// level (e.g. "info" or "danger") and message HTML.
+	JawsRenderAlert(rq *Request, lvl string, msg template.HTML) (RenderedAlert, error)
+}
+
+// AlertData is the data passed to the templates of a TemplateAlertRenderer.
+type AlertData struct {
+	Request *Request
+	Level   string
+	Message template.HTML
+}
+
+// TemplateAlertRenderer is an AlertRenderer that executes a template chosen by
+// the alert level, falling back to the template for the empty level.
+type TemplateAlertRenderer struct {
+	Templates map[string]*template.Template // templates by alert level, "" for the default
+	Container string                        // HTML ID of the alert container, defaults to "jaws-alerts"
+	Limit     int                           // if positive, the maximum number of alerts shown
+	Prepend   bool                          // if true, newer alerts are shown first
+}
+
+func (r *TemplateAlertRenderer) JawsRenderAlert(rq *Request, lvl string, msg template.HTML) (ra RenderedAlert, err error) {
+	ra = RenderedAlert{Container: r.Container, Limit: r.Limit, Prepend: r.Prepend}
+	if ra.Container == "" {
+		ra.Container = "jaws-alerts"
+	}
+	t := r.Templates[lvl]
+	if t == nil {
+		t = r.Templates[""]
+	}
+	if t == nil {
+		return ra, fmt.Errorf("jaws: no alert template for level %q", lvl)
+	}
+	var sb strings.Builder
+	if err = t.Execute(&sb, AlertData{Request: rq, Level: lvl, Message: msg}); err == nil {
+		ra.HTML = template.HTML(sb.String()) // #nosec G203
+	}
+	return
+}
+
+// renderAlert returns the message rendered using the Jaws AlertRenderer if it is an
+// Alert and there is one. If rendering fails, the error is logged and m is returned.
+func (rq *Request) renderAlert(m wsMsg) wsMsg {
+	if m.What == what.Alert && rq.Jaws.AlertRenderer != nil {
+		lvl, msg, _ := strings.Cut(m.Data, "\n")
+		ra, err := rq.Jaws.AlertRenderer.JawsRenderAlert(rq, lvl, template.HTML(msg)) // #nosec G203
+		if rq.Jaws.Log(err) == nil {
+			// an empty level tells the browser the alert is already rendered
+			m.Data = "\n" + ra.Container + "\n" + strconv.Itoa(ra.Limit) + "\n" + strconv.FormatBool(ra.Prepend) + "\n" + string(ra.HTML)
+		}
+	}
+	return m
+}
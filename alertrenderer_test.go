@@ -0,0 +1,54 @@
+package jaws
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestTemplateAlertRenderer(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.AlertRenderer = &TemplateAlertRenderer{
+		Templates: map[string]*template.Template{
+			"":       template.Must(template.New("").Parse(`<div class="note {{.Level}}">{{.Message}}</div>`)),
+			"danger": template.Must(template.New("").Parse(`<div class="error">{{.Message}}</div>`)),
+		},
+		Limit:   3,
+		Prepend: true,
+	}
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	rq.Alert("info", "<b>hi</b>")
+	rq.Alert("danger", "oops")
+	for _, want := range []string{
+		`Alert` + "\t\t" + `"\njaws-alerts\n3\ntrue\n<div class=\"note info\"><b>hi</b></div>"` + "\n",
+		`Alert` + "\t\t" + `"\njaws-alerts\n3\ntrue\n<div class=\"error\">oops</div>"` + "\n",
+	} {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			th.Equal(s, want)
+		}
+	}
+
+	ra, err := (&TemplateAlertRenderer{Container: "alerts"}).JawsRenderAlert(rq.Request, "info", "x")
+	th.True(err != nil)
+	th.Equal(ra.Container, "alerts")
+	m := (&Request{Jaws: tj.Jaws}).renderAlert(wsMsg{What: 0, Data: "info\nx"})
+	th.Equal(m.Data, "info\nx")
+}
+
+func TestRequest_renderAlert_Fallback(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.AlertRenderer = &TemplateAlertRenderer{}
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+	var m wsMsg
+	m.FillAlert(errTooManyTags{})
+	th.Equal(rq.renderAlert(m), m)
+	th.True(strings.Contains(tj.log.String(), "no alert template"))
+}
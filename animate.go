@@ -0,0 +1,30 @@
+package jaws
+
+import (
+	"strconv"
+	"time"
+)
+
+// DefaultAnimateDuration is the animation duration used by Animate(0).
+const DefaultAnimateDuration = 400 * time.Millisecond
+
+// Animate is a parameter that makes the browser animate numeric inner
+// text changes of the Element, counting from the old value to the new
+// with an eased interpolation over the given duration. A duration of
+// zero means DefaultAnimateDuration.
+//
+//	rw.Span(revenue, jaws.Animate(time.Second))
+//
+// Only inner text that is a plain decimal number, such as "1234" or
+// "-12.50", is animated, keeping the number of decimals of the new value.
+// Other changes, and all changes if the user prefers reduced motion,
+// are applied immediately.
+type Animate time.Duration
+
+func (a Animate) attr() string {
+	d := time.Duration(a)
+	if d <= 0 {
+		d = DefaultAnimateDuration
+	}
+	return `data-jaws-animate="` + strconv.FormatInt(d.Milliseconds(), 10) + `"`
+}
@@ -0,0 +1,22 @@
+package jaws
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAnimate_attr(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(Animate(0).attr(), `data-jaws-animate="400"`)
+	th.Equal(Animate(time.Second).attr(), `data-jaws-animate="1000"`)
+}
+
+func TestRequest_Animate(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Span("12.50", Animate(250*time.Millisecond)))
+	th.Equal(rq.BodyString(), `<span id="Jid.1" data-jaws-animate="250">12.50</span>`)
+}
@@ -0,0 +1,133 @@
+package jaws
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAssetNotFound is returned when looking up a path that isn't in Assets.
+var ErrAssetNotFound = errors.New("asset not found")
+
+// Assets serves static files, such as CSS, Javascript and images, using
+// URLs with a fingerprint of the file contents, such as
+// "/static/css/app.2yqaeq6ytl4ki.css" for "css/app.css". Since the URL
+// changes whenever the contents do, browsers may cache them forever.
+//
+// The files are read into memory when the Assets are created.
+//
+//	assets, err := jaws.NewAssets(staticFS, "/static/")
+//	http.Handle("/static/", assets)
+//	tmpl := template.New("").Funcs(assets.FuncMap()) // {{asset "img/logo.png"}}
+//	err = jw.UseAssets(assets, "css/app.css", "js/app.js")
+type Assets struct {
+	Prefix string            // (read-only) URL path prefix, such as "/static/"
+	byPath map[string]*asset // (read-only) files by path relative to the fs.FS
+	byURL  map[string]*asset // (read-only) files by URL path, both fingerprinted and not
+}
+
+type asset struct {
+	name    string // base name of the file
+	url     string // fingerprinted URL path
+	data    []byte
+	modTime time.Time
+}
+
+// NewAssets reads all files in fsys and returns Assets serving them
+// with URL paths starting with prefix.
+func NewAssets(fsys fs.FS, prefix string) (a *Assets, err error) {
+	if !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	a = &Assets{
+		Prefix: prefix,
+		byPath: make(map[string]*asset),
+		byURL:  make(map[string]*asset),
+	}
+	err = fs.WalkDir(fsys, ".", func(fpath string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			var data []byte
+			var info fs.FileInfo
+			if data, err = fs.ReadFile(fsys, fpath); err == nil {
+				if info, err = d.Info(); err == nil {
+					a.add(fpath, data, info.ModTime())
+				}
+			}
+		}
+		return err
+	})
+	return
+}
+
+func (a *Assets) add(fpath string, data []byte, modTime time.Time) {
+	h := fnv.New64a()
+	_, _ = h.Write(data)
+	ext := path.Ext(fpath)
+	fingerprinted := strings.TrimSuffix(fpath, ext) + "." + strconv.FormatUint(h.Sum64(), 36) + ext
+	as := &asset{
+		name:    path.Base(fpath),
+		url:     a.Prefix + fingerprinted,
+		data:    data,
+		modTime: modTime,
+	}
+	a.byPath[fpath] = as
+	a.byURL[as.url] = as
+	a.byURL[a.Prefix+fpath] = as
+}
+
+// URL returns the fingerprinted URL path for the file at fpath, such as
+// "/static/css/app.2yqaeq6ytl4ki.css" for "css/app.css". It returns an
+// error wrapping ErrAssetNotFound if there is no such file.
+func (a *Assets) URL(fpath string) (string, error) {
+	if as, ok := a.byPath[strings.TrimPrefix(fpath, "/")]; ok {
+		return as.url, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrAssetNotFound, fpath)
+}
+
+// FuncMap returns a template.FuncMap with the function "asset" that
+// returns the fingerprinted URL for a path, see URL.
+func (a *Assets) FuncMap() template.FuncMap {
+	return template.FuncMap{"asset": a.URL}
+}
+
+// ServeHTTP serves the files. Fingerprinted URLs are cached by the
+// browser forever, while URLs without the fingerprint, such as those
+// referred to from within CSS files, must be revalidated.
+func (a *Assets) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	as, ok := a.byURL[r.URL.Path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.URL.Path == as.url {
+		w.Header()["Cache-Control"] = headerCacheStatic
+	} else {
+		w.Header()["Cache-Control"] = headerCacheNoCache
+	}
+	http.ServeContent(w, r, as.name, as.modTime, bytes.NewReader(as.data))
+}
+
+// UseAssets calls GenerateHeadHTML with the fingerprinted URLs of the
+// given .js and .css files in a, so that HeadHTML loads them.
+func (jw *Jaws) UseAssets(a *Assets, paths ...string) (err error) {
+	urls := make([]string, len(paths))
+	for i, fpath := range paths {
+		if urls[i], err = a.URL(fpath); err != nil {
+			return
+		}
+	}
+	return jw.GenerateHeadHTML(urls...)
+}
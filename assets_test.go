@@ -0,0 +1,97 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func testAssets(t *testing.T) *Assets {
+	t.Helper()
+	a, err := NewAssets(fstest.MapFS{
+		"css/app.css":  {Data: []byte("body{}")},
+		"js/app.js":    {Data: []byte("var x;")},
+		"img/logo.png": {Data: []byte("\x89PNG")},
+	}, "/static")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestAssets_URL(t *testing.T) {
+	th := newTestHelper(t)
+	a := testAssets(t)
+	th.Equal(a.Prefix, "/static/")
+
+	u, err := a.URL("css/app.css")
+	th.NoErr(err)
+	th.True(strings.HasPrefix(u, "/static/css/app."))
+	th.True(strings.HasSuffix(u, ".css"))
+	u2, err := a.URL("/css/app.css")
+	th.NoErr(err)
+	th.Equal(u2, u)
+
+	_, err = a.URL("missing.css")
+	th.True(errors.Is(err, ErrAssetNotFound))
+
+	tmpl := template.Must(template.New("t").Funcs(a.FuncMap()).Parse(`<img src="{{asset "img/logo.png"}}">`))
+	var sb strings.Builder
+	th.NoErr(tmpl.Execute(&sb, nil))
+	logo, _ := a.URL("img/logo.png")
+	th.Equal(sb.String(), `<img src="`+logo+`">`)
+}
+
+func TestAssets_ServeHTTP(t *testing.T) {
+	th := newTestHelper(t)
+	a := testAssets(t)
+	u, _ := a.URL("css/app.css")
+
+	get := func(method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		a.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+
+	rr := get(http.MethodGet, u)
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.String(), "body{}")
+	th.Equal(rr.Header().Get("Cache-Control"), headerCacheStatic[0])
+	th.True(strings.HasPrefix(rr.Header().Get("Content-Type"), "text/css"))
+
+	rr = get(http.MethodGet, "/static/css/app.css")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Header().Get("Cache-Control"), "no-cache")
+
+	th.Equal(get(http.MethodGet, "/static/nope.css").Code, http.StatusNotFound)
+	th.Equal(get(http.MethodPost, u).Code, http.StatusMethodNotAllowed)
+}
+
+func TestAssets_Errors(t *testing.T) {
+	th := newTestHelper(t)
+	_, err := NewAssets(os.DirFS("/nonexistent-jaws-assets"), "/s/")
+	th.True(errors.Is(err, fs.ErrNotExist))
+}
+
+func TestJaws_UseAssets(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	a := testAssets(t)
+
+	th.NoErr(jw.UseAssets(a, "css/app.css", "js/app.js"))
+	css, _ := a.URL("css/app.css")
+	js, _ := a.URL("js/app.js")
+	th.True(strings.Contains(jw.headPrefix, `<link rel="preload" href="`+css+`" as="style">`))
+	th.True(strings.Contains(jw.headPrefix, `<link rel="preload" href="`+js+`" as="script">`))
+	th.True(strings.Contains(jw.headPrefix, `<link rel="stylesheet" href="`+css+`">`))
+
+	th.True(errors.Is(jw.UseAssets(a, "missing.js"), ErrAssetNotFound))
+	th.True(jw.UseAssets(a, "img/logo.png") != nil)
+}
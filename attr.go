@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"strings"
+)
+
+// ErrInvalidAttrName is the error Attr and Data panic with for invalid attribute names.
+var ErrInvalidAttrName = errors.New("invalid attribute name")
+
+// validAttrName returns true if name is a valid HTML attribute name
+// that JaWS doesn't manage itself.
+func validAttrName(name string) bool {
+	if name == "" || strings.EqualFold(name, "id") {
+		return false
+	}
+	for _, r := range name {
+		if r <= ' ' || r == 0x7f || strings.ContainsRune("\"'>/=<`", r) || (r >= 0xfdd0 && r <= 0xfdef) || r&0xfffe == 0xfffe {
+			return false
+		}
+	}
+	return true
+}
+
+// Attr returns a HTML attribute with the given name and value, suitable
+// for use as a parameter when rendering UI objects, and may be mixed with
+// the existing string and template.HTML parameters.
+//
+// The value is escaped. If it is a bool, a boolean attribute is returned
+// if it is true, and an empty string (meaning no attribute) if it is false.
+//
+// Attr panics with ErrInvalidAttrName if name isn't a valid HTML attribute name,
+// or is "id" which is set by JaWS. Note that escaping doesn't make values safe to
+// use in attributes that are interpreted, such as "onclick" or "href".
+func Attr(name string, value interface{}) template.HTML {
+	if !validAttrName(name) {
+		panic(fmt.Errorf("jaws: %w: %q", ErrInvalidAttrName, name))
+	}
+	var s string
+	switch v := value.(type) {
+	case bool:
+		if v {
+			return template.HTML(name) // #nosec G203
+		}
+		return ""
+	case string:
+		s = v
+	default:
+		s = fmt.Sprint(v)
+	}
+	return template.HTML(name + `="` + html.EscapeString(s) + `"`) // #nosec G203
+}
+
+// Data returns a HTML "data-" attribute with the given name and value, see Attr.
+func Data(name string, value interface{}) template.HTML {
+	return Attr("data-"+name, value)
+}
@@ -0,0 +1,37 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"testing"
+)
+
+func TestAttr(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(Attr("title", `say "hi" & <bye>`), template.HTML(`title="say &#34;hi&#34; &amp; &lt;bye&gt;"`))
+	th.Equal(Attr("tabindex", 3), template.HTML(`tabindex="3"`))
+	th.Equal(Attr("hidden", true), template.HTML(`hidden`))
+	th.Equal(Attr("hidden", false), template.HTML(``))
+	th.Equal(Data("user-id", "x'y"), template.HTML(`data-user-id="x&#39;y"`))
+
+	for _, name := range []string{"", "id", "ID", "a b", `x"`, "x=", "x>", "a/b", "x\x00", "x﷐", "x￿"} {
+		func() {
+			defer func() {
+				err, ok := recover().(error)
+				th.True(ok)
+				th.True(errors.Is(err, ErrInvalidAttrName))
+			}()
+			Attr(name, "v")
+			t.Errorf("expected panic for %q", name)
+		}()
+	}
+}
+
+func TestAttr_Params(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	th.NoErr(rq.Span("x", Attr("title", "<b>"), "hidden", Data("k", 1), Attr("disabled", false)))
+	th.Equal(rq.BodyString(), `<span id="Jid.1" title="&lt;b&gt;" hidden data-k="1">x</span>`)
+}
@@ -0,0 +1,97 @@
+package jaws
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// BindingReport lists likely broken bindings between tags and Elements,
+// as returned by Jaws.Bindings.
+type BindingReport struct {
+	UnboundTags       []interface{} // tags that were dirtied, but that no Element has
+	UndirtiedElements []string      // Elements with tags, none of which were ever dirtied
+}
+
+// String returns the report in a form suitable for debug output.
+func (br BindingReport) String() string {
+	var sb strings.Builder
+	for _, tag := range br.UnboundTags {
+		sb.WriteString("unbound tag: ")
+		sb.WriteString(TagString(tag))
+		sb.WriteByte('\n')
+	}
+	for _, s := range br.UndirtiedElements {
+		sb.WriteString("undirtied element: ")
+		sb.WriteString(s)
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
+
+// maxDirtiedTags is the number of distinct dirtied tags Jaws.Bindings remembers.
+const maxDirtiedTags = 4096
+
+// recordDirtiedLocked remembers the tags as dirtied. Once maxDirtiedTags
+// are remembered, the one first added is forgotten for each new one.
+func (jw *Jaws) recordDirtiedLocked(tags []interface{}) {
+	if jw.dirtied == nil {
+		jw.dirtied = make(map[interface{}]struct{})
+	}
+	for _, tag := range tags {
+		if _, ok := jw.dirtied[tag]; !ok {
+			if len(jw.dirtiedRing) < maxDirtiedTags {
+				jw.dirtiedRing = append(jw.dirtiedRing, tag)
+			} else {
+				delete(jw.dirtied, jw.dirtiedRing[jw.dirtiedNext])
+				jw.dirtiedRing[jw.dirtiedNext] = tag
+				jw.dirtiedNext = (jw.dirtiedNext + 1) % maxDirtiedTags
+			}
+			jw.dirtied[tag] = struct{}{}
+		}
+	}
+}
+
+// Bindings reports tags that have been dirtied but that no Element in any
+// current Request has, and Elements that have tags but none that have been
+// dirtied. Since Elements come and go, treat it as a hint when looking for
+// broken bindings after refactoring.
+//
+// Dirtied tags are only recorded while Debug is set, so only use it during
+// development. Only the last maxDirtiedTags distinct tags dirtied are
+// remembered, so on applications with more tags than that Elements may be
+// reported as undirtied when they aren't.
+func (jw *Jaws) Bindings() (br BindingReport) {
+	jw.mu.RLock()
+	defer jw.mu.RUnlock()
+	bound := map[interface{}]struct{}{}
+	for _, rq := range jw.requests {
+		rq.mu.RLock()
+		elemTags := map[*Element][]string{}
+		dirtied := map[*Element]bool{}
+		for tag, elems := range rq.tagMap {
+			_, wasDirtied := jw.dirtied[tag]
+			for _, e := range elems {
+				bound[tag] = struct{}{}
+				elemTags[e] = append(elemTags[e], TagString(tag))
+				dirtied[e] = dirtied[e] || wasDirtied
+			}
+		}
+		for _, e := range rq.elems {
+			if tags := elemTags[e]; len(tags) > 0 && !dirtied[e] {
+				slices.Sort(tags)
+				br.UndirtiedElements = append(br.UndirtiedElements,
+					fmt.Sprintf("%v: %T id=%q tags=[%s]", rq, e.ui, e.jid, strings.Join(tags, ", ")))
+			}
+		}
+		rq.mu.RUnlock()
+	}
+	for tag := range jw.dirtied {
+		if _, ok := bound[tag]; !ok {
+			br.UnboundTags = append(br.UnboundTags, tag)
+		}
+	}
+	slices.SortFunc(br.UnboundTags, func(a, b interface{}) int { return strings.Compare(TagString(a), TagString(b)) })
+	slices.Sort(br.UndirtiedElements)
+	return
+}
@@ -0,0 +1,52 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestJaws_Bindings(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	tj.Debug = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	bound := rq.NewElement(NewUiSpan(makeHtmlGetter("bound")))
+	bound.Tag(Tag("bound"))
+	stale := rq.NewElement(NewUiSpan(makeHtmlGetter("stale")))
+	stale.Tag(Tag("stale"))
+	rq.NewElement(NewUiSpan(makeHtmlGetter("untagged")))
+
+	th.Equal(tj.Bindings().UnboundTags, []interface{}(nil))
+
+	tj.Dirty(Tag("bound"), Tag("renamed"))
+	br := tj.Bindings()
+	th.Equal(br.UnboundTags, []interface{}{Tag("renamed")})
+	th.Equal(len(br.UndirtiedElements), 1)
+	th.True(strings.Contains(br.UndirtiedElements[0], `id="Jid.2"`))
+	th.True(strings.Contains(br.UndirtiedElements[0], `tags=["stale"]`))
+	th.Equal(br.String(), "unbound tag: \"renamed\"\nundirtied element: "+br.UndirtiedElements[0]+"\n")
+
+	tj.Debug = false
+	tj.Dirty(Tag("ignored"))
+	th.Equal(tj.Bindings().UnboundTags, []interface{}{Tag("renamed")})
+}
+
+func TestJaws_BindingsLimit(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	for i := 0; i <= maxDirtiedTags; i++ {
+		jw.recordDirtiedLocked([]interface{}{i, i})
+	}
+	th.Equal(len(jw.dirtied), maxDirtiedTags)
+	th.Equal(len(jw.dirtiedRing), maxDirtiedTags)
+	_, first := jw.dirtied[0]
+	_, last := jw.dirtied[maxDirtiedTags]
+	th.Equal(first, false)
+	th.True(last)
+}
@@ -0,0 +1,100 @@
+package jaws
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// Node is a HTML element built in Go code using H. It is an UI, and is
+// rendered as an Element with it's own Jid, as are it's child UIs.
+type Node struct {
+	UiHtml
+	Tag      string          // HTML tag name
+	Attrs    []template.HTML // attributes
+	Params   []interface{}   // parameters applied to the Element, such as tags and handlers
+	Children []interface{}   // UI, template.HTML or string children
+}
+
+// H builds a HTML element for pages or fragments composed in Go code, as
+// an alternative to templates. For example:
+//
+//	jaws.H("ul", jaws.Class("list"),
+//		jaws.H("li", "first"),
+//		jaws.H("li", jaws.NewUiSpan(getter)),
+//	)
+//
+// The args are interpreted as:
+//
+//   - template.HTMLAttr, such as returned by Class or A: an attribute
+//   - UI, including other Nodes: a child rendered as an Element
+//   - template.HTML: child HTML
+//   - string: child text, which is escaped
+//   - anything else: a parameter, as when rendering any UI, such as tags,
+//     event handlers, Label or Delegate
+func H(tag string, args ...interface{}) *Node {
+	n := &Node{Tag: tag}
+	for _, arg := range args {
+		switch v := arg.(type) {
+		case template.HTMLAttr:
+			n.Attrs = append(n.Attrs, template.HTML(v)) // #nosec G203
+		case UI, template.HTML, string:
+			n.Children = append(n.Children, v)
+		default:
+			n.Params = append(n.Params, v)
+		}
+	}
+	return n
+}
+
+// A returns an escaped attribute for use with H, see Attr.
+func A(name string, value interface{}) template.HTMLAttr {
+	return template.HTMLAttr(Attr(name, value)) // #nosec G203
+}
+
+// Class returns a class attribute with the given class names for use with H.
+func Class(names ...string) template.HTMLAttr {
+	return A("class", strings.Join(names, " "))
+}
+
+func (n *Node) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	attrs := n.parseParams(e, append(append([]interface{}{n.Attrs}, n.Params...), params...))
+	attrs = clickableAttrs(n.Tag, e.isClickable(n), attrs)
+	b := e.Jid().AppendStartTagAttr(nil, n.Tag)
+	for _, attr := range attrs {
+		if attr != "" {
+			b = append(b, ' ')
+			b = append(b, attr...)
+		}
+	}
+	b = append(b, '>')
+	if _, err = w.Write(b); err == nil {
+		if _, singleton := singletonTags[n.Tag]; !singleton {
+			for _, child := range n.Children {
+				if err == nil {
+					switch v := child.(type) {
+					case UI:
+						err = e.Request.NewElement(v).Render(w, nil)
+					case template.HTML:
+						_, err = io.WriteString(w, string(v))
+					case string:
+						_, err = io.WriteString(w, html.EscapeString(v))
+					}
+				}
+			}
+			if err == nil {
+				_, err = io.WriteString(w, "</"+n.Tag+">")
+			}
+		}
+	}
+	return
+}
+
+// JawsUpdate does nothing, as the children update themselves.
+func (n *Node) JawsUpdate(e *Element) {}
+
+// H renders the HTML element built by calling H with the arguments.
+func (rq RequestWriter) H(tag string, args ...interface{}) error {
+	return rq.UI(H(tag, args...))
+}
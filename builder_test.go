@@ -0,0 +1,48 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestH(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	clicked := make(chan string, 1)
+	page := H("div", Class("row", "main"), A("title", `a "b"`), Tag("page"),
+		H("span", "x < y"),
+		template.HTML("<hr>"),
+		H("input", A("type", "text"), "ignored"),
+		H("p", EventFn(func(e *Element, wht what.What, val string) error {
+			clicked <- val
+			return nil
+		}), NewUiSpan(makeHtmlGetter("inner"))),
+	)
+	th.NoErr(rq.UI(page, `data-x="1"`))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" class="row main" title="a &#34;b&#34;" data-x="1">`+
+		`<span id="Jid.2">x &lt; y</span><hr><input id="Jid.3" type="text">`+
+		`<p id="Jid.4"><span id="Jid.5">inner</span></p></div>`)
+	th.True(rq.getElementByJid(1).HasTag(Tag("page")))
+
+	rq.inCh <- wsMsg{Data: "hi\tJid.4\tJid.1", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-clicked:
+		th.Equal(s, "hi")
+	}
+}
+
+func TestRequestWriter_H(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	th.NoErr(rq.H("b", "bold"))
+	th.Equal(rq.BodyString(), `<b id="Jid.1">bold</b>`)
+}
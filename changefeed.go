@@ -0,0 +1,89 @@
+package jaws
+
+import (
+	"context"
+)
+
+// ChangeNotification is a notification that an entity has changed,
+// such as those sent by PostgreSQL's NOTIFY.
+type ChangeNotification struct {
+	Channel string // notification channel, e.g. a table name
+	Payload string // notification payload, e.g. the changed row's key
+}
+
+// ChangeSource delivers change notifications.
+type ChangeSource interface {
+	// WaitForChange blocks until a notification is received or ctx is done.
+	WaitForChange(ctx context.Context) (ChangeNotification, error)
+}
+
+// ChangeSourceFunc adapts an ordinary function to the ChangeSource interface.
+//
+// For example, using a pgx connection that has executed LISTEN:
+//
+//	src := jaws.ChangeSourceFunc(func(ctx context.Context) (n jaws.ChangeNotification, err error) {
+//		var pn *pgconn.Notification
+//		if pn, err = conn.WaitForNotification(ctx); err == nil {
+//			n = jaws.ChangeNotification{Channel: pn.Channel, Payload: pn.Payload}
+//		}
+//		return
+//	})
+type ChangeSourceFunc func(ctx context.Context) (ChangeNotification, error)
+
+func (fn ChangeSourceFunc) WaitForChange(ctx context.Context) (ChangeNotification, error) {
+	return fn(ctx)
+}
+
+// ChangeMapper returns the tags to dirty for a change notification.
+type ChangeMapper func(n ChangeNotification) []interface{}
+
+// ChangeTag returns the tag used by DefaultChangeMapper for the given
+// channel and key. Tag Elements with it to have them updated when a
+// notification for the key arrives.
+func ChangeTag(channel, key string) Tag {
+	return Tag(channel + ":" + key)
+}
+
+// DefaultChangeMapper maps a notification to the tags ChangeTag(n.Channel, n.Payload)
+// and ChangeTag(n.Channel, ""), the latter to update Elements depending on any
+// entity in the channel, such as lists.
+func DefaultChangeMapper(n ChangeNotification) []interface{} {
+	return []interface{}{ChangeTag(n.Channel, n.Payload), ChangeTag(n.Channel, "")}
+}
+
+// FollowChanges waits for notifications from src and dirties the tags that
+// mapper returns for them. If mapper is nil, DefaultChangeMapper is used.
+//
+// It returns nil when the Jaws is closed, parent.Err() when parent is done
+// and otherwise the error returned by src.
+func (jw *Jaws) FollowChanges(parent context.Context, src ChangeSource, mapper ChangeMapper) (err error) {
+	if mapper == nil {
+		mapper = DefaultChangeMapper
+	}
+	ctx, cancel := context.WithCancel(parent)
+	defer cancel()
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-jw.Done():
+			cancel()
+		}
+	}()
+	for err == nil {
+		var n ChangeNotification
+		if n, err = src.WaitForChange(ctx); err == nil {
+			if tags := mapper(n); len(tags) > 0 {
+				jw.Dirty(tags...)
+			}
+		}
+	}
+	select {
+	case <-jw.Done():
+		err = nil
+	default:
+		if parent.Err() != nil {
+			err = parent.Err()
+		}
+	}
+	return
+}
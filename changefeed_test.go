@@ -0,0 +1,52 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestJaws_FollowChanges(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+
+	srcCh := make(chan ChangeNotification)
+	errStop := errors.New("stop")
+	src := ChangeSourceFunc(func(ctx context.Context) (n ChangeNotification, err error) {
+		select {
+		case <-ctx.Done():
+			err = ctx.Err()
+		case n = <-srcCh:
+			if n.Channel == "" {
+				err = errStop
+			}
+		}
+		return
+	})
+
+	var dirtied []interface{}
+	mapper := func(n ChangeNotification) (tags []interface{}) {
+		tags = DefaultChangeMapper(n)
+		dirtied = append(dirtied, tags...)
+		return
+	}
+
+	doneCh := make(chan error)
+	go func() { doneCh <- rq.Jaws.FollowChanges(context.Background(), src, mapper) }()
+	srcCh <- ChangeNotification{Channel: "orders", Payload: "2"}
+	srcCh <- ChangeNotification{}
+	th.Equal(<-doneCh, errStop)
+	th.Equal(dirtied, []interface{}{ChangeTag("orders", "2"), ChangeTag("orders", "")})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		doneCh <- rq.Jaws.FollowChanges(ctx, src, func(n ChangeNotification) []interface{} { return nil })
+	}()
+	cancel()
+	th.Equal(<-doneCh, context.Canceled)
+
+	go func() { doneCh <- rq.Jaws.FollowChanges(context.Background(), src, nil) }()
+	rq.Jaws.Close()
+	th.NoErr(<-doneCh)
+}
@@ -0,0 +1,22 @@
+package jaws
+
+import "github.com/linkdata/jaws/what"
+
+// ChangeHandler receives the value when the user commits a change to an
+// input Element, such as when it loses focus or Enter is pressed.
+//
+// The HTML element must have the `data-jaws-change` attribute for the browser
+// to send change events. It is added automatically when a ChangeHandler
+// is passed as a parameter when rendering the Element.
+type ChangeHandler interface {
+	JawsChange(e *Element, val string) (err error)
+}
+
+type changeHandlerWrapper struct{ ChangeHandler }
+
+func (chw changeHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Change {
+		return chw.JawsChange(e, v)
+	}
+	return ErrEventUnhandled
+}
@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJawsChange struct {
+	vals []string
+	err  error
+}
+
+func (tjc *testJawsChange) JawsChange(e *Element, val string) error {
+	tjc.vals = append(tjc.vals, val)
+	return tjc.err
+}
+
+var _ ChangeHandler = (*testJawsChange)(nil)
+
+func Test_changeHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	tjc := &testJawsChange{}
+	th.NoErr(rq.Div("inner", tjc))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-change>inner</div>`)
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "x"))
+	th.NoErr(rq.callAllEventHandlers(1, what.Change, "foo"))
+	th.Equal(tjc.vals, []string{"foo"})
+
+	tjc.err = errors.New("bad")
+	th.Equal(rq.callAllEventHandlers(1, what.Change, "bar"), tjc.err)
+}
+
+type testChangeUi struct {
+	UiHtml
+	testJawsChange
+}
+
+func TestUiHtml_JawsEventChangeHandler(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	ui := &testChangeUi{}
+	ui.Tag = &ui.testJawsChange
+	elem := rq.NewElement(ui)
+	th.NoErr(ui.JawsEvent(elem, what.Change, "foo"))
+	th.Equal(ui.vals, []string{"foo"})
+	th.Equal(ui.JawsEvent(elem, what.Input, "foo"), ErrEventUnhandled)
+}
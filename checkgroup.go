@@ -0,0 +1,207 @@
+package jaws
+
+import (
+	"html/template"
+
+	"github.com/linkdata/deadlock"
+)
+
+// CheckItem is a checkbox in a CheckGroup.
+type CheckItem struct {
+	Name  string
+	Label template.HTML
+}
+
+// CheckGroup binds a group of checkboxes to a map[string]bool, a []string
+// set or an integer bitmask. It is safe to use from multiple goroutines
+// concurrently, as long as the bound value is only accessed using the
+// CheckGroup or from within ReadLocked.
+type CheckGroup struct {
+	// OnChange, if not nil, is called for each item that changes state
+	// before the bound value is changed. If it returns an error, the
+	// item is left unchanged.
+	OnChange func(e *Element, name string, checked bool) error
+	mu       deadlock.RWMutex // protects the bound value
+	items    []*checkItem     // (read-only)
+	bound    checkSet         // (read-only)
+	all      *checkAll        // (read-only)
+}
+
+type checkSet interface {
+	has(idx int, name string) bool
+	set(idx int, name string, checked bool)
+}
+
+type checkMap map[string]bool
+
+func (cs checkMap) has(_ int, name string) bool {
+	return cs[name]
+}
+
+func (cs checkMap) set(_ int, name string, checked bool) {
+	cs[name] = checked
+}
+
+type checkSlice struct{ p *[]string }
+
+func (cs checkSlice) has(_ int, name string) bool {
+	for _, s := range *cs.p {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (cs checkSlice) set(idx int, name string, checked bool) {
+	var names []string
+	for _, s := range *cs.p {
+		if s != name {
+			names = append(names, s)
+		}
+	}
+	if checked {
+		names = append(names, name)
+	}
+	*cs.p = names
+}
+
+// Bitmask is the constraint for integer types usable with NewCheckGroupBits.
+type Bitmask interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+type checkBits[T Bitmask] struct{ p *T }
+
+func (cs checkBits[T]) has(idx int, _ string) bool {
+	return *cs.p&(T(1)<<idx) != 0
+}
+
+func (cs checkBits[T]) set(idx int, _ string, checked bool) {
+	if checked {
+		*cs.p |= T(1) << idx
+	} else {
+		*cs.p &^= T(1) << idx
+	}
+}
+
+func newCheckGroup(bound checkSet, items []CheckItem) (cg *CheckGroup) {
+	cg = &CheckGroup{bound: bound}
+	for i, item := range items {
+		cg.items = append(cg.items, &checkItem{cg: cg, idx: i, CheckItem: item})
+	}
+	cg.all = &checkAll{cg: cg}
+	return
+}
+
+// NewCheckGroupMap returns a CheckGroup bound to m, where the item names
+// are the keys. Items that are checked have the value true.
+func NewCheckGroupMap(m map[string]bool, items ...CheckItem) *CheckGroup {
+	return newCheckGroup(checkMap(m), items)
+}
+
+// NewCheckGroupSet returns a CheckGroup bound to the set of names of
+// the checked items in *p. Newly checked items are appended.
+func NewCheckGroupSet(p *[]string, items ...CheckItem) *CheckGroup {
+	return newCheckGroup(checkSlice{p}, items)
+}
+
+// NewCheckGroupBits returns a CheckGroup bound to the bitmask *p, where
+// the n'th item is bit n.
+func NewCheckGroupBits[T Bitmask](p *T, items ...CheckItem) *CheckGroup {
+	return newCheckGroup(checkBits[T]{p}, items)
+}
+
+// ReadLocked calls fn with the CheckGroup locked for reading, so the bound
+// value can be read safely.
+func (cg *CheckGroup) ReadLocked(fn func()) {
+	cg.mu.RLock()
+	defer cg.mu.RUnlock()
+	fn()
+}
+
+// IsChecked returns true if the item with the given name is checked.
+func (cg *CheckGroup) IsChecked(name string) (checked bool) {
+	cg.mu.RLock()
+	defer cg.mu.RUnlock()
+	for _, item := range cg.items {
+		if item.Name == name {
+			return cg.bound.has(item.idx, item.Name)
+		}
+	}
+	return
+}
+
+// count returns the number of checked items.
+func (cg *CheckGroup) count() (n int) {
+	cg.mu.RLock()
+	defer cg.mu.RUnlock()
+	for _, item := range cg.items {
+		if cg.bound.has(item.idx, item.Name) {
+			n++
+		}
+	}
+	return
+}
+
+// setItems sets the state of the given items, calling OnChange for each
+// item that changes, and marks them dirty together with the "select all"
+// checkbox.
+func (cg *CheckGroup) setItems(e *Element, items []*checkItem, checked bool) (err error) {
+	dirty := []interface{}{cg.all}
+	for _, item := range items {
+		if item.JawsGetBool(e) != checked {
+			if cg.OnChange != nil {
+				if err = cg.OnChange(e, item.Name, checked); err != nil {
+					break
+				}
+			}
+			cg.mu.Lock()
+			cg.bound.set(item.idx, item.Name, checked)
+			cg.mu.Unlock()
+			dirty = append(dirty, item)
+		}
+	}
+	e.Dirty(dirty...)
+	return
+}
+
+type checkItem struct {
+	cg  *CheckGroup
+	idx int
+	CheckItem
+}
+
+func (item *checkItem) JawsGetBool(*Element) bool {
+	item.cg.mu.RLock()
+	defer item.cg.mu.RUnlock()
+	return item.cg.bound.has(item.idx, item.Name)
+}
+
+func (item *checkItem) JawsSetBool(e *Element, checked bool) error {
+	return item.cg.setItems(e, []*checkItem{item}, checked)
+}
+
+func (item *checkItem) JawsGetHtml(*Element) template.HTML {
+	return item.Label
+}
+
+type checkAll struct{ cg *CheckGroup }
+
+// JawsGetTriState returns Indeterminate if some but not all items are checked.
+func (ca *checkAll) JawsGetTriState(*Element) TriState {
+	switch ca.cg.count() {
+	case len(ca.cg.items):
+		return Checked
+	case 0:
+		return Unchecked
+	}
+	return Indeterminate
+}
+
+func (ca *checkAll) JawsSetTriState(e *Element, v TriState) error {
+	if v == Indeterminate {
+		return ErrValueNotSettable
+	}
+	return ca.cg.setItems(e, ca.cg.items, v == Checked)
+}
@@ -0,0 +1,73 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+)
+
+var testCheckItems = []CheckItem{{"r", "Red"}, {"g", "Green"}, {"b", "Blue"}}
+
+func TestCheckGroup_Bindings(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	e := rq.NewElement(NewUiSpan(makeHtmlGetter("")))
+
+	m := map[string]bool{"g": true}
+	cgm := NewCheckGroupMap(m, testCheckItems...)
+	th.True(cgm.IsChecked("g"))
+	th.True(!cgm.IsChecked("r"))
+	th.True(!cgm.IsChecked("x"))
+	th.NoErr(cgm.items[0].JawsSetBool(e, true))
+	th.NoErr(cgm.items[1].JawsSetBool(e, false))
+	cgm.ReadLocked(func() { th.Equal(m, map[string]bool{"r": true, "g": false}) })
+
+	set := []string{"b"}
+	cgs := NewCheckGroupSet(&set, testCheckItems...)
+	th.True(cgs.IsChecked("b"))
+	th.NoErr(cgs.items[0].JawsSetBool(e, true))
+	th.Equal(set, []string{"b", "r"})
+	th.NoErr(cgs.items[2].JawsSetBool(e, false))
+	th.Equal(set, []string{"r"})
+
+	type perms uint8
+	var bits perms = 0b100
+	cgb := NewCheckGroupBits(&bits, testCheckItems...)
+	th.True(cgb.IsChecked("b"))
+	th.NoErr(cgb.items[0].JawsSetBool(e, true))
+	th.Equal(bits, perms(0b101))
+	th.NoErr(cgb.all.JawsSetTriState(e, Checked))
+	th.Equal(bits, perms(0b111))
+	th.Equal(cgb.all.JawsGetTriState(e), Checked)
+	th.NoErr(cgb.all.JawsSetTriState(e, Unchecked))
+	th.Equal(bits, perms(0))
+}
+
+func TestCheckGroup_OnChange(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	e := rq.NewElement(NewUiSpan(makeHtmlGetter("")))
+
+	var bits int
+	cg := NewCheckGroupBits(&bits, testCheckItems...)
+	var changes []string
+	errVeto := errors.New("veto")
+	cg.OnChange = func(e *Element, name string, checked bool) error {
+		if name == "b" {
+			return errVeto
+		}
+		changes = append(changes, name)
+		return nil
+	}
+	th.NoErr(cg.items[1].JawsSetBool(e, true))
+	th.NoErr(cg.items[1].JawsSetBool(e, true))
+	th.Equal(changes, []string{"g"})
+	th.Equal(cg.all.JawsSetTriState(e, Checked), errVeto)
+	th.Equal(changes, []string{"g", "r"})
+	th.Equal(bits, 0b011)
+	th.Equal(cg.all.JawsGetTriState(e), Indeterminate)
+	th.Equal(cg.all.JawsSetTriState(e, Indeterminate), ErrValueNotSettable)
+}
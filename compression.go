@@ -0,0 +1,184 @@
+package jaws
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"sync/atomic"
+)
+
+// DefaultCompressMinSize is the CompressMinSize used if Jaws.CompressMinSize is zero.
+const DefaultCompressMinSize = 512
+
+// WireStats counts the messages sent to browsers and the bytes needed to do so.
+type WireStats struct {
+	Messages  uint64 // WebSocket messages sent, each may hold several JaWS messages
+	Bytes     uint64 // size of the messages sent, before compression
+	WireBytes uint64 // bytes written to the network, including WebSocket framing
+}
+
+// Ratio returns WireBytes divided by Bytes, or 1 if nothing has been sent.
+// Values below 1 are the fraction of bandwidth compression leaves in use.
+func (s WireStats) Ratio() float64 {
+	if s.Bytes == 0 {
+		return 1
+	}
+	return float64(s.WireBytes) / float64(s.Bytes)
+}
+
+// CompressionStats summarizes the WebSocket compression of all connections.
+type CompressionStats struct {
+	Connections uint64 // WebSocket connections accepted
+	Compressed  uint64 // connections that negotiated compression
+	WireStats
+}
+
+// Compression describes the compression negotiated for a Request's
+// current WebSocket connection, and the traffic sent on it.
+type Compression struct {
+	Enabled         bool // if permessage-deflate was negotiated with the browser
+	ContextTakeover bool // if the compression context is kept between messages
+	MinSize         int  // messages smaller than this are sent uncompressed
+	WireStats
+}
+
+// CompressionStats returns the compression statistics for all WebSocket connections.
+func (jw *Jaws) CompressionStats() CompressionStats {
+	return CompressionStats{
+		Connections: jw.wsConns.Load(),
+		Compressed:  jw.wsDeflated.Load(),
+		WireStats:   jw.wsTotal.stats(),
+	}
+}
+
+// Compression returns the compression negotiated for the Request's
+// WebSocket connection. It is the zero value if there is none.
+func (rq *Request) Compression() (c Compression) {
+	rq.mu.RLock()
+	c, counters := rq.compress, rq.wsCount
+	rq.mu.RUnlock()
+	if counters != nil {
+		c.WireStats = counters.stats()
+	}
+	return
+}
+
+// compressMinSize returns the message size at which compression starts,
+// or a negative value if compression is disabled.
+func (jw *Jaws) compressMinSize() int {
+	if jw.CompressMinSize != 0 {
+		return jw.CompressMinSize
+	}
+	return DefaultCompressMinSize
+}
+
+// setCompression records the compression parameters negotiated in the
+// WebSocket handshake response headers h.
+func (rq *Request) setCompression(h http.Header, counters *wsCounters) {
+	var c Compression
+	for _, ext := range strings.Split(h.Get("Sec-WebSocket-Extensions"), ",") {
+		params := strings.Split(ext, ";")
+		if strings.TrimSpace(params[0]) == "permessage-deflate" {
+			c.Enabled = true
+			c.ContextTakeover = true
+			c.MinSize = rq.Jaws.compressMinSize()
+			for _, p := range params[1:] {
+				if strings.TrimSpace(p) == "server_no_context_takeover" {
+					c.ContextTakeover = false
+				}
+			}
+		}
+	}
+	rq.Jaws.wsConns.Add(1)
+	if c.Enabled {
+		rq.Jaws.wsDeflated.Add(1)
+	}
+	rq.mu.Lock()
+	rq.compress = c
+	rq.wsCount = counters
+	rq.mu.Unlock()
+}
+
+// wsCounters counts the traffic on a WebSocket connection.
+type wsCounters struct {
+	messages  atomic.Uint64
+	bytes     atomic.Uint64
+	wireBytes atomic.Uint64
+	total     *wsCounters // if not nil, also counts the traffic
+}
+
+func (c *wsCounters) addMessage(n int) {
+	for ; c != nil; c = c.total {
+		c.messages.Add(1)
+		c.bytes.Add(uint64(n))
+	}
+}
+
+func (c *wsCounters) addWire(n int) {
+	for ; c != nil; c = c.total {
+		c.wireBytes.Add(uint64(n))
+	}
+}
+
+func (c *wsCounters) stats() WireStats {
+	return WireStats{
+		Messages:  c.messages.Load(),
+		Bytes:     c.bytes.Load(),
+		WireBytes: c.wireBytes.Load(),
+	}
+}
+
+// wrap returns w with the connection it hijacks counting the bytes written.
+func (c *wsCounters) wrap(w http.ResponseWriter) http.ResponseWriter {
+	if _, ok := w.(http.Hijacker); ok {
+		return wireCountingWriter{ResponseWriter: w, counters: c}
+	}
+	return w
+}
+
+// wireCountingWriter is a http.ResponseWriter whose hijacked connection counts bytes written.
+type wireCountingWriter struct {
+	http.ResponseWriter
+	counters *wsCounters
+}
+
+func (w wireCountingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w wireCountingWriter) Hijack() (conn net.Conn, brw *bufio.ReadWriter, err error) {
+	if conn, brw, err = w.ResponseWriter.(http.Hijacker).Hijack(); err == nil {
+		if err = brw.Writer.Flush(); err == nil {
+			conn = wireCountingConn{Conn: conn, counters: w.counters}
+			brw.Writer.Reset(conn)
+		}
+	}
+	return
+}
+
+// wireCountingConn is a net.Conn counting the bytes written.
+type wireCountingConn struct {
+	net.Conn
+	counters *wsCounters
+}
+
+func (c wireCountingConn) Write(b []byte) (n int, err error) {
+	n, err = c.Conn.Write(b)
+	c.counters.addWire(n)
+	return
+}
+
+// countingWebSocketConn is a WebSocketConn counting the messages written.
+type countingWebSocketConn struct {
+	WebSocketConn
+	counters *wsCounters
+}
+
+func (c countingWebSocketConn) Write(ctx context.Context, msg []byte) (err error) {
+	if err = c.WebSocketConn.Write(ctx, msg); err == nil {
+		c.counters.addMessage(len(msg))
+	}
+	return
+}
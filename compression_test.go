@@ -0,0 +1,36 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWireStats_Ratio(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(WireStats{}.Ratio(), 1.0)
+	th.Equal(WireStats{Bytes: 200, WireBytes: 50}.Ratio(), 0.25)
+}
+
+func TestRequest_setCompression(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+
+	h := http.Header{}
+	rq.setCompression(h, &wsCounters{})
+	th.Equal(rq.Compression(), Compression{})
+
+	jw.CompressMinSize = 100
+	h.Set("Sec-WebSocket-Extensions", "permessage-deflate")
+	rq.setCompression(h, &wsCounters{})
+	th.Equal(rq.Compression(), Compression{Enabled: true, ContextTakeover: true, MinSize: 100})
+
+	h.Set("Sec-WebSocket-Extensions", "permessage-deflate; client_no_context_takeover; server_no_context_takeover")
+	rq.setCompression(h, &wsCounters{})
+	th.Equal(rq.Compression(), Compression{Enabled: true, MinSize: 100})
+
+	th.Equal(jw.CompressionStats().Connections, uint64(3))
+	th.Equal(jw.CompressionStats().Compressed, uint64(2))
+}
@@ -0,0 +1,84 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strconv"
+)
+
+// Confirm is a parameter that makes the browser ask the user to confirm
+// the given message before sending a click event for the Element.
+//
+//	rw.Button("Delete", item, jaws.Confirm("Delete this item?"))
+//
+// To guard against replayed messages, the Element is given a one-time
+// token that the click must carry. The token changes after each accepted
+// click, and clicks without the current token are ignored.
+type Confirm string
+
+// ErrConfirmTokenMismatch is logged when a click for an Element using
+// Confirm does not carry the current confirmation token.
+var ErrConfirmTokenMismatch = errors.New("confirmation token mismatch")
+
+func (jw *Jaws) newConfirmToken() string {
+	jw.mu.Lock()
+	val := jw.nonZeroRandomLocked()
+	jw.mu.Unlock()
+	return strconv.FormatUint(val, 36)
+}
+
+// confirmAttrs gives the Element a new confirmation token and returns
+// the HTML attributes for it.
+func (e *Element) confirmAttrs(msg Confirm) []string {
+	token := e.Request.Jaws.newConfirmToken()
+	e.Request.mu.Lock()
+	e.confirm = token
+	e.Request.mu.Unlock()
+	return []string{
+		`data-jaws-confirm="` + html.EscapeString(string(msg)) + `"`,
+		`data-jaws-confirm-token="` + token + `"`,
+	}
+}
+
+// needsConfirm returns true if the Element requires a confirmation token for clicks.
+func (rq *Request) needsConfirm(e *Element) (yes bool) {
+	rq.mu.RLock()
+	yes = e.confirm != ""
+	rq.mu.RUnlock()
+	return
+}
+
+// clickTokenErr returns an error if clicks for the Element must carry a
+// confirmation token or idempotency key, which only the Jid list form has.
+func (rq *Request) clickTokenErr(e *Element) (err error) {
+	rq.mu.RLock()
+	if e.confirm != "" {
+		err = ErrConfirmTokenMismatch
+	} else if e.once != "" {
+		err = ErrIdempotencyKeyMissing
+	}
+	rq.mu.RUnlock()
+	return
+}
+
+// checkConfirm returns true if the Element doesn't require confirmation,
+// or if token is the current confirmation token, in which case the
+// Element is given a new token.
+func (rq *Request) checkConfirm(e *Element, token string) (ok bool) {
+	if !rq.needsConfirm(e) {
+		return true
+	}
+	newToken := rq.Jaws.newConfirmToken()
+	rq.mu.Lock()
+	if ok = e.confirm == token; ok {
+		e.confirm = newToken
+	}
+	rq.mu.Unlock()
+	if ok {
+		rq.Jaws.SetAttr(e, "data-jaws-confirm-token", newToken)
+	} else {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w", e, ErrConfirmTokenMismatch))
+	}
+	return
+}
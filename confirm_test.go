@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+var confirmTokenRx = regexp.MustCompile(`data-jaws-confirm-token(?:="|\\n)([0-9a-z]+)`)
+
+func TestRequest_Confirm(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tjc := &testJawsClick{
+		clickCh:    make(chan string),
+		testSetter: newTestSetter(""),
+	}
+	th.NoErr(rq.Button("Delete", tjc, Confirm(`Delete "it"?`)))
+	body := rq.BodyString()
+	th.True(strings.Contains(body, `data-jaws-confirm="Delete &#34;it&#34;?"`))
+	m := confirmTokenRx.FindStringSubmatch(body)
+	th.Equal(len(m), 2)
+	token := m[1]
+
+	// clicks without the token or addressed directly are ignored
+	rq.inCh <- wsMsg{Data: "NoToken\tJid.1", What: what.Click}
+	rq.inCh <- wsMsg{Data: "Direct", Jid: 1, What: what.Click}
+	rq.inCh <- wsMsg{Data: "Delete\tJid.1:" + token, What: what.Click}
+	var newToken string
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		m = confirmTokenRx.FindStringSubmatch(s)
+		th.Equal(len(m), 2)
+		newToken = m[1]
+		th.True(newToken != token)
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case name := <-tjc.clickCh:
+		th.Equal(name, "Delete")
+	}
+
+	// replaying the click is ignored
+	rq.inCh <- wsMsg{Data: "Replay\tJid.1:" + token, What: what.Click}
+	rq.inCh <- wsMsg{Data: "Again\tJid.1:" + newToken, What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case name := <-tjc.clickCh:
+		th.Equal(name, "Again")
+	}
+}
@@ -0,0 +1,225 @@
+package jaws
+
+import (
+	"github.com/linkdata/deadlock"
+)
+
+// DataSort orders the items of a DataSource by a field.
+type DataSort struct {
+	Field string // field name as understood by the DataSource, empty for the default order
+	Desc  bool   // if true, sort in descending order
+}
+
+// DataFilter selects the items of a DataSource.
+type DataFilter struct {
+	Query  string            // free text search, empty matches everything
+	Fields map[string]string // required field values, may be nil
+}
+
+// DataSource is the contract between an application's data layer and the
+// paging components, such as Pager and DataSearch. Sorting and filtering
+// is done by the DataSource, usually in a database query.
+type DataSource[T any] interface {
+	// Count returns the number of items matching filter.
+	Count(rq *Request, filter DataFilter) (n int, err error)
+	// FetchPage returns at most limit items matching filter in the given
+	// sort order, skipping the first offset items.
+	FetchPage(rq *Request, offset, limit int, sort DataSort, filter DataFilter) (items []T, err error)
+}
+
+// DefaultPageSize is the default number of items per page for a Pager.
+const DefaultPageSize = 20
+
+// Pager is a Container showing one page of items from a DataSource, so
+// it can be used with RequestWriter.Tbody, RequestWriter.Container and
+// RequestWriter.Select. The UI for each item is made by Render.
+//
+// The Pager is used as a tag, so Elements showing it, including UiPagerNav,
+// are updated when it is dirtied. It is safe to use from multiple goroutines
+// concurrently.
+type Pager[T comparable] struct {
+	Source DataSource[T]    // (read-only) source of items
+	Render func(item T) UI  // (read-only) returns the UI for an item
+	mu     deadlock.RWMutex // protects following
+	offset int
+	limit  int
+	sort   DataSort
+	filter DataFilter
+	count  int
+	err    error
+	uis    map[T]UI
+}
+
+// NewPager returns a Pager showing limit items at a time from src.
+// If limit is less than one, DefaultPageSize is used.
+func NewPager[T comparable](src DataSource[T], limit int, render func(item T) UI) *Pager[T] {
+	if limit < 1 {
+		limit = DefaultPageSize
+	}
+	return &Pager[T]{Source: src, Render: render, limit: limit, uis: map[T]UI{}}
+}
+
+// JawsContains fetches the current page and returns the UIs for it's items.
+// The UIs are reused for items that remain on the page.
+func (p *Pager[T]) JawsContains(rq *Request) (contents []UI) {
+	p.mu.RLock()
+	offset, limit, sort, filter := p.offset, p.limit, p.sort, p.filter
+	p.mu.RUnlock()
+	count, err := p.Source.Count(rq, filter)
+	var items []T
+	if err == nil {
+		items, err = p.Source.FetchPage(rq, offset, limit, sort, filter)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.count, p.err = count, err
+	if err != nil {
+		rq.Jaws.MustLog(err)
+		return
+	}
+	uis := make(map[T]UI, len(items))
+	for _, item := range items {
+		ui := p.uis[item]
+		if ui == nil {
+			ui = p.Render(item)
+		}
+		uis[item] = ui
+		contents = append(contents, ui)
+	}
+	p.uis = uis
+	return
+}
+
+// PageStatus returns the offset of the current page, the page size and
+// the number of items matching the filter.
+func (p *Pager[T]) PageStatus(rq *Request) (offset, limit, count int) {
+	p.mu.RLock()
+	filter := p.filter
+	p.mu.RUnlock()
+	n, err := p.Source.Count(rq, filter)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err == nil {
+		p.count = n
+	}
+	return p.offset, p.limit, p.count
+}
+
+// Err returns the error from the last fetch, if any.
+func (p *Pager[T]) Err() (err error) {
+	p.mu.RLock()
+	err = p.err
+	p.mu.RUnlock()
+	return
+}
+
+// SetOffset moves to the page starting at offset, clamped to the last
+// known item count. Returns true if the offset changed.
+func (p *Pager[T]) SetOffset(offset int) (changed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if offset >= p.count {
+		offset = (p.count - 1) / p.limit * p.limit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	changed = p.offset != offset
+	p.offset = offset
+	return
+}
+
+// Move moves the given number of pages forward, or backward if negative.
+// Returns true if the offset changed.
+func (p *Pager[T]) Move(pages int) (changed bool) {
+	p.mu.RLock()
+	offset := p.offset + pages*p.limit
+	p.mu.RUnlock()
+	return p.SetOffset(offset)
+}
+
+// Sort returns the current sort order.
+func (p *Pager[T]) Sort() (sort DataSort) {
+	p.mu.RLock()
+	sort = p.sort
+	p.mu.RUnlock()
+	return
+}
+
+// SetSort sets the sort order and moves to the first page.
+// Returns true if the sort order changed.
+func (p *Pager[T]) SetSort(sort DataSort) (changed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if changed = p.sort != sort; changed {
+		p.sort, p.offset = sort, 0
+	}
+	return
+}
+
+// Filter returns the current filter.
+func (p *Pager[T]) Filter() (filter DataFilter) {
+	p.mu.RLock()
+	filter = p.filter
+	p.mu.RUnlock()
+	return
+}
+
+// SetFilter sets the filter and moves to the first page.
+// The Fields map must not be modified afterwards.
+func (p *Pager[T]) SetFilter(filter DataFilter) {
+	p.mu.Lock()
+	p.filter, p.offset = filter, 0
+	p.mu.Unlock()
+}
+
+// Query returns a StringSetter bound to the free text query of the filter,
+// for use with RequestWriter.Text. Setting it moves to the first page.
+func (p *Pager[T]) Query() StringSetter {
+	return pagerQuery[T]{p}
+}
+
+type pagerQuery[T comparable] struct{ p *Pager[T] }
+
+func (pq pagerQuery[T]) JawsGetString(*Element) string {
+	return pq.p.Filter().Query
+}
+
+func (pq pagerQuery[T]) JawsSetString(e *Element, v string) error {
+	pq.p.mu.Lock()
+	changed := pq.p.filter.Query != v
+	pq.p.filter.Query, pq.p.offset = v, 0
+	pq.p.mu.Unlock()
+	if changed {
+		e.Dirty(pq.p)
+	}
+	return nil
+}
+
+// DataSearch adapts a DataSource to the SearchSource of a UiSearchSelect,
+// passing the query as DataFilter.Query.
+type DataSearch[T any] struct {
+	Source DataSource[T]                                          // source of options
+	Option func(item T) SearchOption                              // returns the option for an item
+	Label  func(e *Element, key string) (label string, err error) // resolves and validates keys
+	Sort   DataSort                                               // sort order of the options
+}
+
+var _ SearchSource = DataSearch[int]{}
+
+func (ds DataSearch[T]) JawsSearch(e *Element, query string, offset, limit int) (opts []SearchOption, more bool, err error) {
+	var items []T
+	if items, err = ds.Source.FetchPage(e.Request, offset, limit+1, ds.Sort, DataFilter{Query: query}); err == nil {
+		if more = len(items) > limit; more {
+			items = items[:limit]
+		}
+		for _, item := range items {
+			opts = append(opts, ds.Option(item))
+		}
+	}
+	return
+}
+
+func (ds DataSearch[T]) JawsSearchLabel(e *Element, key string) (string, error) {
+	return ds.Label(e, key)
+}
@@ -0,0 +1,134 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testDataSource struct {
+	n       int
+	err     error
+	fetches int
+}
+
+func (ds *testDataSource) match(filter DataFilter) (items []int) {
+	for i := 1; i <= ds.n; i++ {
+		if strings.Contains(strconv.Itoa(i), filter.Query) {
+			items = append(items, i)
+		}
+	}
+	return
+}
+
+func (ds *testDataSource) Count(rq *Request, filter DataFilter) (int, error) {
+	return len(ds.match(filter)), ds.err
+}
+
+func (ds *testDataSource) FetchPage(rq *Request, offset, limit int, sort DataSort, filter DataFilter) (items []int, err error) {
+	ds.fetches++
+	items = ds.match(filter)
+	if sort.Desc {
+		slices.Reverse(items)
+	}
+	items = items[min(offset, len(items)):]
+	return items[:min(limit, len(items))], ds.err
+}
+
+func testPagerNames(t *testing.T, rq *Request, p *Pager[int]) (names []string) {
+	t.Helper()
+	for _, ui := range p.JawsContains(rq) {
+		names = append(names, string(ui.(*UiLi).JawsGetHtml(nil)))
+	}
+	return
+}
+
+func TestPager(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ds := &testDataSource{n: 45}
+	p := NewPager[int](ds, 0, func(i int) UI { return NewUiLi(makeHtmlGetter(template.HTML(strconv.Itoa(i)))) })
+	offset, limit, count := p.PageStatus(rq.Request)
+	th.Equal([]int{offset, limit, count}, []int{0, DefaultPageSize, 45})
+
+	p = NewPager[int](ds, 10, p.Render)
+	first := p.JawsContains(rq.Request)
+	th.Equal(len(first), 10)
+	th.Equal(testPagerNames(t, rq.Request, p)[9], "10")
+	th.Equal(p.JawsContains(rq.Request)[0], first[0]) // UIs are reused
+
+	th.True(p.Move(1))
+	th.Equal(testPagerNames(t, rq.Request, p)[0], "11")
+	th.True(p.SetOffset(1000))
+	th.Equal(testPagerNames(t, rq.Request, p), []string{"41", "42", "43", "44", "45"})
+	th.True(!p.Move(1))
+	th.True(p.SetOffset(-5))
+	th.True(!p.SetOffset(0))
+
+	th.True(p.SetSort(DataSort{Desc: true}))
+	th.True(!p.SetSort(DataSort{Desc: true}))
+	th.Equal(p.Sort(), DataSort{Desc: true})
+	th.Equal(testPagerNames(t, rq.Request, p)[0], "45")
+
+	p.Move(1)
+	p.SetFilter(DataFilter{Query: "4"})
+	th.Equal(p.Filter().Query, "4")
+	th.Equal(testPagerNames(t, rq.Request, p), []string{"45", "44", "43", "42", "41", "40", "34", "24", "14", "4"})
+	offset, _, count = p.PageStatus(rq.Request)
+	th.Equal([]int{offset, count}, []int{0, 10})
+
+	ds.err = errors.New("db down")
+	th.Equal(len(p.JawsContains(rq.Request)), 0)
+	th.Equal(p.Err(), ds.err)
+	th.True(strings.Contains(rq.jw.log.String(), "db down"))
+}
+
+func TestPager_Query(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	p := NewPager[int](&testDataSource{n: 45}, 10, func(i int) UI { return NewUiLi(makeHtmlGetter(strconv.Itoa(i))) })
+	p.Move(2)
+	th.NoErr(rq.Text(p.Query()))
+	th.NoErr(rq.Tbody(p))
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "1"))
+	th.Equal(p.Filter().Query, "1")
+	offset, _, _ := p.PageStatus(rq.Request)
+	th.Equal(offset, 0)
+}
+
+func TestDataSearch(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ds := DataSearch[int]{
+		Source: &testDataSource{n: 45},
+		Option: func(i int) SearchOption { return SearchOption{Key: strconv.Itoa(i), Label: "#" + strconv.Itoa(i)} },
+		Label: func(e *Element, key string) (string, error) {
+			return "#" + key, nil
+		},
+	}
+	th.NoErr(rq.SearchSelect(ds, "4"))
+	th.True(strings.Contains(rq.BodyString(), `value="#4"`))
+	e := rq.getElementByJid(1)
+	opts, more, err := ds.JawsSearch(e, "3", 0, 3)
+	th.NoErr(err)
+	th.True(more)
+	th.Equal(opts, []SearchOption{{"3", "#3"}, {"13", "#13"}, {"23", "#23"}})
+	opts, more, err = ds.JawsSearch(e, "3", 12, 3)
+	th.NoErr(err)
+	th.True(!more)
+	th.Equal(opts, []SearchOption{{"39", "#39"}, {"43", "#43"}})
+}
@@ -0,0 +1,96 @@
+package jaws
+
+import (
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// UIDecorator returns a UI that adds behavior to the next UI,
+// for example caching, authorization or instrumentation.
+//
+// Decorators usually embed DecoratedUI so that only the methods
+// they change need to be implemented.
+type UIDecorator func(next UI) UI
+
+// Wrap decorates ui with the given decorators. The first decorator
+// is the outermost, so it sees calls before the ones following it.
+func Wrap(ui UI, decorators ...UIDecorator) UI {
+	for i := len(decorators) - 1; i >= 0; i-- {
+		ui = decorators[i](ui)
+	}
+	return ui
+}
+
+// DecoratedUI forwards JawsRender, JawsUpdate and events to the embedded UI.
+type DecoratedUI struct {
+	UI
+}
+
+func (d DecoratedUI) JawsEvent(e *Element, wht what.What, val string) error {
+	return callEventHandler(d.UI, e, wht, val)
+}
+
+// TimingFn receives the time taken by a JawsRender ("render") or JawsUpdate ("update") call.
+type TimingFn = func(e *Element, op string, elapsed time.Duration)
+
+type uiTiming struct {
+	DecoratedUI
+	fn TimingFn
+}
+
+func (ui uiTiming) JawsRender(e *Element, w io.Writer, params []any) (err error) {
+	start := time.Now()
+	err = ui.UI.JawsRender(e, w, params)
+	ui.fn(e, "render", time.Since(start))
+	return
+}
+
+func (ui uiTiming) JawsUpdate(e *Element) {
+	start := time.Now()
+	ui.UI.JawsUpdate(e)
+	ui.fn(e, "update", time.Since(start))
+}
+
+// Timing returns a UIDecorator that calls fn with the time taken by
+// each JawsRender and JawsUpdate call.
+func Timing(fn TimingFn) UIDecorator {
+	return func(next UI) UI {
+		return uiTiming{DecoratedUI: DecoratedUI{UI: next}, fn: fn}
+	}
+}
+
+type uiAuthorize struct {
+	DecoratedUI
+	allow func(rq *Request) bool
+}
+
+func (ui uiAuthorize) JawsRender(e *Element, w io.Writer, params []any) error {
+	if ui.allow(e.Request) {
+		return ui.UI.JawsRender(e, w, params)
+	}
+	return WriteHtmlInner(w, e.Jid(), "template", "", "")
+}
+
+func (ui uiAuthorize) JawsUpdate(e *Element) {
+	if ui.allow(e.Request) {
+		ui.UI.JawsUpdate(e)
+	}
+}
+
+func (ui uiAuthorize) JawsEvent(e *Element, wht what.What, val string) error {
+	if ui.allow(e.Request) {
+		return ui.DecoratedUI.JawsEvent(e, wht, val)
+	}
+	return nil
+}
+
+// Authorize returns a UIDecorator that only renders, updates and
+// handles events for the UI if allow returns true for the Request.
+// Otherwise an empty placeholder element is rendered and events are ignored.
+func Authorize(allow func(rq *Request) bool) UIDecorator {
+	return func(next UI) UI {
+		return uiAuthorize{DecoratedUI: DecoratedUI{UI: next}, allow: allow}
+	}
+}
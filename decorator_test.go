@@ -0,0 +1,98 @@
+package jaws
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testDecorator struct {
+	DecoratedUI
+	name string
+}
+
+func (ui testDecorator) JawsRender(e *Element, w io.Writer, params []any) (err error) {
+	if _, err = io.WriteString(w, "<"+ui.name+">"); err == nil {
+		if err = ui.UI.JawsRender(e, w, params); err == nil {
+			_, err = io.WriteString(w, "</"+ui.name+">")
+		}
+	}
+	return
+}
+
+func makeTestDecorator(name string) UIDecorator {
+	return func(next UI) UI {
+		return testDecorator{DecoratedUI: DecoratedUI{UI: next}, name: name}
+	}
+}
+
+func TestWrap(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ui := Wrap(NewUiSpan(makeHtmlGetter("inner")), makeTestDecorator("a"), makeTestDecorator("b"))
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<a><b><span id="Jid.1">inner</span></b></a>`)
+	th.Equal(Wrap(ui), ui)
+}
+
+func TestWrap_Timing(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	opCh := make(chan string, 2)
+	ss := newTestSetter("foo")
+	ui := Wrap(NewUiText(ss), Timing(func(e *Element, op string, elapsed time.Duration) {
+		th.Equal(e.Jid(), Jid(1))
+		th.True(elapsed >= 0)
+		opCh <- op
+	}))
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" value="foo">`)
+	th.Equal(<-opCh, "render")
+
+	rq.inCh <- wsMsg{Data: "bar", Jid: 1, What: what.Input}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-ss.setCalled:
+	}
+	th.Equal(ss.Get(), "bar")
+
+	ss.Set("quux")
+	rq.Dirty(ss)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case op := <-opCh:
+		th.Equal(op, "update")
+	}
+}
+
+func TestWrap_Authorize(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	var allowed bool
+	ss := newTestSetter("foo")
+	ui := Wrap(NewUiText(ss), Authorize(func(rq *Request) bool { return allowed }))
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<template id="Jid.1"></template>`)
+
+	elem := rq.getElementByJid(1)
+	th.NoErr(rq.callElementHandlers(elem, what.Input, "bar"))
+	th.Equal(ss.Get(), "foo")
+	ui.JawsUpdate(elem)
+
+	allowed = true
+	th.Equal(rq.callElementHandlers(elem, what.Input, "bar"), ErrEventUnhandled)
+	th.Equal(ss.Get(), "bar")
+}
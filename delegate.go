@@ -0,0 +1,40 @@
+package jaws
+
+import "html/template"
+
+// Delegate is a parameter that makes the Element handle click events from
+// it's descendants before their own handlers do, outermost delegating
+// Element first. A delegating handler that returns ErrEventUnhandled lets
+// the event continue to the others.
+//
+// This allows a single handler to serve many HTML elements that need not
+// be Elements themselves, such as the rows of a large table, by rendering
+// them with the Item attribute.
+type Delegate struct{}
+
+// Item returns the "data-jaws-item" attribute with the given key. Clicks on,
+// or inside, a HTML element with it send the key as the click name, unless
+// the clicked HTML element has a name attribute.
+func Item(key string) template.HTML {
+	return Data("jaws-item", key)
+}
+
+// delegatesFirst reorders the Elements a click was sent to, innermost
+// first, so that the delegating ones come first, outermost first.
+func delegatesFirst(elems []*Element) []*Element {
+	var delegates []*Element
+	for i := len(elems) - 1; i >= 0; i-- {
+		if elems[i].delegate {
+			delegates = append(delegates, elems[i])
+		}
+	}
+	if len(delegates) == 0 {
+		return elems
+	}
+	for _, e := range elems {
+		if !e.delegate {
+			delegates = append(delegates, e)
+		}
+	}
+	return delegates
+}
@@ -0,0 +1,55 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestItem(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(Item(`row "1"`), template.HTML(`data-jaws-item="row &#34;1&#34;"`))
+}
+
+func TestRequest_Delegate(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	var calls []string
+	outer := func(e *Element, wht what.What, val string) error {
+		calls = append(calls, "outer:"+val)
+		if val == "pass" {
+			return ErrEventUnhandled
+		}
+		return nil
+	}
+	inner := func(e *Element, wht what.What, val string) error {
+		calls = append(calls, "inner:"+val)
+		return nil
+	}
+	th.NoErr(rq.Div("", EventFn(outer), Delegate{}))
+	th.NoErr(rq.Span("", EventFn(inner)))
+	th.True(rq.getElementByJid(1).delegate)
+
+	th.NoErr(rq.callAllEventHandlers(0, what.Click, "row-1\tJid.2\tJid.1"))
+	th.Equal(calls, []string{"outer:row-1"})
+
+	calls = nil
+	th.NoErr(rq.callAllEventHandlers(0, what.Click, "pass\tJid.2\tJid.1"))
+	th.Equal(calls, []string{"outer:pass", "inner:pass"})
+}
+
+func Test_delegatesFirst(t *testing.T) {
+	th := newTestHelper(t)
+	a := &Element{}
+	b := &Element{delegate: true}
+	c := &Element{}
+	d := &Element{delegate: true}
+	th.Equal(delegatesFirst([]*Element{a, c}), []*Element{a, c})
+	th.Equal(delegatesFirst([]*Element{a, b, c, d}), []*Element{d, b, a, c})
+}
@@ -0,0 +1,71 @@
+package jaws
+
+import (
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// Delta is a parameter that makes Element.SetInner send only the part of
+// the inner HTML that changed since the last time it was set, which greatly
+// reduces bandwidth for large content that changes slightly, such as logs.
+//
+// The browser keeps a copy of the inner HTML to apply the changes to,
+// so only use it where the savings are worth the memory.
+type Delta struct{}
+
+// utf16Len returns the number of UTF-16 code units needed to encode s,
+// since that's how Javascript indexes strings.
+func utf16Len(s string) (n int) {
+	for _, r := range s {
+		n++
+		if r >= 0x10000 {
+			n++
+		}
+	}
+	return
+}
+
+// deltaPatch returns the what.Patch data that changes a into b.
+//
+// The format is "start\tcount\ttext", meaning replace count UTF-16 code units
+// starting at start with text. A count of -1 replaces the entire inner HTML.
+func deltaPatch(a, b string) string {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for prefix > 0 && prefix < len(b) && !utf8.RuneStart(b[prefix]) {
+		prefix--
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !utf8.RuneStart(b[len(b)-suffix]) {
+		suffix--
+	}
+	var buf []byte
+	buf = strconv.AppendInt(buf, int64(utf16Len(a[:prefix])), 10)
+	buf = append(buf, '\t')
+	buf = strconv.AppendInt(buf, int64(utf16Len(a[prefix:len(a)-suffix])), 10)
+	buf = append(buf, '\t')
+	buf = append(buf, b[prefix:len(b)-suffix]...)
+	return string(buf)
+}
+
+// setInnerDelta queues a what.Patch message changing the inner HTML to
+// innerHtml, sending it in full if the browser's copy is unknown or
+// the patch wouldn't be smaller.
+func (e *Element) setInnerDelta(innerHtml string) {
+	data := "0\t-1\t" + innerHtml
+	if e.innerOk {
+		if patch := deltaPatch(e.inner, innerHtml); len(patch) < len(data) {
+			data = patch
+		}
+	}
+	e.inner = innerHtml
+	e.innerOk = true
+	e.queue(what.Patch, data)
+}
@@ -0,0 +1,79 @@
+package jaws
+
+import (
+	"html/template"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// applyPatch applies what.Patch data the same way jaws.js does.
+func applyPatch(t *testing.T, inner, data string) string {
+	t.Helper()
+	parts := strings.SplitN(data, "\t", 3)
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	count, err := strconv.Atoi(parts[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count < 0 {
+		return parts[2]
+	}
+	u := utf16.Encode([]rune(inner))
+	return string(utf16.Decode(u[:start])) + parts[2] + string(utf16.Decode(u[start+count:]))
+}
+
+func Test_deltaPatch(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(deltaPatch("", "abc"), "0\t0\tabc")
+	th.Equal(deltaPatch("abc", "abc"), "3\t0\t")
+	th.Equal(deltaPatch("abc", "abcdef"), "3\t0\tdef")
+	th.Equal(deltaPatch("abcdef", "abc"), "3\t3\t")
+	th.Equal(deltaPatch("abcdef", "abXYef"), "2\t2\tXY")
+	th.Equal(deltaPatch("aaa", "aaaa"), "3\t0\ta")
+	th.Equal(deltaPatch("aåb", "aäb"), "1\t1\tä")
+	th.Equal(deltaPatch("x\U0001F600y", "x\U0001F601y"), "1\t2\t\U0001F601")
+	th.Equal(deltaPatch("\U0001F600a", "\U0001F600b"), "2\t1\tb")
+
+	for _, tc := range [][2]string{
+		{"", "abc"},
+		{"line 1\nline 2\n", "line 1\nline 2\nline 3\n"},
+		{"aåb\U0001F600c", "aäb\U0001F601c\t"},
+		{"åå", "åäå"},
+	} {
+		th.Equal(applyPatch(t, tc[0], deltaPatch(tc[0], tc[1])), tc[1])
+	}
+}
+
+func TestElement_SetInnerDelta(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	e := rq.NewElement(NewUiSpan(makeHtmlGetter("x")))
+	th.Equal(parseParams(e, []interface{}{Delta{}}), []string(nil))
+	th.True(e.delta)
+
+	log := template.HTML("<p>first line</p><p>second line</p>")
+	e.SetInner(log)
+	e.SetInner(log + "<p>third line</p>")
+	e.Replace(template.HTML(`<div id="` + e.Jid().String() + `"></div>`))
+	e.SetInner(log)
+	th.Equal(e.wsQueue, []wsMsg{
+		{Jid: e.jid, What: what.Patch, Data: "0\t-1\t" + string(log)},
+		{Jid: e.jid, What: what.Patch, Data: "35\t0\t<p>third line</p>"},
+		{Jid: e.jid, What: what.Replace, Data: `<div id="` + e.Jid().String() + `"></div>`},
+		{Jid: e.jid, What: what.Patch, Data: "0\t-1\t" + string(log)},
+	})
+
+	e.wsQueue = nil
+	e.SetInner("x")
+	th.Equal(e.wsQueue, []wsMsg{{Jid: e.jid, What: what.Patch, Data: "0\t-1\tx"}})
+}
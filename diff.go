@@ -0,0 +1,215 @@
+package jaws
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// DiffKind describes how a DiffLine changed.
+type DiffKind byte
+
+const (
+	DiffEqual  DiffKind = ' ' // line is present in both
+	DiffDelete DiffKind = '-' // line is only present in the old text
+	DiffInsert DiffKind = '+' // line is only present in the new text
+)
+
+// DiffLine is a single line in a line-based diff.
+//
+// OldLine and NewLine are 1-based line numbers, or zero if the
+// line isn't present in the respective text.
+type DiffLine struct {
+	Kind    DiffKind
+	OldLine int
+	NewLine int
+	Text    string
+}
+
+// DiffHunk is a group of changed lines along with their surrounding context.
+type DiffHunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Lines    []DiffLine
+}
+
+// Header returns the unified diff header for the hunk, e.g. "@@ -1,3 +1,4 @@".
+func (h *DiffHunk) Header() string {
+	b := []byte("@@ -")
+	b = strconv.AppendInt(b, int64(h.OldStart), 10)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(h.OldLines), 10)
+	b = append(b, " +"...)
+	b = strconv.AppendInt(b, int64(h.NewStart), 10)
+	b = append(b, ',')
+	b = strconv.AppendInt(b, int64(h.NewLines), 10)
+	b = append(b, " @@"...)
+	return string(b)
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// DiffStrings returns the line-based diff between a and b.
+func DiffStrings(a, b string) []DiffLine {
+	return DiffLines(splitLines(a), splitLines(b))
+}
+
+// maxDiffEdits is the most inserted and deleted lines DiffLines looks for.
+// If more are needed, the differing lines are replaced as a whole. This
+// bounds the time to O((n+m)*maxDiffEdits) and the memory to O(maxDiffEdits²).
+const maxDiffEdits = 1000
+
+// DiffLines returns the diff between the line slices a and b, using
+// Myers' algorithm to find the fewest inserted and deleted lines.
+// If the texts differ too much for that to be cheap, all the lines
+// between the common prefix and suffix are deleted and inserted.
+func DiffLines(a, b []string) (lines []DiffLine) {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+	for i := 0; i < prefix; i++ {
+		lines = append(lines, DiffLine{Kind: DiffEqual, OldLine: i + 1, NewLine: i + 1, Text: a[i]})
+	}
+
+	ma, mb := a[prefix:len(a)-suffix], b[prefix:len(b)-suffix]
+	for _, l := range diffMyers(ma, mb) {
+		if l.OldLine > 0 {
+			l.OldLine += prefix
+		}
+		if l.NewLine > 0 {
+			l.NewLine += prefix
+		}
+		lines = append(lines, l)
+	}
+
+	for k := 0; k < suffix; k++ {
+		lines = append(lines, DiffLine{Kind: DiffEqual, OldLine: len(a) - suffix + k + 1, NewLine: len(b) - suffix + k + 1, Text: a[len(a)-suffix+k]})
+	}
+	return
+}
+
+// diffMyers returns the diff between a and b, or a replacing all of a
+// with all of b if that needs more than maxDiffEdits inserts and deletes.
+func diffMyers(a, b []string) (lines []DiffLine) {
+	n, m := len(a), len(b)
+	limit := min(n+m, maxDiffEdits)
+	off := limit + 1
+	v := make([]int32, 2*limit+3) // v[off+k] is the furthest x reached on diagonal k
+	var trace [][]int32           // trace[d] is v[off-d:off+d+1] before step d
+	found := false
+	for d := 0; d <= limit && !found; d++ {
+		trace = append(trace, append([]int32(nil), v[off-d:off+d+1]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[off+k-1] < v[off+k+1]) {
+				x = int(v[off+k+1])
+			} else {
+				x = int(v[off+k-1]) + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = int32(x)
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		for i := range a {
+			lines = append(lines, DiffLine{Kind: DiffDelete, OldLine: i + 1, Text: a[i]})
+		}
+		for j := range b {
+			lines = append(lines, DiffLine{Kind: DiffInsert, NewLine: j + 1, Text: b[j]})
+		}
+		return
+	}
+
+	// backtrack from the end, collecting the lines in reverse
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		tv := trace[d]
+		at := func(k int) int { return int(tv[k+d]) }
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && at(k-1) < at(k+1)) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX, prevY := 0, 0
+		if d > 0 {
+			prevX = at(prevK)
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x--
+			y--
+			lines = append(lines, DiffLine{Kind: DiffEqual, OldLine: x + 1, NewLine: y + 1, Text: a[x]})
+		}
+		if d > 0 {
+			if x == prevX {
+				y--
+				lines = append(lines, DiffLine{Kind: DiffInsert, NewLine: y + 1, Text: b[y]})
+			} else {
+				x--
+				lines = append(lines, DiffLine{Kind: DiffDelete, OldLine: x + 1, Text: a[x]})
+			}
+		}
+	}
+	slices.Reverse(lines)
+	return
+}
+
+// DiffHunks groups the changed lines into hunks with up to context unchanged lines around them.
+func DiffHunks(lines []DiffLine, context int) (hunks []DiffHunk) {
+	start, end := -1, -1
+	flush := func() {
+		if start >= 0 {
+			h := DiffHunk{Lines: lines[start:end]}
+			for _, l := range h.Lines {
+				if l.Kind != DiffInsert {
+					if h.OldStart == 0 {
+						h.OldStart = l.OldLine
+					}
+					h.OldLines++
+				}
+				if l.Kind != DiffDelete {
+					if h.NewStart == 0 {
+						h.NewStart = l.NewLine
+					}
+					h.NewLines++
+				}
+			}
+			hunks = append(hunks, h)
+		}
+	}
+	for i := range lines {
+		if lines[i].Kind != DiffEqual {
+			from, to := max(i-context, 0), min(i+context+1, len(lines))
+			if start >= 0 && from <= end {
+				end = to
+			} else {
+				flush()
+				start, end = from, to
+			}
+		}
+	}
+	flush()
+	return
+}
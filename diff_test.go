@@ -0,0 +1,129 @@
+package jaws
+
+import (
+	"math/rand"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestDiffStrings(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(DiffStrings("", ""), nil)
+	th.Equal(DiffStrings("a\nb\nc\n", "a\nx\nc\n"), []DiffLine{
+		{Kind: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+		{Kind: DiffDelete, OldLine: 2, Text: "b"},
+		{Kind: DiffInsert, NewLine: 2, Text: "x"},
+		{Kind: DiffEqual, OldLine: 3, NewLine: 3, Text: "c"},
+	})
+	th.Equal(DiffStrings("a", "a\nb"), []DiffLine{
+		{Kind: DiffEqual, OldLine: 1, NewLine: 1, Text: "a"},
+		{Kind: DiffInsert, NewLine: 2, Text: "b"},
+	})
+	th.Equal(DiffStrings("x\na\ny", "a"), []DiffLine{
+		{Kind: DiffDelete, OldLine: 1, Text: "x"},
+		{Kind: DiffEqual, OldLine: 2, NewLine: 1, Text: "a"},
+		{Kind: DiffDelete, OldLine: 3, Text: "y"},
+	})
+}
+
+func TestDiffHunks(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(DiffHunks(DiffStrings("a\nb", "a\nb"), 3), nil)
+
+	lines := DiffStrings("1\n2\n3\n4\n5\n6\n7\n8\n9", "1\nX\n3\n4\n5\n6\n7\nY\n9")
+	hunks := DiffHunks(lines, 1)
+	th.Equal(len(hunks), 2)
+	th.Equal(hunks[0].Header(), "@@ -1,3 +1,3 @@")
+	th.Equal(hunks[1].Header(), "@@ -7,3 +7,3 @@")
+
+	hunks = DiffHunks(lines, 3)
+	th.Equal(len(hunks), 1)
+	th.Equal(hunks[0].Header(), "@@ -1,9 +1,9 @@")
+}
+
+// checkDiff verifies that lines turn a into b and returns the number of changed lines.
+func checkDiff(t *testing.T, a, b []string, lines []DiffLine) (changes int) {
+	t.Helper()
+	var gotA, gotB []string
+	for _, l := range lines {
+		if l.Kind != DiffInsert {
+			gotA = append(gotA, l.Text)
+			if l.OldLine != len(gotA) {
+				t.Fatalf("old line %d numbered %d", len(gotA), l.OldLine)
+			}
+		}
+		if l.Kind != DiffDelete {
+			gotB = append(gotB, l.Text)
+			if l.NewLine != len(gotB) {
+				t.Fatalf("new line %d numbered %d", len(gotB), l.NewLine)
+			}
+		}
+		if l.Kind != DiffEqual {
+			changes++
+		}
+	}
+	if !slices.Equal(gotA, a) || !slices.Equal(gotB, b) {
+		t.Fatalf("diff doesn't reproduce the texts")
+	}
+	return
+}
+
+func TestDiffLines_Minimal(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	randLines := func() (l []string) {
+		for i := rnd.Intn(12); i > 0; i-- {
+			l = append(l, string(rune('a'+rnd.Intn(4))))
+		}
+		return
+	}
+	for i := 0; i < 500; i++ {
+		a, b := randLines(), randLines()
+		changes := checkDiff(t, a, b, DiffLines(a, b))
+		// the fewest changes are the lines not in the longest common subsequence
+		lcs := make([][]int, len(a)+1)
+		for i := range lcs {
+			lcs[i] = make([]int, len(b)+1)
+		}
+		for i := len(a) - 1; i >= 0; i-- {
+			for j := len(b) - 1; j >= 0; j-- {
+				if a[i] == b[j] {
+					lcs[i][j] = lcs[i+1][j+1] + 1
+				} else {
+					lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+				}
+			}
+		}
+		if want := len(a) + len(b) - 2*lcs[0][0]; changes != want {
+			t.Fatalf("%q -> %q: %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+func TestDiffLines_Large(t *testing.T) {
+	const n = 100000
+	a := make([]string, n)
+	b := make([]string, n)
+	for i := range a {
+		a[i] = "a" + strconv.Itoa(i)
+		b[i] = a[i]
+	}
+	for i := 0; i < n; i += n / 10 {
+		b[i] = "changed"
+	}
+	start := time.Now()
+	if changes := checkDiff(t, a, b, DiffLines(a, b)); changes != 20 {
+		t.Errorf("few changes: got %d, want 20", changes)
+	}
+
+	for i := range b {
+		b[i] = "b" + strconv.Itoa(i)
+	}
+	if changes := checkDiff(t, a, b, DiffLines(a, b)); changes != 2*n {
+		t.Errorf("all changed: got %d, want %d", changes, 2*n)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second*10 {
+		t.Errorf("took %v", elapsed)
+	}
+}
@@ -0,0 +1,34 @@
+package jaws
+
+import (
+	"net/http"
+)
+
+// preloadLinks returns Link header values preloading the scripts and
+// stylesheets, in the same order as HeadHTML preloads them.
+func preloadLinks(js, css []string) (links []string) {
+	for _, u := range css {
+		links = append(links, "<"+u+">; rel=preload; as=style")
+	}
+	for _, u := range js {
+		links = append(links, "<"+u+">; rel=preload; as=script")
+	}
+	return
+}
+
+// WriteEarlyHints adds Link headers to w preloading the JaWS Javascript
+// and the scripts and stylesheets given to GenerateHeadHTML or UseAssets,
+// and sends them in a 103 Early Hints response.
+//
+// Call it first thing in a page handler, before doing any slow work, so
+// the browser can fetch the assets meanwhile. The Link headers remain
+// set for the final response, which helps browsers and proxies that
+// ignore Early Hints. The HEAD section written by HeadHTML preloads the
+// same assets for browsers that see neither.
+func (jw *Jaws) WriteEarlyHints(w http.ResponseWriter) {
+	hdr := w.Header()
+	for _, link := range jw.headLinks {
+		hdr.Add("Link", link)
+	}
+	w.WriteHeader(http.StatusEarlyHints)
+}
@@ -0,0 +1,55 @@
+package jaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+)
+
+func TestJaws_WriteEarlyHints(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	th.NoErr(jw.GenerateHeadHTML("/app.css", "/app.js"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jw.WriteEarlyHints(w)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var hints []string
+	ctx := httptrace.WithClientTrace(context.Background(), &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			if code == http.StatusEarlyHints {
+				hints = header.Values("Link")
+			}
+			return nil
+		},
+	})
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	th.NoErr(err)
+	resp, err := http.DefaultClient.Do(req)
+	th.NoErr(err)
+	defer resp.Body.Close()
+
+	want := []string{
+		"</app.css>; rel=preload; as=style",
+		"</app.js>; rel=preload; as=script",
+		"<" + JavascriptPath + ">; rel=preload; as=script",
+	}
+	th.Equal(resp.StatusCode, http.StatusOK)
+	th.Equal(hints, want)
+	th.Equal(resp.Header.Values("Link"), want)
+}
+
+func Test_preloadLinks(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(preloadLinks(nil, nil), []string(nil))
+	jw := New()
+	defer jw.Close()
+	th.Equal(jw.headLinks, []string{"<" + JavascriptPath + ">; rel=preload; as=script"})
+}
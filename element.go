@@ -2,6 +2,7 @@ package jaws
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"html/template"
 	"io"
@@ -16,9 +17,21 @@ type Element struct {
 	ui       UI      // (read-only) the UI object
 	jid      jid.Jid // (read-only) JaWS ID, unique to this Element within it's Request
 	// internals
-	updating bool           // about to have Update() called
-	wsQueue  []wsMsg        // changes queued
-	handlers []EventHandler // custom event handlers registered, if any
+	updating bool            // about to have Update() called
+	wsQueue  []wsMsg         // changes queued
+	delta    bool            // SetInner sends patches, see Delta
+	inner    string          // inner HTML last sent, if delta is set
+	innerOk  bool            // browser has inner, if delta is set
+	redact   bool            // values are sensitive, see Redact
+	delegate bool            // handles descendants clicks first, see Delegate
+	handlers []EventHandler  // custom event handlers registered, if any
+	handled  bool            // current event was marked as handled, only used by the event caller
+	ctx      context.Context // context while handling an event, protected by Request.mu
+	pending  bool            // a setter is Pending, protected by Request.mu
+	confirm  string          // current Confirm token, protected by Request.mu
+	once     string          // Idempotent action, protected by Request.mu
+	priority bool            // has a high priority tag, protected by Request.mu
+	detached bool            // JawsRender still running after a render timeout, protected by Request.mu
 }
 
 func (e *Element) String() string {
@@ -45,6 +58,58 @@ func (e *Element) Ui() UI {
 	return e.ui
 }
 
+// Context returns the Context to use when handling an event for the Element,
+// such as in a setter. It is derived from the Request's Context, and has a
+// deadline if Jaws.EventTimeout or RequestOptions.EventTimeout is set.
+//
+// While the Element is being rendered, it is derived from the initial HTTP
+// request's Context until the WebSocket connects, and has a deadline if
+// Jaws.RenderTimeout or RequestOptions.RenderTimeout is set.
+// Otherwise it returns the Request's Context.
+//
+// If the Element has been deleted, the returned Context is already cancelled.
+func (e *Element) Context() (ctx context.Context) {
+	rq := e.Request
+	if rq == nil {
+		return deletedElementCtx
+	}
+	rq.mu.RLock()
+	ctx = e.ctx
+	rq.mu.RUnlock()
+	if ctx == nil {
+		ctx = rq.Context()
+	}
+	return
+}
+
+// deletedElementCtx is the cancelled Context returned for deleted Elements.
+var deletedElementCtx = func() context.Context {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	return ctx
+}()
+
+// beginEvent sets up the Context for handling an event for the Element.
+// The returned function must be called when the event has been handled.
+func (e *Element) beginEvent() (done func()) {
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if d := e.Request.eventTimeout(); d > 0 {
+		ctx, cancel = context.WithTimeout(e.Request.Context(), d)
+	} else {
+		ctx, cancel = context.WithCancel(e.Request.Context())
+	}
+	e.Request.mu.Lock()
+	e.ctx = ctx
+	e.Request.mu.Unlock()
+	return func() {
+		e.Request.mu.Lock()
+		e.ctx = nil
+		e.Request.mu.Unlock()
+		cancel()
+	}
+}
+
 // Render calls Request.JawsRender() for this Element.
 func (e *Element) Render(w io.Writer, params []interface{}) error {
 	return e.Request.JawsRender(e, w, params)
@@ -97,8 +162,15 @@ func (e *Element) RemoveClass(cls string) {
 // SetInner queues sending a new inner HTML content
 // to the browser for the Element.
 //
+// If the Element was rendered with the Delta parameter, only the changes
+// since the last call are sent.
+//
 // Call this only during JawsRender() or JawsUpdate() processing.
 func (e *Element) SetInner(innerHtml template.HTML) {
+	if e.delta {
+		e.setInnerDelta(string(innerHtml))
+		return
+	}
 	e.queue(what.Inner, string(innerHtml))
 }
 
@@ -110,6 +182,21 @@ func (e *Element) SetValue(val string) {
 	e.queue(what.Value, val)
 }
 
+// RequestFullscreen queues a request to show the Element in fullscreen mode.
+//
+// The result is sent back as a what.Fullscreen event to the Element with
+// "true" if it entered fullscreen, or an error message if it failed. Browsers
+// usually only allow this shortly after user interaction, such as a click.
+// When the Element leaves fullscreen, an event with "false" is sent.
+func (e *Element) RequestFullscreen() {
+	e.queue(what.Fullscreen, "true")
+}
+
+// ExitFullscreen queues a request to leave fullscreen mode.
+func (e *Element) ExitFullscreen() {
+	e.queue(what.Fullscreen, "false")
+}
+
 // Replace replaces the elements entire HTML DOM node with new HTML code.
 // If the HTML code doesn't seem to contain correct HTML ID, it panics.
 //
@@ -121,6 +208,7 @@ func (e *Element) Replace(htmlCode template.HTML) {
 	if !bytes.Contains([]byte(htmlCode), b) {
 		panic(fmt.Errorf("jaws: Element.Replace(): expected HTML " + string(b)))
 	}
+	e.innerOk = false
 	e.queue(what.Replace, string(htmlCode))
 }
 
@@ -1,6 +1,7 @@
 package jaws
 
 import (
+	"context"
 	"fmt"
 	"html/template"
 	"io"
@@ -100,6 +101,8 @@ func TestElement_Queued(t *testing.T) {
 			e.Order([]jid.Jid{1, 2})
 			replaceHtml := template.HTML(fmt.Sprintf("<div id=\"%s\"></div>", e.Jid().String()))
 			e.Replace(replaceHtml)
+			e.RequestFullscreen()
+			e.ExitFullscreen()
 			th.Equal(e.wsQueue, []wsMsg{
 				{
 					Data: "hidden\n",
@@ -151,6 +154,16 @@ func TestElement_Queued(t *testing.T) {
 					Jid:  e.jid,
 					What: what.Replace,
 				},
+				{
+					Data: "true",
+					Jid:  e.jid,
+					What: what.Fullscreen,
+				},
+				{
+					Data: "false",
+					Jid:  e.jid,
+					What: what.Fullscreen,
+				},
 			})
 		},
 	}
@@ -187,3 +200,52 @@ func TestElement_ReplacePanicsOnMissingId(t *testing.T) {
 	e.Replace(template.HTML("<div id=\"wrong\"></div>"))
 	is.Fail()
 }
+
+type testContextSetter struct {
+	ctxCh chan context.Context
+}
+
+func (tcs *testContextSetter) JawsGetString(e *Element) string {
+	return ""
+}
+
+func (tcs *testContextSetter) JawsSetString(e *Element, val string) error {
+	tcs.ctxCh <- e.Context()
+	return nil
+}
+
+func TestElement_Context(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	rq.Jaws.EventTimeout = time.Minute
+	tcs := &testContextSetter{ctxCh: make(chan context.Context, 1)}
+	th.NoErr(rq.Text(tcs))
+	elem := rq.getElementByJid(1)
+	th.Equal(elem.Context(), rq.Request.Context())
+
+	rq.inCh <- wsMsg{Data: "foo", Jid: 1, What: what.Input}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case ctx := <-tcs.ctxCh:
+		deadline, ok := ctx.Deadline()
+		th.True(ok)
+		th.True(time.Until(deadline) <= time.Minute)
+		for ctx.Err() == nil {
+			select {
+			case <-th.C:
+				th.Timeout()
+			default:
+				time.Sleep(time.Millisecond)
+			}
+		}
+		th.Equal(ctx.Err(), context.Canceled)
+	}
+	th.Equal(elem.Context(), rq.Request.Context())
+
+	rq.deleteElement(elem)
+	th.Equal(elem.Context().Err(), context.Canceled)
+}
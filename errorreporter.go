@@ -0,0 +1,113 @@
+package jaws
+
+import (
+	"fmt"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// ErrorReport describes an error returned by, or a panic in, an event handler.
+type ErrorReport struct {
+	Request *Request      // the Request the event was for
+	Element *Element      // the Element the event was for
+	Tags    []interface{} // the tags of the Element
+	What    what.What     // the event type, or what.Update if the error occurred while rendering
+	Value   string        // the event value, or RedactedText if the Element's values are sensitive
+	Err     error         // the error returned, or created from the panic value
+	Panic   interface{}   // if not nil, the value the handler panicked with
+	Stack   []byte        // if the handler panicked, the stack trace
+}
+
+func (r *ErrorReport) Error() string {
+	return r.Err.Error()
+}
+
+func (r *ErrorReport) Unwrap() error {
+	return r.Err
+}
+
+// Context returns the report details as a map of strings, suitable
+// for use as tags or extra data in error tracking services.
+func (r *ErrorReport) Context() (m map[string]string) {
+	m = map[string]string{
+		"jaws.request": r.Request.String(),
+		"jaws.what":    r.What.String(),
+		"jaws.value":   r.Value,
+		"jaws.panic":   strconv.FormatBool(r.Panic != nil),
+	}
+	if r.Element != nil {
+		m["jaws.element"] = r.Element.Jid().String()
+		m["jaws.ui"] = fmt.Sprintf("%T", r.Element.Ui())
+	}
+	if len(r.Tags) > 0 {
+		tags := make([]string, len(r.Tags))
+		for i, tag := range r.Tags {
+			tags[i] = TagString(tag)
+		}
+		m["jaws.tags"] = strings.Join(tags, ", ")
+	}
+	return
+}
+
+// ErrorReporter is called whenever an event handler returns an error or panics,
+// and when rendering an Element exceeds Jaws.RenderTimeout.
+// Set it in Jaws.ErrorReporter to integrate with error tracking services.
+type ErrorReporter interface {
+	JawsReportError(report *ErrorReport)
+}
+
+// ErrorReporterFunc adapts an ordinary function to the ErrorReporter interface.
+type ErrorReporterFunc func(report *ErrorReport)
+
+func (fn ErrorReporterFunc) JawsReportError(report *ErrorReport) {
+	fn(report)
+}
+
+func (rq *Request) reportError(e *Element, wht what.What, val string, err error, x interface{}, stack []byte) {
+	if er := rq.Jaws.ErrorReporter; er != nil {
+		er.JawsReportError(&ErrorReport{
+			Request: rq,
+			Element: e,
+			Tags:    rq.TagsOf(e),
+			What:    wht,
+			Value:   redactValue(val, e),
+			Err:     err,
+			Panic:   x,
+			Stack:   stack,
+		})
+	}
+}
+
+// callElementHandlers calls the event handlers for the Element until one of them
+// doesn't return ErrEventUnhandled. Panics are recovered and returned as errors.
+// Errors and panics are sent to the Jaws ErrorReporter.
+func (rq *Request) callElementHandlers(e *Element, wht what.What, val string) (err error) {
+	defer e.beginEvent()()
+	defer func() {
+		if x := recover(); x != nil {
+			var ok bool
+			if err, ok = x.(error); !ok {
+				err = fmt.Errorf("jaws: %v panic: %v", e, x)
+			}
+			rq.reportError(e, wht, val, err, x, debug.Stack())
+		}
+	}()
+	if err = callEventHandler(e.ui, e, wht, val); err == ErrEventUnhandled {
+		for _, h := range e.handlers {
+			if err = h.JawsEvent(e, wht, val); err != ErrEventUnhandled {
+				break
+			}
+		}
+	}
+	if p, ok := err.(*Pending); ok {
+		rq.handlePending(e, wht, val, p)
+		err = nil
+	}
+	if err != nil && err != ErrEventUnhandled {
+		rq.reportError(e, wht, val, err, nil, nil)
+	}
+	return
+}
@@ -0,0 +1,100 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testPanicker struct {
+	x interface{}
+}
+
+func (tp testPanicker) JawsClick(e *Element, name string) error {
+	panic(tp.x)
+}
+
+func TestErrorReporter_Error(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	reportCh := make(chan *ErrorReport, 1)
+	rq.Jaws.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reportCh <- report })
+
+	errBoom := errors.New("boom")
+	tss := newTestSetter("")
+	tss.err = errBoom
+	th.NoErr(rq.Text(tss, Tag("mytag")))
+	rq.inCh <- wsMsg{Data: "foo", Jid: 1, What: what.Input}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case report := <-reportCh:
+		th.Equal(report.Request, rq.Request)
+		th.Equal(report.Element, rq.getElementByJid(1))
+		th.Equal(report.What, what.Input)
+		th.Equal(report.Value, "foo")
+		th.True(errors.Is(report, errBoom))
+		th.Equal(report.Error(), "boom")
+		th.Equal(report.Panic, nil)
+		th.Equal(report.Stack, []byte(nil))
+		th.True(len(report.Tags) > 0)
+		ctx := report.Context()
+		th.Equal(ctx["jaws.element"], "Jid.1")
+		th.Equal(ctx["jaws.what"], "Input")
+		th.Equal(ctx["jaws.panic"], "false")
+		th.True(strings.Contains(ctx["jaws.tags"], "mytag"))
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.True(strings.HasPrefix(s, "Alert\t\t"))
+	}
+}
+
+func TestErrorReporter_Panic(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	reportCh := make(chan *ErrorReport, 1)
+	rq.Jaws.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reportCh <- report })
+
+	th.NoErr(rq.Span("inner", testPanicker{x: "oops"}))
+	rq.inCh <- wsMsg{Data: "name", Jid: 1, What: what.Click}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case report := <-reportCh:
+		th.Equal(report.Panic, "oops")
+		th.True(strings.Contains(report.Error(), "oops"))
+		th.True(len(report.Stack) > 0)
+		th.Equal(report.Context()["jaws.panic"], "true")
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.True(strings.HasPrefix(s, "Alert\t\t"))
+	}
+}
+
+func TestErrorReporter_PanicError(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	errBoom := errors.New("boom")
+	elem := rq.NewElement(NewUiSpan(makeHtmlGetter("inner")))
+	elem.handlers = append(elem.handlers, clickHandlerWapper{testPanicker{x: errBoom}})
+	th.Equal(rq.callElementHandlers(elem, what.Click, "name"), errBoom)
+}
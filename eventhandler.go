@@ -30,13 +30,43 @@ func (ehf eventFnWrapper) JawsEvent(e *Element, w what.What, v string) (err erro
 
 var _ EventFn = eventFnWrapper{}.JawsEvent // statically ensure JawsEvent and EventFn are compatible
 
+// callEventHandler calls the handler interface obj implements for the
+// event type, such as ClickHandler or InputHandler, and then it's
+// EventHandler if that returned ErrEventUnhandled.
 func callEventHandler(obj any, e *Element, wht what.What, val string) (err error) {
-	if wht == what.Click {
+	err = ErrEventUnhandled
+	switch wht {
+	case what.Click:
 		if h, ok := obj.(ClickHandler); ok {
-			if err = h.JawsClick(e, val); err != ErrEventUnhandled {
-				return
-			}
+			err = h.JawsClick(e, val)
 		}
+	case what.Input:
+		if h, ok := obj.(InputHandler); ok {
+			err = h.JawsInput(e, val)
+		}
+	case what.Change:
+		if h, ok := obj.(ChangeHandler); ok {
+			err = h.JawsChange(e, val)
+		}
+	case what.Key:
+		if h, ok := obj.(KeyHandler); ok {
+			err = h.JawsKey(e, val)
+		}
+	case what.Paste:
+		if h, ok := obj.(PasteHandler); ok {
+			err = callPasteHandler(h, e, val)
+		}
+	case what.Fullscreen:
+		if h, ok := obj.(FullscreenHandler); ok {
+			err = callFullscreenHandler(h, e, val)
+		}
+	case what.Custom:
+		if h, ok := obj.(CustomHandler); ok {
+			err = callCustomHandler(h, e, val)
+		}
+	}
+	if err != ErrEventUnhandled {
+		return
 	}
 	if h, ok := obj.(EventHandler); ok {
 		return h.JawsEvent(e, wht, val)
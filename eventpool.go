@@ -0,0 +1,159 @@
+package jaws
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/linkdata/deadlock"
+)
+
+// eventPool is the shared event worker pool, see Jaws.EventWorkers.
+//
+// Jobs are queued without limit, so they can be submitted from the
+// workers themselves without risking a deadlock. There is at most one
+// job per Request, so the queue is bounded by the number of Requests.
+type eventPool struct {
+	mu     deadlock.Mutex // protects following
+	jobs   []func()
+	queued []time.Time // when each job was queued
+	wake   chan struct{}
+}
+
+// eventJobs returns the shared event worker pool, starting it if needed.
+// Returns nil if Jaws.EventWorkers is not positive.
+func (jw *Jaws) eventJobs() (pool *eventPool) {
+	jw.mu.RLock()
+	pool = jw.eventPool
+	jw.mu.RUnlock()
+	if pool == nil && jw.EventWorkers > 0 {
+		jw.mu.Lock()
+		if jw.eventPool == nil {
+			jw.eventPool = &eventPool{wake: make(chan struct{}, jw.EventWorkers)}
+			for i := 0; i < jw.EventWorkers; i++ {
+				go jw.eventWorker(jw.eventPool)
+			}
+		}
+		pool = jw.eventPool
+		jw.mu.Unlock()
+	}
+	return
+}
+
+func (jw *Jaws) eventWorker(pool *eventPool) {
+	for {
+		if fn := pool.next(); fn != nil {
+			fn()
+			continue
+		}
+		select {
+		case <-jw.Done():
+			pool.runAll()
+			return
+		case <-pool.wake:
+		}
+	}
+}
+
+// submitEvent queues fn to be run by a worker. If the Jaws is closed and the
+// workers have stopped, the queued jobs are run by the caller.
+func (jw *Jaws) submitEvent(pool *eventPool, fn func()) {
+	pool.mu.Lock()
+	pool.jobs = append(pool.jobs, fn)
+	pool.queued = append(pool.queued, time.Now())
+	pool.mu.Unlock()
+	select {
+	case <-jw.Done():
+		pool.runAll()
+	default:
+		select {
+		case pool.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// next removes and returns the oldest queued job, or nil if there are none.
+func (pool *eventPool) next() (fn func()) {
+	pool.mu.Lock()
+	if len(pool.jobs) > 0 {
+		fn = pool.jobs[0]
+		pool.jobs[0] = nil
+		pool.jobs = pool.jobs[1:]
+		pool.queued = pool.queued[1:]
+	}
+	pool.mu.Unlock()
+	return
+}
+
+// runAll runs the queued jobs until there are none left.
+func (pool *eventPool) runAll() {
+	for fn := pool.next(); fn != nil; fn = pool.next() {
+		fn()
+	}
+}
+
+// waiting returns the number of queued jobs and how long the oldest has waited.
+func (pool *eventPool) waiting() (n int, wait time.Duration) {
+	pool.mu.Lock()
+	if n = len(pool.jobs); n > 0 {
+		wait = time.Since(pool.queued[0])
+	}
+	pool.mu.Unlock()
+	return
+}
+
+// eventRunner calls a Request's event functions using the shared event
+// worker pool, instead of a goroutine of it's own. Each job handles a
+// single event and then queues the next one behind those of other
+// Requests, so Requests are served fairly.
+type eventRunner struct {
+	rq         *Request
+	pool       *eventPool
+	callCh     <-chan eventFnCall
+	outboundCh chan<- string
+	doneCh     chan<- struct{} // closed once callCh is closed and drained
+	scheduled  atomic.Bool     // set while a job is queued or running
+	closed     atomic.Bool     // set once callCh is closed
+}
+
+// newEventRunner returns an eventRunner for the Request if Jaws.EventWorkers
+// is set, or nil if event functions should be called by a goroutine of it's own.
+func (rq *Request) newEventRunner(callCh <-chan eventFnCall, outboundCh chan<- string, doneCh chan<- struct{}) (er *eventRunner) {
+	if pool := rq.Jaws.eventJobs(); pool != nil {
+		er = &eventRunner{rq: rq, pool: pool, callCh: callCh, outboundCh: outboundCh, doneCh: doneCh}
+	}
+	return
+}
+
+// schedule queues a job to handle the next event, unless one already is.
+// It is safe to call on a nil eventRunner.
+func (er *eventRunner) schedule() {
+	if er != nil && er.scheduled.CompareAndSwap(false, true) {
+		er.rq.Jaws.submitEvent(er.pool, er.run)
+	}
+}
+
+// close is called after closing callCh, and makes sure doneCh gets closed.
+func (er *eventRunner) close() {
+	if er != nil {
+		er.closed.Store(true)
+		er.schedule()
+	}
+}
+
+func (er *eventRunner) run() {
+	select {
+	case call, ok := <-er.callCh:
+		if !ok {
+			close(er.doneCh)
+			return
+		}
+		er.rq.callEvent(call, er.outboundCh)
+		er.rq.Jaws.submitEvent(er.pool, er.run)
+	default:
+		er.scheduled.Store(false)
+		if len(er.callCh) > 0 || er.closed.Load() {
+			er.schedule()
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package jaws
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testSlowClick struct {
+	running *int32
+	maxSeen *int32
+	doneCh  chan string
+}
+
+func (tsc testSlowClick) JawsClick(e *Element, name string) error {
+	n := atomic.AddInt32(tsc.running, 1)
+	for {
+		m := atomic.LoadInt32(tsc.maxSeen)
+		if n <= m || atomic.CompareAndSwapInt32(tsc.maxSeen, m, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond * 5)
+	atomic.AddInt32(tsc.running, -1)
+	tsc.doneCh <- name
+	return nil
+}
+
+func TestJaws_EventWorkers(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.EventWorkers = 1
+
+	var running, maxSeen int32
+	tsc := testSlowClick{running: &running, maxSeen: &maxSeen, doneCh: make(chan string, 4)}
+
+	var rqs []*testRequest
+	for i := 0; i < 2; i++ {
+		rq := tj.newRequest(nil)
+		defer rq.Close()
+		th.NoErr(rq.Span("x", tsc))
+		rqs = append(rqs, rq)
+	}
+	for _, rq := range rqs {
+		jid := rq.Request.elems[0].jid
+		rq.inCh <- wsMsg{Data: "a", Jid: jid, What: what.Click}
+		rq.inCh <- wsMsg{Data: "b", Jid: jid, What: what.Click}
+	}
+	for i := 0; i < 4; i++ {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-tsc.doneCh:
+		}
+	}
+	th.Equal(atomic.LoadInt32(&maxSeen), int32(1))
+	th.True(tj.eventJobs() != nil)
+}
+
+type testReentrantClick struct {
+	other  *Element
+	count  *int32
+	doneCh chan struct{}
+}
+
+func (trc testReentrantClick) JawsClick(e *Element, name string) error {
+	if n := atomic.AddInt32(trc.count, 1); n < 20 {
+		// trigger the next event in the other Request from within this one
+		e.Jaws.Broadcast(Message{Dest: trc.other, What: what.Click, Data: name})
+	} else if n == 20 {
+		close(trc.doneCh)
+	}
+	return nil
+}
+
+func TestJaws_EventWorkersReentrant(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.EventWorkers = 1
+
+	rq1 := tj.newRequest(nil)
+	defer rq1.Close()
+	rq2 := tj.newRequest(nil)
+	defer rq2.Close()
+	var count int32
+	doneCh := make(chan struct{})
+	trc1 := &testReentrantClick{count: &count, doneCh: doneCh}
+	trc2 := &testReentrantClick{count: &count, doneCh: doneCh}
+	th.NoErr(rq1.Span("1", trc1))
+	th.NoErr(rq2.Span("2", trc2))
+	e1, e2 := rq1.Request.elems[0], rq2.Request.elems[0]
+	trc1.other, trc2.other = e2, e1
+
+	rq1.inCh <- wsMsg{Data: "x", Jid: e1.jid, What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+	th.Equal(atomic.LoadInt32(&count) >= 20, true)
+}
+
+func TestJaws_eventPool(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	th.Equal(jw.eventJobs(), (*eventPool)(nil))
+	jw.EventWorkers = 1
+	pool := jw.eventJobs()
+	th.True(pool != nil)
+	th.Equal(jw.eventJobs(), pool)
+
+	// jobs may queue more jobs even when all workers are busy
+	doneCh := make(chan struct{})
+	jw.submitEvent(pool, func() {
+		jw.submitEvent(pool, func() { close(doneCh) })
+	})
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+	n, wait := pool.waiting()
+	th.Equal(n, 0)
+	th.Equal(wait, time.Duration(0))
+
+	// once closed, jobs are run by the caller
+	jw.Close()
+	ran := false
+	jw.submitEvent(pool, func() { ran = true })
+	th.True(ran)
+}
@@ -0,0 +1,112 @@
+package jaws
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// ExposureFn is called the first time a Session is exposed to an Experiment variant.
+type ExposureFn = func(rq *Request, experiment, variant string)
+
+// Experiment deterministically assigns Sessions to one of a set of variants,
+// for example to run A/B tests of UI changes.
+//
+// Requests without a Session are assigned using their JawsKey, so their
+// variant is only stable for the lifetime of the Request, and each use
+// is reported as an exposure.
+type Experiment struct {
+	Name       string     // (read-only) experiment name, also used to seed the assignment
+	Variants   []string   // (read-only) variant names
+	Weights    []int      // (read-only) relative weight of each variant, or nil for equal weights
+	OnExposure ExposureFn // (read-only) if not nil, called when a Session is first exposed to the experiment
+}
+
+// NewExperiment returns an Experiment with the given variants having equal weights.
+func NewExperiment(name string, variants ...string) *Experiment {
+	return &Experiment{Name: name, Variants: variants}
+}
+
+func (x *Experiment) weight(i int) int {
+	if i < len(x.Weights) {
+		return max(0, x.Weights[i])
+	}
+	if x.Weights == nil {
+		return 1
+	}
+	return 0
+}
+
+// Assign returns the variant for the given id. The result depends only on
+// the Experiment name, variants, weights and the id.
+func (x *Experiment) Assign(id uint64) (variant string) {
+	total := 0
+	for i := range x.Variants {
+		total += x.weight(i)
+	}
+	if total > 0 {
+		h := fnv.New64a()
+		_, _ = h.Write([]byte(x.Name))
+		_, _ = h.Write(binary.LittleEndian.AppendUint64(nil, id))
+		n := int(h.Sum64() % uint64(total))
+		for i, v := range x.Variants {
+			if n -= x.weight(i); n < 0 {
+				variant = v
+				break
+			}
+		}
+	}
+	return
+}
+
+// Variant returns the variant for the Request, calling OnExposure
+// if it is the first time the Session sees the experiment.
+func (x *Experiment) Variant(rq *Request) (variant string) {
+	id := rq.Session().ID()
+	if id == 0 {
+		id = rq.JawsKey
+	}
+	variant = x.Assign(id)
+	key := "jaws.experiment:" + x.Name
+	if rq.Get(key) != variant {
+		rq.Set(key, variant)
+		if x.OnExposure != nil {
+			x.OnExposure(rq, x.Name, variant)
+		}
+	}
+	return
+}
+
+// AddExperiment registers the Experiment so it can be used with Request.Variant().
+// Replaces any previously registered Experiment with the same name.
+func (jw *Jaws) AddExperiment(x *Experiment) {
+	jw.mu.Lock()
+	if jw.experiments == nil {
+		jw.experiments = make(map[string]*Experiment)
+	}
+	jw.experiments[x.Name] = x
+	jw.mu.Unlock()
+}
+
+// Experiment returns the registered Experiment with the given name, or nil.
+func (jw *Jaws) Experiment(name string) (x *Experiment) {
+	jw.mu.RLock()
+	x = jw.experiments[name]
+	jw.mu.RUnlock()
+	return
+}
+
+// Variant returns the variant of the named Experiment assigned to the Request,
+// or an empty string if no such Experiment has been registered.
+//
+// In templates, use it like `{{if eq ($.Variant "new-nav") "b"}}...{{end}}`.
+func (rq *Request) Variant(name string) (variant string) {
+	if x := rq.Jaws.Experiment(name); x != nil {
+		variant = x.Variant(rq)
+	}
+	return
+}
+
+// Variant returns the variant of the named Experiment assigned to the Request.
+func (rw RequestWriter) Variant(name string) string {
+	return rw.rq.Variant(name)
+}
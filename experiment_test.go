@@ -0,0 +1,55 @@
+package jaws
+
+import (
+	"html/template"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestExperiment_Assign(t *testing.T) {
+	th := newTestHelper(t)
+	x := NewExperiment("exp", "a", "b")
+	counts := map[string]int{}
+	for id := uint64(1); id <= 1000; id++ {
+		v := x.Assign(id)
+		th.Equal(x.Assign(id), v)
+		counts[v]++
+	}
+	th.Equal(len(counts), 2)
+	th.True(counts["a"] > 400 && counts["b"] > 400)
+
+	x.Weights = []int{0, 1}
+	for id := uint64(1); id <= 100; id++ {
+		th.Equal(x.Assign(id), "b")
+	}
+	th.Equal(NewExperiment("none").Assign(1), "")
+}
+
+func TestRequest_Variant(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+
+	type exposure struct{ name, variant string }
+	var exposures []exposure
+	x := NewExperiment("new-nav", "a", "b")
+	x.OnExposure = func(rq *Request, name, variant string) {
+		exposures = append(exposures, exposure{name, variant})
+	}
+	rq.Jaws.AddExperiment(x)
+	th.Equal(rq.Jaws.Experiment("new-nav"), x)
+	th.Equal(rq.Request.Variant("missing"), "")
+
+	rq.session = newSession(rq.Jaws, 1234, netip.Addr{})
+	want := x.Assign(1234)
+	th.Equal(rq.Request.Variant("new-nav"), want)
+	th.Equal(rq.Request.Variant("new-nav"), want)
+	th.Equal(exposures, []exposure{{"new-nav", want}})
+
+	tmpl := template.Must(template.New("variant").Parse(`{{$.Variant "new-nav"}}`))
+	var sb strings.Builder
+	th.NoErr(tmpl.Execute(&sb, With{RequestWriter: rq.Request.Writer(&sb)}))
+	th.Equal(sb.String(), want)
+	rq.session = nil
+}
@@ -0,0 +1,134 @@
+package jaws
+
+import (
+	"io"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// FlagProvider provides the state of feature flags, set it in Jaws.Flags.
+//
+// When a flag changes, call Jaws.Dirty(jaws.Flag(name)) to update the
+// Elements that depend on it.
+type FlagProvider interface {
+	JawsGetFlag(rq *Request, name string) bool
+}
+
+// Flag is the tag used for Elements that depend on a feature flag.
+type Flag string
+
+// IfFlag returns a parameter that makes the UI it's passed to only render
+// if the named feature flag is enabled. The Element is re-rendered whenever
+// the flag changes.
+//
+// Flags are provided by Jaws.Flags, if it is nil all flags are disabled.
+func IfFlag(name string) Flag {
+	return Flag(name)
+}
+
+// FlagEnabled returns true if the named flag is enabled for the Request.
+func (rq *Request) FlagEnabled(name string) bool {
+	if fp := rq.Jaws.Flags; fp != nil {
+		return fp.JawsGetFlag(rq, name)
+	}
+	return false
+}
+
+// UiIfFlag renders UI only if a feature flag is enabled, and an empty
+// placeholder element otherwise.
+type UiIfFlag struct {
+	UI
+	Flag    Flag
+	mu      deadlock.Mutex
+	params  []interface{}
+	enabled bool
+}
+
+func NewUiIfFlag(flag Flag, ui UI) *UiIfFlag {
+	return &UiIfFlag{UI: ui, Flag: flag}
+}
+
+func (ui *UiIfFlag) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	e.Tag(ui.Flag)
+	enabled := e.Request.FlagEnabled(string(ui.Flag))
+	ui.mu.Lock()
+	ui.params = params
+	ui.enabled = enabled
+	ui.mu.Unlock()
+	if enabled {
+		return ui.UI.JawsRender(e, w, params)
+	}
+	return WriteHtmlInner(w, e.Jid(), "template", "", "")
+}
+
+func (ui *UiIfFlag) JawsUpdate(e *Element) {
+	enabled := e.Request.FlagEnabled(string(ui.Flag))
+	ui.mu.Lock()
+	changed := enabled != ui.enabled
+	params := ui.params
+	ui.mu.Unlock()
+	if changed {
+		// render using a new Element so that tags and handlers start out fresh,
+		// and have the browser replace the old one with it
+		elem := e.Request.NewElement(ui)
+		var sb strings.Builder
+		maybePanic(ui.JawsRender(elem, &sb, params))
+		elem.wsQueue = append(elem.wsQueue, wsMsg{
+			Data: sb.String(),
+			Jid:  e.jid,
+			What: what.Replace,
+		})
+		e.Request.deleteElement(e)
+	} else if enabled {
+		ui.UI.JawsUpdate(e)
+	}
+}
+
+func (ui *UiIfFlag) JawsEvent(e *Element, wht what.What, val string) error {
+	return callEventHandler(ui.UI, e, wht, val)
+}
+
+// FlagSet is a simple FlagProvider that updates Elements when flags change.
+type FlagSet struct {
+	jw    *Jaws
+	mu    deadlock.RWMutex
+	flags map[string]bool
+}
+
+// NewFlagSet returns a new FlagSet and sets it as jw.Flags.
+func NewFlagSet(jw *Jaws) (fs *FlagSet) {
+	fs = &FlagSet{jw: jw, flags: make(map[string]bool)}
+	jw.Flags = fs
+	return
+}
+
+func (fs *FlagSet) JawsGetFlag(rq *Request, name string) (enabled bool) {
+	fs.mu.RLock()
+	enabled = fs.flags[name]
+	fs.mu.RUnlock()
+	return
+}
+
+// Set enables or disables the named flag, updating all Elements depending on it.
+func (fs *FlagSet) Set(name string, enabled bool) {
+	fs.mu.Lock()
+	changed := fs.flags[name] != enabled
+	fs.flags[name] = enabled
+	fs.mu.Unlock()
+	if changed {
+		fs.jw.Dirty(Flag(name))
+	}
+}
+
+// extractFlag removes a Flag from params, returning it if found.
+func extractFlag(params []interface{}) (flag Flag, rest []interface{}, found bool) {
+	for i, p := range params {
+		if flag, found = p.(Flag); found {
+			rest = append(append(rest, params[:i]...), params[i+1:]...)
+			return
+		}
+	}
+	return "", params, false
+}
@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_IfFlag(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.Equal(rq.FlagEnabled("new-nav"), false)
+	fs := NewFlagSet(rq.Jaws)
+	th.Equal(rq.Jaws.Flags, FlagProvider(fs))
+
+	th.NoErr(rq.Div("nav", IfFlag("new-nav"), "hidden"))
+	th.Equal(rq.BodyString(), `<template id="Jid.1"></template>`)
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(Flag("new-nav")))
+	ui := elem.Ui().(*UiIfFlag)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	fs.mu.Lock()
+	fs.flags["new-nav"] = true
+	fs.mu.Unlock()
+	th.True(rq.FlagEnabled("new-nav"))
+	ui.JawsUpdate(elem)
+	th.Equal(rq.getElementByJid(1), (*Element)(nil))
+	elem2 := rq.getElementByJid(2)
+	th.True(elem2.HasTag(Flag("new-nav")))
+	th.Equal(elem2.wsQueue, []wsMsg{{Data: `<div id="Jid.2" hidden>nav</div>`, Jid: 1, What: what.Replace}})
+	elem2.wsQueue = elem2.wsQueue[:0]
+
+	ui.JawsUpdate(elem2)
+	th.Equal(elem2.wsQueue, []wsMsg{{Data: `nav`, Jid: 2, What: what.Inner}})
+	th.Equal(ui.JawsEvent(elem2, what.Click, "x"), ErrEventUnhandled)
+}
+
+func TestFlagSet_Set(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	fs := NewFlagSet(rq.Jaws)
+	th.NoErr(rq.Span("beta", IfFlag("beta")))
+	fs.Set("beta", false)
+	fs.Set("beta", true)
+	th.True(fs.JawsGetFlag(rq.Request, "beta"))
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Replace\tJid.1\t\"<span id=\\\"Jid.2\\\">beta</span>\"\n")
+	}
+}
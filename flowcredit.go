@@ -0,0 +1,99 @@
+package jaws
+
+import (
+	"context"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// flowControl holds the WebSocket messages the browser has granted the
+// server to send, see Jaws.FlowCredits.
+//
+// The server offers the browser Jaws.FlowCredits credits when it connects.
+// Browsers that support flow control grant that many back, and then one more
+// each time they have applied a message. Until the first grant arrives
+// messages are sent without credits, so older scripts keep working.
+type flowControl struct {
+	credits chan struct{} // a token per message the browser has room for
+	enabled atomic.Bool   // set when the browser first grants credits
+}
+
+// flowControl returns the flowControl for a new WebSocket connection,
+// or nil if Jaws.FlowCredits isn't set.
+func (rq *Request) flowControl() *flowControl {
+	if n := rq.Jaws.FlowCredits; n > 0 {
+		return &flowControl{credits: make(chan struct{}, n)}
+	}
+	return nil
+}
+
+// offer returns the message offering the browser credits.
+func (fc *flowControl) offer() string {
+	return (&wsMsg{What: what.Credit, Data: strconv.Itoa(cap(fc.credits))}).Format()
+}
+
+// grant adds n credits, ignoring those exceeding the number offered.
+func (fc *flowControl) grant(n int) {
+	fc.enabled.Store(true)
+	for ; n > 0; n-- {
+		select {
+		case fc.credits <- struct{}{}:
+		default:
+			return
+		}
+	}
+}
+
+// available returns the number of credits not yet used, or -1 if flow control isn't in effect.
+func (fc *flowControl) available() int {
+	if fc == nil || !fc.enabled.Load() {
+		return -1
+	}
+	return len(fc.credits)
+}
+
+// setFlow records the flowControl of the running Request's WebSocket connection.
+func (rq *Request) setFlow(fc *flowControl) {
+	rq.mu.Lock()
+	rq.flow = fc
+	rq.mu.Unlock()
+}
+
+// handleCredit handles credits granted by the browser.
+func (rq *Request) handleCredit(data string) {
+	rq.mu.RLock()
+	fc := rq.flow
+	rq.mu.RUnlock()
+	if n, err := strconv.Atoi(data); err == nil && fc != nil {
+		fc.grant(n)
+	}
+}
+
+// limitFlow returns ws with writes waiting for credits if fc isn't nil.
+func limitFlow(ws WebSocketConn, fc *flowControl) WebSocketConn {
+	if fc != nil {
+		return creditWebSocketConn{WebSocketConn: ws, flow: fc}
+	}
+	return ws
+}
+
+// creditWebSocketConn is a WebSocketConn that waits for a credit before
+// each write once the browser has granted some. Messages queued meanwhile
+// are batched into the next write by wsWriter.
+type creditWebSocketConn struct {
+	WebSocketConn
+	flow *flowControl
+}
+
+func (c creditWebSocketConn) Write(ctx context.Context, msg []byte) error {
+	if c.flow.enabled.Load() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-c.flow.credits:
+		}
+	}
+	return c.WebSocketConn.Write(ctx, msg)
+}
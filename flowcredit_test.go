@@ -0,0 +1,129 @@
+package jaws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_flowControl(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+
+	th.Equal(rq.flowControl(), (*flowControl)(nil))
+	th.Equal((*flowControl)(nil).available(), -1)
+
+	jw.FlowCredits = 3
+	fc := rq.flowControl()
+	th.Equal(fc.offer(), "Credit\t\t\"3\"\n")
+	th.Equal(fc.available(), -1)
+	fc.grant(2)
+	th.Equal(fc.available(), 2)
+	fc.grant(5)
+	th.Equal(fc.available(), 3)
+
+	rq.handleCredit("1")
+	th.Equal(rq.SendStats().Credits, -1)
+	rq.setFlow(fc)
+	<-fc.credits
+	rq.handleCredit("x")
+	th.Equal(rq.SendStats().Credits, 2)
+	rq.handleCredit("1")
+	th.Equal(rq.SendStats().Credits, 3)
+	rq.setFlow(nil)
+	th.Equal(rq.SendStats().Credits, -1)
+}
+
+func Test_creditWebSocketConn(t *testing.T) {
+	th := newTestHelper(t)
+	conn := &testWebSocketConn{outCh: make(chan []byte, 4), closedCh: make(chan struct{})}
+	th.Equal(limitFlow(conn, nil), WebSocketConn(conn))
+
+	fc := &flowControl{credits: make(chan struct{}, 1)}
+	ws := limitFlow(conn, fc)
+	th.NoErr(ws.Write(context.Background(), []byte("1")))
+	th.NoErr(ws.Write(context.Background(), []byte("2")))
+	th.Equal(len(conn.outCh), 2)
+
+	fc.grant(1)
+	th.NoErr(ws.Write(context.Background(), []byte("3")))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*10)
+	defer cancel()
+	th.Equal(ws.Write(ctx, []byte("4")), context.DeadlineExceeded)
+	th.Equal(len(conn.outCh), 3)
+}
+
+func TestWS_FlowCredits(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	go ts.jw.Serve()
+	ts.jw.FlowCredits = 4
+
+	conn := &testWebSocketConn{inCh: make(chan []byte), outCh: make(chan []byte), closedCh: make(chan struct{})}
+	ts.jw.WebSocket = &testWebSocketTransport{conn: conn}
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		ts.rq.ServeHTTP(httptest.NewRecorder(), newProtocolRequest(http.MethodGet, ts.Path(), nil))
+	}()
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case b := <-conn.outCh:
+		th.Equal(string(b), "Credit\t\t\"4\"\n")
+	}
+
+	grant := wsMsg{What: what.Credit, Data: "1"}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case conn.inCh <- grant.Append(nil):
+	}
+	for ts.rq.SendStats().Credits != 1 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	ts.rq.Alert("info", "first")
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-conn.outCh:
+	}
+	ts.rq.Alert("info", "second")
+	select {
+	case <-conn.outCh:
+		t.Error("sent without credit")
+	case <-time.After(time.Millisecond * 20):
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case conn.inCh <- grant.Append(nil):
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-conn.outCh:
+	}
+
+	conn.Close("")
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+}
@@ -0,0 +1,35 @@
+package jaws
+
+import (
+	"errors"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// FullscreenHandler is notified when an Element enters or leaves fullscreen
+// mode, see Element.RequestFullscreen.
+//
+// If the browser refused the request, active is false and reason holds the
+// error message from the browser, otherwise reason is nil.
+type FullscreenHandler interface {
+	JawsFullscreen(e *Element, active bool, reason error) (err error)
+}
+
+type fullscreenHandlerWrapper struct{ FullscreenHandler }
+
+func (fhw fullscreenHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Fullscreen {
+		return callFullscreenHandler(fhw.FullscreenHandler, e, v)
+	}
+	return ErrEventUnhandled
+}
+
+func callFullscreenHandler(h FullscreenHandler, e *Element, val string) error {
+	switch val {
+	case "true":
+		return h.JawsFullscreen(e, true, nil)
+	case "false":
+		return h.JawsFullscreen(e, false, nil)
+	}
+	return h.JawsFullscreen(e, false, errors.New(val))
+}
@@ -0,0 +1,49 @@
+package jaws
+
+import (
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJawsFullscreen struct {
+	active bool
+	reason error
+	calls  int
+}
+
+func (tjf *testJawsFullscreen) JawsFullscreen(e *Element, active bool, reason error) error {
+	tjf.active, tjf.reason = active, reason
+	tjf.calls++
+	return nil
+}
+
+var _ FullscreenHandler = (*testJawsFullscreen)(nil)
+
+func Test_fullscreenHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	tjf := &testJawsFullscreen{}
+	th.NoErr(rq.Div("inner", tjf))
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "x"))
+	th.Equal(tjf.calls, 0)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Fullscreen, "true"))
+	th.Equal(tjf.active, true)
+	th.Equal(tjf.reason, nil)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Fullscreen, "false"))
+	th.Equal(tjf.active, false)
+	th.Equal(tjf.reason, nil)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Fullscreen, "Permissions check failed"))
+	th.Equal(tjf.active, false)
+	th.Equal(tjf.reason.Error(), "Permissions check failed")
+	th.Equal(tjf.calls, 3)
+}
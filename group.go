@@ -0,0 +1,93 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+	"net/http"
+	"path"
+)
+
+// MaxGroupSize is the maximum number of Jaws instances in a Group.
+const MaxGroupSize = 255
+
+// ErrGroupConflict is returned by NewGroup if the Jaws instances can't be grouped.
+var ErrGroupConflict = errors.New("jaws group conflict")
+
+// Group routes the JaWS endpoints to several Jaws instances in the same
+// binary, such as one for a public UI and one for an internal UI.
+//
+// Each Jaws keeps it's own Requests, Sessions and broadcasts. Request keys
+// and session IDs are namespaced by the Jaws index in the Group, so the Group
+// can route WebSocket upgrades without asking each Jaws, while the embedded
+// Javascript is served once for all of them.
+//
+// The index takes the top bits.Len(n) bits of the keys of a Group of n
+// instances, leaving the rest random. A Group of two or three instances
+// thus has 62 random bits per key instead of 64, and the largest 56.
+type Group struct {
+	jaws  []*Jaws
+	shift int // bit position of the Jaws index in keys
+}
+
+// NewGroup returns a Group routing to the given Jaws instances. Create it
+// before creating any Requests or Sessions.
+//
+// Returns an error wrapping ErrGroupConflict if there are too many instances,
+// if an instance is already in a Group or if two share a session cookie name and path.
+func NewGroup(jws ...*Jaws) (g *Group, err error) {
+	if len(jws) > MaxGroupSize {
+		return nil, fmt.Errorf("%w: too many instances", ErrGroupConflict)
+	}
+	for i, jw := range jws {
+		jw.mu.RLock()
+		grouped := jw.keyNs != 0
+		jw.mu.RUnlock()
+		if grouped {
+			return nil, fmt.Errorf("%w: instance already in a group", ErrGroupConflict)
+		}
+		for _, other := range jws[:i] {
+			if jw.CookieName == other.CookieName && jw.CookiePath == other.CookiePath {
+				return nil, fmt.Errorf("%w: shared session cookie %q", ErrGroupConflict, jw.CookieName)
+			}
+		}
+	}
+	shift := 64 - bits.Len(uint(len(jws)))
+	for i, jw := range jws {
+		jw.mu.Lock()
+		jw.keyNs = uint64(i+1) << shift
+		jw.keyNsBits = 64 - shift
+		jw.mu.Unlock()
+	}
+	return &Group{jaws: append([]*Jaws(nil), jws...), shift: shift}, nil
+}
+
+// Owner returns the Jaws in the Group that issued the Request key or session ID, or nil.
+func (g *Group) Owner(key uint64) (jw *Jaws) {
+	if idx := int(key >> g.shift); idx > 0 && idx <= len(g.jaws) {
+		jw = g.jaws[idx-1]
+	}
+	return
+}
+
+// ServeHTTP handles the JaWS endpoints for all the Jaws instances in the Group.
+func (g *Group) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if jawsKey := JawsKeyValue(path.Base(r.URL.Path)); jawsKey != 0 {
+		if jw := g.Owner(jawsKey); jw != nil {
+			jw.ServeHTTP(w, r)
+			return
+		}
+	} else if len(g.jaws) > 0 {
+		g.jaws[0].ServeHTTP(w, r)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// newKeyLocked returns a new random Request key or session ID in the Jaws key namespace.
+func (jw *Jaws) newKeyLocked() uint64 {
+	if jw.keyNs == 0 {
+		return jw.nonZeroRandomLocked()
+	}
+	return jw.nonZeroRandomLocked()>>jw.keyNsBits | jw.keyNs
+}
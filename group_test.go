@@ -0,0 +1,79 @@
+package jaws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewGroup(t *testing.T) {
+	th := newTestHelper(t)
+	jw1 := New()
+	defer jw1.Close()
+	jw2 := New()
+	defer jw2.Close()
+
+	_, err := NewGroup(jw1, jw2)
+	th.True(errors.Is(err, ErrGroupConflict))
+
+	jw2.CookieName = "jaws-internal"
+	g, err := NewGroup(jw1, jw2)
+	th.NoErr(err)
+	_, err = NewGroup(jw2)
+	th.True(errors.Is(err, ErrGroupConflict))
+	_, err = NewGroup(make([]*Jaws, MaxGroupSize+1)...)
+	th.True(errors.Is(err, ErrGroupConflict))
+
+	hr := httptest.NewRequest("GET", "/", nil)
+	rq1 := jw1.NewRequest(hr)
+	rq2 := jw2.NewRequest(hr)
+	th.Equal(g.Owner(rq1.JawsKey), jw1)
+	th.Equal(g.Owner(rq2.JawsKey), jw2)
+	th.Equal(g.Owner(jw2.NewSession(nil, hr).ID()), jw2)
+	th.Equal(g.Owner(0), (*Jaws)(nil))
+	th.Equal(g.Owner(3<<62), (*Jaws)(nil))
+	th.Equal(rq1.JawsKey>>62, uint64(1))
+	th.Equal(rq2.JawsKey>>62, uint64(2))
+
+	empty, err := NewGroup()
+	th.NoErr(err)
+	th.Equal(empty.Owner(rq1.JawsKey), (*Jaws)(nil))
+}
+
+func TestGroup_ServeHTTP(t *testing.T) {
+	th := newTestHelper(t)
+	jw1 := New()
+	defer jw1.Close()
+	jw2 := New()
+	defer jw2.Close()
+	jw2.CookiePath = "/internal"
+	g, err := NewGroup(jw1, jw2)
+	th.NoErr(err)
+
+	serve := func(uri string) int {
+		rr := httptest.NewRecorder()
+		g.ServeHTTP(rr, newProtocolRequest(http.MethodGet, uri, nil))
+		return rr.Code
+	}
+	th.Equal(serve(JavascriptPath), http.StatusOK)
+	th.Equal(serve("/jaws/.ping"), http.StatusNoContent)
+	th.Equal(serve("/jaws/"+JawsKeyString(1)), http.StatusNotFound)
+
+	rq := jw2.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	// not a WebSocket upgrade, but proves it reached jw2 and claimed the Request
+	th.Equal(serve("/jaws/"+rq.JawsKeyString()), testUpgradeStatus)
+	th.Equal(jw2.UseRequest(rq.JawsKey, httptest.NewRequest(http.MethodGet, "/", nil)), (*Request)(nil))
+	rq = jw2.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	th.Equal(serve(PollPath+rq.JawsKeyString()), http.StatusOK)
+	th.Equal(jw2.UseRequest(rq.JawsKey, httptest.NewRequest(http.MethodGet, "/", nil)), (*Request)(nil))
+	jw2.PollInterval = time.Millisecond
+	th.Equal(serve(PollPath+rq.JawsKeyString()+"?ack=0"), http.StatusOK)
+
+	empty, err := NewGroup()
+	th.NoErr(err)
+	rr := httptest.NewRecorder()
+	empty.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, JavascriptPath, nil))
+	th.Equal(rr.Code, http.StatusNotFound)
+}
@@ -0,0 +1,157 @@
+package jaws
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+)
+
+var headerContentTypeHTML = []string{"text/html; charset=utf-8"}
+
+// pageHandler renders a page for each HTTP request, and serves the JaWS
+// endpoints under "/jaws/".
+type pageHandler struct {
+	jw   *Jaws
+	view interface{}
+}
+
+// Handler returns a http.Handler serving the JaWS endpoints under "/jaws/"
+// using ServeHTTP, and rendering the view as a page for GET requests to
+// other paths.
+//
+// The view is either a Template, a *template.Template or the name of a
+// template in Jaws.Template. The latter two are executed with a nil Dot.
+// Pages are buffered, so if rendering fails, the error is logged and the
+// response is "500 Internal Server Error". HEAD requests are answered
+// without rendering the page.
+func (jw *Jaws) Handler(view interface{}) http.Handler {
+	return pageHandler{jw: jw, view: view}
+}
+
+// Handle registers a Handler for the view with http.DefaultServeMux for
+// the pattern, as well as one for "/jaws/" unless the pattern is "/",
+// so a minimal application only needs:
+//
+//	jw.Handle("/", "index.html")
+//	http.ListenAndServe(":8080", nil)
+//
+// See HandleMux.
+func (jw *Jaws) Handle(pattern string, view interface{}) {
+	jw.HandleMux(http.DefaultServeMux, pattern, view)
+}
+
+// HandleMux registers a Handler for the view with mux for the pattern, as
+// well as one for "/jaws/" unless the pattern is "/".
+//
+// The "/jaws/" handler is registered once per mux, and is shared by all
+// the Jaws instances that call HandleMux with it. Requests for a Request
+// key are routed to the Jaws that has the Request, others go to the first.
+//
+// It panics if a handler is already registered for the pattern, or for
+// "/jaws/" by something other than HandleMux.
+func (jw *Jaws) HandleMux(mux *http.ServeMux, pattern string, view interface{}) {
+	mux.Handle(pattern, jw.Handler(view))
+	mountsMu.Lock()
+	defer mountsMu.Unlock()
+	m := mounts[mux]
+	if m == nil {
+		m = &jawsMount{}
+		mounts[mux] = m
+	}
+	if !slices.Contains(m.jaws, jw) {
+		m.jaws = append(m.jaws, jw)
+	}
+	if pattern != "/" && !m.registered {
+		m.registered = true
+		mux.Handle("/jaws/", m)
+	}
+}
+
+var (
+	mountsMu deadlock.Mutex                    // protects following
+	mounts   = map[*http.ServeMux]*jawsMount{} // "/jaws/" handlers registered by HandleMux
+)
+
+// jawsMount routes "/jaws/" on a ServeMux to the Jaws instances that called HandleMux with it.
+type jawsMount struct {
+	registered bool
+	jaws       []*Jaws
+}
+
+func (m *jawsMount) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mountsMu.Lock()
+	jws := m.jaws
+	mountsMu.Unlock()
+	owner := jws[0]
+	if jawsKey := JawsKeyValue(path.Base(r.URL.Path)); jawsKey != 0 {
+		for _, jw := range jws {
+			if jw.hasRequest(jawsKey) {
+				owner = jw
+				break
+			}
+		}
+	}
+	owner.ServeHTTP(w, r)
+}
+
+// hasRequest returns true if the Jaws has a Request with the given key.
+func (jw *Jaws) hasRequest(jawsKey uint64) (ok bool) {
+	jw.mu.RLock()
+	_, ok = jw.requests[jawsKey]
+	jw.mu.RUnlock()
+	return
+}
+
+func (h pageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if strings.HasPrefix(h.jw.requestPath(r), "/jaws/") {
+		h.jw.ServeHTTP(w, r)
+		return
+	}
+	hdr := w.Header()
+	switch r.Method {
+	case http.MethodHead:
+		// rendering would create a Request the browser never connects to
+		hdr["Cache-Control"] = headerCacheNoCache
+		hdr["Content-Type"] = headerContentTypeHTML
+		w.WriteHeader(http.StatusOK)
+		return
+	case http.MethodGet:
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	rq := h.jw.NewRequest(r)
+	buf := getRenderBuf()
+	defer putRenderBuf(buf)
+	err := h.render(rq, buf)
+	if err != nil {
+		h.jw.recycle(rq)
+		_ = h.jw.Log(fmt.Errorf("jaws: %s: %w", r.URL.Path, err))
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	hdr["Cache-Control"] = headerCacheNoCache
+	hdr["Content-Type"] = headerContentTypeHTML
+	hdr["Content-Length"] = []string{strconv.Itoa(buf.Len())}
+	_, _ = buf.WriteTo(w) // #nosec G104
+}
+
+// render renders the view, returning panics from resolving it as errors.
+func (h pageHandler) render(rq *Request, buf *bytes.Buffer) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = fmt.Errorf("%v", x)
+		}
+	}()
+	t, ok := h.view.(Template)
+	if !ok {
+		t = rq.MakeTemplate(h.view, nil)
+	}
+	return rq.Writer(buf).UI(NewUiTemplate(t))
+}
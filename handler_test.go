@@ -0,0 +1,133 @@
+package jaws
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestJaws_Handler(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	page := template.Must(template.New("page").Parse(`<html><head>{{$.HeadHTML}}</head><body>{{$.Dot}}</body></html>`))
+	serve := func(h http.Handler, method, target string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(method, target, nil))
+		return rr
+	}
+
+	h := tj.Handler(Template{Template: page, Dot: "hello"})
+	rr := serve(h, http.MethodGet, "/")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	th.Equal(rr.Header().Get("Cache-Control"), "no-cache")
+	body := rr.Body.String()
+	th.True(strings.Contains(body, `<noscript><div class="jaws-alert">`))
+	th.True(strings.HasSuffix(body, `<body>hello</body></html>`))
+
+	pending := tj.Pending()
+	rr = serve(h, http.MethodHead, "/other")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.Len(), 0)
+	th.Equal(rr.Header().Get("Content-Type"), "text/html; charset=utf-8")
+	th.Equal(tj.Pending(), pending)
+	th.Equal(serve(h, http.MethodPost, "/").Code, http.StatusMethodNotAllowed)
+
+	rr = serve(h, http.MethodGet, JavascriptPath)
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.Bytes(), JavascriptText)
+	th.Equal(serve(h, http.MethodGet, "/jaws/.ping").Code, http.StatusNoContent)
+
+	rr = serve(tj.Handler("testtemplate"), http.MethodGet, "/")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.String(), "")
+
+	pending = tj.Pending()
+	rr = serve(tj.Handler("missing"), http.MethodGet, "/")
+	th.Equal(rr.Code, http.StatusInternalServerError)
+	th.True(strings.Contains(tj.log.String(), `jaws: /: expected template, not missing`))
+	th.Equal(tj.Pending(), pending)
+}
+
+func TestJaws_Handle(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	page := template.Must(template.New("page").Parse(`page`))
+	tj.Handle("/jaws-handle-test/", page)
+	tj.Handle("/jaws-handle-test2/", page)
+
+	rr := httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/jaws-handle-test2/x", nil))
+	th.Equal(rr.Body.String(), "page")
+	rr = httptest.NewRecorder()
+	http.DefaultServeMux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/jaws/.ping", nil))
+	th.Equal(rr.Code, http.StatusNoContent)
+
+	tj2 := newTestJaws()
+	defer tj2.Close()
+	tj2.Handle("/jaws-handle-test3/", page)
+}
+
+func TestJaws_HandleMux(t *testing.T) {
+	th := newTestHelper(t)
+	jw1 := New()
+	defer jw1.Close()
+	jw2 := New()
+	defer jw2.Close()
+
+	mux := http.NewServeMux()
+	page := template.Must(template.New("page").Parse(`page`))
+	jw1.HandleMux(mux, "/", page)
+	jw2.HandleMux(mux, "/two/", page)
+	jw2.HandleMux(mux, "/three/", page)
+
+	serve := func(target string) int {
+		rr := httptest.NewRecorder()
+		mux.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, target, nil))
+		return rr.Code
+	}
+	th.Equal(serve("/jaws/.ping"), http.StatusNoContent)
+	for _, jw := range []*Jaws{jw1, jw2} {
+		hr := httptest.NewRequest(http.MethodGet, "/", nil)
+		rq := jw.NewRequest(hr)
+		th.Equal(serve("/jaws/"+rq.JawsKeyString()), testUpgradeStatus)
+	}
+	th.Equal(serve("/jaws/"+JawsKeyString(1)), http.StatusNotFound)
+}
+
+type discardResponseWriter struct{ hdr http.Header }
+
+func (w discardResponseWriter) Header() http.Header         { return w.hdr }
+func (w discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w discardResponseWriter) WriteHeader(int)             {}
+
+func BenchmarkJaws_Handler(b *testing.B) {
+	jw := New()
+	defer jw.Close()
+	rows := make([]string, 200)
+	for i := range rows {
+		rows[i] = "row " + strconv.Itoa(i)
+	}
+	page := template.Must(template.New("page").Parse(`<html><body>{{range $.Dot}}<p>{{.}}</p>{{end}}</body></html>`))
+	h := jw.Handler(Template{Template: page, Dot: rows})
+	w := discardResponseWriter{hdr: http.Header{}}
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%1000 == 0 {
+			jw.mu.Lock()
+			for _, rq := range jw.requests {
+				jw.recycleLocked(rq)
+			}
+			jw.mu.Unlock()
+		}
+		h.ServeHTTP(w, hr)
+	}
+}
@@ -0,0 +1,139 @@
+package jaws
+
+import (
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/netip"
+	"slices"
+	"time"
+)
+
+// DefaultHandoffTTL is the validity of session handoff tokens if none is given.
+const DefaultHandoffTTL = 2 * time.Minute
+
+// ErrHandoffInvalid is returned by Jaws.Handoff if the token is unknown,
+// expired, already used or if it's Session is closed.
+var ErrHandoffInvalid = errors.New("invalid session handoff token")
+
+// handoffTokenLen is the number of random bytes in a handoff token.
+const handoffTokenLen = 16
+
+// handoff is an unused session handoff token.
+type handoff struct {
+	sess    *Session
+	expires time.Time
+}
+
+// expireHandoffsLocked forgets the handoff tokens that have expired.
+func (jw *Jaws) expireHandoffsLocked(now time.Time) {
+	for token, h := range jw.handoffs {
+		if now.After(h.expires) {
+			delete(jw.handoffs, token)
+		}
+	}
+}
+
+// HandoffToken returns a random token that may be passed to Jaws.Handoff
+// once, within ttl, to continue the Session on another device. This is
+// typically done by rendering an URL containing it as a QR code. If ttl is
+// not positive, DefaultHandoffTTL is used.
+//
+// The token only refers to the Session, so it doesn't reveal the session
+// cookie. Anyone holding the token can take over the Session until it's
+// used or expires, so keep ttl short.
+// Returns an empty string if called on a nil Session.
+func (sess *Session) HandoffToken(ttl time.Duration) string {
+	if sess == nil {
+		return ""
+	}
+	if ttl <= 0 {
+		ttl = DefaultHandoffTTL
+	}
+	b := make([]byte, handoffTokenLen)
+	now := time.Now()
+	jw := sess.jw
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	if _, err := io.ReadFull(jw.kg, b); err != nil {
+		panic(err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+	jw.expireHandoffsLocked(now)
+	if jw.handoffs == nil {
+		jw.handoffs = make(map[string]handoff)
+	}
+	jw.handoffs[token] = handoff{sess: sess, expires: now.Add(ttl)}
+	return token
+}
+
+// Handoff redeems a token from Session.HandoffToken, setting the session
+// cookie in w (if not nil) and in hr, so that Requests created with
+// NewRequest(hr) are attached to the Session.
+//
+// Since the Session is bound to the remote IP it was created from, hr's
+// remote IP is added to the IPs allowed to use it. Use Session.IPs to see
+// them and Session.RemoveIP to revoke one.
+//
+// Returns ErrHandoffInvalid if the token can't be used.
+func (jw *Jaws) Handoff(w http.ResponseWriter, hr *http.Request, token string) (sess *Session, err error) {
+	now := time.Now()
+	jw.mu.Lock()
+	h, ok := jw.handoffs[token]
+	delete(jw.handoffs, token)
+	jw.mu.Unlock()
+	if !ok || now.After(h.expires) {
+		return nil, ErrHandoffInvalid
+	}
+	remoteIP := parseIP(hr.RemoteAddr)
+	h.sess.mu.Lock()
+	if !h.sess.isDeadLocked() {
+		if !equalIP(remoteIP, h.sess.remoteIP) && !slices.Contains(h.sess.ips, remoteIP) {
+			h.sess.ips = append(h.sess.ips, remoteIP)
+		}
+		sess = h.sess
+	}
+	h.sess.mu.Unlock()
+	if sess == nil {
+		return nil, ErrHandoffInvalid
+	}
+	cookie := sess.Cookie()
+	if w != nil {
+		http.SetCookie(w, cookie)
+	}
+	hr.AddCookie(cookie)
+	return
+}
+
+// allowsIP returns true if Requests from remoteIP may use the Session.
+func (sess *Session) allowsIP(remoteIP netip.Addr) (yes bool) {
+	if yes = equalIP(remoteIP, sess.remoteIP); !yes {
+		sess.mu.RLock()
+		yes = slices.Contains(sess.ips, remoteIP)
+		sess.mu.RUnlock()
+	}
+	return
+}
+
+// IPs returns the remote IPs other than IP that Jaws.Handoff has allowed
+// to use the Session.
+// It is safe to call on a nil Session, in which case it returns nil.
+func (sess *Session) IPs() (ips []netip.Addr) {
+	if sess != nil {
+		sess.mu.RLock()
+		ips = slices.Clone(sess.ips)
+		sess.mu.RUnlock()
+	}
+	return
+}
+
+// RemoveIP stops new Requests from ip using the Session, undoing a Jaws.Handoff.
+// It is safe to call on a nil Session.
+func (sess *Session) RemoveIP(ip netip.Addr) {
+	if sess != nil {
+		sess.mu.Lock()
+		sess.ips = slices.DeleteFunc(sess.ips, func(a netip.Addr) bool { return a == ip })
+		sess.mu.Unlock()
+	}
+}
@@ -0,0 +1,97 @@
+package jaws
+
+import (
+	"encoding/base64"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJaws_Handoff(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+
+	hr1 := httptest.NewRequest("GET", "/", nil)
+	hr1.RemoteAddr = "10.0.0.1:1234"
+	sess := jw.NewSession(nil, hr1)
+	sess.Set("cart", 3)
+	token := sess.HandoffToken(0)
+	th.Equal(len(token), base64.RawURLEncoding.EncodedLen(handoffTokenLen))
+	th.True(!strings.Contains(token, sess.CookieValue()))
+
+	hr2 := httptest.NewRequest("GET", "/continue", nil)
+	hr2.RemoteAddr = "10.0.0.2:5678"
+	th.Equal(jw.GetSession(hr2), (*Session)(nil))
+
+	rr := httptest.NewRecorder()
+	got, err := jw.Handoff(rr, hr2, token)
+	th.NoErr(err)
+	th.Equal(got, sess)
+	th.Equal(jw.GetSession(hr2), sess)
+	th.Equal(jw.NewRequest(hr2).Session(), sess)
+	th.Equal(len(rr.Result().Cookies()), 1)
+	th.Equal(rr.Result().Cookies()[0].Value, sess.CookieValue())
+	th.Equal(sess.IPs(), []netip.Addr{netip.MustParseAddr("10.0.0.2")})
+
+	// tokens can only be used once
+	hr3 := httptest.NewRequest("GET", "/continue", nil)
+	hr3.RemoteAddr = "10.0.0.3:5678"
+	got, err = jw.Handoff(nil, hr3, token)
+	th.Equal(err, ErrHandoffInvalid)
+	th.Equal(got, (*Session)(nil))
+	th.Equal(jw.GetSession(hr3), (*Session)(nil))
+	th.Equal(len(sess.IPs()), 1)
+
+	// the IP a handoff allowed can be revoked
+	sess.RemoveIP(netip.MustParseAddr("10.0.0.2"))
+	th.Equal(len(sess.IPs()), 0)
+	th.Equal(jw.GetSession(hr2), (*Session)(nil))
+
+	token = sess.HandoffToken(time.Minute)
+	_, err = jw.Handoff(nil, hr3, "!"+token)
+	th.Equal(err, ErrHandoffInvalid)
+	_, err = jw.Handoff(nil, hr3, strings.Repeat("A", len(token)))
+	th.Equal(err, ErrHandoffInvalid)
+
+	sess.Close()
+	_, err = jw.Handoff(nil, hr3, token)
+	th.Equal(err, ErrHandoffInvalid)
+
+	var nilSess *Session
+	th.Equal(nilSess.HandoffToken(time.Minute), "")
+	th.Equal(nilSess.IPs(), []netip.Addr(nil))
+	nilSess.RemoveIP(netip.Addr{})
+}
+
+func TestJaws_HandoffExpired(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+
+	hr1 := httptest.NewRequest("GET", "/", nil)
+	sess := jw.NewSession(nil, hr1)
+	expired := sess.HandoffToken(time.Minute)
+	token := sess.HandoffToken(time.Minute)
+	jw.mu.Lock()
+	jw.handoffs[expired] = handoff{sess: sess, expires: time.Now().Add(-time.Second)}
+	jw.mu.Unlock()
+
+	hr2 := httptest.NewRequest("GET", "/", nil)
+	_, err := jw.Handoff(nil, hr2, expired)
+	th.Equal(err, ErrHandoffInvalid)
+
+	// expired tokens are forgotten
+	jw.mu.Lock()
+	jw.handoffs[expired] = handoff{sess: sess, expires: time.Now().Add(-time.Second)}
+	jw.mu.Unlock()
+	jw.maintenance(time.Minute)
+	jw.mu.RLock()
+	th.Equal(len(jw.handoffs), 1)
+	jw.mu.RUnlock()
+
+	_, err = jw.Handoff(nil, hr2, token)
+	th.NoErr(err)
+}
@@ -0,0 +1,134 @@
+package jaws
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxServeStall is how long the broadcast loop started by Serve may go
+// without completing an iteration before Healthz reports it as stalled.
+const MaxServeStall = time.Second * 5
+
+// healthHandler reports on the state of a Jaws, see Jaws.Healthz and Jaws.Readyz.
+type healthHandler struct {
+	jw    *Jaws
+	ready bool
+}
+
+// Healthz returns a http.Handler for liveness probes. It responds with
+// "200 OK" if the broadcast loop started by Serve is running and has
+// completed an iteration within MaxServeStall, and the Jaws isn't closed.
+// Otherwise it responds with "503 Service Unavailable". The body lists
+// the checks made.
+func (jw *Jaws) Healthz() http.Handler {
+	return healthHandler{jw: jw}
+}
+
+// Readyz returns a http.Handler for readiness probes. In addition to the
+// checks made by Healthz, it responds with "503 Service Unavailable" once
+// Drain has been called, if events have waited longer than MaxServeStall
+// for an event worker, or if any Request has more outbound or broadcast
+// messages in it's backlog than fit in it's outbound queue (see
+// OverflowGrow and OverflowBlock), so that new page loads are routed to
+// other instances.
+func (jw *Jaws) Readyz() http.Handler {
+	return healthHandler{jw: jw, ready: true}
+}
+
+// Drain marks the Jaws as shutting down, making Readyz report it as not
+// ready while existing Requests continue to be served.
+func (jw *Jaws) Drain() {
+	jw.draining.Store(true)
+}
+
+// Draining returns true if Drain has been called.
+func (jw *Jaws) Draining() bool {
+	return jw.draining.Load()
+}
+
+// healthChecks returns the names of the checks made and the problems found, if any.
+func (jw *Jaws) healthChecks(ready bool) (names, problems []string) {
+	check := func(name, problem string) {
+		names = append(names, name)
+		problems = append(problems, problem)
+	}
+
+	var problem string
+	if beat := jw.serveBeat.Load(); beat == 0 {
+		problem = "not running"
+	} else if stall := time.Since(time.Unix(0, beat)); stall > MaxServeStall {
+		problem = "stalled for " + stall.Round(time.Millisecond).String()
+	}
+	check("broadcast", problem)
+
+	problem = ""
+	select {
+	case <-jw.Done():
+		problem = "closed"
+	default:
+		if ready && jw.Draining() {
+			problem = "draining"
+		}
+	}
+	check("shutdown", problem)
+
+	if ready {
+		problem = ""
+		jw.mu.RLock()
+		pool := jw.eventPool
+		jw.mu.RUnlock()
+		if pool != nil {
+			if n, wait := pool.waiting(); wait > MaxServeStall {
+				problem = fmt.Sprintf("event queue stalled for %v (%d)", wait.Round(time.Millisecond), n)
+			}
+		}
+		check("events", problem)
+
+		problem = ""
+		jw.mu.RLock()
+		reqs := make([]*Request, 0, len(jw.requests))
+		for _, rq := range jw.requests {
+			reqs = append(reqs, rq)
+		}
+		jw.mu.RUnlock()
+		n := 0
+		for _, rq := range reqs {
+			if rq.backlogged() {
+				n++
+			}
+		}
+		if n > 0 {
+			problem = fmt.Sprintf("%d of %d Requests backlogged", n, len(reqs))
+		}
+		check("queues", problem)
+	}
+	return
+}
+
+func (h healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	names, problems := h.jw.healthChecks(h.ready)
+	var sb strings.Builder
+	code := http.StatusOK
+	for i, name := range names {
+		sb.WriteString(name)
+		sb.WriteString(": ")
+		if problems[i] == "" {
+			sb.WriteString("ok")
+		} else {
+			sb.WriteString(problems[i])
+			code = http.StatusServiceUnavailable
+		}
+		sb.WriteByte('\n')
+	}
+	hdr := w.Header()
+	hdr["Cache-Control"] = headerCacheNoCache
+	hdr["Content-Type"] = headerContentTypeText
+	hdr["Content-Length"] = []string{strconv.Itoa(sb.Len())}
+	w.WriteHeader(code)
+	if r.Method != http.MethodHead {
+		_, _ = w.Write([]byte(sb.String())) // #nosec G104
+	}
+}
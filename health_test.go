@@ -0,0 +1,84 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestJaws_Healthz_Readyz(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+
+	probe := func(h http.Handler) (int, string) {
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+		return rr.Code, rr.Body.String()
+	}
+
+	code, body := probe(jw.Healthz())
+	th.Equal(code, http.StatusServiceUnavailable)
+	th.Equal(body, "broadcast: not running\nshutdown: ok\n")
+
+	go jw.Serve()
+	for jw.serveBeat.Load() == 0 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	code, body = probe(jw.Healthz())
+	th.Equal(code, http.StatusOK)
+	th.Equal(body, "broadcast: ok\nshutdown: ok\n")
+	code, body = probe(jw.Readyz())
+	th.Equal(code, http.StatusOK)
+	th.Equal(body, "broadcast: ok\nshutdown: ok\nevents: ok\nqueues: ok\n")
+
+	rr := httptest.NewRecorder()
+	jw.Readyz().ServeHTTP(rr, httptest.NewRequest(http.MethodHead, "/readyz", nil))
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.Len(), 0)
+	th.Equal(rr.Header()["Content-Length"], []string{"49"})
+
+	jw.Drain()
+	th.True(jw.Draining())
+	code, _ = probe(jw.Healthz())
+	th.Equal(code, http.StatusOK)
+	code, body = probe(jw.Readyz())
+	th.Equal(code, http.StatusServiceUnavailable)
+	th.Equal(body, "broadcast: ok\nshutdown: draining\nevents: ok\nqueues: ok\n")
+
+	jw.Close()
+	code, body = probe(jw.Healthz())
+	th.Equal(code, http.StatusServiceUnavailable)
+	th.True(body == "broadcast: ok\nshutdown: closed\n" || body == "broadcast: not running\nshutdown: closed\n")
+}
+
+func TestJaws_Readyz_Stalled(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.Close()
+	jw.serveBeat.Store(time.Now().Add(-MaxServeStall * 2).UnixNano())
+	pool := &eventPool{jobs: []func(){func() {}}, queued: []time.Time{time.Now().Add(-MaxServeStall * 2)}}
+	jw.mu.Lock()
+	jw.eventPool = pool // no workers, so the job stays queued
+	jw.mu.Unlock()
+	jw.NewRequest(nil)
+	rq := jw.NewRequest(nil)
+	rq.setOutbound(make(chan string, 2))
+	rq.sendCount.bcastBacklog.Store(2)
+	th.Equal(rq.backlogged(), false)
+	rq.sendCount.backlog.Store(3)
+	th.Equal(rq.backlogged(), true)
+
+	names, problems := jw.healthChecks(true)
+	th.Equal(names, []string{"broadcast", "shutdown", "events", "queues"})
+	th.Equal(problems[0][:len("stalled for ")], "stalled for ")
+	th.Equal(problems[1], "closed")
+	th.Equal(problems[2][:len("event queue stalled for ")], "event queue stalled for ")
+	th.Equal(problems[3], "1 of 2 Requests backlogged")
+}
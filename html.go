@@ -1,9 +1,11 @@
 package jaws
 
 import (
+	"bytes"
 	"html/template"
 	"io"
 	"strconv"
+	"sync"
 
 	"github.com/linkdata/jaws/jid"
 )
@@ -29,6 +31,55 @@ var singletonTags = map[string]struct{}{
 
 const jidPrealloc = 7 + 4
 
+// maxPooledHtmlBuf is the largest buffer kept for reuse, so that
+// rendering a huge element doesn't pin the memory.
+const maxPooledHtmlBuf = 64 * 1024
+
+var htmlBufPool = sync.Pool{New: func() any { return new([]byte) }}
+
+// getHtmlBuf returns an empty buffer from the pool with room for at least need bytes.
+func getHtmlBuf(need int) *[]byte {
+	bp := htmlBufPool.Get().(*[]byte)
+	if cap(*bp) < need {
+		*bp = make([]byte, 0, need)
+	}
+	*bp = (*bp)[:0]
+	return bp
+}
+
+// writeHtmlBuf writes b to w and returns the buffer bp to the pool.
+func writeHtmlBuf(w io.Writer, bp *[]byte, b []byte) (err error) {
+	_, err = w.Write(b)
+	if cap(b) <= maxPooledHtmlBuf {
+		*bp = b
+		htmlBufPool.Put(bp)
+	}
+	return
+}
+
+var renderBufPool = sync.Pool{New: func() any { return new(bytes.Buffer) }}
+
+// getRenderBuf returns an empty buffer from the pool to render an Element into.
+func getRenderBuf() (buf *bytes.Buffer) {
+	buf = renderBufPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return
+}
+
+// putRenderBuf returns buf to the pool.
+func putRenderBuf(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledHtmlBuf {
+		renderBufPool.Put(buf)
+	}
+}
+
+// writeRenderBuf streams buf to w using it's WriteTo method and returns it to the pool.
+func writeRenderBuf(w io.Writer, buf *bytes.Buffer) (err error) {
+	_, err = buf.WriteTo(w)
+	putRenderBuf(buf)
+	return
+}
+
 func needClosingTag(tag string) bool {
 	_, ok := singletonTags[tag]
 	return !ok
@@ -55,7 +106,8 @@ func appendAttrs(b []byte, attrs []string) []byte {
 
 func WriteHtmlInput(w io.Writer, jid jid.Jid, typ, val string, attrs ...string) (err error) {
 	need := 11 + jidPrealloc + 8 + len(typ) + 9 + len(val) + 1 + 1 + getAttrsLen(attrs) + 1
-	b := make([]byte, 0, need)
+	bp := getHtmlBuf(need)
+	b := *bp
 	b = jid.AppendStartTagAttr(b, "input")
 	b = append(b, ` type=`...)
 	b = strconv.AppendQuote(b, typ)
@@ -65,13 +117,13 @@ func WriteHtmlInput(w io.Writer, jid jid.Jid, typ, val string, attrs ...string)
 	}
 	b = appendAttrs(b, attrs)
 	b = append(b, '>')
-	_, err = w.Write(b)
-	return
+	return writeHtmlBuf(w, bp, b)
 }
 
 func WriteHtmlInner(w io.Writer, jid jid.Jid, tag, typ string, inner template.HTML, attrs ...string) (err error) {
 	need := 1 + len(tag)*2 + jidPrealloc + 8 + len(typ) + 1 + 1 + getAttrsLen(attrs) + 1 + len(inner) + 2 + 1
-	b := make([]byte, 0, need)
+	bp := getHtmlBuf(need)
+	b := *bp
 	b = jid.AppendStartTagAttr(b, tag)
 	if typ != "" {
 		b = append(b, ` type=`...)
@@ -85,8 +137,7 @@ func WriteHtmlInner(w io.Writer, jid jid.Jid, tag, typ string, inner template.HT
 		b = append(b, tag...)
 		b = append(b, '>')
 	}
-	_, err = w.Write(b)
-	return
+	return writeHtmlBuf(w, bp, b)
 }
 
 func WriteHtmlSelect(w io.Writer, jid jid.Jid, nba *NamedBoolArray, attrs ...string) (err error) {
@@ -99,7 +150,8 @@ func WriteHtmlSelect(w io.Writer, jid jid.Jid, nba *NamedBoolArray, attrs ...str
 			}
 		}
 	})
-	b := make([]byte, 0, need)
+	bp := getHtmlBuf(need)
+	b := *bp
 	b = jid.AppendStartTagAttr(b, "select")
 	b = appendAttrs(b, attrs)
 	b = append(b, ">\n"...)
@@ -116,6 +168,5 @@ func WriteHtmlSelect(w io.Writer, jid jid.Jid, nba *NamedBoolArray, attrs ...str
 		}
 	})
 	b = append(b, "</select>\n"...)
-	_, err = w.Write(b)
-	return
+	return writeHtmlBuf(w, bp, b)
 }
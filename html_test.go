@@ -2,6 +2,7 @@ package jaws
 
 import (
 	"html/template"
+	"io"
 	"strings"
 	"testing"
 
@@ -185,3 +186,68 @@ func TestHtmlSelect(t *testing.T) {
 		})
 	}
 }
+
+func BenchmarkWriteHtmlInner(b *testing.B) {
+	inner := template.HTML(strings.Repeat("<p>paragraph</p>", 16))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = WriteHtmlInner(io.Discard, 1234, "div", "", inner, `class="row"`, `hidden`)
+	}
+}
+
+func BenchmarkWriteHtmlInput(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = WriteHtmlInput(io.Discard, 1234, "text", "some value", `class="form-control"`)
+	}
+}
+
+func BenchmarkRequestWriter_Span(b *testing.B) {
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+	defer jw.recycle(rq)
+	rw := rq.Writer(io.Discard)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%1000 == 0 {
+			rq.mu.Lock()
+			rq.elems = rq.elems[:0]
+			clear(rq.tagMap)
+			rq.mu.Unlock()
+		}
+		_ = rw.Span("some text", `class="row"`)
+	}
+}
+
+func Test_htmlBufPool(t *testing.T) {
+	th := newTestHelper(t)
+	bp := getHtmlBuf(10)
+	th.Equal(len(*bp), 0)
+	th.True(cap(*bp) >= 10)
+	var sb strings.Builder
+	th.NoErr(writeHtmlBuf(&sb, bp, append(*bp, "abc"...)))
+	th.Equal(sb.String(), "abc")
+
+	big := template.HTML(strings.Repeat("x", maxPooledHtmlBuf))
+	sb.Reset()
+	th.NoErr(WriteHtmlInner(&sb, 1, "div", "", big))
+	th.Equal(sb.String(), `<div id="Jid.1">`+string(big)+`</div>`)
+	bp = getHtmlBuf(0)
+	th.True(cap(*bp) <= maxPooledHtmlBuf)
+}
+
+func Test_renderBufPool(t *testing.T) {
+	th := newTestHelper(t)
+	buf := getRenderBuf()
+	buf.WriteString("abc")
+	var sb strings.Builder
+	th.NoErr(writeRenderBuf(&sb, buf))
+	th.Equal(sb.String(), "abc")
+	th.Equal(getRenderBuf().Len(), 0)
+
+	buf = getRenderBuf()
+	buf.Grow(maxPooledHtmlBuf * 2)
+	putRenderBuf(buf)
+	th.True(getRenderBuf().Cap() <= maxPooledHtmlBuf)
+}
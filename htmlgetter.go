@@ -35,6 +35,8 @@ func makeHtmlGetter(v interface{}) HtmlGetter {
 	switch v := v.(type) {
 	case HtmlGetter:
 		return v
+	case AsyncHtmlGetter:
+		return asyncHtmlGetter{v}
 	case StringSetter:
 		return htmlStringGetter{v}
 	case template.HTML:
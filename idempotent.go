@@ -0,0 +1,101 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"slices"
+	"strings"
+)
+
+// Idempotent is a parameter that makes click events for the Element run
+// their handlers at most once per idempotency key.
+//
+//	rw.Button("Pay", order, jaws.Idempotent("pay-order"))
+//
+// The browser attaches a key to each click, reusing it until the server
+// has acknowledged it. Keys are remembered in the Session (or the Request
+// if there is no Session), so retried clicks are ignored even across
+// reconnects and page reloads. The value names the action, and should be
+// stable across page loads.
+type Idempotent string
+
+// ErrIdempotencyKeyMissing is logged when a click for an Element using
+// Idempotent does not carry an idempotency key.
+var ErrIdempotencyKeyMissing = errors.New("idempotency key missing")
+
+// maxOnceKeys is the number of idempotency keys remembered.
+const maxOnceKeys = 256
+
+// onceKeys remembers recently used idempotency keys.
+type onceKeys struct {
+	keys []string // "action\tkey", oldest first
+}
+
+// claim returns true and remembers the key if it hasn't been seen before for the action.
+func (ok *onceKeys) claim(action, key string) bool {
+	s := action + "\t" + key
+	if slices.Contains(ok.keys, s) {
+		return false
+	}
+	if len(ok.keys) >= maxOnceKeys {
+		ok.keys = slices.Delete(ok.keys, 0, len(ok.keys)-maxOnceKeys+1)
+	}
+	ok.keys = append(ok.keys, s)
+	return true
+}
+
+// last returns the most recently claimed key for the action.
+func (ok *onceKeys) last(action string) string {
+	for i := len(ok.keys) - 1; i >= 0; i-- {
+		if key, found := strings.CutPrefix(ok.keys[i], action+"\t"); found {
+			return key
+		}
+	}
+	return ""
+}
+
+// onceDo calls fn with the onceKeys for the Request's Session,
+// or those of the Request if it has no Session.
+func (rq *Request) onceDo(fn func(ok *onceKeys)) {
+	if sess := rq.Session(); sess != nil {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		fn(&sess.once)
+	} else {
+		rq.mu.Lock()
+		defer rq.mu.Unlock()
+		fn(&rq.once)
+	}
+}
+
+// onceAttrs sets the idempotency action for the Element and returns the HTML attributes for it.
+func (e *Element) onceAttrs(action Idempotent) []string {
+	var done string
+	e.Request.onceDo(func(ok *onceKeys) { done = ok.last(string(action)) })
+	e.Request.mu.Lock()
+	e.once = string(action)
+	e.Request.mu.Unlock()
+	return []string{
+		`data-jaws-once="` + html.EscapeString(string(action)) + `"`,
+		`data-jaws-once-done="` + html.EscapeString(done) + `"`,
+	}
+}
+
+// checkOnce returns true if the Element doesn't use Idempotent, or if
+// key hasn't been seen before for the Element's action.
+func (rq *Request) checkOnce(e *Element, key string) (claimed bool) {
+	rq.mu.RLock()
+	action := e.once
+	rq.mu.RUnlock()
+	if action == "" {
+		return true
+	}
+	if key == "" {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w", e, ErrIdempotencyKeyMissing))
+		return false
+	}
+	rq.onceDo(func(ok *onceKeys) { claimed = ok.claim(action, key) })
+	rq.Jaws.SetAttr(e, "data-jaws-once-done", key)
+	return
+}
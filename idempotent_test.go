@@ -0,0 +1,71 @@
+package jaws
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_onceKeys(t *testing.T) {
+	th := newTestHelper(t)
+	var ok onceKeys
+	th.Equal(ok.last("a"), "")
+	th.True(ok.claim("a", "1"))
+	th.True(ok.claim("b", "1"))
+	th.True(!ok.claim("a", "1"))
+	th.True(ok.claim("a", "2"))
+	th.Equal(ok.last("a"), "2")
+	th.Equal(ok.last("b"), "1")
+	for i := 0; i < maxOnceKeys; i++ {
+		th.True(ok.claim("c", strconv.Itoa(i)))
+	}
+	th.Equal(len(ok.keys), maxOnceKeys)
+	th.Equal(ok.last("a"), "")
+	th.True(ok.claim("a", "1"))
+}
+
+func TestRequest_Idempotent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tjc := &testJawsClick{
+		clickCh:    make(chan string),
+		testSetter: newTestSetter(""),
+	}
+	th.NoErr(rq.Button("Pay", tjc, Idempotent("pay")))
+	th.Equal(rq.BodyString(), `<button id="Jid.1" type="button" data-jaws-once="pay" data-jaws-once-done="">Pay</button>`)
+
+	// clicks without a key or addressed directly are ignored
+	rq.inCh <- wsMsg{Data: "NoKey\tJid.1", What: what.Click}
+	rq.inCh <- wsMsg{Data: "Direct", Jid: 1, What: what.Click}
+	rq.inCh <- wsMsg{Data: "First\tJid.1!k1", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case name := <-tjc.clickCh:
+		th.Equal(name, "First")
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "SAttr\tJid.1\t\"data-jaws-once-done\\nk1\"\n")
+	}
+
+	// retries are acknowledged but ignored
+	rq.inCh <- wsMsg{Data: "Retry\tJid.1!k1", What: what.Click}
+	rq.inCh <- wsMsg{Data: "Second\tJid.1!k2", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case name := <-tjc.clickCh:
+		th.Equal(name, "Second")
+	}
+
+	rq.mu.RLock()
+	th.Equal(rq.once.last("pay"), "k2")
+	rq.mu.RUnlock()
+}
@@ -0,0 +1,31 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// ErrNotInjectable is returned by InjectEvent for event types other than Input and Click.
+var ErrNotInjectable = errors.New("event type can't be injected")
+
+// InjectEvent queues an event for the Elements in the Request that have the
+// target tag(s), as if the browser had sent it. The event is handled the same
+// way as one from the browser, so handler errors are shown as alerts.
+//
+// The event is handled asynchronously after the WebSocket connects. Only
+// what.Input and what.Click events can be injected. Returns the number of
+// Elements the event was queued for.
+func (rq *Request) InjectEvent(target interface{}, wht what.What, val string) (n int, err error) {
+	switch wht {
+	case what.Input, what.Click:
+		for _, e := range rq.GetElements(target) {
+			rq.Jaws.Broadcast(Message{Dest: e, What: wht, Data: val})
+			n++
+		}
+	default:
+		err = fmt.Errorf("jaws: %v: %w: %v", rq, ErrNotInjectable, wht)
+	}
+	return
+}
@@ -0,0 +1,49 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_InjectEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tss := newTestSetter("")
+	th.NoErr(rq.Text(tss, Tag("name")))
+	th.NoErr(rq.Button("save", Tag("save"), EventFn(func(e *Element, wht what.What, val string) error {
+		return errors.New("save failed: " + val)
+	})))
+
+	n, err := rq.InjectEvent(Tag("name"), what.Input, "alice")
+	th.NoErr(err)
+	th.Equal(n, 1)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-tss.setCalled:
+	}
+	th.Equal(tss.Get(), "alice")
+
+	n, err = rq.InjectEvent(Tag("save"), what.Click, "now")
+	th.NoErr(err)
+	th.Equal(n, 1)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.True(strings.HasPrefix(s, "Alert\t\t\"danger\\nsave failed: now"))
+	}
+
+	n, err = rq.InjectEvent(Tag("missing"), what.Click, "")
+	th.NoErr(err)
+	th.Equal(n, 0)
+
+	_, err = rq.InjectEvent(Tag("name"), what.Inner, "x")
+	th.True(errors.Is(err, ErrNotInjectable))
+}
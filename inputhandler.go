@@ -0,0 +1,17 @@
+package jaws
+
+import "github.com/linkdata/jaws/what"
+
+// InputHandler receives the new value when the user changes an input Element.
+type InputHandler interface {
+	JawsInput(e *Element, val string) (err error)
+}
+
+type inputHandlerWrapper struct{ InputHandler }
+
+func (ihw inputHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Input {
+		return ihw.JawsInput(e, v)
+	}
+	return ErrEventUnhandled
+}
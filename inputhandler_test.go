@@ -0,0 +1,59 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJawsInput struct {
+	vals []string
+	err  error
+}
+
+func (tji *testJawsInput) JawsInput(e *Element, val string) error {
+	tji.vals = append(tji.vals, val)
+	return tji.err
+}
+
+var _ InputHandler = (*testJawsInput)(nil)
+
+func Test_inputHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	tji := &testJawsInput{}
+	th.NoErr(rq.Div("inner", tji))
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "x"))
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "foo"))
+	th.Equal(tji.vals, []string{"foo"})
+
+	tji.err = errors.New("bad")
+	th.Equal(rq.callAllEventHandlers(1, what.Input, "bar"), tji.err)
+}
+
+type testInputUi struct {
+	UiHtml
+	testJawsInput
+}
+
+func TestUiHtml_JawsEventInputHandler(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	ui := &testInputUi{}
+	ui.Tag = &ui.testJawsInput
+	elem := rq.NewElement(ui)
+	th.NoErr(ui.JawsEvent(elem, what.Input, "foo"))
+	th.Equal(ui.vals, []string{"foo"})
+	th.Equal(ui.JawsEvent(elem, what.Click, "foo"), ErrEventUnhandled)
+}
@@ -0,0 +1,72 @@
+package jaws
+
+import (
+	"html/template"
+	"sync/atomic"
+
+	"github.com/linkdata/deadlock"
+)
+
+// maxInternedAttrs is the number of distinct attribute strings that may be
+// interned. Once reached, strings not used recently are evicted one at a time.
+const maxInternedAttrs = 4096
+
+type internEntry struct {
+	s    template.HTMLAttr
+	used atomic.Bool // used since the eviction hand last passed it
+}
+
+var attrIntern struct {
+	mu   deadlock.RWMutex // protects following
+	m    map[string]*internEntry
+	ring []string // interned strings, in the order the eviction hand visits them
+	hand int      // next position in ring to consider for eviction
+}
+
+// internAttrs returns the attributes joined with leading spaces.
+//
+// The result is interned, so rendering the same attributes again
+// returns the same string without allocating it.
+func internAttrs(attrs []string) (s template.HTMLAttr) {
+	if len(attrs) > 0 {
+		var buf [256]byte
+		b := appendAttrs(buf[:0], attrs)
+		attrIntern.mu.RLock()
+		e := attrIntern.m[string(b)]
+		attrIntern.mu.RUnlock()
+		if e == nil {
+			attrIntern.mu.Lock()
+			if e = attrIntern.m[string(b)]; e == nil {
+				e = &internEntry{s: template.HTMLAttr(b)} // #nosec G203
+				internLocked(string(e.s), e)
+			}
+			attrIntern.mu.Unlock()
+		}
+		e.used.Store(true)
+		s = e.s
+	}
+	return
+}
+
+// internLocked adds e to the interned strings, evicting the first one
+// the hand finds that hasn't been used since it last passed it.
+func internLocked(key string, e *internEntry) {
+	if attrIntern.m == nil {
+		attrIntern.m = make(map[string]*internEntry)
+	}
+	if len(attrIntern.ring) < maxInternedAttrs {
+		attrIntern.ring = append(attrIntern.ring, key)
+	} else {
+		for {
+			old := attrIntern.ring[attrIntern.hand]
+			if !attrIntern.m[old].used.Swap(false) {
+				delete(attrIntern.m, old)
+				attrIntern.ring[attrIntern.hand] = key
+				attrIntern.hand = (attrIntern.hand + 1) % len(attrIntern.ring)
+				break
+			}
+			attrIntern.hand = (attrIntern.hand + 1) % len(attrIntern.ring)
+		}
+	}
+	attrIntern.m[key] = e
+}
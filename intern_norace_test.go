@@ -0,0 +1,19 @@
+//go:build !race
+
+package jaws
+
+import (
+	"testing"
+)
+
+// The race detector and deadlock detection allocate, so this is only
+// checked in normal builds.
+func Test_internAttrs_NoAllocs(t *testing.T) {
+	th := newTestHelper(t)
+	attrs := []string{`class="row"`, "", `hidden`}
+	internAttrs(attrs)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = internAttrs(attrs)
+	})
+	th.Equal(allocs, 0.0)
+}
@@ -0,0 +1,36 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+)
+
+func Test_internAttrs(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(internAttrs(nil), template.HTMLAttr(""))
+	th.Equal(internAttrs([]string{"", ""}), template.HTMLAttr(""))
+	attrs := []string{`class="row"`, "", `hidden`}
+	th.Equal(internAttrs(attrs), template.HTMLAttr(` class="row" hidden`))
+	th.Equal(internAttrs([]string{`class="row"`, `hidden`}), template.HTMLAttr(` class="row" hidden`))
+}
+
+func Test_internAttrs_Limit(t *testing.T) {
+	th := newTestHelper(t)
+	attr := func(i int) []string {
+		return []string{string(rune('a'+i%26)) + string(rune(0x100+i))}
+	}
+	keep := []string{"keep"}
+	internAttrs(keep)
+	for i := 0; i <= maxInternedAttrs*2; i++ {
+		internAttrs(attr(i))
+		internAttrs(keep)
+	}
+	attrIntern.mu.RLock()
+	n := len(attrIntern.m)
+	_, kept := attrIntern.m[" keep"]
+	_, evicted := attrIntern.m[" "+attr(0)[0]]
+	attrIntern.mu.RUnlock()
+	th.Equal(n, maxInternedAttrs)
+	th.True(kept)
+	th.Equal(evicted, false)
+}
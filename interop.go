@@ -0,0 +1,35 @@
+package jaws
+
+import (
+	"strings"
+)
+
+// Alpine is a parameter that makes the Element an Alpine.js component
+// with the given x-data expression.
+//
+//	rw.Div(content, jaws.Alpine("{ open: false }"))
+//
+// Updates from JaWS, such as Element.SetAttr or Element.Replace, leave Alpine's
+// directives ("x-*", ":*" and "@*" attributes) and the style attribute alone.
+type Alpine string
+
+// Stimulus is a parameter that connects the Element to the Stimulus
+// controllers with the given space-separated identifiers.
+//
+//	rw.Div(content, jaws.Stimulus("clipboard"))
+//
+// Updates from JaWS, such as Element.SetAttr or Element.Replace, leave the
+// "data-controller", "data-action" and "data-<identifier>-*" attributes alone.
+type Stimulus string
+
+// alpinePreserve are the attributes Alpine.js manages.
+var alpinePreserve = []string{"x-*", ":*", "@*", "style"}
+
+// stimulusPreserve returns the attributes Stimulus manages for the controllers.
+func stimulusPreserve(identifiers string) (preserve []string) {
+	preserve = append(preserve, "data-controller", "data-action")
+	for _, id := range strings.Fields(identifiers) {
+		preserve = append(preserve, "data-"+id+"-*")
+	}
+	return
+}
@@ -0,0 +1,26 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestRequest_Alpine(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Div("x", Alpine("{ open: false }")))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" x-data="{ open: false }" data-jaws-preserve="x-* :* @* style">x</div>`)
+}
+
+func TestRequest_Stimulus(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Div("x", Stimulus("clipboard  toggle"), Alpine("{}")))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-controller="clipboard  toggle" x-data="{}" `+
+		`data-jaws-preserve="data-controller data-action data-clipboard-* data-toggle-* x-* :* @* style">x</div>`)
+}
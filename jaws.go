@@ -39,42 +39,133 @@ const (
 type Jid = jid.Jid // convenience alias
 
 type Jaws struct {
-	CookieName   string             // Name for session cookies, defaults to "jaws"
-	Logger       *log.Logger        // If not nil, send debug info and errors here
-	Template     *template.Template // User templates in use, may be nil
-	Debug        bool               // set to true to enable debugging output
-	doneCh       <-chan struct{}
-	bcastCh      chan Message
-	subCh        chan subscription
-	unsubCh      chan chan Message
-	updateTicker *time.Ticker
-	headPrefix   string
-	reqPool      sync.Pool
-	mu           deadlock.RWMutex // protects following
-	kg           *bufio.Reader
-	closeCh      chan struct{}
-	requests     map[uint64]*Request
-	sessions     map[uint64]*Session
-	dirty        map[interface{}]int
-	dirtOrder    int
-}
+	BasePath        string             // Path prefix the application is served under behind a proxy, such as "/app"
+	BaseURL         string             // If not empty, the scheme and host browsers use to reach JaWS, such as "https://example.com"
+	WebSocketURL    string             // If not empty, the URL prefix browsers connect the WebSocket to, such as "wss://rt.example.com"
+	CookieName      string             // Name for session cookies, defaults to "jaws"
+	CookiePath      string             // Path for session cookies, defaults to "/"
+	CookieDomain    string             // Domain for session cookies, if not empty
+	CookieSecure    bool               // Secure attribute for session cookies, defaults to true
+	CookieSameSite  http.SameSite      // SameSite attribute for session cookies, defaults to http.SameSiteLaxMode
+	CookieMaxAge    int                // If positive, session cookies persist for this many seconds instead of until the browser closes
+	CookieDeferred  bool               // If true, NewSession doesn't set the session cookie, call Session.SetCookie to do so
+	SessionMaxKeys  int                // If positive, the maximum number of values a Session may hold
+	SessionMaxBytes int                // If positive, the maximum approximate total size of the values a Session may hold
+	SessionEvict    bool               // If true, exceeding the session quotas evicts the least recently set values instead of rejecting new ones
+	TokenValidator  TokenValidator     // If not nil, validates the bearer token sent when a Request's WebSocket connects
+	Logger          *log.Logger        // If not nil, send debug info and errors here
+	Template        *template.Template // User templates in use, may be nil
+	Debug           bool               // set to true to enable debugging output
+	MaxPasteSize    int                // Maximum size of pasted or dropped data being received for a Request, defaults to DefaultMaxPasteSize
+	MaxMessageSize  int                // Largest message accepted from a browser, larger ones close the connection, defaults to DefaultMaxMessageSize
+	MaxEventRate    int                // If positive, the most messages per second read from a browser, further ones wait
+	ForceReload     bool               // If true, reload pages when the asset version changes instead of alerting
+	Flags           FlagProvider       // If not nil, provides the feature flags used by IfFlag
+	Principal       PrincipalProvider  // If not nil, provides the authenticated principal of Requests, see Request.Principal
+	Translator      Translator         // If not nil, translates text for Request.Translate
+	ErrorReporter   ErrorReporter      // If not nil, called when an event handler returns an error or panics
+	EventTimeout    time.Duration      // If nonzero, the deadline of Element.Context() while handling an event
+	RenderTimeout   time.Duration      // If nonzero, Elements taking longer than this to render are replaced with RenderFallback
+	RenderFallback  template.HTML      // HTML written in place of Elements exceeding RenderTimeout, defaults to DefaultRenderFallback
+	PendingText     string             // Value of the "data-jaws-pending" attribute while a setter is Pending, defaults to DefaultPendingText
+	EventWorkers    int                // If positive, event handlers for all Requests run on this many shared goroutines instead of one per Request
+	MessageTTL      time.Duration      // If nonzero, broadcast Inner, Value and SAttr messages older than this are dropped when a newer one is queued
+	WebSocket       WebSocketTransport // If not nil, accepts WebSocket connections instead of nhooyr.io/websocket, required if built with the jaws_nonhooyr tag
+	ReconnectWindow time.Duration      // If nonzero, how long a Request whose WebSocket dropped waits for the browser to reconnect
+	ReconnectDelay  time.Duration      // Delay before the browser first tries to reconnect, doubling each attempt, defaults to DefaultReconnectDelay
+	ReconnectMax    time.Duration      // Maximum delay between the browser's reconnection attempts, defaults to DefaultReconnectMax
+	PingInterval    time.Duration      // If nonzero, how often WebSocket connections are pinged to check that the browser is alive
+	PingTimeout     time.Duration      // How long to wait for the browser to answer a ping before closing the connection, defaults to DefaultPingTimeout
+	CompressMinSize int                // Messages and long-polling responses smaller than this many bytes are sent uncompressed, defaults to DefaultCompressMinSize, negative disables compression
+	CompressContext bool               // If true, WebSocket compression keeps context between messages, compressing better but using more memory
+	FlushInterval   time.Duration      // If nonzero, how long to collect outbound messages to send them in a single WebSocket message
+	FlushMax        time.Duration      // If greater than FlushInterval, slow connections collect messages for up to this long, based on how long their writes take
+	ParkWindow      time.Duration      // If nonzero, how long alerts and other one-shot messages for a disconnected Request are kept for when it reconnects, see ReconnectWindow
+	ParkLimit       int                // Maximum number of messages kept per Session for ParkWindow, defaults to DefaultParkLimit
+	Overflow        OverflowPolicy     // What a Request does when it's broadcast, outbound message or event queue is full, defaults to OverflowDisconnect
+	OverflowWait    time.Duration      // How long OverflowBlock waits for room, stalling the Request meanwhile, defaults to DefaultOverflowWait
+	SendRate        int                // If positive, the most WebSocket messages written to a Request per second, messages queued meanwhile are batched
+	SendBurst       int                // How many WebSocket messages may be written at once before SendRate applies, defaults to SendRate
+	FlowCredits     int                // If positive, browsers that support it may grant this many WebSocket messages at a time, replenishing them as they apply them
+	Tabs            TabPolicy          // What happens when a Session has several browser tabs showing the same view, defaults to TabsAllow
+	TabsText        string             // Banner shown in tabs that can't be used because of Tabs, defaults to DefaultTabsText
+	MirrorText      string             // Banner shown in the browser of a Request mirroring another, defaults to DefaultMirrorText
+	TrackTags       bool               // If true, record how often tags are dirtied and how many Elements they update, see TagStats
+	ProfileRender   bool               // If true, record the time spent rendering and updating Elements, see RenderStats
+	LongPoll        bool               // If true, browsers connect using HTTP long-polling instead of a WebSocket
+	PollInterval    time.Duration      // How long a long-polling request waits for messages, defaults to DefaultPollInterval
+	RandomJids      bool               // If true, Jids are hard to guess and events for forged ones are logged
+	StrictEvents    bool               // If true, log input events that no handler consumed, and alert them if Debug is set
+	Audit           bool               // If true, the browser warns about inputs rendered without labels
+	KeyboardClicks  bool               // If true, elements other than links, buttons and inputs that handle clicks get role="button" and tabindex="0" so they work with the keyboard
+	AlertRenderer   AlertRenderer      // If not nil, renders alerts instead of the browser using Bootstrap
+	AlertDedup      time.Duration      // If nonzero, alerts identical to one shown to the Request within this duration are suppressed
+	AlertHistory    int                // Number of alerts each Request retains for AlertLog, defaults to DefaultAlertHistory
+	Redaction       RedactPolicy       // Selects Elements whose values are replaced with RedactedText in logs and error reports
+	doneCh          <-chan struct{}
+	bcastCh         chan Message
+	subCh           chan subscription
+	unsubCh         chan chan Message
+	updateTicker    *time.Ticker
+	headPrefix      string
+	headLinks       []string // Link header values preloading the assets in headPrefix
+	reqPool         sync.Pool
+	mu              deadlock.RWMutex // protects following
+	kg              *bufio.Reader
+	keyNs           uint64 // namespace bits of Request keys and session IDs, set by NewGroup
+	keyNsBits       int    // number of namespace bits in keyNs
+	closeCh         chan struct{}
+	requests        map[uint64]*Request
+	sessions        map[uint64]*Session
+	dirty           map[interface{}]int
+	dirtOrder       int
+	dirtied         map[interface{}]struct{}  // tags dirtied while Debug is set, at most maxDirtiedTags
+	dirtiedRing     []interface{}             // the tags in dirtied, in the order they were added
+	dirtiedNext     int                       // position in dirtiedRing of the oldest tag once it's full
+	tagStats        map[interface{}]*TagStats // statistics of tags dirtied while TrackTags is set
+	renderStats     map[profKey]*RenderStats  // time spent rendering while ProfileRender is set
+	handoffs        map[string]handoff        // unused session handoff tokens, see Session.HandoffToken
+	assetVersion    string
+	experiments     map[string]*Experiment
+	memos           []*MemoGetter
+	widgets         map[string]WidgetFn
+	eventPool       *eventPool
+	priority        atomic.Pointer[prioritySet]
+	sessRejected    atomic.Uint64
+	sessEvicted     atomic.Uint64
+	serveBeat       atomic.Int64 // UnixNano when the Serve loop last completed an iteration, or zero
+	draining        atomic.Bool  // set by Drain
+	wsConns         atomic.Uint64
+	wsDeflated      atomic.Uint64
+	wsTotal         wsCounters // traffic of all WebSocket connections
+}
+
+// NewVersionAlert is the alert message shown by SetAssetVersion unless Jaws.ForceReload is set.
+const NewVersionAlert = `A new version is available. <a href="javascript:window.location.reload()">Reload</a>`
 
 // NewWithDone returns a new JaWS object using the given completion channel.
 // This is expected to be created once per HTTP server and handles
 // publishing HTML changes across all connections.
 func NewWithDone(doneCh <-chan struct{}) (jw *Jaws) {
 	jw = &Jaws{
-		CookieName:   DefaultCookieName,
-		doneCh:       doneCh,
-		bcastCh:      make(chan Message, 1),
-		subCh:        make(chan subscription, 1),
-		unsubCh:      make(chan chan Message, 1),
-		updateTicker: time.NewTicker(DefaultUpdateInterval),
-		headPrefix:   HeadHTML([]string{JavascriptPath}, nil),
-		kg:           bufio.NewReader(rand.Reader),
-		requests:     make(map[uint64]*Request),
-		sessions:     make(map[uint64]*Session),
-		dirty:        make(map[interface{}]int),
+		CookieName:     DefaultCookieName,
+		CookiePath:     "/",
+		CookieSecure:   true,
+		CookieSameSite: http.SameSiteLaxMode,
+		MaxPasteSize:   DefaultMaxPasteSize,
+		PendingText:    DefaultPendingText,
+		RenderFallback: DefaultRenderFallback,
+		doneCh:         doneCh,
+		bcastCh:        make(chan Message, 1),
+		subCh:          make(chan subscription, 1),
+		unsubCh:        make(chan chan Message, 1),
+		updateTicker:   time.NewTicker(DefaultUpdateInterval),
+		headPrefix:     HeadHTML([]string{JavascriptPath}, nil),
+		headLinks:      preloadLinks([]string{JavascriptPath}, nil),
+		kg:             bufio.NewReader(rand.Reader),
+		requests:       make(map[uint64]*Request),
+		sessions:       make(map[uint64]*Session),
+		dirty:          make(map[interface{}]int),
 	}
 	jw.reqPool.New = func() any {
 		return (&Request{
@@ -164,6 +255,7 @@ func MakeID() string {
 }
 
 // NewRequest returns a new pending JaWS request that times out after 10 seconds.
+// Use NewRequestWithOptions to override Jaws settings for the Request.
 //
 // Call this as soon as you start processing a HTML request, and store the
 // returned Request pointer so it can be used while constructing the HTML
@@ -173,7 +265,7 @@ func (jw *Jaws) NewRequest(hr *http.Request) (rq *Request) {
 	jw.mu.Lock()
 	defer jw.mu.Unlock()
 	for rq == nil {
-		jawsKey := jw.nonZeroRandomLocked()
+		jawsKey := jw.newKeyLocked()
 		if _, ok := jw.requests[jawsKey]; !ok {
 			rq = jw.getRequestLocked(jawsKey, hr)
 			jw.requests[jawsKey] = rq
@@ -240,7 +332,7 @@ func (jw *Jaws) Sessions() (sl []*Session) {
 
 func (jw *Jaws) getSessionLocked(sessIds []uint64, remoteIP netip.Addr) *Session {
 	for _, sessId := range sessIds {
-		if sess, ok := jw.sessions[sessId]; ok && equalIP(remoteIP, sess.remoteIP) {
+		if sess, ok := jw.sessions[sessId]; ok && sess.allowsIP(remoteIP) {
 			return sess
 		}
 	}
@@ -296,6 +388,9 @@ func (jw *Jaws) GetSession(hr *http.Request) (sess *Session) {
 //
 // Subsequent Requests created with `NewRequest()` that have the cookie set and
 // originates from the same IP will be able to access the Session.
+//
+// If Jaws.CookieDeferred is set, the cookie is not set in w. Call Session.SetCookie
+// when it may be, such as after the user consents to cookies.
 func (jw *Jaws) NewSession(w http.ResponseWriter, hr *http.Request) (sess *Session) {
 	if oldSess := jw.GetSession(hr); oldSess != nil {
 		oldSess.Clear()
@@ -305,11 +400,11 @@ func (jw *Jaws) NewSession(w http.ResponseWriter, hr *http.Request) (sess *Sessi
 	jw.mu.Lock()
 	defer jw.mu.Unlock()
 	for sess == nil {
-		sessionID := jw.nonZeroRandomLocked()
+		sessionID := jw.newKeyLocked()
 		if _, ok := jw.sessions[sessionID]; !ok {
 			sess = newSession(jw, sessionID, parseIP(hr.RemoteAddr))
 			jw.sessions[sessionID] = sess
-			if w != nil {
+			if w != nil && !jw.CookieDeferred {
 				http.SetCookie(w, &sess.cookie)
 			}
 			hr.AddCookie(&sess.cookie)
@@ -327,7 +422,8 @@ func (jw *Jaws) deleteSession(sessionID uint64) {
 // GenerateHeadHTML (re-)generates the HTML code that goes in the HEAD section, ensuring
 // that the provided scripts and stylesheets in `extra` are loaded.
 //
-// You only need to call this if you want to add your own scripts and stylesheets.
+// You only need to call this if you want to add your own scripts and stylesheets,
+// or have changed BasePath, BaseURL or WebSocketURL.
 func (jw *Jaws) GenerateHeadHTML(extra ...string) error {
 	var js, css []string
 	addedJaws := false
@@ -345,15 +441,31 @@ func (jw *Jaws) GenerateHeadHTML(extra ...string) error {
 			return err
 		}
 	}
+	base := jw.baseURL()
 	if !addedJaws {
-		js = append(js, JavascriptPath)
+		js = append(js, base+JavascriptPath)
+	}
+	jw.headPrefix = HeadHTML(js, css) + `<script>`
+	if base != "" {
+		jw.headPrefix += `var jawsBase="` + template.JSEscapeString(base) + `";`
+	}
+	if wsURL := strings.TrimSuffix(jw.WebSocketURL, "/"); wsURL != "" {
+		jw.headPrefix += `var jawsWsBase="` + template.JSEscapeString(wsURL) + `";`
 	}
-	jw.headPrefix = HeadHTML(js, css) + `<script>var jawsKey="`
+	jw.headPrefix += `var jawsKey="`
+	jw.headLinks = preloadLinks(js, css)
 	return nil
 }
 
 // Broadcast sends a message to all Requests.
+//
+// If MessageTTL is set, Inner, Value and SAttr messages that have waited
+// longer than that to be processed by a Request are dropped if a message
+// setting the same state for the same destination is queued after them.
 func (jw *Jaws) Broadcast(msg Message) {
+	if jw.MessageTTL > 0 {
+		msg.sent = time.Now()
+	}
 	select {
 	case <-jw.Done():
 	case jw.bcastCh <- msg:
@@ -368,6 +480,15 @@ func (jw *Jaws) setDirty(tags []any) {
 		jw.dirtOrder++
 		jw.dirty[tag] = jw.dirtOrder
 	}
+	if jw.Debug {
+		jw.recordDirtiedLocked(tags)
+	}
+	if jw.TrackTags {
+		jw.recordDirtyLocked(tags)
+	}
+	for _, m := range jw.memos {
+		m.invalidateIf(tags)
+	}
 }
 
 // Dirty marks all Elements that have one or more of the given tags as dirty.
@@ -410,10 +531,45 @@ func (jw *Jaws) distributeDirt() int {
 		for _, rq := range reqs {
 			rq.appendDirtyTags(tags)
 		}
+		if jw.TrackTags {
+			jw.recordFanout(tags, reqs)
+		}
 	}
 	return len(dirt)
 }
 
+// AssetVersion returns the current asset version as set by SetAssetVersion.
+func (jw *Jaws) AssetVersion() (v string) {
+	jw.mu.RLock()
+	v = jw.assetVersion
+	jw.mu.RUnlock()
+	return
+}
+
+// SetAssetVersion sets the version of the assets and templates used to render pages,
+// typically a build identifier or content hash.
+//
+// If the version changes, connected Requests are either reloaded (if ForceReload is set)
+// or shown the NewVersionAlert. Requests that were rendered with a different version are
+// notified the same way when their WebSocket connects.
+func (jw *Jaws) SetAssetVersion(v string) {
+	jw.mu.Lock()
+	changed := jw.assetVersion != v
+	jw.assetVersion = v
+	jw.mu.Unlock()
+	if changed {
+		jw.newVersion(nil)
+	}
+}
+
+func (jw *Jaws) newVersion(dest interface{}) {
+	if jw.ForceReload {
+		jw.Broadcast(Message{Dest: dest, What: what.Reload})
+	} else {
+		jw.Broadcast(Message{Dest: dest, What: what.Alert, Data: "info\n" + NewVersionAlert})
+	}
+}
+
 // Reload requests all Requests to reload their current page.
 func (jw *Jaws) Reload() {
 	jw.Broadcast(Message{
@@ -465,35 +621,55 @@ func (jw *Jaws) ServeWithTimeout(requestTimeout time.Duration) {
 	}
 	t := time.NewTicker(maintenanceInterval)
 	defer t.Stop()
-	subs := map[chan Message]*Request{}
+	subs := map[chan Message]*subscription{}
+	backlogged := map[chan Message]*subscription{}
 
 	killSub := func(msgCh chan Message) {
 		if _, ok := subs[msgCh]; ok {
 			delete(subs, msgCh)
+			delete(backlogged, msgCh)
 			close(msgCh)
 		}
 	}
 
 	// it's critical that we keep the broadcast
-	// distribution loop running, so any Request
-	// that fails to process it's messages quickly
-	// enough must be terminated. the alternative
-	// would be to drop some messages, but that
-	// could mean nonreproducible and seemingly
-	// random failures in processing logic.
+	// distribution loop running, so it never waits
+	// for a Request that fails to process it's
+	// messages quickly enough. what happens to it
+	// is up to Jaws.Overflow. by default it is
+	// terminated, since dropping messages could mean
+	// nonreproducible and seemingly random failures
+	// in processing logic.
 	mustBroadcast := func(msg Message) {
-		for msgCh, rq := range subs {
-			if msg.Dest == nil || rq.wantMessage(&msg) {
-				select {
-				case msgCh <- msg:
-				default:
+		now := time.Now()
+		for msgCh, sub := range subs {
+			if msg.Dest == nil || sub.rq.wantMessage(&msg) {
+				if !sub.send(msg, jw.Overflow, now) {
 					killSub(msgCh)
-					rq.cancel(fmt.Errorf("%v: broadcast channel full sending %s", rq, msg.String()))
+					sub.rq.cancel(fmt.Errorf("%v: broadcast channel full sending %s", sub.rq, msg.String()))
+				} else if len(sub.backlog) > 0 {
+					backlogged[msgCh] = sub
 				}
 			}
 		}
 	}
 
+	// send what we can of the backlogs, giving up
+	// on Requests that OverflowBlock has waited for
+	flushBacklogs := func() {
+		now := time.Now()
+		for msgCh, sub := range backlogged {
+			if !sub.flush(jw.Overflow, jw.overflowWait(), now) {
+				killSub(msgCh)
+				sub.rq.cancel(fmt.Errorf("%v: broadcast channel full for %v", sub.rq, jw.overflowWait()))
+			} else if len(sub.backlog) == 0 {
+				delete(backlogged, msgCh)
+			}
+		}
+	}
+
+	jw.serveBeat.Store(time.Now().UnixNano())
+	defer jw.serveBeat.Store(0)
 	for {
 		select {
 		case <-jw.Done():
@@ -506,7 +682,7 @@ func (jw *Jaws) ServeWithTimeout(requestTimeout time.Duration) {
 			jw.maintenance(requestTimeout)
 		case sub := <-jw.subCh:
 			if sub.msgCh != nil {
-				subs[sub.msgCh] = sub.rq
+				subs[sub.msgCh] = &sub
 			}
 		case msgCh := <-jw.unsubCh:
 			killSub(msgCh)
@@ -515,6 +691,10 @@ func (jw *Jaws) ServeWithTimeout(requestTimeout time.Duration) {
 				mustBroadcast(msg)
 			}
 		}
+		if len(backlogged) > 0 {
+			flushBacklogs()
+		}
+		jw.serveBeat.Store(time.Now().UnixNano())
 	}
 }
 
@@ -555,6 +735,7 @@ func (jw *Jaws) maintenance(requestTimeout time.Duration) {
 			delete(jw.sessions, k)
 		}
 	}
+	jw.expireHandoffsLocked(time.Now())
 }
 
 func equalIP(a, b netip.Addr) bool {
@@ -684,7 +865,12 @@ func (jw *Jaws) getRequestLocked(jawsKey uint64, hr *http.Request) (rq *Request)
 	rq.JawsKey = jawsKey
 	rq.Created = time.Now()
 	rq.Initial = hr
+	rq.assetVersion = jw.assetVersion
 	rq.ctx, rq.cancelFn = context.WithCancelCause(context.Background())
+	if jw.RandomJids {
+		rq.jids = make(map[Jid]*Element)
+		rq.jidMix = newJidMixer(jw.nonZeroRandomLocked(), jw.nonZeroRandomLocked())
+	}
 	if hr != nil {
 		rq.remoteIP = parseIP(hr.RemoteAddr)
 		if sess := jw.getSessionLocked(getCookieSessionsIds(hr.Header, jw.CookieName), rq.remoteIP); sess != nil {
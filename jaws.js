@@ -2,6 +2,9 @@
 
 var jaws = null;
 
+// jawsProtocolVersion must match ProtocolVersion in protocol.go.
+var jawsProtocolVersion = 2;
+
 function jawsContains(a, v) {
 	return a.indexOf(String(v).trim().toLowerCase()) !== -1;
 }
@@ -23,10 +26,26 @@ function jawsIsTrue(v) {
 }
 
 function jawsClickHandler(e) {
-	if (jaws instanceof WebSocket && e instanceof Event) {
+	if (jawsLive() && e instanceof Event) {
 		e.stopPropagation();
 		var elem = e.target;
+		if (elem.dataset.jawsCopy !== undefined) {
+			e.preventDefault();
+			if (navigator.clipboard) {
+				navigator.clipboard.writeText(elem.dataset.jawsCopy);
+			}
+			return;
+		}
+		var conf = elem.closest('[data-jaws-confirm]');
+		if (conf != null && !window.confirm(conf.dataset.jawsConfirm)) {
+			e.preventDefault();
+			return;
+		}
 		var val = elem.getAttribute('name');
+		var item = elem.closest('[data-jaws-item]');
+		if (val == null && item != null) {
+			val = item.dataset.jawsItem;
+		}
 		if (val == null) {
 			if (elem.tagName.toLowerCase() === 'button') {
 				val = elem.innerHTML;
@@ -39,6 +58,12 @@ function jawsClickHandler(e) {
 		while (elem != null) {
 			if (elem.id.startsWith('Jid.') && !jawsIsInputTag(elem.tagName)) {
 				val += "\t" + elem.id;
+				if (elem.dataset.jawsConfirmToken !== undefined) {
+					val += ":" + elem.dataset.jawsConfirmToken;
+				}
+				if (elem.dataset.jawsOnce !== undefined) {
+					val += "!" + jawsOnceKey(elem);
+				}
 			}
 			elem = elem.parentElement;
 		}
@@ -46,19 +71,250 @@ function jawsClickHandler(e) {
 	}
 }
 
+// returns the idempotency key for the element's action, reusing
+// the previous key until the server has acknowledged it.
+function jawsOnceKey(elem) {
+	var action = 'jaws-once:' + elem.dataset.jawsOnce;
+	var key = sessionStorage.getItem(action);
+	if (key == null || key === elem.dataset.jawsOnceDone) {
+		key = Date.now().toString(36) + '-' + Math.random().toString(36).substring(2);
+		sessionStorage.setItem(action, key);
+	}
+	return key;
+}
+
+function jawsInputValue(elem) {
+	if (jawsIsCheckable(elem.getAttribute('type'))) {
+		return elem.checked;
+	}
+	if (elem.tagName.toLowerCase() === 'option') {
+		return elem.selected;
+	}
+	return elem.value;
+}
+
+function jawsSendInput(elem) {
+	if (jawsLive()) {
+		jaws.send("Input\t" + elem.id + "\t" + JSON.stringify(jawsInputValue(elem)) + "\n");
+	}
+}
+
+function jawsChangeHandler(e) {
+	if (jawsLive() && e instanceof Event) {
+		e.stopPropagation();
+		var elem = e.currentTarget;
+		jaws.send("Change\t" + elem.id + "\t" + JSON.stringify(jawsInputValue(elem)) + "\n");
+	}
+}
+
+// jawsKeyName returns the key of e prefixed with the modifiers held,
+// matching the KeyHandler documentation in keyhandler.go.
+function jawsKeyName(e) {
+	return (e.ctrlKey ? 'Ctrl+' : '') + (e.altKey ? 'Alt+' : '') +
+		(e.shiftKey && e.key.length > 1 ? 'Shift+' : '') + (e.metaKey ? 'Meta+' : '') + e.key;
+}
+
+function jawsKeysHandler(e) {
+	if (jawsLive() && e instanceof KeyboardEvent && e.key && !e.isComposing && !jawsContains(['control', 'alt', 'shift', 'meta'], e.key)) {
+		var elem = e.currentTarget;
+		var name = jawsKeyName(e);
+		var keys = elem.dataset.jawsKeys;
+		if (keys ? keys.split(' ').indexOf(name) !== -1 : name !== e.key || e.key.length > 1) {
+			jawsSend('Key', elem.id, name);
+		}
+	}
+}
+
+// jawsMaskApply returns value formatted using mask and whether all
+// placeholders were filled, matching Mask.apply in mask.go.
+function jawsMaskApply(mask, value) {
+	var out = '', i = 0, placeholders = 0, filled = 0;
+	var classes = { '9': /[0-9]/, 'a': /[A-Za-z]/, '*': /[0-9A-Za-z]/ };
+	for (var j = 0; j < mask.length; j++) {
+		var c = mask.charAt(j);
+		var re = classes[c];
+		if (c === '\\' && j + 1 < mask.length) {
+			c = mask.charAt(++j);
+			re = undefined;
+		}
+		if (re === undefined) {
+			if (i < value.length) {
+				if (value.charAt(i) === c) {
+					i++;
+				}
+				out += c;
+			}
+			continue;
+		}
+		placeholders++;
+		while (i < value.length && !re.test(value.charAt(i))) {
+			i++;
+		}
+		if (i < value.length) {
+			out += value.charAt(i++);
+			filled++;
+		}
+	}
+	return [out, filled === placeholders];
+}
+
+// jawsMaskInput formats the element value using it's mask, and returns
+// true if the value is complete or empty and should be sent.
+function jawsMaskInput(elem) {
+	var r = jawsMaskApply(elem.dataset.jawsMask, elem.value);
+	if (elem.value !== r[0]) {
+		elem.value = r[0];
+	}
+	return r[1] || r[0] === '';
+}
+
+// jawsCounterSync updates the character counter following elem,
+// matching writeCounter in maxlength.go.
+function jawsCounterSync(elem) {
+	var counter = elem.nextElementSibling;
+	if (elem.dataset.jawsMaxlength !== undefined && counter && counter.classList.contains('jaws-counter')) {
+		counter.textContent = Array.from(elem.value).length + '/' + elem.dataset.jawsMaxlength;
+	}
+}
+
 function jawsInputHandler(e) {
-	if (jaws instanceof WebSocket && e instanceof Event) {
+	if (jawsLive() && e instanceof Event) {
 		e.stopPropagation();
-		var val;
 		var elem = e.currentTarget;
-		if (jawsIsCheckable(elem.getAttribute('type'))) {
-			val = elem.checked;
-		} else if (elem.tagName.toLowerCase() === 'option') {
-			val = elem.selected;
+		jawsCounterSync(elem);
+		if (elem.dataset.jawsIndeterminate !== undefined) {
+			elem.removeAttribute('data-jaws-indeterminate');
+		}
+		if (elem.dataset.jawsMask !== undefined && !jawsMaskInput(elem)) {
+			return;
+		}
+		if (elem.dataset.jawsDebounce) {
+			clearTimeout(elem.jawsDebounceTimer);
+			elem.jawsDebounceTimer = setTimeout(function () { jawsSendInput(elem); }, +elem.dataset.jawsDebounce);
+			return;
+		}
+		jawsSendInput(elem);
+	}
+}
+
+var jawsBlobId = 0;
+
+function jawsSendBlob(what, id, blob, name) {
+	var reader = new FileReader();
+	reader.onload = function () {
+		if (jawsLive()) {
+			var data = reader.result.substring(reader.result.indexOf(',') + 1);
+			var head = (++jawsBlobId) + "\t";
+			var tail = "\t" + (blob.type || 'application/octet-stream') + "\t" + (name || '').replaceAll('\t', ' ') + "\t";
+			var chunk = 16384;
+			for (var i = 0; i < data.length || i == 0; i += chunk) {
+				var last = (i + chunk >= data.length) ? "1" : "0";
+				jaws.send(what + "\t" + id + "\t" + JSON.stringify(head + last + tail + data.substring(i, i + chunk)) + "\n");
+			}
+		}
+	};
+	reader.readAsDataURL(blob);
+}
+
+function jawsPasteHandler(e) {
+	var elem = e.currentTarget;
+	var items = (e.clipboardData || {}).items || [];
+	for (var i = 0; i < items.length; i++) {
+		if (items[i].kind === 'file') {
+			var file = items[i].getAsFile();
+			if (file) {
+				e.preventDefault();
+				jawsSendBlob('Paste', elem.id, file, file.name);
+			}
+		}
+	}
+}
+
+function jawsDropHandler(e) {
+	var elem = e.currentTarget;
+	var files = (e.dataTransfer || {}).files || [];
+	if (files.length > 0) {
+		e.preventDefault();
+		e.stopPropagation();
+		for (var i = 0; i < files.length; i++) {
+			jawsSendBlob('Paste', elem.id, files[i], files[i].name);
+		}
+	}
+}
+
+function jawsCaptureError(elem, err) {
+	if (jawsLive()) {
+		var msg = btoa(unescape(encodeURIComponent(String(err.message || err))));
+		jaws.send("Capture\t" + elem.id + "\t" + JSON.stringify("0\t1\t\t" + (err.name || 'Error') + "\t" + msg) + "\n");
+	}
+}
+
+function jawsCaptureSnapshot(elem) {
+	var video = elem.querySelector('video');
+	if (video && video.videoWidth > 0) {
+		var canvas = document.createElement('canvas');
+		canvas.width = video.videoWidth;
+		canvas.height = video.videoHeight;
+		canvas.getContext('2d').drawImage(video, 0, 0);
+		canvas.toBlob(function (blob) {
+			if (blob) {
+				jawsSendBlob('Capture', elem.id, blob, 'snapshot');
+			}
+		}, elem.dataset.jawsCaptureMime || 'image/jpeg');
+	}
+}
+
+function jawsCaptureStart(elem) {
+	var kind = elem.dataset.jawsCapture;
+	var interval = +elem.dataset.jawsCaptureInterval || 0;
+	if (!navigator.mediaDevices || !navigator.mediaDevices.getUserMedia) {
+		jawsCaptureError(elem, { name: 'NotSupportedError', message: 'getUserMedia not available' });
+		return;
+	}
+	elem.jawsCapturing = true;
+	navigator.mediaDevices.getUserMedia({ video: kind.includes('video'), audio: kind.includes('audio') }).then(function (stream) {
+		if (!elem.jawsCapturing) {
+			stream.getTracks().forEach(function (track) { track.stop(); });
+			return;
+		}
+		elem.jawsStream = stream;
+		var video = elem.querySelector('video');
+		if (video) {
+			video.srcObject = stream;
+		}
+		if (elem.dataset.jawsCaptureMode === 'record') {
+			var opts = {};
+			if (elem.dataset.jawsCaptureMime && MediaRecorder.isTypeSupported(elem.dataset.jawsCaptureMime)) {
+				opts.mimeType = elem.dataset.jawsCaptureMime;
+			}
+			elem.jawsRecorder = new MediaRecorder(stream, opts);
+			elem.jawsRecorder.ondataavailable = function (e) {
+				if (e.data && e.data.size > 0) {
+					jawsSendBlob('Capture', elem.id, e.data, 'recording');
+				}
+			};
+			elem.jawsRecorder.onerror = function (e) { jawsCaptureError(elem, e.error || e); };
+			elem.jawsRecorder.start(interval > 0 ? interval : undefined);
+		} else if (interval > 0) {
+			elem.jawsCaptureTimer = setInterval(function () { jawsCaptureSnapshot(elem); }, interval);
 		} else {
-			val = elem.value;
+			elem.addEventListener('click', function () { jawsCaptureSnapshot(elem); }, false);
 		}
-		jaws.send("Input\t" + elem.id + "\t" + JSON.stringify(val) + "\n");
+	}).catch(function (err) {
+		elem.jawsCapturing = false;
+		jawsCaptureError(elem, err);
+	});
+}
+
+function jawsCaptureStop(elem) {
+	elem.jawsCapturing = false;
+	clearInterval(elem.jawsCaptureTimer);
+	if (elem.jawsRecorder && elem.jawsRecorder.state !== 'inactive') {
+		elem.jawsRecorder.stop();
+	}
+	if (elem.jawsStream) {
+		elem.jawsStream.getTracks().forEach(function (track) { track.stop(); });
+		elem.jawsStream = null;
 	}
 }
 
@@ -71,32 +327,299 @@ function jawsRemoving(topElem) {
 			val += '\t';
 		}
 		val += elements[i].id;
+		if (elements[i].jawsCapturing) {
+			jawsCaptureStop(elements[i]);
+		}
 	}
 	jaws.send("Remove\t" + topElem.id + "\t" + JSON.stringify(val) + "\n");
 }
 
+function jawsKeyHandler(e) {
+	if (e.target === e.currentTarget && (e.key === 'Enter' || e.key === ' ')) {
+		e.preventDefault();
+		e.currentTarget.click();
+	}
+}
+
+function jawsAuditLabel(elem) {
+	if (!(elem.labels && elem.labels.length) && !elem.getAttribute('aria-label') && !elem.getAttribute('aria-labelledby') && !elem.title) {
+		console.warn("jaws: input without label: " + elem.id);
+	}
+}
+
+// jawsLogViewWindow returns the offset and total from the data-jaws-logview
+// attribute, and the number of records rendered.
+function jawsLogViewWindow(elem) {
+	var pos = (elem.dataset.jawsLogview || '').split(' ');
+	return {
+		offset: parseInt(pos[0], 10) || 0,
+		total: parseInt(pos[1], 10) || 0,
+		count: elem.children.length
+	};
+}
+
+// jawsLogViewSync pads a log view so it's scrollbar covers all the
+// matching records, not just the window of them that is rendered.
+function jawsLogViewSync(elem) {
+	var lv = elem.jawsLogview;
+	if (!lv) {
+		return;
+	}
+	var win = jawsLogViewWindow(elem);
+	if (win.count > 0 && elem.children[0].offsetHeight > 0) {
+		lv.rowHeight = elem.children[0].offsetHeight;
+	}
+	elem.style.paddingTop = (win.offset * lv.rowHeight) + 'px';
+	elem.style.paddingBottom = (Math.max(0, win.total - win.offset - win.count) * lv.rowHeight) + 'px';
+	if (lv.atBottom) {
+		elem.scrollTop = elem.scrollHeight;
+	}
+}
+
+// jawsLogViewScroll tells the server which records are visible when the
+// user scrolls near the edge of the rendered window, or to or from the end.
+function jawsLogViewScroll(e) {
+	var elem = e.currentTarget;
+	var lv = elem.jawsLogview;
+	if (!lv || lv.timer !== null) {
+		return;
+	}
+	lv.timer = setTimeout(function () {
+		lv.timer = null;
+		lv.atBottom = elem.scrollTop + elem.clientHeight >= elem.scrollHeight - 1;
+		if (lv.rowHeight <= 0) {
+			return;
+		}
+		var win = jawsLogViewWindow(elem);
+		var first = Math.floor(elem.scrollTop / lv.rowHeight);
+		var last = Math.min(win.total - 1, Math.floor((elem.scrollTop + elem.clientHeight - 1) / lv.rowHeight));
+		var margin = Math.floor(win.count / 4);
+		var follow = lv.atBottom || last >= win.total - 1;
+		if (follow !== lv.follow ||
+			(win.offset > 0 && first < win.offset + margin) ||
+			(win.offset + win.count < win.total && last >= win.offset + win.count - margin)) {
+			lv.follow = follow;
+			jawsSend('Custom', elem.id, 'scroll\t' + JSON.stringify([first, follow ? win.total - 1 : last]));
+		}
+	}, 100);
+}
+
+var jawsLazyObserver = null;
+
+// jawsLazyVisible asks the server to render the lazy stubs that scrolled into view.
+function jawsLazyVisible(entries) {
+	entries.forEach(function (entry) {
+		if (entry.isIntersecting) {
+			jawsLazyObserver.unobserve(entry.target);
+			jawsSend('Custom', entry.target.id, 'visible\tnull');
+		}
+	});
+}
+
+// jawsLazyObserve waits for a lazy stub to scroll into view, or asks
+// for it to be rendered at once if the browser can't tell.
+function jawsLazyObserve(elem) {
+	if (typeof IntersectionObserver !== 'function') {
+		jawsSend('Custom', elem.id, 'visible\tnull');
+		return;
+	}
+	if (jawsLazyObserver === null) {
+		jawsLazyObserver = new IntersectionObserver(jawsLazyVisible, { rootMargin: '200px' });
+	}
+	jawsLazyObserver.observe(elem);
+}
+
 function jawsAttach(topElem) {
 	var elements = topElem.querySelectorAll('[id^="Jid."]');
 	for (var i = 0; i < elements.length; i++) {
 		var elem = elements[i];
 		if (jawsIsInputTag(elem.tagName)) {
 			elem.addEventListener('input', jawsInputHandler, false);
+			if (elem.dataset.jawsAudit !== undefined) {
+				jawsAuditLabel(elem);
+			}
+			if (elem.dataset.jawsSearch !== undefined) {
+				jawsSearchAttach(elem);
+			}
+			jawsIndeterminateSync(elem, 'data-jaws-indeterminate');
+			if (elem.dataset.jawsCode !== undefined && typeof jawsCodeAdapter === 'function' && !elem.jawsCode) {
+				elem.jawsCode = jawsCodeAdapter(elem);
+				jawsCodeSync(elem, 'readonly');
+				jawsCodeSync(elem, 'data-jaws-annotations');
+			}
 		} else {
 			elem.addEventListener('click', jawsClickHandler, false);
+			if (elem.getAttribute('role') === 'button' && !jawsContains(['a', 'button'], elem.tagName)) {
+				elem.addEventListener('keydown', jawsKeyHandler, false);
+			}
+		}
+		if (elem.dataset.jawsChange !== undefined) {
+			elem.addEventListener('change', jawsChangeHandler, false);
+		}
+		if (elem.dataset.jawsKeys !== undefined) {
+			elem.addEventListener('keydown', jawsKeysHandler, false);
+		}
+		if (elem.dataset.jawsPaste !== undefined) {
+			elem.addEventListener('paste', jawsPasteHandler, false);
+			elem.addEventListener('dragover', function (e) { e.preventDefault(); }, false);
+			elem.addEventListener('drop', jawsDropHandler, false);
 		}
+		if (elem.dataset.jawsCapture !== undefined && !elem.jawsCapturing) {
+			jawsCaptureStart(elem);
+		}
+		if (elem.dataset.jawsLazy !== undefined) {
+			jawsLazyObserve(elem);
+		}
+		if (elem.dataset.jawsLogview !== undefined && !elem.jawsLogview) {
+			elem.jawsLogview = { follow: true, atBottom: true, rowHeight: 0, timer: null };
+			elem.addEventListener('scroll', jawsLogViewScroll, false);
+			jawsLogViewSync(elem);
+		}
+	}
+	var reveals = topElem.querySelectorAll('[data-jaws-reveal]');
+	for (i = 0; i < reveals.length; i++) {
+		if (!reveals[i].jawsReveal) {
+			reveals[i].jawsReveal = true;
+			reveals[i].addEventListener('click', jawsRevealHandler, false);
+		}
+	}
+	if (jawsTimeTimer === null && topElem.querySelector('[data-jaws-time]')) {
+		jawsTimeTimer = setInterval(jawsTimeTick, 1000);
 	}
 	return topElem;
 }
 
+// jawsDuration returns the text for a time ms milliseconds from now,
+// matching relativeText in uireltime.go.
+function jawsDuration(ms, countdown) {
+	var s = Math.floor(Math.abs(ms) / 1000);
+	var text;
+	if (s === 0) {
+		return countdown ? '0s' : 'just now';
+	}
+	if (countdown) {
+		if (s >= 86400) {
+			text = Math.floor(s / 86400) + 'd ' + (Math.floor(s / 3600) % 24) + 'h';
+		} else if (s >= 3600) {
+			text = Math.floor(s / 3600) + 'h ' + (Math.floor(s / 60) % 60) + 'm';
+		} else if (s >= 60) {
+			text = Math.floor(s / 60) + 'm ' + (s % 60) + 's';
+		} else {
+			text = s + 's';
+		}
+	} else {
+		var n, unit;
+		if (s < 60) {
+			return 'just now';
+		} else if (s < 3600) {
+			n = Math.floor(s / 60);
+			unit = ' minute';
+		} else if (s < 86400) {
+			n = Math.floor(s / 3600);
+			unit = ' hour';
+		} else {
+			n = Math.floor(s / 86400);
+			unit = ' day';
+		}
+		text = n + unit + (n === 1 ? '' : 's');
+	}
+	return ms > 0 ? 'in ' + text : text + ' ago';
+}
+
+var jawsTimeTimer = null;
+
+// jawsTimeTick updates the text of relative time elements, using the
+// server time they were rendered at as the reference.
+function jawsTimeTick() {
+	var elements = document.querySelectorAll('[data-jaws-time]');
+	if (elements.length === 0) {
+		clearInterval(jawsTimeTimer);
+		jawsTimeTimer = null;
+		return;
+	}
+	for (var i = 0; i < elements.length; i++) {
+		var elem = elements[i];
+		if (elem.jawsNow !== elem.dataset.jawsNow) {
+			elem.jawsNow = elem.dataset.jawsNow;
+			elem.jawsSkew = Number(elem.jawsNow) - Date.now();
+		}
+		var text = jawsDuration(Date.parse(elem.getAttribute('datetime')) - (Date.now() + elem.jawsSkew), elem.dataset.jawsTime === 'countdown');
+		if (elem.textContent !== text) {
+			elem.textContent = text;
+		}
+	}
+}
+
+// jawsAnimate animates the text of elem from the number it shows to the number
+// in data, returning false if either isn't a plain number or the user prefers
+// reduced motion. Any running animation is cancelled.
+function jawsAnimate(elem, data) {
+	if (elem.jawsAnimation) {
+		cancelAnimationFrame(elem.jawsAnimation);
+		elem.jawsAnimation = null;
+	}
+	var re = /^\s*-?\d+(\.\d+)?\s*$/;
+	var from = elem.textContent;
+	if (elem.dataset.jawsAnimate === undefined || elem.children.length > 0 || !re.test(from) || !re.test(data)) {
+		return false;
+	}
+	if (window.matchMedia && window.matchMedia('(prefers-reduced-motion: reduce)').matches) {
+		return false;
+	}
+	var a = parseFloat(from);
+	var b = parseFloat(data);
+	var decimals = (data.trim().split('.')[1] || '').length;
+	var ms = parseInt(elem.dataset.jawsAnimate) || 1;
+	var start = null;
+	function step(ts) {
+		if (start === null) {
+			start = ts;
+		}
+		var t = Math.min((ts - start) / ms, 1);
+		if (t < 1) {
+			elem.textContent = (a + (b - a) * (1 - Math.pow(1 - t, 3))).toFixed(decimals);
+			elem.jawsAnimation = requestAnimationFrame(step);
+		} else {
+			elem.textContent = data;
+			elem.jawsAnimation = null;
+		}
+	}
+	elem.jawsAnimation = requestAnimationFrame(step);
+	return true;
+}
+
+// jawsAlertRendered adds an alert rendered by the server's AlertRenderer.
+function jawsAlertRendered(lines) {
+	var container = document.getElementById(lines.shift());
+	var limit = parseInt(lines.shift());
+	var prepend = lines.shift() === 'true';
+	if (container) {
+		var alert = jawsAttach(jawsElement(lines.join('\n')));
+		if (prepend) {
+			container.prepend(alert);
+		} else {
+			container.append(alert);
+		}
+		while (limit > 0 && container.children.length > limit) {
+			(prepend ? container.lastElementChild : container.firstElementChild).remove();
+		}
+	}
+}
+
 function jawsAlert(data) {
 	var lines = data.split('\n');
 	var type = lines.shift();
+	if (type === '') {
+		jawsAlertRendered(lines);
+		return;
+	}
 	var message = lines.join('\n');
 	if (typeof bootstrap !== 'undefined') {
 		var alertsElem = document.getElementById('jaws-alerts');
 		if (alertsElem) {
 			var wrapper = document.createElement('div');
-			wrapper.innerHTML = '<div class="alert alert-' + type + ' alert-dismissible" role="alert">' + message +
+			var role = (type === 'danger' || type === 'warning') ? 'alert' : 'status';
+			wrapper.innerHTML = '<div class="alert alert-' + type + ' alert-dismissible" role="' + role + '">' + message +
 				'<button type="button" class="btn-close" data-bs-dismiss="alert" aria-label="Close"></button></div>';
 			alertsElem.append(wrapper);
 			return;
@@ -105,6 +628,165 @@ function jawsAlert(data) {
 	console.log("jaws: " + type + ": " + message);
 }
 
+function jawsAnnounce(data) {
+	var i = data.indexOf('\n');
+	var politeness = data.substring(0, i);
+	var text = data.substring(i + 1);
+	var id = 'jaws-announce-' + politeness;
+	var region = document.getElementById(id);
+	if (!region) {
+		region = document.createElement('div');
+		region.id = id;
+		region.className = 'jaws-sr-only';
+		region.setAttribute('aria-live', politeness);
+		region.setAttribute('aria-atomic', 'true');
+		document.body.appendChild(region);
+	}
+	// clear it first so that repeating the same text is announced again
+	region.textContent = '';
+	setTimeout(function () { region.textContent = text; }, 100);
+}
+
+// jawsInert makes the page inert and shows text in a banner, or
+// restores it if text is empty.
+function jawsInert(text) {
+	var banner = document.getElementById('jaws-inert');
+	var i;
+	for (i = 0; i < document.body.children.length; i++) {
+		if (document.body.children[i] !== banner) {
+			document.body.children[i].inert = !!text;
+		}
+	}
+	if (text) {
+		if (!banner) {
+			banner = document.createElement('div');
+			banner.id = 'jaws-inert';
+			banner.className = 'jaws-inert';
+			banner.setAttribute('role', 'status');
+			document.body.insertBefore(banner, document.body.firstChild);
+		}
+		banner.textContent = text;
+	} else if (banner) {
+		banner.remove();
+	}
+}
+
+// jawsSnapshot sends the page HTML with the current values of inputs,
+// in chunks small enough to be received, ending with an empty chunk.
+// Each chunk starts with the nonce the server asked for it with.
+function jawsSnapshot(nonce) {
+	var i, elem, html = '';
+	var elems = document.body.querySelectorAll('input, textarea, option');
+	for (i = 0; i < elems.length; i++) {
+		elem = elems[i];
+		if (elem.tagName === 'OPTION') {
+			elem.toggleAttribute('selected', elem.selected);
+		} else if (elem.tagName === 'TEXTAREA') {
+			elem.textContent = elem.value;
+		} else if (elem.type === 'checkbox' || elem.type === 'radio') {
+			elem.toggleAttribute('checked', elem.checked);
+		} else if (elem.type !== 'password' && elem.type !== 'file') {
+			elem.setAttribute('value', elem.value);
+		}
+	}
+	for (i = 0; i < document.body.children.length; i++) {
+		elem = document.body.children[i];
+		if (elem.id !== 'jaws-inert' && elem.tagName !== 'SCRIPT') {
+			html += elem.outerHTML;
+		}
+	}
+	for (i = 0; i < html.length;) {
+		var end = Math.min(i + 4096, html.length);
+		var code = html.charCodeAt(end - 1);
+		if (end < html.length && code >= 0xD800 && code <= 0xDBFF) {
+			end--; // don't split surrogate pairs
+		}
+		jawsSend('Mirror', '', nonce + '\n' + html.substring(i, end));
+		i = end;
+	}
+	jawsSend('Mirror', '', nonce + '\n');
+}
+
+// jawsMirror handles the Mirror messages, see mirrorMsg in mirror.go.
+function jawsMirror(kind, data) {
+	switch (kind) {
+		case 'snapshot':
+			jawsSnapshot(data);
+			break;
+		case 'page':
+			jawsMirrorPage(data);
+			break;
+		case 'update':
+			jawsMirrorUpdate(data);
+			break;
+	}
+}
+
+// jawsMirrorPage replaces the page with a sandboxed iframe showing the
+// sanitized HTML of a mirrored page, keeping the inert banner. The iframe
+// can't run scripts, but shares our origin so updates can be applied to it.
+function jawsMirrorPage(html) {
+	var banner = document.getElementById('jaws-inert');
+	var frame = document.getElementById('jaws-mirror');
+	var head = '';
+	var i;
+	if (!frame) {
+		for (i = document.body.children.length - 1; i >= 0; i--) {
+			if (document.body.children[i] !== banner && document.body.children[i].tagName !== 'SCRIPT') {
+				document.body.children[i].remove();
+			}
+		}
+		frame = document.createElement('iframe');
+		frame.id = 'jaws-mirror';
+		frame.setAttribute('sandbox', 'allow-same-origin');
+		frame.style.cssText = 'border:0;width:100%;height:100vh;display:block';
+		frame.addEventListener('load', function () {
+			var queued = frame.jawsQueue;
+			frame.jawsLoaded = true;
+			frame.jawsQueue = [];
+			for (var j = 0; j < queued.length; j++) {
+				jawsMirrorUpdate(queued[j]);
+			}
+		});
+		document.body.appendChild(frame);
+	}
+	var base = document.createElement('base');
+	base.href = window.location.href;
+	head += base.outerHTML;
+	document.head.querySelectorAll('link[rel="stylesheet"], style').forEach(function (elem) {
+		head += elem.outerHTML;
+	});
+	frame.jawsLoaded = false;
+	frame.jawsQueue = [];
+	frame.srcdoc = '<!DOCTYPE html><html><head>' + head + '</head><body>' + html + '</body></html>';
+	if (banner) {
+		jawsInert(banner.textContent);
+	}
+}
+
+// jawsMirrorUpdate applies messages updating a mirrored page to the
+// elements in it's iframe, once it has loaded.
+function jawsMirrorUpdate(lines) {
+	var frame = document.getElementById('jaws-mirror');
+	if (!frame) {
+		return;
+	}
+	if (!frame.jawsLoaded) {
+		frame.jawsQueue.push(lines);
+		return;
+	}
+	var orders = lines.split('\n');
+	for (var i = 0; i < orders.length; i++) {
+		var parts = orders[i].split('\t');
+		if (parts.length === 3) {
+			var elem = frame.contentDocument.getElementById(parts[1]);
+			if (elem) {
+				jawsPerformElement(parts[0], elem, JSON.parse(parts[2]));
+			}
+		}
+	}
+}
+
 function jawsList(idlist) {
 	var i;
 	var elements = [];
@@ -134,6 +816,11 @@ function jawsOrder(idlist) {
 }
 
 function jawsSetValue(elem, str) {
+	if (elem.jawsCode) {
+		elem.value = str;
+		elem.jawsCode.setValue(str);
+		return;
+	}
 	var elemtype = elem.getAttribute('type');
 	if (jawsIsCheckable(elemtype)) {
 		elem.checked = jawsIsTrue(str);
@@ -207,22 +894,64 @@ function jawsHandleReconnect(e) {
 	}
 }
 
+// jawsURL returns the absolute URL of a JaWS endpoint, taking into
+// account the jawsBase variable set if JaWS is served under a subpath
+// or another host.
+function jawsURL(path) {
+	var base = (typeof jawsBase === 'string') ? jawsBase : '';
+	if (!/^[a-z][a-z0-9+.-]*:\/\//i.test(base)) {
+		base = window.location.protocol + '//' + window.location.host + base;
+	}
+	return base + path;
+}
+
+// jawsRealtimeURL returns the URL of the WebSocket or long-polling
+// endpoint, which is at jawsWsBase if it is served separately.
+function jawsRealtimeURL(path, ws) {
+	var url = (typeof jawsWsBase === 'string' && jawsWsBase) ? jawsWsBase + path : jawsURL(path);
+	return ws ? url.replace(/^http/, 'ws') : url.replace(/^ws/, 'http');
+}
+
 function jawsReconnect() {
 	var req = new XMLHttpRequest();
-	req.open("GET", window.location.protocol + "//" + window.location.host + "/jaws/.ping", true);
+	req.open("GET", jawsURL("/jaws/.ping"), true);
 	req.addEventListener('readystatechange', jawsHandleReconnect);
 	req.send(null);
 }
 
+var jawsLostAt = null;
+var jawsAttempts = 0;
+
+// jawsFailed handles losing the connection. If the server keeps the
+// Request for a while (see jawsRetry), it tries to reconnect to it with
+// exponential backoff before falling back to reloading the page.
 function jawsFailed(e) {
-	if (jaws instanceof WebSocket) {
-		jaws = new Date();
+	if (jawsLive()) {
+		if (typeof jawsRetry !== 'undefined' && jawsRetry) {
+			if (jawsLostAt === null) {
+				jawsLostAt = new Date();
+				jawsAttempts = 0;
+			}
+			jaws = jawsLostAt;
+			if (new Date() - jawsLostAt < jawsRetry[2]) {
+				setTimeout(jawsResume, Math.min(jawsRetry[0] * Math.pow(2, jawsAttempts++), jawsRetry[1]));
+				return;
+			}
+		} else {
+			jaws = new Date();
+		}
 		jawsReconnect();
 	}
 }
 
+function jawsResume() {
+	if (jaws instanceof Date) {
+		jawsOpen();
+	}
+}
+
 function jawsUnloading() {
-	if (jaws instanceof WebSocket) {
+	if (jawsLive()) {
 		jaws.removeEventListener('close', jawsFailed);
 		jaws.removeEventListener('error', jawsFailed);
 		jaws.close();
@@ -230,6 +959,104 @@ function jawsUnloading() {
 	}
 }
 
+// JawsPoll is used instead of a WebSocket when long-polling. It sends
+// messages using POST and receives them using GET requests to url.
+function JawsPoll(url) {
+	this.url = url;
+	this.readyState = 0;
+	this.listeners = {};
+	this.outbox = '';
+	this.sending = false;
+	this.ack = 0;
+	this.poll();
+}
+
+JawsPoll.prototype.addEventListener = function (type, fn) {
+	(this.listeners[type] = this.listeners[type] || []).push(fn);
+};
+
+JawsPoll.prototype.removeEventListener = function (type, fn) {
+	this.listeners[type] = (this.listeners[type] || []).filter(function (f) { return f !== fn; });
+};
+
+JawsPoll.prototype.emit = function (type, e) {
+	(this.listeners[type] || []).slice().forEach(function (fn) { fn(e); });
+};
+
+JawsPoll.prototype.request = function (method, url, body, done) {
+	var self = this;
+	var req = new XMLHttpRequest();
+	req.open(method, url, true);
+	req.setRequestHeader('X-Jaws-Protocol', String(jawsProtocolVersion));
+	if (typeof jawsToken === 'string' && jawsToken) {
+		req.setRequestHeader('Authorization', 'Bearer ' + jawsToken);
+	}
+	req.addEventListener('readystatechange', function () {
+		if (req.readyState == 4 && self.readyState < 2) {
+			if (req.status == 200 || req.status == 204) {
+				done(req.responseText, req);
+			} else {
+				self.readyState = 3;
+				self.emit('close', {});
+			}
+		}
+	});
+	req.send(body);
+};
+
+JawsPoll.prototype.poll = function () {
+	var self = this;
+	var url = this.url + (this.url.indexOf('?') < 0 ? '?' : '&') + 'ack=' + this.ack;
+	this.request('GET', url, null, function (text, req) {
+		var seq = req.getResponseHeader('X-Jaws-Poll-Seq');
+		if (seq) {
+			self.ack = seq;
+		}
+		if (self.readyState == 0) {
+			self.readyState = 1;
+			self.emit('open', {});
+			self.flush();
+		}
+		if (text) {
+			self.emit('message', { data: text });
+		}
+		if (self.readyState == 1) {
+			self.poll();
+		}
+	});
+};
+
+JawsPoll.prototype.flush = function () {
+	var self = this;
+	if (this.readyState == 1 && !this.sending && this.outbox) {
+		var body = this.outbox;
+		this.outbox = '';
+		this.sending = true;
+		this.request('POST', this.url, body, function () {
+			self.sending = false;
+			self.flush();
+		});
+	}
+};
+
+JawsPoll.prototype.send = function (text) {
+	this.outbox += text;
+	this.flush();
+};
+
+JawsPoll.prototype.close = function () {
+	this.readyState = 3;
+};
+
+// jawsLive returns true if jaws is a connection rather than the time it was lost.
+function jawsLive() {
+	return jaws instanceof JawsPoll || (typeof WebSocket !== 'undefined' && jaws instanceof WebSocket);
+}
+
+function jawsIsOpen() {
+	return jawsLive() && jaws.readyState === 1;
+}
+
 function jawsElement(html) {
 	var template = document.createElement('template');
 	template.innerHTML = html;
@@ -258,9 +1085,265 @@ function jawsInsert(elem, data) {
 	}
 }
 
+// jawsPreserved returns true if the attribute, or the class if attr is a
+// class name prefixed with '.', is in the elements data-jaws-preserve list,
+// where entries ending with '*' are prefixes.
+function jawsPreserved(elem, attr) {
+	var list = elem.getAttribute('data-jaws-preserve');
+	if (list) {
+		var names = list.split(' ');
+		for (var i = 0; i < names.length; i++) {
+			var name = names[i];
+			if (name === attr || (name.endsWith('*') && attr.startsWith(name.slice(0, -1)))) {
+				return true;
+			}
+		}
+	}
+	return false;
+}
+
+// jawsKeepClasses makes the classes elem preserves present in target
+// only if they are in classes.
+function jawsKeepClasses(elem, classes, target) {
+	Array.from(target.classList).forEach(function (c) {
+		if (jawsPreserved(elem, '.' + c) && !jawsContains(classes, c)) {
+			target.classList.remove(c);
+		}
+	});
+	classes.forEach(function (c) {
+		if (jawsPreserved(elem, '.' + c)) {
+			target.classList.add(c);
+		}
+	});
+}
+
+// jawsPreserveOnto gives repl the attributes and classes elem preserves.
+function jawsPreserveOnto(elem, repl) {
+	var i;
+	for (i = repl.attributes.length - 1; i >= 0; i--) {
+		if (jawsPreserved(elem, repl.attributes[i].name)) {
+			repl.removeAttribute(repl.attributes[i].name);
+		}
+	}
+	for (i = 0; i < elem.attributes.length; i++) {
+		if (jawsPreserved(elem, elem.attributes[i].name)) {
+			repl.setAttribute(elem.attributes[i].name, elem.attributes[i].value);
+		}
+	}
+	if (!jawsPreserved(elem, 'class')) {
+		jawsKeepClasses(elem, Array.from(elem.classList), repl);
+	}
+}
+
+// jawsPreserve gives the replacement for elem the attributes and classes it preserves.
+function jawsPreserve(elem, fragment) {
+	var repl = fragment.firstElementChild;
+	if (repl && repl.id === elem.id && elem.hasAttribute('data-jaws-preserve')) {
+		jawsPreserveOnto(elem, repl);
+	}
+	return fragment;
+}
+
+// jawsSetInner sets the inner HTML of elem, keeping the attributes and
+// classes preserved by the descendants it replaces.
+function jawsSetInner(elem, html) {
+	var kept = elem.querySelectorAll('[id][data-jaws-preserve]');
+	elem.innerHTML = html;
+	kept.forEach(function (old) {
+		var repl = elem.querySelector('[id="' + old.id + '"]');
+		if (repl) {
+			jawsPreserveOnto(old, repl);
+		}
+	});
+}
+
+// jawsSetClasses changes the class attribute of elem using fn, keeping the classes it preserves.
+function jawsSetClasses(elem, fn) {
+	var classes = Array.from(elem.classList);
+	fn();
+	jawsKeepClasses(elem, classes, elem);
+}
+
 function jawsSetAttr(elem, data) {
 	var lines = data.split('\n');
-	elem.setAttribute(lines.shift(), lines.join('\n'));
+	var attr = lines.shift();
+	if (jawsPreserved(elem, attr)) {
+		return;
+	}
+	jawsSetClasses(elem, function () { elem.setAttribute(attr, lines.join('\n')); });
+	jawsCodeSync(elem, attr);
+	if (attr === 'data-jaws-strength' || attr === 'data-jaws-feedback') {
+		jawsStrengthSync(elem);
+	} else if (attr === 'data-jaws-results') {
+		jawsSearchResults(elem);
+	} else if (attr === 'data-jaws-logview') {
+		jawsLogViewSync(elem);
+	}
+	jawsIndeterminateSync(elem, attr);
+}
+
+// jawsIndeterminateSync sets the indeterminate property of a checkbox
+// from it's data-jaws-indeterminate attribute.
+function jawsIndeterminateSync(elem, attr) {
+	if (attr === 'data-jaws-indeterminate') {
+		elem.indeterminate = elem.hasAttribute(attr);
+	}
+}
+
+// jawsSearchList returns the listbox following a search select input.
+function jawsSearchList(elem) {
+	var list = elem.nextElementSibling;
+	if (list && list.classList.contains('jaws-search-results')) {
+		return list;
+	}
+	return null;
+}
+
+function jawsSearchShow(elem, show) {
+	var list = jawsSearchList(elem);
+	if (list) {
+		list.hidden = !show || list.children.length === 0;
+		elem.setAttribute('aria-expanded', list.hidden ? 'false' : 'true');
+	}
+}
+
+// jawsSearchResults renders the options in the data-jaws-results attribute
+// of a search select input, replacing the list if the offset is zero.
+function jawsSearchResults(elem) {
+	var list = jawsSearchList(elem);
+	if (!list) {
+		return;
+	}
+	var res = JSON.parse(elem.dataset.jawsResults || '{}');
+	if (!res.offset) {
+		list.textContent = '';
+	}
+	var more = list.querySelector('.jaws-search-more');
+	if (more) {
+		more.remove();
+	}
+	var opts = res.options || [];
+	for (var i = 0; i < opts.length; i++) {
+		var li = document.createElement('li');
+		li.setAttribute('role', 'option');
+		li.dataset.jawsKey = opts[i].key;
+		li.textContent = opts[i].label;
+		list.appendChild(li);
+	}
+	if (res.more) {
+		more = document.createElement('li');
+		more.className = 'jaws-search-more';
+		more.textContent = '\u2026';
+		list.appendChild(more);
+	}
+	jawsSearchShow(elem, document.activeElement === elem);
+}
+
+function jawsSearchPick(elem, li) {
+	if (li.classList.contains('jaws-search-more')) {
+		jawsSend('Custom', elem.id, 'more\tnull');
+		return;
+	}
+	jawsSend('Custom', elem.id, 'select\t' + JSON.stringify(li.dataset.jawsKey));
+	jawsSearchShow(elem, false);
+}
+
+function jawsSearchClick(e) {
+	var list = e.currentTarget;
+	var li = e.target.closest('li');
+	e.stopPropagation();
+	if (li && list.contains(li)) {
+		jawsSearchPick(list.previousElementSibling, li);
+	}
+}
+
+// jawsSearchKey handles keyboard navigation of the search select options.
+function jawsSearchKey(e) {
+	var elem = e.currentTarget;
+	var list = jawsSearchList(elem);
+	if (!list) {
+		return;
+	}
+	var items = list.children;
+	var active = list.querySelector('.jaws-active');
+	var i = Array.prototype.indexOf.call(items, active);
+	switch (e.key) {
+		case 'ArrowDown':
+		case 'ArrowUp':
+			e.preventDefault();
+			jawsSearchShow(elem, true);
+			if (items.length > 0) {
+				i = e.key === 'ArrowDown' ? Math.min(i + 1, items.length - 1) : Math.max(i - 1, 0);
+				if (active) {
+					active.classList.remove('jaws-active');
+					active.removeAttribute('aria-selected');
+				}
+				items[i].classList.add('jaws-active');
+				items[i].setAttribute('aria-selected', 'true');
+				items[i].scrollIntoView({ block: 'nearest' });
+			}
+			break;
+		case 'Enter':
+			if (active && !list.hidden) {
+				e.preventDefault();
+				jawsSearchPick(elem, active);
+			}
+			break;
+		case 'Escape':
+			jawsSearchShow(elem, false);
+			break;
+	}
+}
+
+function jawsSearchAttach(elem) {
+	var list = jawsSearchList(elem);
+	if (list && !elem.jawsSearch) {
+		elem.jawsSearch = true;
+		elem.addEventListener('keydown', jawsSearchKey, false);
+		elem.addEventListener('focus', function () { jawsSearchShow(elem, true); }, false);
+		elem.addEventListener('blur', function () { jawsSearchShow(elem, false); }, false);
+		// keep focus in the input so blur doesn't hide the list before the click
+		list.addEventListener('mousedown', function (e) { e.preventDefault(); }, false);
+		list.addEventListener('click', jawsSearchClick, false);
+	}
+}
+
+// jawsStrengthSync updates the password strength meter following elem.
+function jawsStrengthSync(elem) {
+	var meter = elem.nextElementSibling;
+	if (meter && meter.classList.contains('jaws-password-meter')) {
+		meter.value = +elem.dataset.jawsStrength;
+		meter.title = elem.dataset.jawsFeedback || '';
+	}
+}
+
+// jawsRevealHandler shows or hides the password of the input the button
+// controls. This is done in the browser only, the value isn't sent.
+function jawsRevealHandler(e) {
+	e.stopPropagation();
+	var btn = e.currentTarget;
+	var elem = document.getElementById(btn.dataset.jawsReveal);
+	if (elem) {
+		var show = elem.type === 'password';
+		elem.type = show ? 'text' : 'password';
+		btn.setAttribute('aria-pressed', show ? 'true' : 'false');
+	}
+}
+
+// jawsCodeAdapter may be set to a function that is called with the textarea
+// of a jaws code element and returns an object with the methods
+// setValue(str), setReadOnly(bool) and annotate(list). The editor must
+// set the textarea value and dispatch an 'input' event on it when changed.
+var jawsCodeAdapter;
+
+function jawsCodeSync(elem, attr) {
+	if (elem.jawsCode) {
+		if (attr === 'readonly') {
+			elem.jawsCode.setReadOnly(elem.hasAttribute('readonly'));
+		} else if (attr === 'data-jaws-annotations') {
+			elem.jawsCode.annotate(JSON.parse(elem.getAttribute(attr) || '[]'));
+		}
+	}
 }
 
 function jawsMessage(e) {
@@ -272,6 +1355,136 @@ function jawsMessage(e) {
 			jawsPerform(parts.shift(), parts.shift(), parts.shift());
 		}
 	}
+	if (jawsFlow) {
+		jawsGrant(1);
+	}
+}
+
+// jawsFlow is true if the server has offered flow control credits,
+// jawsGranted counts the credits waiting to be granted.
+var jawsFlow = false;
+var jawsGranted = 0;
+
+// jawsGrant grants the server n more messages, coalescing the grants
+// of messages applied in the same task.
+function jawsGrant(n) {
+	if (jawsGranted === 0) {
+		setTimeout(function () {
+			var credits = jawsGranted;
+			jawsGranted = 0;
+			jawsSend('Credit', '', String(credits));
+		}, 0);
+	}
+	jawsGranted += n;
+}
+
+function jawsSend(what, id, val) {
+	if (jawsLive()) {
+		jaws.send(what + "\t" + id + "\t" + JSON.stringify(val) + "\n");
+	}
+}
+
+function jawsSendPrefs() {
+	if (window.matchMedia) {
+		var motion = window.matchMedia('(prefers-reduced-motion: reduce)').matches;
+		var scheme = window.matchMedia('(prefers-color-scheme: dark)').matches ? 'dark' : 'light';
+		jawsSend('Prefs', '', String(motion) + '\t' + scheme);
+	}
+}
+
+function jawsWatchPrefs() {
+	if (window.matchMedia) {
+		['(prefers-reduced-motion: reduce)', '(prefers-color-scheme: dark)'].forEach(function (q) {
+			var mql = window.matchMedia(q);
+			if (mql.addEventListener) {
+				mql.addEventListener('change', jawsSendPrefs);
+			}
+		});
+	}
+}
+
+var jawsFullscreenElem = null;
+
+function jawsFullscreen(elem, data) {
+	var p;
+	if (data === 'true') {
+		p = elem.requestFullscreen ? elem.requestFullscreen() : Promise.reject(new Error('fullscreen not supported'));
+	} else {
+		p = document.fullscreenElement ? document.exitFullscreen() : Promise.resolve();
+	}
+	p.catch(function (err) { jawsSend('Fullscreen', elem.id, String(err.message || err)); });
+}
+
+function jawsFullscreenChange() {
+	var elem = document.fullscreenElement;
+	if (jawsFullscreenElem && jawsFullscreenElem !== elem) {
+		jawsSend('Fullscreen', jawsFullscreenElem.id, 'false');
+	}
+	jawsFullscreenElem = null;
+	if (elem && elem.id.startsWith('Jid.')) {
+		jawsFullscreenElem = elem;
+		jawsSend('Fullscreen', elem.id, 'true');
+	}
+}
+
+var jawsWakeLock = null;
+var jawsWakeWanted = false;
+
+function jawsWakeAcquire() {
+	if (!('wakeLock' in navigator)) {
+		jawsSend('WakeLock', '', 'wake lock not supported');
+		return;
+	}
+	navigator.wakeLock.request('screen').then(function (lock) {
+		jawsWakeLock = lock;
+		lock.addEventListener('release', function () {
+			if (jawsWakeLock === lock) {
+				jawsWakeLock = null;
+				jawsSend('WakeLock', '', 'false');
+			}
+		});
+		jawsSend('WakeLock', '', 'true');
+	}).catch(function (err) {
+		jawsSend('WakeLock', '', String(err.message || err));
+	});
+}
+
+function jawsKeepAwake(data) {
+	jawsWakeWanted = (data === 'true');
+	if (jawsWakeWanted) {
+		if (!jawsWakeLock) {
+			jawsWakeAcquire();
+		}
+	} else if (jawsWakeLock) {
+		jawsWakeLock.release();
+	}
+}
+
+function jawsVisibilityChange() {
+	if (document.visibilityState === 'visible' && jawsWakeWanted && !jawsWakeLock) {
+		jawsWakeAcquire();
+	}
+}
+
+// jawsPatch applies "start\tcount\ttext" to the inner HTML last set by a Patch.
+// A count of -1 replaces it entirely.
+function jawsPatch(elem, data) {
+	var i = data.indexOf('\t');
+	var j = data.indexOf('\t', i + 1);
+	var start = parseInt(data.substring(0, i));
+	var count = parseInt(data.substring(i + 1, j));
+	var inner = data.substring(j + 1);
+	if (count >= 0) {
+		if (typeof elem.jawsInner !== 'string') {
+			console.log("jaws: no inner HTML to patch: " + elem.id);
+			return;
+		}
+		inner = elem.jawsInner.substring(0, start) + inner + elem.jawsInner.substring(start + count);
+	}
+	jawsRemoving(elem);
+	jawsSetInner(elem, inner);
+	elem.jawsInner = inner;
+	jawsAttach(elem);
 }
 
 function jawsPerform(what, id, data) {
@@ -286,39 +1499,85 @@ function jawsPerform(what, id, data) {
 		case 'Alert':
 			jawsAlert(data);
 			return;
+		case 'Print':
+			window.print();
+			return;
+		case 'WakeLock':
+			jawsKeepAwake(data);
+			return;
+		case 'Announce':
+			jawsAnnounce(data);
+			return;
 		case 'Order':
 			jawsOrder(data);
 			return;
+		case 'Inert':
+			jawsInert(data);
+			return;
+		case 'Credit':
+			jawsFlow = true;
+			jawsGrant(parseInt(data, 10) || 0);
+			return;
+		case 'Mirror':
+			jawsMirror(id, data);
+			return;
 	}
 	var elem = document.getElementById(id);
 	if (elem === null) {
 		console.log("jaws: id not found: " + id);
 		return;
 	}
+	if (elem.dataset.jawsDup !== undefined) {
+		document.querySelectorAll('[id="' + id + '"]').forEach(function (dup) {
+			jawsPerformElement(what, dup, data);
+		});
+		return;
+	}
+	jawsPerformElement(what, elem, data);
+}
+
+function jawsPerformElement(what, elem, data) {
 	var where = null;
 	switch (what) {
 		case 'Inner':
+			delete elem.jawsInner;
+			if (jawsAnimate(elem, data)) {
+				break;
+			}
 			jawsRemoving(elem);
-			elem.innerHTML = data;
+			jawsSetInner(elem, data);
 			jawsAttach(elem);
+			jawsCounterSync(elem);
+			jawsLogViewSync(elem);
+			break;
+		case 'Patch':
+			jawsPatch(elem, data);
 			break;
 		case 'Value':
 			jawsSetValue(elem, data);
+			jawsCounterSync(elem);
+			break;
+		case 'Fullscreen':
+			jawsFullscreen(elem, data);
 			break;
 		case 'Append':
 			elem.appendChild(jawsAttach(jawsElement(data)));
+			jawsLogViewSync(elem);
 			break;
 		case 'Replace':
+			jawsCaptureStop(elem);
 			jawsRemoving(elem);
-			elem.replaceWith(jawsAttach(jawsElement(data)));
+			elem.replaceWith(jawsAttach(jawsPreserve(elem, jawsElement(data))));
 			break;
 		case 'Delete':
+			jawsCaptureStop(elem);
 			jawsRemoving(elem);
 			elem.remove();
 			break;
 		case 'Remove':
 			where = jawsWhere(elem, data);
 			if (where instanceof Node) {
+				jawsCaptureStop(where);
 				jawsRemoving(where);
 				elem.removeChild(where);
 			}
@@ -330,13 +1589,22 @@ function jawsPerform(what, id, data) {
 			jawsSetAttr(elem, data);
 			break;
 		case 'RAttr':
-			elem.removeAttribute(data);
+			if (jawsPreserved(elem, data)) {
+				break;
+			}
+			jawsSetClasses(elem, function () { elem.removeAttribute(data); });
+			jawsCodeSync(elem, data);
+			jawsIndeterminateSync(elem, data);
 			break;
 		case 'SClass':
-			elem.classList.add(data);
+			if (!jawsPreserved(elem, '.' + data)) {
+				elem.classList.add(data);
+			}
 			break;
 		case 'RClass':
-			elem.classList.remove(data);
+			if (!jawsPreserved(elem, '.' + data)) {
+				elem.classList.remove(data);
+			}
 			break;
 		default:
 			console.log("jaws: unknown operation: " + what);
@@ -344,6 +1612,63 @@ function jawsPerform(what, id, data) {
 	}
 }
 
+var jawsWidgetCount = 0;
+
+// jawsWidgetLoad asks the server to render the widget inside a <jaws-widget>.
+function jawsWidgetLoad(elem) {
+	if (!jawsIsOpen() || !elem.isConnected) {
+		return;
+	}
+	if (!elem.id) {
+		elem.id = 'jaws-widget-' + (++jawsWidgetCount);
+	}
+	var attrs = {};
+	for (var i = 0; i < elem.attributes.length; i++) {
+		var attr = elem.attributes[i];
+		if (!jawsContains(['name', 'id', 'events'], attr.name)) {
+			attrs[attr.name] = attr.value;
+		}
+	}
+	jawsSend('Widget', '', elem.id + '\t' + elem.getAttribute('name') + '\t' + JSON.stringify(attrs));
+}
+
+// jawsWidgetEvent sends a DOM event dispatched on a <jaws-widget> to it's first Element.
+function jawsWidgetEvent(e) {
+	var elem = e.currentTarget.querySelector('[id^="Jid."]');
+	if (elem) {
+		jawsSend('Custom', elem.id, e.type + '\t' + JSON.stringify(e.detail === undefined ? null : e.detail));
+	}
+}
+
+function jawsWidgetListen(elem) {
+	(elem.jawsEvents || []).forEach(function (name) { elem.removeEventListener(name, jawsWidgetEvent, false); });
+	elem.jawsEvents = (elem.getAttribute('events') || '').split(' ').filter(function (name) { return name; });
+	elem.jawsEvents.forEach(function (name) { elem.addEventListener(name, jawsWidgetEvent, false); });
+}
+
+if (window.customElements && !window.customElements.get('jaws-widget')) {
+	window.customElements.define('jaws-widget', class extends HTMLElement {
+		connectedCallback() {
+			var elem = this;
+			jawsWidgetListen(elem);
+			if (!elem.jawsObserver) {
+				elem.jawsObserver = new MutationObserver(function () {
+					jawsWidgetListen(elem);
+					jawsWidgetLoad(elem);
+				});
+			}
+			elem.jawsObserver.observe(elem, { attributes: true });
+			jawsWidgetLoad(elem);
+		}
+		disconnectedCallback() {
+			this.jawsObserver.disconnect();
+			if (jawsIsOpen()) {
+				jawsRemoving(this);
+			}
+		}
+	});
+}
+
 function jawsPageshow(e) {
 	if (e.persisted) {
 		window.location.reload();
@@ -351,14 +1676,38 @@ function jawsPageshow(e) {
 }
 
 function jawsConnect() {
-	var wsScheme = 'ws://';
-	if (window.location.protocol === 'https:') {
-		wsScheme = 'wss://';
-	}
 	window.addEventListener('beforeunload', jawsUnloading);
 	window.addEventListener('pageshow', jawsPageshow);
-	jaws = new WebSocket(wsScheme + window.location.host + '/jaws/' + encodeURIComponent(jawsKey));
-	jaws.addEventListener('open', function () { jawsAttach(document); });
+	document.addEventListener('fullscreenchange', jawsFullscreenChange);
+	document.addEventListener('visibilitychange', jawsVisibilityChange);
+	jawsWatchPrefs();
+	jawsOpen();
+}
+
+// jawsOpen connects to the server, or reconnects if jawsLostAt is set.
+function jawsOpen() {
+	var resuming = jawsLostAt !== null;
+	jawsFlow = false;
+	jawsGranted = 0;
+	if (typeof WebSocket === 'undefined' || (typeof jawsPoll !== 'undefined' && jawsPoll)) {
+		jaws = new JawsPoll(jawsRealtimeURL('/jaws/.poll/' + encodeURIComponent(jawsKey), false));
+	} else {
+		var wsProtocols = ['jaws', 'jaws.v' + jawsProtocolVersion];
+		if (typeof jawsToken === 'string' && jawsToken) {
+			wsProtocols.push('jaws.bearer.' + jawsToken);
+		}
+		jaws = new WebSocket(jawsRealtimeURL('/jaws/' + encodeURIComponent(jawsKey), true), wsProtocols);
+	}
+	jaws.addEventListener('open', function () {
+		if (resuming) {
+			jawsLostAt = null;
+			jawsAttempts = 0;
+			return;
+		}
+		jawsAttach(document);
+		jawsSendPrefs();
+		document.querySelectorAll('jaws-widget').forEach(jawsWidgetLoad);
+	});
 	jaws.addEventListener('message', jawsMessage);
 	jaws.addEventListener('close', jawsFailed);
 	jaws.addEventListener('error', jawsFailed);
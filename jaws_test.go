@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"net/netip"
+	"strconv"
 	"strings"
 	"testing"
 	"time"
@@ -415,4 +416,48 @@ func TestJaws_GenerateHeadHTML(t *testing.T) {
 
 	th.True(jw.GenerateHeadHTML("random.crap") != nil)
 	th.True(jw.GenerateHeadHTML("\n") != nil)
+
+	jw.BasePath = "/app/"
+	jw.BaseURL = "https://example.com"
+	th.NoErr(jw.GenerateHeadHTML())
+	th.True(strings.Contains(jw.headPrefix, `"https://example.com/app`+JavascriptPath+`"`))
+	th.True(strings.HasSuffix(jw.headPrefix, `<script>var jawsBase="https://example.com/app";var jawsKey="`))
+	th.Equal(jw.headLinks, []string{"<https://example.com/app" + JavascriptPath + ">; rel=preload; as=script"})
+}
+
+func TestJaws_SetAssetVersion(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+
+	th.Equal(tj.AssetVersion(), "")
+	rq.checkAssetVersion()
+	tj.SetAssetVersion("")
+
+	tj.SetAssetVersion("v2")
+	th.Equal(tj.AssetVersion(), "v2")
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Alert\t\t"+strconv.Quote("info\n"+NewVersionAlert)+"\n")
+	}
+
+	tj.ForceReload = true
+	rq.checkAssetVersion()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Reload\t\t\"\"\n")
+	}
+
+	rq2 := tj.newRequest(nil)
+	rq2.checkAssetVersion()
+	select {
+	case s := <-rq2.outCh:
+		t.Errorf("%q", s)
+	case <-time.After(time.Millisecond * 10):
+	}
 }
@@ -54,7 +54,8 @@ func JawsKeyValue(jawsKey string) uint64 {
 const jsLoader = `.forEach(function(c){var e=document.createElement("script");e.src=c;e.async=!1;document.head.appendChild(e);});`
 
 // HeadHTML returns HTML code to load the given scripts and CSS files efficiently,
-// as well as a basic CSS "jaws-alert" class for JaWS to use.
+// as well as basic CSS "jaws-alert", "jaws-screen" and "jaws-print" classes
+// and pending Element styling for JaWS to use.
 func HeadHTML(js []string, css []string) string {
 	var s []byte
 
@@ -70,6 +71,11 @@ func HeadHTML(js []string, css []string) string {
 	}
 	s = append(s, `<style>
 .jaws-alert { height: 3em; display: flex; justify-content: center; align-items: center; background-color: red; color: white; }
+@media print { .jaws-screen { display: none !important; } }
+@media not print { .jaws-print { display: none !important; } }
+[data-jaws-pending] { cursor: progress; opacity: 0.6; }
+.jaws-sr-only { position: absolute; width: 1px; height: 1px; margin: -1px; padding: 0; overflow: hidden; clip: rect(0, 0, 0, 0); white-space: nowrap; border: 0; }
+.jaws-inert { position: sticky; top: 0; z-index: 1000; padding: 0.75em; text-align: center; background-color: #fff3cd; color: #664d03; }
 </style>
 `...)
 
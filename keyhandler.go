@@ -0,0 +1,27 @@
+package jaws
+
+import "github.com/linkdata/jaws/what"
+
+// KeyHandler receives the keys the user presses on an Element.
+//
+// The key is the browser's KeyboardEvent.key, prefixed with "Ctrl+", "Alt+",
+// "Shift+" and "Meta+" in that order for the modifiers held, e.g. "Enter"
+// or "Ctrl+s". Shift is only included for keys that aren't characters.
+//
+// The HTML element must have the `data-jaws-keys` attribute for the browser
+// to send key events. If the attribute has a value, only the space-separated
+// keys it lists are sent, otherwise keys that don't just type a character.
+// The attribute is added automatically without a value when a KeyHandler
+// is passed as a parameter when rendering the Element.
+type KeyHandler interface {
+	JawsKey(e *Element, key string) (err error)
+}
+
+type keyHandlerWrapper struct{ KeyHandler }
+
+func (khw keyHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Key {
+		return khw.JawsKey(e, v)
+	}
+	return ErrEventUnhandled
+}
@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJawsKey struct {
+	vals []string
+	err  error
+}
+
+func (tjk *testJawsKey) JawsKey(e *Element, key string) error {
+	tjk.vals = append(tjk.vals, key)
+	return tjk.err
+}
+
+var _ KeyHandler = (*testJawsKey)(nil)
+
+func Test_keyHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	tjk := &testJawsKey{}
+	th.NoErr(rq.Div("inner", tjk))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-keys>inner</div>`)
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "x"))
+	th.NoErr(rq.callAllEventHandlers(1, what.Key, "Ctrl+s"))
+	th.Equal(tjk.vals, []string{"Ctrl+s"})
+
+	tjk.err = errors.New("bad")
+	th.Equal(rq.callAllEventHandlers(1, what.Key, "Enter"), tjk.err)
+}
+
+type testKeyUi struct {
+	UiHtml
+	testJawsKey
+}
+
+func TestUiHtml_JawsEventKeyHandler(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	ui := &testKeyUi{}
+	ui.Tag = &ui.testJawsKey
+	elem := rq.NewElement(ui)
+	th.NoErr(ui.JawsEvent(elem, what.Key, "Ctrl+s"))
+	th.Equal(ui.vals, []string{"Ctrl+s"})
+	th.Equal(ui.JawsEvent(elem, what.Input, "Ctrl+s"), ErrEventUnhandled)
+}
@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"fmt"
+	"html/template"
+)
+
+// BlockName is the tag of the Elements rendering a layout block using
+// With.Block. Dirty it to have those Elements render the block again.
+type BlockName string
+
+// Extend returns a copy of the base layout template with the templates
+// in text parsed into it. Templates defined in text replace the blocks
+// of the same name in the layout, as with {{block}} in html/template.
+//
+// The layout renders a block as a live region using With.Block:
+//
+//	base := template.Must(template.New("base").Parse(
+//		`<header>{{$.Block "header" $.Dot}}</header><main>{{$.Block "content" $.Dot}}</main>` +
+//			`{{define "header"}}My Site{{end}}{{define "content"}}{{end}}`))
+//	page := template.Must(jaws.Extend(base, `{{define "content"}}Hello {{$.Dot}}{{end}}`))
+//
+// The base template must not have been executed.
+func Extend(base *template.Template, text string) (tmpl *template.Template, err error) {
+	if tmpl, err = base.Clone(); err == nil {
+		tmpl, err = tmpl.Parse(text)
+	}
+	return
+}
+
+// Block renders the named template, looked up among those associated
+// with the template being executed, as a live region using UiBlock.
+// The region is updated when any tag of the dot, or the BlockName, is dirtied.
+func (w With) Block(name string, dot interface{}, params ...interface{}) error {
+	var t *template.Template
+	if w.tmpl != nil {
+		t = w.tmpl.Lookup(name)
+	}
+	if t == nil {
+		return fmt.Errorf("jaws: block %q not defined", name)
+	}
+	return w.RequestWriter.UI(NewUiBlock(name, Template{Template: t, Dot: dot}), params...)
+}
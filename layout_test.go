@@ -0,0 +1,44 @@
+package jaws
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestExtend(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	base := template.Must(template.New("base").Parse(
+		`<header>{{$.Block "header" nil}}</header><main>{{$.Block "content" $.Dot "class=\"main\""}}</main>` +
+			`{{define "header"}}Site{{end}}{{define "content"}}default{{end}}`))
+	page, err := Extend(base, `{{define "content"}}Hello {{$.Dot}}{{end}}`)
+	th.NoErr(err)
+
+	th.NoErr(rq.UI(Template{Template: page, Dot: "world"}))
+	th.Equal(rq.BodyString(), `<header><div id="Jid.2" data-jaws-block="header">Site</div></header>`+
+		`<main><div id="Jid.3" class="main" data-jaws-block="content">Hello world</div></main>`)
+	th.True(rq.getElementByJid(3).HasTag(BlockName("content")))
+
+	rq.rr.Body.Reset()
+	th.NoErr(rq.UI(Template{Template: base}))
+	th.True(strings.Contains(rq.BodyString(), `data-jaws-block="content">default</div>`))
+
+	_, err = Extend(base, `{{define "x"}}{{end`)
+	th.True(err != nil)
+}
+
+func TestWith_BlockUndefined(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.Equal(With{}.Block("x", nil).Error(), `jaws: block "x" not defined`)
+	tmpl := template.Must(template.New("t").Parse(`{{$.Block "missing" nil}}`))
+	th.NoErr(rq.UI(Template{Template: tmpl}))
+	th.Equal(rq.BodyString(), `jaws: block &#34;missing&#34; not defined`)
+}
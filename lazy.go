@@ -0,0 +1,90 @@
+package jaws
+
+import (
+	"io"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// Lazy is the parameter returned by LazyRender.
+type Lazy struct{}
+
+// LazyRender returns a parameter that makes the UI it's passed to render
+// a lightweight stub initially. The UI itself is only rendered once the
+// browser reports that the stub has scrolled into view, replacing the stub.
+//
+// Use it for elements below the fold on long pages. The stub is an empty
+// div with the class "jaws-lazy", style it to reserve space if needed.
+func LazyRender() Lazy {
+	return Lazy{}
+}
+
+// lazyVisibleEvent is the custom event sent when a stub scrolls into view.
+const lazyVisibleEvent = "visible"
+
+// UiLazy renders UI only once the browser has scrolled it into view,
+// and a stub element before that.
+type UiLazy struct {
+	UI
+	mu      deadlock.Mutex
+	params  []interface{}
+	visible bool
+}
+
+func NewUiLazy(ui UI) *UiLazy {
+	return &UiLazy{UI: ui}
+}
+
+func (ui *UiLazy) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	e.Tag(ui)
+	ui.mu.Lock()
+	ui.params = params
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "div", "", "", `class="jaws-lazy"`, "data-jaws-lazy")
+}
+
+func (ui *UiLazy) JawsUpdate(e *Element) {
+	ui.mu.Lock()
+	visible := ui.visible
+	params := ui.params
+	ui.mu.Unlock()
+	if visible {
+		// render using a new Element so that tags and handlers are those
+		// of the UI rather than the stub, and have the browser replace it
+		elem := e.Request.NewElement(ui.UI)
+		var sb strings.Builder
+		maybePanic(elem.Render(&sb, params))
+		elem.wsQueue = append(elem.wsQueue, wsMsg{
+			Data: sb.String(),
+			Jid:  e.jid,
+			What: what.Replace,
+		})
+		e.Request.deleteElement(e)
+	}
+}
+
+func (ui *UiLazy) JawsEvent(e *Element, wht what.What, val string) error {
+	if wht == what.Custom {
+		if event, _, _ := strings.Cut(val, "\t"); event == lazyVisibleEvent {
+			ui.mu.Lock()
+			ui.visible = true
+			ui.mu.Unlock()
+			e.Dirty(ui)
+			return nil
+		}
+	}
+	return ErrEventUnhandled
+}
+
+// extractLazy removes a Lazy from params, returning true if found.
+func extractLazy(params []interface{}) (rest []interface{}, found bool) {
+	for i, p := range params {
+		if _, found = p.(Lazy); found {
+			rest = append(append(rest, params[:i]...), params[i+1:]...)
+			return
+		}
+	}
+	return params, false
+}
@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_LazyRender(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ts := newTestSetter(template.HTML("content"))
+	th.NoErr(rq.Div(ts, LazyRender(), "hidden"))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" class="jaws-lazy" data-jaws-lazy></div>`)
+	elem := rq.getElementByJid(1)
+	th.True(!elem.HasTag(ts))
+	ui := elem.Ui().(*UiLazy)
+	th.True(elem.HasTag(ui))
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	th.Equal(ui.JawsEvent(elem, what.Click, "x"), ErrEventUnhandled)
+	th.Equal(ui.JawsEvent(elem, what.Custom, "other\tnull"), ErrEventUnhandled)
+	th.NoErr(rq.callAllEventHandlers(1, what.Custom, "visible\tnull"))
+	ui.JawsUpdate(elem)
+	th.Equal(rq.getElementByJid(1), (*Element)(nil))
+	elem2 := rq.getElementByJid(2)
+	th.True(elem2.HasTag(ts))
+	th.Equal(elem2.wsQueue, []wsMsg{{Data: `<div id="Jid.2" hidden>content</div>`, Jid: 1, What: what.Replace}})
+}
+
+func TestRequest_LazyRenderReplaces(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Span("later", LazyRender()))
+	rq.inCh <- wsMsg{Data: "visible\tnull", Jid: 1, What: what.Custom}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Replace\tJid.1\t\"<span id=\\\"Jid.2\\\">later</span>\"\n")
+	}
+}
+
+func Test_extractLazy(t *testing.T) {
+	th := newTestHelper(t)
+	rest, found := extractLazy([]interface{}{"a", LazyRender(), "b"})
+	th.True(found)
+	th.Equal(rest, []interface{}{"a", "b"})
+	rest, found = extractLazy([]interface{}{"a"})
+	th.True(!found)
+	th.Equal(rest, []interface{}{"a"})
+}
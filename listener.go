@@ -0,0 +1,49 @@
+package jaws
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"time"
+)
+
+// loopbackHandler serves requests from unix sockets as if they came
+// from the loopback address, since they have no remote IP.
+type loopbackHandler struct{ h http.Handler }
+
+func (lh loopbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !parseIP(r.RemoteAddr).IsValid() {
+		r.RemoteAddr = "127.0.0.1:0"
+	}
+	lh.h.ServeHTTP(w, r)
+}
+
+// ServeListener serves the JaWS endpoints on l until the Jaws is closed,
+// allowing the WebSocket to be served separately from the pages, such
+// as on another port, a unix socket or a listener requiring client
+// certificates. Set WebSocketURL to the URL browsers reach it at.
+//
+// Connections on unix sockets come from local processes such as a
+// reverse proxy, and are treated as coming from the loopback address.
+//
+// Returns nil when the Jaws is closed, or the error from http.Server.Serve.
+func (jw *Jaws) ServeListener(l net.Listener) (err error) {
+	var h http.Handler = jw
+	if _, ok := l.Addr().(*net.UnixAddr); ok {
+		h = loopbackHandler{h}
+	}
+	srv := &http.Server{Handler: h, ReadHeaderTimeout: 10 * time.Second}
+	stopCh := make(chan struct{})
+	defer close(stopCh)
+	go func() {
+		select {
+		case <-jw.Done():
+		case <-stopCh:
+		}
+		_ = srv.Close()
+	}()
+	if err = srv.Serve(l); errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package jaws
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestJaws_ServeListener_Unix(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+	jw.WebSocketURL = "wss://rt.example.com/"
+	th.NoErr(jw.GenerateHeadHTML())
+	th.True(strings.HasSuffix(jw.headPrefix, `var jawsWsBase="wss://rt.example.com";var jawsKey="`))
+
+	sock := filepath.Join(t.TempDir(), "jaws.sock")
+	l, err := net.Listen("unix", sock)
+	th.NoErr(err)
+	errCh := make(chan error, 1)
+	go func() { errCh <- jw.ServeListener(l) }()
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", sock)
+		},
+	}}
+	get := func(path string) int {
+		resp, err := client.Get("http://rt.example.com" + path)
+		th.NoErr(err)
+		if resp == nil {
+			return 0
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	th.Equal(get("/jaws/.ping"), http.StatusNoContent)
+
+	// proxied over the unix socket, so claimed as if from the loopback address
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.RemoteAddr = "127.0.0.1:1234"
+	rq := jw.NewRequest(hr)
+	th.Equal(get(PollPath+rq.JawsKeyString()), http.StatusOK)
+	client.CloseIdleConnections()
+
+	jw.Close()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case err = <-errCh:
+		th.NoErr(err)
+	}
+}
+
+func TestJaws_ServeListener_Error(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	th.NoErr(err)
+	l.Close()
+	th.True(jw.ServeListener(l) != nil)
+}
+
+func TestRequest_originPatterns_WebSocketURL(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	hr := httptest.NewRequest(http.MethodGet, "http://app.example.com:8080/", nil)
+	rq := jw.NewRequestWithOptions(hr, &RequestOptions{OriginPatterns: []string{"*.example.org"}})
+	th.Equal(rq.originPatterns(), []string{"*.example.org"})
+	jw.WebSocketURL = "wss://rt.example.com"
+	th.Equal(rq.originPatterns(), []string{"*.example.org", "app.example.com:8080"})
+}
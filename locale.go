@@ -0,0 +1,60 @@
+package jaws
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultLocale is the locale of Requests whose browser doesn't state
+// any preferred language.
+const DefaultLocale = "en"
+
+// Translator translates text shown to users, set it in Jaws.Translator.
+type Translator interface {
+	// JawsTranslate returns the text for key in the given locale, such
+	// as "de-CH", formatted using args if there are any.
+	JawsTranslate(locale, key string, args ...interface{}) string
+}
+
+// TranslatorFunc adapts an ordinary function to the Translator interface.
+type TranslatorFunc func(locale, key string, args ...interface{}) string
+
+func (fn TranslatorFunc) JawsTranslate(locale, key string, args ...interface{}) string {
+	return fn(locale, key, args...)
+}
+
+// acceptLanguages returns the language tags in the Accept-Language
+// header of the initial HTTP request, in the order they were given.
+func (rq *Request) acceptLanguages() (tags []string) {
+	if hr := rq.Initial; hr != nil {
+		for _, tag := range strings.Split(hr.Header.Get("Accept-Language"), ",") {
+			tag, _, _ = strings.Cut(tag, ";")
+			if tag = strings.TrimSpace(tag); tag != "" && tag != "*" {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	return
+}
+
+// Locale returns the first language tag in the Accept-Language header
+// of the initial HTTP request, such as "de-CH", or DefaultLocale.
+func (rq *Request) Locale() string {
+	if tags := rq.acceptLanguages(); len(tags) > 0 {
+		return tags[0]
+	}
+	return DefaultLocale
+}
+
+// Translate returns the text for key in the Request's Locale using
+// Jaws.Translator. If no Translator is set, it returns key formatted
+// with fmt.Sprintf if there are args, or else key as-is.
+func (rq *Request) Translate(key string, args ...interface{}) string {
+	if tr := rq.Jaws.Translator; tr != nil {
+		return tr.JawsTranslate(rq.Locale(), key, args...)
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(key, args...)
+	}
+	return key
+}
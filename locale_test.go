@@ -0,0 +1,40 @@
+package jaws
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequest_Locale(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	th.Equal(tj.NewRequest(nil).Locale(), DefaultLocale)
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "*")
+	th.Equal(tj.NewRequest(hr).Locale(), DefaultLocale)
+	hr.Header.Set("Accept-Language", " de-CH;q=0.9 , en;q=0.5")
+	rq := tj.NewRequest(hr)
+	th.Equal(rq.Locale(), "de-CH")
+	th.Equal(rq.acceptLanguages(), []string{"de-CH", "en"})
+}
+
+func TestRequest_Translate(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "sv-SE")
+	rq := tj.NewRequest(hr)
+	th.Equal(rq.Translate("Hello"), "Hello")
+	th.Equal(rq.Translate("Hello, %s!", "Ann"), "Hello, Ann!")
+
+	tj.Translator = TranslatorFunc(func(locale, key string, args ...interface{}) string {
+		return fmt.Sprintf("%s:%s:%v", locale, key, args)
+	})
+	th.Equal(rq.Translate("Hello %d", 1), "sv-SE:Hello %d:[1]")
+}
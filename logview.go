@@ -0,0 +1,453 @@
+package jaws
+
+import (
+	"bufio"
+	"encoding/json"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultLogViewLimit is the default maximum number of records a LogView retains.
+const DefaultLogViewLimit = 10000
+
+// DefaultLogViewWindow is the default maximum number of records rendered at once by a UiLogView.
+const DefaultLogViewWindow = 200
+
+// LogRecord is a single line in a LogView.
+type LogRecord struct {
+	When  time.Time
+	Level string
+	Text  string
+}
+
+var logLevels = []string{"debug", "info", "warning", "error"}
+
+func logLevelIndex(lvl string) int {
+	lvl = strings.ToLower(lvl)
+	if lvl == "warn" {
+		lvl = "warning"
+	}
+	for i, s := range logLevels {
+		if s == lvl {
+			return i
+		}
+	}
+	return -1
+}
+
+// LogView retains the most recent log records and the filter settings used
+// when rendering them. It is safe to use from multiple goroutines concurrently.
+//
+// A LogView is used as the tag for UiLogView elements and the filter inputs
+// returned from LevelFilter() and TextFilter(), so calling Jaws.Dirty() with
+// the LogView updates all of them.
+type LogView struct {
+	Limit  int              // (read-only) maximum number of records retained
+	Window int              // (read-only) maximum number of records rendered at once
+	mu     deadlock.RWMutex // protects following
+	recs   []logEntry       // ring buffer of records, oldest at head once full
+	head   int              // index in recs of the oldest record
+	seq    uint64           // number of records ever added
+	level  string
+	lvl    int // logLevelIndex of level
+	text   string
+	lower  string // text in lower case
+}
+
+// logEntry is a LogRecord with what filtering needs precomputed.
+type logEntry struct {
+	LogRecord
+	lower string // Text in lower case
+	lvl   int    // logLevelIndex of Level
+}
+
+// NewLogView returns a LogView retaining at most limit records.
+// If limit is less than one, DefaultLogViewLimit is used.
+func NewLogView(limit int) *LogView {
+	if limit < 1 {
+		limit = DefaultLogViewLimit
+	}
+	return &LogView{
+		Limit:  limit,
+		Window: DefaultLogViewWindow,
+	}
+}
+
+// Add appends a record, discarding the oldest if the Limit is exceeded.
+func (lv *LogView) Add(rec LogRecord) {
+	lv.add([]LogRecord{rec})
+}
+
+func (lv *LogView) add(recs []LogRecord) {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+	for _, rec := range recs {
+		ent := logEntry{LogRecord: rec, lower: strings.ToLower(rec.Text), lvl: logLevelIndex(rec.Level)}
+		if len(lv.recs) < lv.Limit {
+			lv.recs = append(lv.recs, ent)
+		} else if len(lv.recs) > 0 {
+			lv.recs[lv.head] = ent
+			lv.head = (lv.head + 1) % len(lv.recs)
+		}
+		lv.seq++
+	}
+}
+
+// at returns the record at index i, with zero being the oldest.
+func (lv *LogView) at(i int) *logEntry {
+	return &lv.recs[(lv.head+i)%len(lv.recs)]
+}
+
+// Tail reads lines from r until EOF or an error occurs, adding each as a record
+// with the given level and marking the LogView dirty in jw once for all lines
+// read at the same time.
+//
+// Returns nil on EOF.
+func (lv *LogView) Tail(jw *Jaws, r io.Reader, level string) (err error) {
+	br := bufio.NewReader(r)
+	var recs []LogRecord
+	for err == nil {
+		var line string
+		if line, err = br.ReadString('\n'); line != "" {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			recs = append(recs, LogRecord{When: time.Now(), Level: level, Text: line})
+		}
+		if len(recs) > 0 && (err != nil || br.Buffered() == 0) {
+			lv.add(recs)
+			jw.Dirty(lv)
+			recs = recs[:0]
+		}
+	}
+	if err == io.EOF {
+		err = nil
+	}
+	return
+}
+
+// TailChan adds records received on ch until it is closed, marking the
+// LogView dirty in jw once for all records that were waiting in ch.
+func (lv *LogView) TailChan(jw *Jaws, ch <-chan LogRecord) {
+	var recs []LogRecord
+	for rec := range ch {
+		recs = append(recs[:0], rec)
+		for more := true; more; {
+			select {
+			case rec, more = <-ch:
+				if more {
+					recs = append(recs, rec)
+				}
+			default:
+				more = false
+			}
+		}
+		lv.add(recs)
+		jw.Dirty(lv)
+	}
+}
+
+// Len returns the number of records retained.
+func (lv *LogView) Len() (n int) {
+	lv.mu.RLock()
+	n = len(lv.recs)
+	lv.mu.RUnlock()
+	return
+}
+
+func (lv *LogView) matchLocked(rec *logEntry) bool {
+	if lv.level != "" {
+		if lv.lvl >= 0 {
+			if rec.lvl < lv.lvl {
+				return false
+			}
+		} else if !strings.EqualFold(lv.level, rec.Level) {
+			return false
+		}
+	}
+	return lv.lower == "" || strings.Contains(rec.lower, lv.lower)
+}
+
+// Records returns the newest records that match the current filter,
+// at most max of them. If max is less than one, all matching records are returned.
+func (lv *LogView) Records(max int) (recs []LogRecord) {
+	lv.mu.RLock()
+	recs, _ = lv.recordsLocked(max, 0)
+	lv.mu.RUnlock()
+	return
+}
+
+// recordsLocked returns the newest matching records and the number of them added after sequence number since.
+func (lv *LogView) recordsLocked(max int, since uint64) (recs []LogRecord, newer int) {
+	first := lv.seq - uint64(len(lv.recs))
+	for i := len(lv.recs) - 1; i >= 0 && (max < 1 || len(recs) < max); i-- {
+		if ent := lv.at(i); lv.matchLocked(ent) {
+			recs = append(recs, ent.LogRecord)
+			if first+uint64(i) >= since {
+				newer++
+			}
+		}
+	}
+	for i, j := 0, len(recs)-1; i < j; i, j = i+1, j-1 {
+		recs[i], recs[j] = recs[j], recs[i]
+	}
+	return
+}
+
+// SetFilter sets the level and text filters.
+//
+// Records with a level below the level filter are hidden. If the level filter
+// is not one of "debug", "info", "warning" or "error", only records with exactly
+// that level are shown. Records not containing the text filter (case insensitive)
+// are hidden. Empty strings disable the respective filter.
+func (lv *LogView) SetFilter(level, text string) {
+	lv.mu.Lock()
+	lv.setLevelLocked(level)
+	lv.setTextLocked(text)
+	lv.mu.Unlock()
+}
+
+func (lv *LogView) setLevelLocked(level string) {
+	lv.level = level
+	lv.lvl = logLevelIndex(level)
+}
+
+func (lv *LogView) setTextLocked(text string) {
+	lv.text = text
+	lv.lower = strings.ToLower(text)
+}
+
+// Filter returns the current level and text filters.
+func (lv *LogView) Filter() (level, text string) {
+	lv.mu.RLock()
+	level, text = lv.level, lv.text
+	lv.mu.RUnlock()
+	return
+}
+
+// LevelFilter returns a StringSetter bound to the level filter, suitable for Select or Text inputs.
+func (lv *LogView) LevelFilter() StringSetter {
+	return logViewFilter{lv: lv, level: true}
+}
+
+// TextFilter returns a StringSetter bound to the text filter, suitable for Text inputs.
+func (lv *LogView) TextFilter() StringSetter {
+	return logViewFilter{lv: lv}
+}
+
+type logViewFilter struct {
+	lv    *LogView
+	level bool
+}
+
+func (f logViewFilter) JawsGetString(e *Element) string {
+	level, text := f.lv.Filter()
+	if f.level {
+		return level
+	}
+	return text
+}
+
+func (f logViewFilter) JawsSetString(e *Element, v string) (err error) {
+	f.lv.mu.Lock()
+	if f.level {
+		f.lv.setLevelLocked(v)
+	} else {
+		f.lv.setTextLocked(v)
+	}
+	f.lv.mu.Unlock()
+	return
+}
+
+func (f logViewFilter) JawsGetTag(rq *Request) interface{} {
+	return f.lv
+}
+
+func appendLogRecord(b []byte, rec *LogRecord) []byte {
+	b = append(b, `<div class="jaws-log jaws-log-`...)
+	b = append(b, html.EscapeString(strings.ToLower(rec.Level))...)
+	b = append(b, `">`...)
+	if !rec.When.IsZero() {
+		b = append(b, `<time>`...)
+		b = rec.When.AppendFormat(b, time.TimeOnly)
+		b = append(b, `</time> `...)
+	}
+	b = append(b, html.EscapeString(rec.Text)...)
+	b = append(b, "</div>"...)
+	return b
+}
+
+// logWindow is the part of the matching records of a LogView a UiLogView shows.
+type logWindow struct {
+	recs   []LogRecord // the records shown
+	offset int         // number of matching records before them
+	total  int         // number of matching records
+	first  uint64      // sequence number of the first record shown
+	newer  int         // number of records shown added at or after the since sequence number
+}
+
+// windowLocked returns at most max matching records starting with the first
+// one having a sequence number of at least first, or the newest ones if
+// follow is true or there are too few records after it to fill the window.
+func (lv *LogView) windowLocked(first uint64, follow bool, max int, since uint64) (w logWindow) {
+	base := lv.seq - uint64(len(lv.recs))
+	var idx []int
+	for i := range lv.recs {
+		if lv.matchLocked(lv.at(i)) {
+			if !follow && base+uint64(i) < first {
+				w.offset++
+			}
+			idx = append(idx, i)
+		}
+	}
+	w.total = len(idx)
+	w.offset = min(w.offset, w.total-min(w.total, max))
+	if follow {
+		w.offset = w.total - min(w.total, max)
+	}
+	w.first = lv.seq
+	for _, i := range idx[w.offset:min(w.offset+max, w.total)] {
+		if len(w.recs) == 0 {
+			w.first = base + uint64(i)
+		}
+		if base+uint64(i) >= since {
+			w.newer++
+		}
+		w.recs = append(w.recs, lv.at(i).LogRecord)
+	}
+	return
+}
+
+// seqOfLocked returns the sequence number of the matching record at index n.
+func (lv *LogView) seqOfLocked(n int) uint64 {
+	base := lv.seq - uint64(len(lv.recs))
+	for i := range lv.recs {
+		if lv.matchLocked(lv.at(i)) {
+			if n--; n < 0 {
+				return base + uint64(i)
+			}
+		}
+	}
+	return lv.seq
+}
+
+// logScrollEvent is the custom event sent when a UiLogView is scrolled,
+// with the indices of the first and last matching records visible.
+const logScrollEvent = "scroll"
+
+// UiLogView renders the records of a LogView that match it's filter.
+//
+// Only a window of at most Window records is rendered. It starts out
+// showing the newest records, and follows new ones while the newest is
+// shown. The browser pads the element so that it's scrollbar covers all
+// matching records, and when the user scrolls outside the window the
+// records around the visible ones are rendered instead. Style the element
+// with a height and "overflow-y: auto" for it to scroll.
+type UiLogView struct {
+	UiHtml
+	*LogView
+	mu      deadlock.Mutex // protects following
+	seq     uint64         // LogView sequence number when last rendered
+	start   uint64         // sequence number of the first record to show, unless following
+	follow  bool           // if true, the newest records are shown
+	first   uint64         // sequence number of the first record shown
+	shown   int            // number of records currently shown in the browser
+	offset  int            // number of matching records before those shown
+	total   int            // number of matching records when last rendered
+	filterL string         // level filter when last rendered
+	filterT string         // text filter when last rendered
+}
+
+func NewUiLogView(lv *LogView) *UiLogView {
+	return &UiLogView{LogView: lv, follow: true}
+}
+
+// windowAttr returns the value of the data-jaws-logview attribute.
+func (w *logWindow) windowAttr() string {
+	return strconv.Itoa(w.offset) + " " + strconv.Itoa(w.total)
+}
+
+func (ui *UiLogView) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	e.Tag(ui)
+	ui.parseGetter(e, ui.LogView)
+	attrs := ui.parseParams(e, params)
+	ui.LogView.mu.RLock()
+	ui.mu.Lock()
+	win := ui.windowLocked(ui.start, ui.follow, ui.Window, ui.LogView.seq)
+	ui.seq, ui.first, ui.shown, ui.offset, ui.total = ui.LogView.seq, win.first, len(win.recs), win.offset, win.total
+	ui.filterL, ui.filterT = ui.LogView.level, ui.LogView.text
+	ui.mu.Unlock()
+	ui.LogView.mu.RUnlock()
+	var b []byte
+	for i := range win.recs {
+		b = appendLogRecord(b, &win.recs[i])
+	}
+	attrs = append(attrs, `data-jaws-logview="`+win.windowAttr()+`"`)
+	return WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(b), attrs...) // #nosec G203
+}
+
+func (ui *UiLogView) JawsUpdate(e *Element) {
+	ui.LogView.mu.RLock()
+	ui.mu.Lock()
+	win := ui.windowLocked(ui.start, ui.follow, ui.Window, ui.seq)
+	// if the window starts with the same record, only new records need to be appended
+	appendOnly := ui.first == win.first && ui.shown+win.newer == len(win.recs) &&
+		ui.filterL == ui.LogView.level && ui.filterT == ui.LogView.text
+	moved := ui.offset != win.offset || ui.total != win.total
+	ui.seq, ui.first, ui.shown, ui.offset, ui.total = ui.LogView.seq, win.first, len(win.recs), win.offset, win.total
+	ui.filterL, ui.filterT = ui.LogView.level, ui.LogView.text
+	ui.mu.Unlock()
+	ui.LogView.mu.RUnlock()
+	var b []byte
+	if appendOnly {
+		for i := len(win.recs) - win.newer; i < len(win.recs); i++ {
+			b = appendLogRecord(b, &win.recs[i])
+		}
+		if len(b) > 0 {
+			e.Append(template.HTML(b)) // #nosec G203
+		}
+	} else {
+		for i := range win.recs {
+			b = appendLogRecord(b, &win.recs[i])
+		}
+		e.SetInner(template.HTML(b)) // #nosec G203
+	}
+	if moved || !appendOnly {
+		e.SetAttr("data-jaws-logview", win.windowAttr())
+	}
+}
+
+// JawsEvent moves the window of records shown when the browser reports
+// which records are visible after scrolling.
+func (ui *UiLogView) JawsEvent(e *Element, wht what.What, val string) error {
+	if wht == what.Custom {
+		if event, data, _ := strings.Cut(val, "\t"); event == logScrollEvent {
+			var visible [2]int
+			if err := json.Unmarshal([]byte(data), &visible); err != nil {
+				return err
+			}
+			ui.LogView.mu.RLock()
+			ui.mu.Lock()
+			ui.follow = visible[1] >= ui.total-1
+			if !ui.follow {
+				start := max(0, visible[0]-max(0, ui.Window-(visible[1]-visible[0]+1))/2)
+				ui.start = ui.seqOfLocked(start)
+			}
+			ui.mu.Unlock()
+			ui.LogView.mu.RUnlock()
+			e.Dirty(ui)
+			return nil
+		}
+	}
+	return ErrEventUnhandled
+}
+
+// LogView renders a HTML div element showing the records of the LogView, see UiLogView.
+func (rq RequestWriter) LogView(lv *LogView, params ...interface{}) error {
+	return rq.UI(NewUiLogView(lv), params...)
+}
@@ -0,0 +1,206 @@
+package jaws
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestLogView_AddAndFilter(t *testing.T) {
+	th := newTestHelper(t)
+	lv := NewLogView(3)
+	th.Equal(lv.Window, DefaultLogViewWindow)
+	lv.Add(LogRecord{Level: "debug", Text: "one"})
+	lv.Add(LogRecord{Level: "info", Text: "two"})
+	lv.Add(LogRecord{Level: "warn", Text: "three"})
+	lv.Add(LogRecord{Level: "error", Text: "four"})
+	th.Equal(lv.Len(), 3)
+	th.Equal(len(lv.Records(0)), 3)
+	th.Equal(lv.Records(1)[0].Text, "four")
+
+	lv.SetFilter("warning", "")
+	th.Equal(len(lv.Records(0)), 2)
+	lv.SetFilter("info", "THR")
+	recs := lv.Records(0)
+	th.Equal(len(recs), 1)
+	th.Equal(recs[0].Text, "three")
+	lv.SetFilter("custom", "")
+	th.Equal(len(lv.Records(0)), 0)
+	lv.Add(LogRecord{Level: "Custom", Text: "five"})
+	th.Equal(len(lv.Records(0)), 1)
+}
+
+func TestLogView_Wraparound(t *testing.T) {
+	th := newTestHelper(t)
+	lv := NewLogView(3)
+	for i := 0; i < 10; i++ {
+		lv.Add(LogRecord{Level: "info", Text: "Line " + strconv.Itoa(i)})
+		var want []string
+		for j := max(0, i-2); j <= i; j++ {
+			want = append(want, "Line "+strconv.Itoa(j))
+		}
+		var got []string
+		for _, rec := range lv.Records(0) {
+			got = append(got, rec.Text)
+		}
+		th.Equal(got, want)
+	}
+	th.Equal(lv.Len(), 3)
+	lv.SetFilter("", "LINE 8")
+	th.Equal(len(lv.Records(0)), 1)
+
+	var zero LogView
+	zero.Add(LogRecord{Text: "x"})
+	th.Equal(zero.Len(), 0)
+}
+
+func TestLogView_Tail(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	lv := NewLogView(0)
+	th.Equal(lv.Limit, DefaultLogViewLimit)
+	th.NoErr(lv.Tail(jw, strings.NewReader("a\nb\nc\n"), "info"))
+	th.Equal(lv.Len(), 3)
+	th.NoErr(lv.Tail(jw, strings.NewReader("x\r\n\ny"), "info"))
+	th.Equal(lv.Len(), 6)
+	th.Equal(lv.Records(3)[0].Text, "x")
+	th.Equal(lv.Records(3)[1].Text, "")
+	th.Equal(lv.Records(3)[2].Text, "y")
+	ch := make(chan LogRecord, 2)
+	ch <- LogRecord{Text: "d"}
+	ch <- LogRecord{Text: "e"}
+	close(ch)
+	lv.TailChan(jw, ch)
+	th.Equal(lv.Len(), 8)
+}
+
+func TestRequest_LogView(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	lv := NewLogView(10)
+	lv.Window = 2
+	lv.Add(LogRecord{Level: "info", Text: "<one>"})
+	th.NoErr(rq.LogView(lv, "hidden"))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" hidden data-jaws-logview="0 1"><div class="jaws-log jaws-log-info">&lt;one&gt;</div></div>`)
+
+	elem := rq.getElementByJid(1)
+	ui := elem.Ui().(*UiLogView)
+
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	lv.Add(LogRecord{Level: "info", Text: "two"})
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{
+		Data: `<div class="jaws-log jaws-log-info">two</div>`,
+		Jid:  1,
+		What: what.Append,
+	}, {
+		Data: "data-jaws-logview\n0 2",
+		Jid:  1,
+		What: what.SAttr,
+	}})
+	elem.wsQueue = elem.wsQueue[:0]
+
+	lv.Add(LogRecord{Level: "info", Text: "three"})
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{
+		Data: `<div class="jaws-log jaws-log-info">two</div><div class="jaws-log jaws-log-info">three</div>`,
+		Jid:  1,
+		What: what.Inner,
+	}, {
+		Data: "data-jaws-logview\n1 3",
+		Jid:  1,
+		What: what.SAttr,
+	}})
+	elem.wsQueue = elem.wsQueue[:0]
+
+	th.NoErr(lv.TextFilter().JawsSetString(elem, "two"))
+	th.Equal(lv.TextFilter().JawsGetString(elem), "two")
+	th.NoErr(lv.LevelFilter().JawsSetString(elem, "info"))
+	th.Equal(lv.LevelFilter().JawsGetString(elem), "info")
+	th.Equal(lv.TextFilter().(TagGetter).JawsGetTag(nil), lv)
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{
+		Data: `<div class="jaws-log jaws-log-info">two</div>`,
+		Jid:  1,
+		What: what.Inner,
+	}, {
+		Data: "data-jaws-logview\n0 1",
+		Jid:  1,
+		What: what.SAttr,
+	}})
+}
+
+func TestRequest_LogViewScroll(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	lv := NewLogView(100)
+	lv.Window = 4
+	for i := 0; i < 20; i++ {
+		lv.Add(LogRecord{Text: strconv.Itoa(i)})
+	}
+	th.NoErr(rq.LogView(lv))
+	th.True(strings.Contains(rq.BodyString(), `data-jaws-logview="16 20"><div class="jaws-log jaws-log-">16</div>`))
+
+	elem := rq.getElementByJid(1)
+	ui := elem.Ui().(*UiLogView)
+	th.True(elem.HasTag(ui))
+	th.Equal(ui.JawsEvent(elem, what.Custom, "scroll\tnot json") != nil, true)
+	th.Equal(ui.JawsEvent(elem, what.Click, ""), ErrEventUnhandled)
+
+	// scrolling up renders the records around the visible ones
+	th.NoErr(ui.JawsEvent(elem, what.Custom, "scroll\t[5,6]"))
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{
+		Data: `<div class="jaws-log jaws-log-">4</div><div class="jaws-log jaws-log-">5</div>` +
+			`<div class="jaws-log jaws-log-">6</div><div class="jaws-log jaws-log-">7</div>`,
+		Jid:  1,
+		What: what.Inner,
+	}, {
+		Data: "data-jaws-logview\n4 20",
+		Jid:  1,
+		What: what.SAttr,
+	}})
+	elem.wsQueue = elem.wsQueue[:0]
+
+	// new records don't move the window while not following
+	lv.Add(LogRecord{Text: "20"})
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{
+		Data: "data-jaws-logview\n4 21",
+		Jid:  1,
+		What: what.SAttr,
+	}})
+	elem.wsQueue = elem.wsQueue[:0]
+
+	// scrolling to the end follows new records again
+	th.NoErr(ui.JawsEvent(elem, what.Custom, "scroll\t[19,20]"))
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 2)
+	th.Equal(elem.wsQueue[1].Data, "data-jaws-logview\n17 21")
+	elem.wsQueue = elem.wsQueue[:0]
+
+	// scrolling past the records kept shows the oldest
+	th.NoErr(ui.JawsEvent(elem, what.Custom, "scroll\t[0,1]"))
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue[1].Data, "data-jaws-logview\n0 21")
+}
+
+func BenchmarkLogView_Add(b *testing.B) {
+	lv := NewLogView(0)
+	rec := LogRecord{Level: "info", Text: "Some log line"}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		lv.Add(rec)
+	}
+}
@@ -0,0 +1,314 @@
+package jaws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkdata/deadlock"
+)
+
+// PollPath is the URI prefix of the long-polling transport, followed by the Request key.
+//
+// A GET waits for outbound messages and returns them as text, a POST
+// delivers incoming messages in the same format as WebSocket messages.
+const PollPath = "/jaws/.poll/"
+
+// DefaultPollInterval is the PollInterval used if Jaws.PollInterval isn't set.
+const DefaultPollInterval = 25 * time.Second
+
+// PollSeqHeader is the response header holding the sequence number of a
+// long-polling response with messages. The browser acknowledges receiving
+// them by passing the number in the "ack" query parameter of the next poll.
+const PollSeqHeader = "X-Jaws-Poll-Seq"
+
+// ErrPollTimeout is the cause of a long-polling Request being cancelled
+// because the browser stopped polling.
+var ErrPollTimeout = errors.New("long-poll timeout")
+
+var headerContentTypeText = []string{"text/plain; charset=utf-8"}
+
+// poller is the state of a Request using the long-polling transport.
+// Outbound messages stay in the Request's outbound queue until the browser
+// polls for them, so Jaws.Overflow applies just as with a WebSocket. Messages
+// taken from the queue are kept until the browser acknowledges them.
+type poller struct {
+	incomingCh chan wsMsg
+	outboundCh <-chan string  // the Request's outbound queue, set by processPoll
+	limits     readLimits     // limits on incoming messages
+	receiving  deadlock.Mutex // serializes receive, which applies limits
+	serving    deadlock.Mutex // serializes serve, protects following
+	seq        uint64         // sequence number of the last response with messages
+	sent       string         // messages in the last response, until acknowledged
+	taken      string         // messages taken from outboundCh but not yet sent
+	mu         deadlock.Mutex // protects following
+	polls      int            // number of polls waiting for messages
+	lastPoll   time.Time      // when the last poll ended
+	stopCh     chan struct{}  // closed to end the current poll when a newer one arrives
+}
+
+func newPoller(outboundCh <-chan string) *poller {
+	return &poller{
+		incomingCh: make(chan wsMsg),
+		outboundCh: outboundCh,
+		lastPoll:   time.Now(),
+	}
+}
+
+// pollInterval returns how long a poll waits for messages.
+func (jw *Jaws) pollInterval() time.Duration {
+	if jw.PollInterval > 0 {
+		return jw.PollInterval
+	}
+	return DefaultPollInterval
+}
+
+// servePoll handles requests to PollPath.
+func (jw *Jaws) servePoll(w http.ResponseWriter, r *http.Request, uri string) {
+	uri, _, _ = strings.Cut(uri, "?")
+	jawsKey := JawsKeyValue(strings.TrimPrefix(uri, PollPath))
+	w.Header()["Cache-Control"] = headerCacheNoCache
+	switch r.Method {
+	case http.MethodGet:
+		if rq, p := jw.getPoller(jawsKey, r); p != nil {
+			rq.markSeen()
+			p.serve(rq, w, r)
+			return
+		}
+		// the Request outlives the HTTP request that claims it
+		if rq := jw.UseRequest(jawsKey, r.WithContext(context.WithoutCancel(r.Context()))); rq != nil {
+			if rq.startPoll(w, r) {
+				return
+			}
+		}
+	case http.MethodPost:
+		if rq, p := jw.getPoller(jawsKey, r); p != nil {
+			rq.markSeen()
+			p.receive(rq, w, r)
+			return
+		}
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+// getPoller returns the Request with the given key and it's poller,
+// if it is using the long-polling transport from the same IP as r.
+func (jw *Jaws) getPoller(jawsKey uint64, r *http.Request) (rq *Request, p *poller) {
+	jw.mu.RLock()
+	rq = jw.requests[jawsKey]
+	jw.mu.RUnlock()
+	if rq != nil {
+		rq.mu.RLock()
+		if rq.JawsKey == jawsKey && equalIP(rq.remoteIP, parseIP(r.RemoteAddr)) {
+			p = rq.poll
+		}
+		rq.mu.RUnlock()
+	}
+	return
+}
+
+// startPoll starts processing messages for the Request using the
+// long-polling transport. Returns false if it's already being served.
+func (rq *Request) startPoll(w http.ResponseWriter, r *http.Request) bool {
+	if ok, _ := rq.startServe(); !ok {
+		return false
+	}
+	err := rq.checkProtocol(r)
+	if err == nil {
+		err = rq.authenticate(r)
+	}
+	if err == nil {
+		err = rq.onConnect()
+	}
+	w.Header()["Content-Type"] = headerContentTypeText
+	if err == nil {
+		broadcastMsgCh := rq.Jaws.subscribe(rq, 4+len(rq.elems)*4)
+		outboundCh := make(chan string, cap(broadcastMsgCh))
+		p := newPoller(outboundCh)
+		p.limits = rq.readLimits()
+		rq.mu.Lock()
+		rq.poll = p
+		rq.lastSeen = time.Now()
+		rq.mu.Unlock()
+		go rq.processPoll(p, broadcastMsgCh, outboundCh)
+		w.WriteHeader(http.StatusOK)
+		return true
+	}
+	msg := rq.connectErrorMsg(err)
+	_, _ = w.Write(msg.Append(nil)) // #nosec G104
+	rq.cancel(err)
+	rq.stopServe()
+	return true
+}
+
+// processPoll runs the message processing loop for a long-polling Request.
+func (rq *Request) processPoll(p *poller, broadcastMsgCh chan Message, outboundCh chan string) {
+	defer rq.stopServe()
+	rq.setOutbound(outboundCh)
+	defer rq.setOutbound(nil)
+	rq.checkAssetVersion()
+	rq.checkTabs(false)
+	go p.watch(rq, rq.Jaws.pollInterval())
+	rq.process(broadcastMsgCh, p.incomingCh, outboundCh) // unsubscribes broadcastMsgCh, closes outboundCh
+}
+
+// watch cancels the Request if the browser hasn't polled for two poll intervals.
+func (p *poller) watch(rq *Request, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-rq.Done():
+			return
+		case now := <-t.C:
+			p.mu.Lock()
+			idle := p.polls == 0 && now.Sub(p.lastPoll) > 2*interval
+			p.mu.Unlock()
+			if idle {
+				rq.cancel(ErrPollTimeout)
+				return
+			}
+		}
+	}
+}
+
+// takeLocked moves the queued messages to p.taken, starting with first.
+// Returns true if the queue is closed.
+func (p *poller) takeLocked(first string) (closed bool) {
+	var sb strings.Builder
+	sb.WriteString(p.taken)
+	sb.WriteString(first)
+	for !closed {
+		select {
+		case msg, ok := <-p.outboundCh:
+			closed = !ok
+			sb.WriteString(msg)
+		default:
+			p.taken = wsCoalesce(sb.String())
+			return
+		}
+	}
+	p.taken = wsCoalesce(sb.String())
+	return
+}
+
+// start registers a new poll, ending the one in progress if any.
+func (p *poller) start() (stopCh chan struct{}) {
+	stopCh = make(chan struct{})
+	p.mu.Lock()
+	p.polls++
+	if p.stopCh != nil {
+		close(p.stopCh)
+	}
+	p.stopCh = stopCh
+	p.mu.Unlock()
+	return
+}
+
+func (p *poller) stop(stopCh chan struct{}) {
+	p.mu.Lock()
+	p.polls--
+	p.lastPoll = time.Now()
+	if p.stopCh == stopCh {
+		p.stopCh = nil
+	}
+	p.mu.Unlock()
+}
+
+// serve waits up to the poll interval for outbound messages and writes them,
+// along with those of the previous response unless the browser acknowledged
+// them. Only one poll is served at a time, a new one ends the previous.
+func (p *poller) serve(rq *Request, w http.ResponseWriter, r *http.Request) {
+	stopCh := p.start()
+	defer p.stop(stopCh)
+	p.serving.Lock()
+	defer p.serving.Unlock()
+	if ack, err := strconv.ParseUint(r.URL.Query().Get("ack"), 10, 64); err == nil && ack == p.seq {
+		p.sent = ""
+	}
+	t := time.NewTimer(rq.Jaws.pollInterval())
+	defer t.Stop()
+	doneCh := rq.Done()
+	closed := p.takeLocked("")
+	for timeout := false; p.sent == "" && p.taken == "" && !timeout; {
+		if closed {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		select {
+		case <-r.Context().Done():
+			return
+		case <-stopCh:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-doneCh:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		case <-t.C:
+			timeout = true
+		case msg := <-p.outboundCh:
+			closed = p.takeLocked(msg)
+		}
+	}
+	msgs := p.sent + p.taken
+	hdr := w.Header()
+	if msgs != "" {
+		p.seq++
+		p.sent = wsCoalesce(msgs)
+		p.taken = ""
+		msgs = p.sent
+		hdr[PollSeqHeader] = []string{strconv.FormatUint(p.seq, 10)}
+	}
+	hdr["Content-Type"] = headerContentTypeText
+	hdr["Vary"] = headerAcceptEncoding
+	if minSize := rq.Jaws.compressMinSize(); minSize >= 0 && len(msgs) >= minSize && acceptsGZip(r) {
+		hdr["Content-Encoding"] = headerContentGZip
+		gw := gzip.NewWriter(w)
+		_, _ = io.WriteString(gw, msgs) // #nosec G104
+		_ = gw.Close()
+		return
+	}
+	_, _ = io.WriteString(w, msgs) // #nosec G104
+}
+
+// receive delivers the messages in the body of r to the Request.
+func (p *poller) receive(rq *Request, w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 2*int64(rq.maxPasteSize())+64*1024))
+	if err != nil {
+		w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+	p.receiving.Lock()
+	defer p.receiving.Unlock()
+	doneCh := rq.Done()
+	for _, line := range bytes.SplitAfter(body, []byte{'\n'}) {
+		if err = p.limits.checkSize(line); err != nil {
+			_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w", rq, err))
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+			return
+		}
+		if !p.limits.wait(r.Context(), doneCh) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if msg, ok := wsParse(line); ok {
+			select {
+			case <-doneCh:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case p.incomingCh <- msg:
+			}
+		}
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
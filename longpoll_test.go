@@ -0,0 +1,305 @@
+package jaws
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func servePollTest(jw *Jaws, method, path, body string) *httptest.ResponseRecorder {
+	rr := httptest.NewRecorder()
+	jw.ServeHTTP(rr, newProtocolRequest(method, path, strings.NewReader(body)))
+	return rr
+}
+
+func TestLongPoll_Exchange(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	fooError := errors.New("this foo failed")
+	gotCallCh := make(chan struct{})
+	rq.Register(("foo"), func(e *Element, evt what.What, val string) error {
+		close(gotCallCh)
+		return fooError
+	})
+	path := PollPath + rq.JawsKeyString()
+
+	th.Equal(servePollTest(jw, http.MethodPost, path, "").Code, http.StatusNotFound)
+	rr := servePollTest(jw, http.MethodGet, path, "")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Header()["Content-Type"], headerContentTypeText)
+	th.Equal(rr.Header()["Cache-Control"], headerCacheNoCache)
+	th.Equal(rr.Body.Len(), 0)
+
+	msg := wsMsg{Jid: jidForTag(rq, Tag("foo")), What: what.Input}
+	rr = servePollTest(jw, http.MethodPost, path, "garbage\n"+msg.Format())
+	th.Equal(rr.Code, http.StatusNoContent)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-gotCallCh:
+	}
+
+	rr = servePollTest(jw, http.MethodGet, path, "")
+	th.Equal(rr.Code, http.StatusOK)
+	var m2 wsMsg
+	m2.FillAlert(fooError)
+	th.Equal(rr.Body.String(), m2.Format())
+
+	th.Equal(servePollTest(jw, http.MethodPut, path, "").Code, http.StatusMethodNotAllowed)
+	th.Equal(servePollTest(jw, http.MethodGet, PollPath+JawsKeyString(1), "").Code, http.StatusNotFound)
+}
+
+func TestLongPoll_Timeout(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+	jw.PollInterval = time.Millisecond * 10
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	path := PollPath + rq.JawsKeyString()
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+	doneCh := rq.Done()
+
+	start := time.Now()
+	rr := servePollTest(jw, http.MethodGet, path, "")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.Len(), 0)
+	th.True(time.Since(start) >= jw.PollInterval)
+
+	// stops polling, so the Request is cancelled
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusNotFound)
+}
+
+func TestLongPoll_ConnectFnFails(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	rq.SetConnectFn(func(_ *Request) error { return errors.New("nope") })
+	path := PollPath + rq.JawsKeyString()
+
+	rr := servePollTest(jw, http.MethodGet, path, "")
+	th.Equal(rr.Code, http.StatusOK)
+	var msg wsMsg
+	msg.FillAlert(errors.New("nope"))
+	th.Equal(rr.Body.String(), msg.Format())
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusNotFound)
+}
+
+func TestRequest_HeadHTML_LongPoll(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.LongPoll = true
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	var sb strings.Builder
+	th.NoErr(rq.HeadHTML(&sb))
+	th.True(strings.Contains(sb.String(), rq.JawsKeyString()+`";var jawsPoll=true;</script>`))
+}
+
+func TestLongPoll_Compression(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	path := PollPath + rq.JawsKeyString()
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+	for {
+		rq.mu.RLock()
+		started := rq.outbound != nil
+		rq.mu.RUnlock()
+		if started {
+			break
+		}
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	for i := 0; i <= cap(jw.subCh); i++ {
+		jw.subCh <- subscription{} // ensure subscription is processed
+	}
+
+	poll := func() *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		hr := newProtocolRequest(http.MethodGet, path, nil)
+		hr.Header.Add("Accept-Encoding", "gzip, deflate")
+		jw.ServeHTTP(rr, hr)
+		return rr
+	}
+
+	text := strings.Repeat("compressible ", 100)
+	want := (&wsMsg{What: what.Alert, Data: "info\n" + text}).Format()
+	rq.Alert("info", text)
+	rr := poll()
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Header()["Content-Encoding"], headerContentGZip)
+	th.Equal(rr.Header()["Vary"], headerAcceptEncoding)
+	th.True(rr.Body.Len() < len(want)/2)
+	gr, err := gzip.NewReader(rr.Body)
+	th.NoErr(err)
+	b, err := io.ReadAll(gr)
+	th.NoErr(err)
+	th.Equal(string(b), want)
+
+	jw.CompressMinSize = -1
+	rq.Alert("info", text)
+	rr = poll()
+	th.Equal(rr.Header()["Content-Encoding"], []string(nil))
+	th.Equal(rr.Body.String(), want)
+}
+
+func TestLongPoll_Overflow(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDisconnect, OverflowDropOldest} {
+		th := newTestHelper(t)
+		jw := New()
+		var logBuf bytes.Buffer
+		jw.Logger = log.New(&logBuf, "", 0)
+		go jw.Serve()
+		jw.Overflow = policy
+
+		rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+		path := PollPath + rq.JawsKeyString()
+		th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+		for i := 0; i <= cap(jw.subCh); i++ {
+			jw.subCh <- subscription{} // ensure subscription is processed
+		}
+
+		doneCh := rq.Done()
+
+		// no poll is pending, so the messages stay in the outbound queue
+		const n = 20
+		for i := 0; i < n; i++ {
+			rq.Alert("info", strconv.Itoa(i))
+			time.Sleep(time.Millisecond)
+		}
+
+		if policy == OverflowDisconnect {
+			select {
+			case <-th.C:
+				th.Timeout()
+			case <-doneCh:
+			}
+			jw.Close()
+			th.True(strings.Contains(logBuf.String(), ErrWebsocketQueueOverflow.Error()))
+		} else {
+			for rq.SendStats().Dropped == 0 {
+				select {
+				case <-th.C:
+					th.Timeout()
+				case <-time.After(time.Millisecond):
+				}
+			}
+			rr := servePollTest(jw, http.MethodGet, path, "")
+			th.Equal(rr.Code, http.StatusOK)
+			body := rr.Body.String()
+			th.True(strings.Count(body, "\n") < n)
+			th.True(strings.Contains(body, strconv.Quote("info\n"+strconv.Itoa(n-1))))
+		}
+		jw.Close()
+	}
+}
+
+func TestLongPoll_Ack(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+	jw.PollInterval = time.Millisecond * 50
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	path := PollPath + rq.JawsKeyString()
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+	for i := 0; i <= cap(jw.subCh); i++ {
+		jw.subCh <- subscription{} // ensure subscription is processed
+	}
+
+	want := (&wsMsg{What: what.Alert, Data: "info\nx"}).Format()
+	rq.Alert("info", "x")
+	rr := servePollTest(jw, http.MethodGet, path+"?ack=0", "")
+	th.Equal(rr.Body.String(), want)
+	th.Equal(rr.Header().Get(PollSeqHeader), "1")
+
+	// the response was lost, so the browser acknowledges the one before
+	rr = servePollTest(jw, http.MethodGet, path+"?ack=0", "")
+	th.Equal(rr.Body.String(), want)
+	th.Equal(rr.Header().Get(PollSeqHeader), "2")
+
+	rr = servePollTest(jw, http.MethodGet, path+"?ack=2", "")
+	th.Equal(rr.Code, http.StatusOK)
+	th.Equal(rr.Body.Len(), 0)
+	th.Equal(rr.Header().Get(PollSeqHeader), "")
+}
+
+func TestLongPoll_NewPollEndsPrevious(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	path := PollPath + rq.JawsKeyString()
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+	for i := 0; i <= cap(jw.subCh); i++ {
+		jw.subCh <- subscription{} // ensure subscription is processed
+	}
+
+	firstCh := make(chan *httptest.ResponseRecorder)
+	go func() { firstCh <- servePollTest(jw, http.MethodGet, path, "") }()
+	for {
+		_, p := jw.getPoller(rq.JawsKey, httptest.NewRequest(http.MethodGet, path, nil))
+		p.mu.Lock()
+		polls := p.polls
+		p.mu.Unlock()
+		if polls > 0 {
+			break
+		}
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+
+	secondCh := make(chan *httptest.ResponseRecorder)
+	go func() { secondCh <- servePollTest(jw, http.MethodGet, path, "") }()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case rr := <-firstCh:
+		th.Equal(rr.Code, http.StatusNoContent)
+	}
+
+	rq.Alert("info", "x")
+	select {
+	case <-th.C:
+		th.Timeout()
+	case rr := <-secondCh:
+		th.Equal(rr.Body.String(), (&wsMsg{What: what.Alert, Data: "info\nx"}).Format())
+	}
+}
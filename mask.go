@@ -0,0 +1,99 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrMaskMismatch is returned when an input value doesn't match it's Mask.
+var ErrMaskMismatch = errors.New("value does not match mask")
+
+// Mask is a declarative input mask. In the mask, '9' matches a digit,
+// 'a' matches a letter and '*' matches a letter or digit, all ASCII.
+// Any other character is a literal that is inserted as the user types.
+// A backslash makes the character following it a literal.
+//
+// The raw value is the characters matched by placeholders, so with
+// MaskPhone the formatted value "(555) 123-4567" has the raw value "5551234567".
+type Mask string
+
+const (
+	MaskPhone      = Mask("(999) 999-9999")
+	MaskCreditCard = Mask("9999 9999 9999 9999")
+)
+
+func maskMatch(placeholder, r rune) bool {
+	isDigit := r >= '0' && r <= '9'
+	isLetter := (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+	switch placeholder {
+	case '9':
+		return isDigit
+	case 'a':
+		return isLetter
+	case '*':
+		return isDigit || isLetter
+	}
+	return false
+}
+
+// apply formats s using the mask. Input runes not matching a placeholder
+// are skipped, and literals are only written while input remains.
+// The Javascript function jawsMaskApply must match this.
+func (m Mask) apply(s string) (formatted, raw string, complete, exact bool) {
+	in, mask := []rune(s), []rune(string(m))
+	var fb, rb []rune
+	var i, placeholders, filled, skipped int
+	for j := 0; j < len(mask); j++ {
+		c := mask[j]
+		literal := c != '9' && c != 'a' && c != '*'
+		if c == '\\' && j+1 < len(mask) {
+			j++
+			c, literal = mask[j], true
+		}
+		if literal {
+			if i < len(in) {
+				if in[i] == c {
+					i++
+				}
+				fb = append(fb, c)
+			}
+			continue
+		}
+		placeholders++
+		for i < len(in) && !maskMatch(c, in[i]) {
+			i++
+			skipped++
+		}
+		if i < len(in) {
+			fb = append(fb, in[i])
+			rb = append(rb, in[i])
+			i++
+			filled++
+		}
+	}
+	complete = filled == placeholders
+	exact = complete && skipped == 0 && i == len(in)
+	return string(fb), string(rb), complete, exact
+}
+
+// Format returns s formatted using the mask, skipping characters that
+// don't fit. It accepts both raw and formatted values.
+func (m Mask) Format(s string) (formatted string) {
+	formatted, _, _, _ = m.apply(s)
+	return
+}
+
+// Parse validates s against the mask and returns the raw and formatted
+// values. Literals may be omitted in s, but every placeholder must be
+// filled and no other characters may be present. An empty s is valid.
+//
+// The error does not include s, as masked values are often sensitive.
+func (m Mask) Parse(s string) (raw, formatted string, err error) {
+	if s != "" {
+		var exact bool
+		if formatted, raw, _, exact = m.apply(s); !exact {
+			raw, formatted, err = "", "", fmt.Errorf("%w %q", ErrMaskMismatch, string(m))
+		}
+	}
+	return
+}
@@ -0,0 +1,48 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestMask_Format(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(MaskPhone.Format(""), "")
+	th.Equal(MaskPhone.Format("5"), "(5")
+	th.Equal(MaskPhone.Format("555"), "(555")
+	th.Equal(MaskPhone.Format("5551"), "(555) 1")
+	th.Equal(MaskPhone.Format("5551234567"), "(555) 123-4567")
+	th.Equal(MaskPhone.Format("(555) 123-4567"), "(555) 123-4567")
+	th.Equal(MaskPhone.Format("555.123.4567 ext 89"), "(555) 123-4567")
+	th.Equal(MaskCreditCard.Format("4111111111111111"), "4111 1111 1111 1111")
+	th.Equal(Mask("aa-**").Format("se1x2"), "se-1x")
+	th.Equal(Mask(`+1 \9 999`).Format("456"), "+1 9 456")
+	th.Equal(Mask(`+1 \9 999`).Format("+1 9 123"), "+1 9 123")
+}
+
+func TestMask_Parse(t *testing.T) {
+	th := newTestHelper(t)
+	raw, formatted, err := MaskPhone.Parse("(555) 123-4567")
+	th.NoErr(err)
+	th.Equal(raw, "5551234567")
+	th.Equal(formatted, "(555) 123-4567")
+
+	raw, formatted, err = MaskPhone.Parse("5551234567")
+	th.NoErr(err)
+	th.Equal(raw, "5551234567")
+	th.Equal(formatted, "(555) 123-4567")
+
+	raw, formatted, err = MaskPhone.Parse("")
+	th.NoErr(err)
+	th.Equal(raw, "")
+	th.Equal(formatted, "")
+
+	for _, s := range []string{"555", "(555) 123-456x", "(555) 123-45678", "555-123-4567", "x5551234567"} {
+		raw, formatted, err = MaskPhone.Parse(s)
+		th.True(errors.Is(err, ErrMaskMismatch))
+		th.Equal(raw, "")
+		th.Equal(formatted, "")
+		th.True(!strings.Contains(err.Error(), s))
+	}
+}
@@ -0,0 +1,56 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"unicode/utf8"
+)
+
+// ErrValueTooLong is returned when an input value exceeds it's MaxLength.
+var ErrValueTooLong = errors.New("value too long")
+
+// MaxLength is a parameter to RequestWriter.Text and RequestWriter.Textarea
+// that limits the value to the given number of characters. A counter such
+// as "123/500" is rendered after the input and kept current by the browser.
+//
+// The limit is also enforced on the server, where values that are too long
+// are rejected before reaching the StringSetter.
+type MaxLength int
+
+// extractMaxLength removes any MaxLength from params and sets ui.MaxLength.
+func (ui *UiInputText) extractMaxLength(params []interface{}) (rest []interface{}) {
+	for _, p := range params {
+		if ml, ok := p.(MaxLength); ok {
+			ui.MaxLength = int(ml)
+		} else {
+			rest = append(rest, p)
+		}
+	}
+	if ui.MaxLength > 0 {
+		n := strconv.Itoa(ui.MaxLength)
+		rest = append(rest, `maxlength="`+n+`"`, `data-jaws-maxlength="`+n+`"`)
+	}
+	return
+}
+
+// writeCounter writes the character counter if MaxLength is set.
+// The Javascript function jawsCounterSync must match this.
+func (ui *UiInputText) writeCounter(w io.Writer, v string) (err error) {
+	if ui.MaxLength > 0 {
+		_, err = io.WriteString(w, `<span class="jaws-counter" aria-live="polite">`+
+			strconv.Itoa(utf8.RuneCountInString(v))+"/"+strconv.Itoa(ui.MaxLength)+`</span>`)
+	}
+	return
+}
+
+// checkMaxLength returns an error wrapping ErrValueTooLong if v is too long.
+func (ui *UiInputText) checkMaxLength(v string) (err error) {
+	if ui.MaxLength > 0 {
+		if n := utf8.RuneCountInString(v); n > ui.MaxLength {
+			err = fmt.Errorf("%w: %d > %d", ErrValueTooLong, n, ui.MaxLength)
+		}
+	}
+	return
+}
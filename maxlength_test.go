@@ -0,0 +1,58 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_TextMaxLength(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ss := newTestSetter("åäö")
+	th.NoErr(rq.Text(ss, MaxLength(5), `class="x"`))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" value="åäö" class="x" maxlength="5" data-jaws-maxlength="5">`+
+		`<span class="jaws-counter" aria-live="polite">3/5</span>`)
+
+	e := rq.getElementByJid(1)
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "12345"))
+	th.Equal(ss.Get(), "12345")
+	err := rq.callAllEventHandlers(e.Jid(), what.Input, "123456")
+	th.True(errors.Is(err, ErrValueTooLong))
+	th.Equal(err.Error(), "value too long: 6 > 5")
+	th.Equal(ss.Get(), "12345")
+	th.Equal(ss.SetCount(), 1)
+
+	// the rejected value is replaced with the current one
+	e.Ui().JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.Value)
+	th.Equal(e.wsQueue[0].Data, "12345")
+}
+
+func TestRequest_TextareaMaxLength(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ss := newTestSetter("hello")
+	th.NoErr(rq.Textarea(ss, MaxLength(500)))
+	th.Equal(rq.BodyString(), `<textarea id="Jid.1" maxlength="500" data-jaws-maxlength="500">hello</textarea>`+
+		`<span class="jaws-counter" aria-live="polite">5/500</span>`)
+}
+
+func TestRequest_TextNoMaxLength(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ss := newTestSetter("")
+	th.NoErr(rq.Text(ss, MaxLength(0)))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text">`)
+}
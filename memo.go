@@ -0,0 +1,93 @@
+package jaws
+
+import (
+	"html/template"
+	"slices"
+
+	"github.com/linkdata/deadlock"
+)
+
+// MemoGetter is a HtmlGetter that caches the value of another HtmlGetter
+// until one of it's dependency tags is dirtied.
+//
+// The cached value is shared by all Elements using the MemoGetter, so
+// the wrapped getter must not depend on the Element or it's Request.
+type MemoGetter struct {
+	getter HtmlGetter
+	deps   []interface{} // expanded dependency tags
+	mu     deadlock.Mutex
+	jw     *Jaws         // the Jaws we're registered with
+	gen    uint64        // incremented when invalidated
+	valid  bool          // if value is valid
+	value  template.HTML // cached value
+}
+
+// Memo returns a MemoGetter caching the value of g (as accepted by
+// RequestWriter.Span and friends) until any of the dependency tags
+// are dirtied. Elements using it are tagged with the dependencies.
+//
+// Dependencies that are TagGetters are expanded with a nil Request.
+// A MemoGetter remains registered with the Jaws it is first used
+// with, so create them once rather than per render.
+func Memo(g interface{}, deps ...interface{}) *MemoGetter {
+	return &MemoGetter{
+		getter: makeHtmlGetter(g),
+		deps:   MustTagExpand(nil, deps),
+	}
+}
+
+// Invalidate discards the cached value.
+func (m *MemoGetter) Invalidate() {
+	m.mu.Lock()
+	m.gen++
+	m.valid = false
+	m.mu.Unlock()
+}
+
+// invalidateIf discards the cached value if any of the tags is a dependency.
+func (m *MemoGetter) invalidateIf(tags []interface{}) {
+	for _, tag := range tags {
+		if slices.Contains(m.deps, tag) {
+			m.Invalidate()
+			return
+		}
+	}
+}
+
+func (m *MemoGetter) JawsGetHtml(e *Element) (v template.HTML) {
+	m.mu.Lock()
+	jw := m.jw
+	gen := m.gen
+	valid := m.valid
+	v = m.value
+	m.mu.Unlock()
+	if jw == nil {
+		jw = e.Request.Jaws
+		jw.addMemo(m)
+		m.mu.Lock()
+		m.jw = jw
+		m.mu.Unlock()
+	}
+	if !valid {
+		v = m.getter.JawsGetHtml(e)
+		m.mu.Lock()
+		if m.gen == gen {
+			m.valid = true
+			m.value = v
+		}
+		m.mu.Unlock()
+	}
+	return
+}
+
+func (m *MemoGetter) JawsGetTag(rq *Request) interface{} {
+	return m.deps
+}
+
+func (jw *Jaws) addMemo(m *MemoGetter) {
+	jw.mu.Lock()
+	if !slices.Contains(jw.memos, m) {
+		jw.memos = append(jw.memos, m)
+	}
+	jw.mu.Unlock()
+}
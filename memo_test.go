@@ -0,0 +1,47 @@
+package jaws
+
+import (
+	"html/template"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+type testCountingGetter struct {
+	calls int32
+}
+
+func (g *testCountingGetter) JawsGetHtml(e *Element) template.HTML {
+	return template.HTML(strconv.Itoa(int(atomic.AddInt32(&g.calls, 1))))
+}
+
+func TestMemo(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	g := &testCountingGetter{}
+	dep := Tag("dep")
+	m := Memo(g, dep)
+	th.NoErr(rq.Span(m))
+	th.NoErr(rq.Span(m))
+	th.Equal(rq.BodyString(), `<span id="Jid.1">1</span><span id="Jid.2">1</span>`)
+	th.Equal(atomic.LoadInt32(&g.calls), int32(1))
+	th.True(rq.HasTag(rq.getElementByJid(1), dep))
+
+	rq.Jaws.Dirty(Tag("other"))
+	th.Equal(string(m.JawsGetHtml(rq.getElementByJid(1))), "1")
+
+	rq.Dirty(dep)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"2\"\nInner\tJid.2\t\"2\"\n")
+	}
+	th.Equal(atomic.LoadInt32(&g.calls), int32(2))
+
+	m.Invalidate()
+	th.Equal(string(m.JawsGetHtml(rq.getElementByJid(1))), "3")
+}
@@ -2,6 +2,9 @@ package jaws
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
+	"time"
 
 	"github.com/linkdata/jaws/what"
 )
@@ -11,6 +14,56 @@ type Message struct {
 	Dest interface{} // destination (tag, html ID or *Element)
 	What what.What   // what to change or do
 	Data interface{} // data (e.g. inner HTML content or slice of tags)
+	sent time.Time   // when it was broadcast, if Jaws.MessageTTL is set
+}
+
+// isExpired returns true if the Message replaces element state
+// and was broadcast more than ttl ago.
+func (msg *Message) isExpired(ttl time.Duration) bool {
+	if ttl > 0 && !msg.sent.IsZero() {
+		switch msg.What {
+		case what.Inner, what.Value, what.SAttr:
+			return time.Since(msg.sent) > ttl
+		}
+	}
+	return false
+}
+
+// isStale returns true if the Message has expired and one of the
+// queued Messages following it replaces the same state.
+func (msg *Message) isStale(ttl time.Duration, queued []Message) bool {
+	if msg.isExpired(ttl) {
+		for i := range queued {
+			if msg.replacedBy(&queued[i]) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replacedBy returns true if other sets the same state as msg
+// for the same destination.
+func (msg *Message) replacedBy(other *Message) bool {
+	if other.What != msg.What || !sameDest(msg.Dest, other.Dest) {
+		return false
+	}
+	if msg.What == what.SAttr {
+		a, _ := msg.Data.(string)
+		b, _ := other.Data.(string)
+		a, _, _ = strings.Cut(a, "\n")
+		b, _, _ = strings.Cut(b, "\n")
+		return a == b
+	}
+	return true
+}
+
+// sameDest returns true if a and b are the same Message destination.
+func sameDest(a, b interface{}) bool {
+	if t := reflect.TypeOf(a); t != reflect.TypeOf(b) || (t != nil && !t.Comparable()) {
+		return false
+	}
+	return a == b
 }
 
 // String returns the Message in a form suitable for debug output.
@@ -2,6 +2,7 @@ package jaws
 
 import (
 	"testing"
+	"time"
 
 	"github.com/linkdata/jaws/what"
 )
@@ -16,3 +17,68 @@ func Test_Message_String(t *testing.T) {
 		t.Fail()
 	}
 }
+
+func TestMessage_isStale(t *testing.T) {
+	th := newTestHelper(t)
+	old := time.Now().Add(-time.Hour)
+	th.Equal((&Message{What: what.Inner}).isExpired(time.Second), false)
+	th.Equal((&Message{What: what.Inner, sent: old}).isExpired(0), false)
+	th.Equal((&Message{What: what.Inner, sent: old}).isExpired(time.Second), true)
+	th.Equal((&Message{What: what.Value, sent: old}).isExpired(time.Second), true)
+	th.Equal((&Message{What: what.SAttr, sent: old}).isExpired(time.Second), true)
+	th.Equal((&Message{What: what.Alert, sent: old}).isExpired(time.Second), false)
+	th.Equal((&Message{What: what.Inner, sent: time.Now()}).isExpired(time.Hour), false)
+
+	msg := &Message{Dest: Tag("foo"), What: what.Inner, sent: old}
+	th.Equal(msg.isStale(time.Second, nil), false)
+	th.Equal(msg.isStale(time.Second, []Message{{Dest: Tag("bar"), What: what.Inner}}), false)
+	th.Equal(msg.isStale(time.Second, []Message{{Dest: Tag("foo"), What: what.Value}}), false)
+	th.Equal(msg.isStale(time.Second, []Message{{Dest: Tag("foo"), What: what.Inner}}), true)
+	th.Equal(msg.isStale(0, []Message{{Dest: Tag("foo"), What: what.Inner}}), false)
+
+	sattr := &Message{Dest: "id", What: what.SAttr, Data: "title\nold", sent: old}
+	th.Equal(sattr.isStale(time.Second, []Message{{Dest: "id", What: what.SAttr, Data: "class\nnew"}}), false)
+	th.Equal(sattr.isStale(time.Second, []Message{{Dest: "id", What: what.SAttr, Data: "title\nnew"}}), true)
+
+	tags := &Message{Dest: []interface{}{Tag("foo")}, What: what.Inner, sent: old}
+	th.Equal(tags.isStale(time.Second, []Message{{Dest: []interface{}{Tag("foo")}, What: what.Inner}}), false)
+}
+
+func TestJaws_MessageTTL(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.MessageTTL = time.Minute
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+	jid := rq.Register("foo")
+
+	// keep the Request busy so both are queued when it gets to the stale one
+	rq.mu.Lock()
+	rq.bcastCh <- Message{Dest: Tag("bar"), What: what.Inner}
+	rq.bcastCh <- Message{Dest: Tag("foo"), What: what.Inner, Data: "stale", sent: time.Now().Add(-time.Hour)}
+	rq.bcastCh <- Message{Dest: Tag("foo"), What: what.Inner, Data: "fresh", sent: time.Now()}
+	rq.mu.Unlock()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msgstr := <-rq.outCh:
+		th.Equal(msgstr, what.Inner.String()+"\t"+jid.String()+"\t\"fresh\"\n")
+	}
+}
+
+func TestJaws_MessageTTLKeepsLast(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.MessageTTL = time.Minute
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+	jid := rq.Register("foo")
+
+	rq.bcastCh <- Message{Dest: Tag("foo"), What: what.Inner, Data: "last", sent: time.Now().Add(-time.Hour)}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case msgstr := <-rq.outCh:
+		th.Equal(msgstr, what.Inner.String()+"\t"+jid.String()+"\t\"last\"\n")
+	}
+}
@@ -0,0 +1,208 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultMirrorText is the banner shown in a viewer's browser if Jaws.MirrorText isn't set.
+const DefaultMirrorText = "You are viewing another user's page."
+
+// maxMirrorSnapshot is the largest page HTML accepted from a browser for mirroring.
+const maxMirrorSnapshot = 4 * 1024 * 1024
+
+var ErrMirrorSelf = errors.New("request can't mirror itself")
+
+// ErrMirrorUnrequested is logged when a browser sends page HTML for
+// mirroring that wasn't asked for.
+var ErrMirrorUnrequested = errors.New("unrequested mirror snapshot")
+
+// mirrorFrame carries messages sent to a mirrored Request to one of it's viewers.
+type mirrorFrame struct {
+	from  uint64 // JawsKey of the mirrored Request
+	lines string // newline terminated messages
+}
+
+// mirrorable returns true if messages of this kind are mirrored to viewers.
+func mirrorable(wht what.What) bool {
+	switch wht {
+	case what.Reload, what.Redirect, what.Print, what.WakeLock, what.Inert, what.Mirror, what.Credit, what.Fullscreen:
+		return false
+	}
+	return wht.IsValid()
+}
+
+// mirrorableLines returns the newline terminated messages in s that are
+// mirrorable and update an element of the page.
+func mirrorableLines(s string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if wht, rest, found := strings.Cut(line, "\t"); found && mirrorable(what.Parse(wht)) {
+			if id, _, found := strings.Cut(rest, "\t"); found && id != "" {
+				sb.WriteString(line)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// mirrorMsg returns a Mirror message of the given kind for the browser.
+//
+//	"snapshot": asks for the page HTML, data is the nonce to send it with
+//	"page": the sanitized page HTML of the mirrored Request
+//	"update": newline terminated messages updating the mirrored page
+func mirrorMsg(kind, data string) string {
+	return (&wsMsg{What: what.Mirror, Jid: -1, Data: kind + "\t" + strconv.Quote(data)}).Format()
+}
+
+func (jw *Jaws) mirrorText() string {
+	if jw.MirrorText != "" {
+		return jw.MirrorText
+	}
+	return DefaultMirrorText
+}
+
+// Mirror attaches viewer to the Request, so that the viewer's browser shows
+// the Request's page and all updates to it as they happen. The viewer's page
+// is inert and shows a banner with Jaws.MirrorText, so it's input isn't
+// shared. A viewer mirrors at most one Request at a time.
+//
+// The page is copied from the Request's browser, including the current
+// values of inputs. Since the browser can send anything, the copy is
+// sanitized and shown in a sandboxed iframe that can't run scripts.
+// Use Unmirror to stop mirroring.
+func (rq *Request) Mirror(viewer *Request) error {
+	if viewer == nil || viewer == rq {
+		return fmt.Errorf("jaws: %v: %w", rq, ErrMirrorSelf)
+	}
+	rq.mu.Lock()
+	jawsKey := rq.JawsKey
+	if !slices.Contains(rq.viewers, viewer) {
+		rq.viewers = append(rq.viewers, viewer)
+	}
+	rq.mu.Unlock()
+	viewer.mu.Lock()
+	viewer.mirrorOf = jawsKey
+	viewer.inert = true
+	viewer.mu.Unlock()
+	rq.Jaws.Broadcast(Message{Dest: viewer, What: what.Inert, Data: rq.Jaws.mirrorText()})
+	rq.Jaws.Broadcast(Message{Dest: rq, What: what.Mirror})
+	return nil
+}
+
+// Unmirror detaches viewer from the Request and reloads the viewer's page.
+func (rq *Request) Unmirror(viewer *Request) {
+	rq.mu.Lock()
+	jawsKey := rq.JawsKey
+	rq.viewers = slices.DeleteFunc(rq.viewers, func(v *Request) bool { return v == viewer })
+	rq.mu.Unlock()
+	if viewer != nil {
+		viewer.mu.Lock()
+		stopped := viewer.mirrorOf == jawsKey
+		if stopped {
+			viewer.mirrorOf = 0
+		}
+		viewer.mu.Unlock()
+		if stopped {
+			rq.Jaws.Broadcast(Message{Dest: viewer, What: what.Reload})
+		}
+	}
+}
+
+// Viewers returns the Requests mirroring the Request.
+func (rq *Request) Viewers() (viewers []*Request) {
+	rq.mu.RLock()
+	jawsKey := rq.JawsKey
+	viewers = slices.Clone(rq.viewers)
+	rq.mu.RUnlock()
+	return slices.DeleteFunc(viewers, func(v *Request) bool { return !v.mirrors(jawsKey) })
+}
+
+// mirrors returns true if the Request is a viewer of the Request with the given key.
+func (rq *Request) mirrors(jawsKey uint64) (yes bool) {
+	rq.mu.RLock()
+	yes = jawsKey != 0 && rq.mirrorOf == jawsKey
+	rq.mu.RUnlock()
+	return
+}
+
+// mirrorSend sends the mirrorable messages in s to the Request's viewers,
+// forgetting viewers that no longer mirror it.
+func (rq *Request) mirrorSend(s string) {
+	rq.mu.RLock()
+	jawsKey := rq.JawsKey
+	n := len(rq.viewers)
+	rq.mu.RUnlock()
+	if n > 0 {
+		viewers := rq.Viewers()
+		if len(viewers) != n {
+			rq.mu.Lock()
+			rq.viewers = slices.DeleteFunc(rq.viewers, func(v *Request) bool { return !slices.Contains(viewers, v) })
+			rq.mu.Unlock()
+		}
+		if lines := mirrorableLines(s); lines != "" {
+			lines = mirrorMsg("update", lines)
+			for _, viewer := range viewers {
+				rq.Jaws.Broadcast(Message{Dest: viewer, What: what.Mirror, Data: mirrorFrame{from: jawsKey, lines: lines}})
+			}
+		}
+	}
+}
+
+// handleMirror collects the page HTML sent in chunks by the browser, and
+// sends it sanitized to the viewers when the empty chunk ending it arrives.
+//
+// Each chunk starts with the nonce of the snapshot asked for and a newline.
+// Chunks are ignored unless a snapshot has been asked for.
+func (rq *Request) handleMirror(data string) {
+	nonce, chunk, found := strings.Cut(data, "\n")
+	if !found || rq.mirrorNonce == "" || nonce != rq.mirrorNonce {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w", rq, ErrMirrorUnrequested))
+		return
+	}
+	if chunk != "" {
+		if rq.mirrorSnap.Len()+len(chunk) > maxMirrorSnapshot {
+			rq.mirrorSnap.Reset()
+			rq.mirrorNonce = ""
+			_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: mirrored page larger than %d bytes", rq, maxMirrorSnapshot))
+			return
+		}
+		rq.mirrorSnap.WriteString(chunk)
+		return
+	}
+	lines := mirrorMsg("page", sanitizeHTML(rq.mirrorSnap.String()))
+	rq.mirrorSnap.Reset()
+	rq.mirrorNonce = ""
+	rq.mu.RLock()
+	jawsKey := rq.JawsKey
+	rq.mu.RUnlock()
+	for _, viewer := range rq.Viewers() {
+		rq.Jaws.Broadcast(Message{Dest: viewer, What: what.Mirror, Data: mirrorFrame{from: jawsKey, lines: lines}})
+	}
+}
+
+// mirrorMessage handles a Mirror message sent to the Request. If it carries
+// messages from a Request it mirrors, they are sent to the browser after
+// those in wsQueue, otherwise the browser is asked for the page HTML
+// with a new nonce.
+func (rq *Request) mirrorMessage(outboundCh chan string, wsQueue []wsMsg, data any) []wsMsg {
+	if frame, ok := data.(mirrorFrame); ok {
+		if rq.mirrors(frame.from) {
+			if len(wsQueue) > 0 {
+				wsQueue = rq.sendQueue(outboundCh, wsQueue)
+			}
+			rq.wsSend(outboundCh, frame.lines)
+		}
+		return wsQueue
+	}
+	rq.Jaws.mu.Lock()
+	rq.mirrorNonce = strconv.FormatUint(rq.Jaws.nonZeroRandomLocked(), 36)
+	rq.Jaws.mu.Unlock()
+	rq.mirrorSnap.Reset()
+	return append(wsQueue, wsMsg{What: what.Mirror, Jid: -1, Data: "snapshot\t" + strconv.Quote(rq.mirrorNonce)})
+}
@@ -0,0 +1,93 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_mirrorableLines(t *testing.T) {
+	th := newTestHelper(t)
+	s := (&wsMsg{What: what.Reload}).Format() +
+		(&wsMsg{What: what.Inner, Jid: 1, Data: "x"}).Format() +
+		(&wsMsg{What: what.Inert, Data: "busy"}).Format() +
+		(&wsMsg{What: what.Alert, Data: "info\nhi"}).Format()
+	th.Equal(mirrorableLines(s), (&wsMsg{What: what.Inner, Jid: 1, Data: "x"}).Format())
+	th.Equal(mirrorableLines(""), "")
+}
+
+func TestRequest_Mirror(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	src := tj.newRequest(nil)
+	viewer := tj.newRequest(nil)
+	defer src.Close()
+
+	nextMsg := func(tr *testRequest) (s string) {
+		t.Helper()
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s = <-tr.outCh:
+		}
+		return
+	}
+
+	th.True(errors.Is(src.Mirror(src.Request), ErrMirrorSelf))
+	th.True(errors.Is(src.Mirror(nil), ErrMirrorSelf))
+
+	th.NoErr(src.Mirror(viewer.Request))
+	th.Equal(nextMsg(viewer), (&wsMsg{What: what.Inert, Data: DefaultMirrorText}).Format())
+	snapshot := nextMsg(src)
+	th.True(strings.HasPrefix(snapshot, "Mirror\tsnapshot\t"))
+	th.True(viewer.Inert())
+	th.Equal(src.Viewers(), []*Request{viewer.Request})
+
+	nonce, err := strconv.Unquote(strings.TrimSpace(strings.TrimPrefix(snapshot, "Mirror\tsnapshot\t")))
+	th.NoErr(err)
+	src.inCh <- wsMsg{What: what.Mirror, Data: "forged\n<p>evil</p>"}
+	src.inCh <- wsMsg{What: what.Mirror, Data: nonce + "\n<p>hello</p><script>alert(1)</script>"}
+	src.inCh <- wsMsg{What: what.Mirror, Data: nonce + "\n<p onclick=\"x()\">world</p>"}
+	src.inCh <- wsMsg{What: what.Mirror, Data: nonce + "\n"}
+	th.Equal(nextMsg(viewer), mirrorMsg("page", "<p>hello</p><p>world</p>"))
+
+	// no snapshot is outstanding any more
+	src.inCh <- wsMsg{What: what.Mirror, Data: nonce + "\n<p>again</p>"}
+	src.inCh <- wsMsg{What: what.Mirror, Data: nonce + "\n"}
+
+	tj.Broadcast(Message{Dest: src.Request, What: what.Reload})
+	th.Equal(nextMsg(src), (&wsMsg{What: what.Reload}).Format())
+	tj.Broadcast(Message{Dest: src.Request, What: what.Alert, Data: "info\nhi"})
+	th.Equal(nextMsg(src), (&wsMsg{What: what.Alert, Data: "info\nhi"}).Format())
+	tj.Broadcast(Message{Dest: "foo", What: what.Inner, Data: "x"})
+	inner := (&wsMsg{What: what.Inner, Jid: -1, Data: "foo\t" + strconv.Quote("x")}).Format()
+	th.Equal(nextMsg(src), inner)
+	th.Equal(nextMsg(viewer), inner)
+	th.Equal(nextMsg(viewer), mirrorMsg("update", inner))
+	th.True(strings.Contains(tj.log.String(), ErrMirrorUnrequested.Error()))
+
+	src.Unmirror(viewer.Request)
+	th.Equal(nextMsg(viewer), (&wsMsg{What: what.Reload}).Format())
+	th.Equal(len(src.Viewers()), 0)
+
+	// frames from a Request no longer mirrored are ignored
+	tj.Broadcast(Message{Dest: viewer.Request, What: what.Mirror, Data: mirrorFrame{from: src.JawsKey, lines: "x\n"}})
+	tj.Broadcast(Message{Dest: viewer.Request, What: what.Announce, Data: "done"})
+	th.Equal(nextMsg(viewer), (&wsMsg{What: what.Announce, Data: "done"}).Format())
+}
+
+func TestRequest_handleMirrorTooLarge(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.NewRequest(nil)
+	rq.mirrorNonce = "n"
+	rq.handleMirror("n\n" + strings.Repeat("x", maxMirrorSnapshot))
+	rq.handleMirror("n\nx")
+	th.Equal(rq.mirrorSnap.Len(), 0)
+	th.Equal(rq.mirrorNonce, "")
+	th.True(strings.Contains(tj.log.String(), "mirrored page larger than"))
+}
@@ -0,0 +1,134 @@
+package jaws
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+)
+
+// Money is an amount of money in the minor units of a currency, such as
+// cents, avoiding the rounding errors of floating point amounts.
+type Money struct {
+	Minor    int64  // amount in minor units
+	Currency string // ISO 4217 currency code, such as "EUR"
+}
+
+type currencyInfo struct {
+	symbol string
+	digits int
+}
+
+var currencies = map[string]currencyInfo{
+	"AUD": {"A$", 2},
+	"CAD": {"CA$", 2},
+	"CHF": {"CHF", 2},
+	"CNY": {"CN¥", 2},
+	"DKK": {"kr.", 2},
+	"EUR": {"€", 2},
+	"GBP": {"£", 2},
+	"INR": {"₹", 2},
+	"JPY": {"¥", 0},
+	"KRW": {"₩", 0},
+	"NOK": {"kr", 2},
+	"PLN": {"zł", 2},
+	"SEK": {"kr", 2},
+	"USD": {"$", 2},
+}
+
+// currency returns the symbol and number of minor unit digits for the code.
+// Unknown currencies use the code as symbol and two digits.
+func currency(code string) currencyInfo {
+	if ci, ok := currencies[code]; ok {
+		return ci
+	}
+	return currencyInfo{code, 2}
+}
+
+// Format returns the amount with it's currency symbol as written in the
+// locale described by nf, such as "$1,234.50" or "1.234,50 €".
+func (m Money) Format(nf NumberFormat) string {
+	ci := currency(m.Currency)
+	s := nf.FormatDecimal(m.Minor, ci.digits)
+	if nf.SymbolAfter {
+		return s + " " + ci.symbol
+	}
+	if neg, ok := strings.CutPrefix(s, "-"); ok {
+		return "-" + ci.symbol + neg
+	}
+	return ci.symbol + s
+}
+
+// String returns the amount formatted using DefaultNumberFormat.
+func (m Money) String() string {
+	return m.Format(DefaultNumberFormat)
+}
+
+// ParseMoney parses user input in the locale described by nf as an amount
+// in the given currency. The currency symbol or code may be present.
+// It returns an error wrapping ErrInvalidNumber if the input isn't an
+// amount, or has more decimals than the currency has minor unit digits.
+func ParseMoney(s string, nf NumberFormat, code string) (m Money, err error) {
+	ci := currency(code)
+	txt := strings.TrimSpace(s)
+	for _, affix := range []string{code, ci.symbol} {
+		if v, ok := strings.CutSuffix(txt, affix); ok {
+			txt = v
+		} else if v, ok := strings.CutPrefix(txt, affix); ok {
+			txt = v
+		} else if v, ok := strings.CutPrefix(txt, "-"+affix); ok {
+			txt = "-" + v
+		}
+		txt = strings.TrimSpace(txt)
+	}
+	if m.Minor, err = nf.ParseDecimal(txt, ci.digits); err == nil {
+		m.Currency = code
+	} else {
+		err = fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	return
+}
+
+// MoneyValue holds a Money value, and is a StringSetter that formats and
+// parses it in the locale of the Element's Request, see Request.NumberFormat.
+//
+//	price := jaws.NewMoneyValue(jaws.Money{Minor: 1999, Currency: "EUR"})
+//	rw.Text(price)
+type MoneyValue struct {
+	mu deadlock.RWMutex // protects following
+	v  Money
+}
+
+func NewMoneyValue(v Money) *MoneyValue {
+	return &MoneyValue{v: v}
+}
+
+// Get returns the current value.
+func (mv *MoneyValue) Get() (v Money) {
+	mv.mu.RLock()
+	v = mv.v
+	mv.mu.RUnlock()
+	return
+}
+
+// Set sets the current value.
+func (mv *MoneyValue) Set(v Money) {
+	mv.mu.Lock()
+	mv.v = v
+	mv.mu.Unlock()
+}
+
+func (mv *MoneyValue) JawsGetString(e *Element) string {
+	return mv.Get().Format(e.Request.NumberFormat())
+}
+
+// JawsSetString parses the input as an amount in the current currency.
+func (mv *MoneyValue) JawsSetString(e *Element, s string) (err error) {
+	mv.mu.Lock()
+	defer mv.mu.Unlock()
+	var m Money
+	if m, err = ParseMoney(s, e.Request.NumberFormat(), mv.v.Currency); err == nil {
+		mv.v = m
+	}
+	return
+}
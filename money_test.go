@@ -0,0 +1,68 @@
+package jaws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestMoney_Format(t *testing.T) {
+	th := newTestHelper(t)
+	de := LocaleNumberFormat("de")
+	th.Equal(Money{123450, "USD"}.String(), "$1,234.50")
+	th.Equal(Money{-5, "USD"}.String(), "-$0.05")
+	th.Equal(Money{123450, "EUR"}.Format(de), "1.234,50 €")
+	th.Equal(Money{1500, "JPY"}.String(), "¥1,500")
+	th.Equal(Money{199, "XYZ"}.String(), "XYZ1.99")
+}
+
+func TestParseMoney(t *testing.T) {
+	th := newTestHelper(t)
+	de := LocaleNumberFormat("de")
+	for _, s := range []string{"1234.5", "$1,234.50", " USD 1234.50", "1234.50 $"} {
+		m, err := ParseMoney(s, DefaultNumberFormat, "USD")
+		th.NoErr(err)
+		th.Equal(m, Money{123450, "USD"})
+	}
+	m, err := ParseMoney("-$3", DefaultNumberFormat, "USD")
+	th.NoErr(err)
+	th.Equal(m, Money{-300, "USD"})
+	m, err = ParseMoney("1.234,50 €", de, "EUR")
+	th.NoErr(err)
+	th.Equal(m, Money{123450, "EUR"})
+	m, err = ParseMoney("1500", DefaultNumberFormat, "JPY")
+	th.NoErr(err)
+	th.Equal(m, Money{1500, "JPY"})
+
+	for _, s := range []string{"", "€", "1.001", "ten"} {
+		_, err = ParseMoney(s, DefaultNumberFormat, "USD")
+		th.True(errors.Is(err, ErrInvalidNumber))
+	}
+	_, err = ParseMoney("1.5", DefaultNumberFormat, "JPY")
+	th.True(errors.Is(err, ErrInvalidNumber))
+}
+
+func TestMoneyValue(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "de-DE")
+	rq := tj.newRequest(hr)
+	defer rq.Close()
+
+	mv := NewMoneyValue(Money{1999, "EUR"})
+	th.NoErr(rq.Text(mv))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" value="19,99 €">`)
+
+	e := rq.getElementByJid(1)
+	th.NoErr(mv.JawsSetString(e, "1.000,5"))
+	th.Equal(mv.Get(), Money{100050, "EUR"})
+	th.True(errors.Is(mv.JawsSetString(e, "1,001"), ErrInvalidNumber))
+	th.Equal(mv.Get(), Money{100050, "EUR"})
+
+	mv.Set(Money{5, "SEK"})
+	th.Equal(mv.JawsGetString(e), "0,05 kr")
+}
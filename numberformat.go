@@ -0,0 +1,165 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrInvalidNumber is returned when parsing user input that isn't a number
+// in the expected format.
+var ErrInvalidNumber = errors.New("invalid number")
+
+// NumberFormat describes how a locale writes numbers and amounts of money.
+type NumberFormat struct {
+	Decimal     string // decimal separator
+	Group       string // digit group separator
+	SymbolAfter bool   // if true, currency symbols are written after the amount
+}
+
+// DefaultNumberFormat is the NumberFormat used for unknown locales.
+var DefaultNumberFormat = NumberFormat{Decimal: ".", Group: ","}
+
+var numberFormats = map[string]NumberFormat{
+	"en": DefaultNumberFormat,
+	"ja": DefaultNumberFormat,
+	"zh": DefaultNumberFormat,
+	"de": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"es": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"it": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"nl": {Decimal: ",", Group: "."},
+	"pt": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"da": {Decimal: ",", Group: ".", SymbolAfter: true},
+	"fr": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"sv": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"nb": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"fi": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"pl": {Decimal: ",", Group: " ", SymbolAfter: true},
+	"ru": {Decimal: ",", Group: " ", SymbolAfter: true},
+}
+
+// LocaleNumberFormat returns the NumberFormat for a language tag such as
+// "de-CH" or "sv", or DefaultNumberFormat if the language isn't known.
+func LocaleNumberFormat(tag string) NumberFormat {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	if nf, ok := numberFormats[lang]; ok {
+		return nf
+	}
+	return DefaultNumberFormat
+}
+
+// NumberFormat returns the NumberFormat for the first known language in
+// the Accept-Language header of the initial HTTP request, or DefaultNumberFormat.
+func (rq *Request) NumberFormat() NumberFormat {
+	for _, tag := range rq.acceptLanguages() {
+		lang, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if nf, ok := numberFormats[lang]; ok {
+			return nf
+		}
+	}
+	return DefaultNumberFormat
+}
+
+// FormatDecimal formats n divided by 10^decimals, such as 123456 with
+// two decimals as "1,234.56" in English.
+func (nf NumberFormat) FormatDecimal(n int64, decimals int) string {
+	var neg bool
+	u := uint64(n)
+	if n < 0 {
+		neg = true
+		u = -u
+	}
+	digits := strconv.FormatUint(u, 10)
+	if len(digits) <= decimals {
+		digits = strings.Repeat("0", decimals-len(digits)+1) + digits
+	}
+	intPart, frac := digits[:len(digits)-decimals], digits[len(digits)-decimals:]
+	var sb strings.Builder
+	if neg {
+		sb.WriteByte('-')
+	}
+	for i := range intPart {
+		if i > 0 && (len(intPart)-i)%3 == 0 {
+			sb.WriteString(nf.Group)
+		}
+		sb.WriteByte(intPart[i])
+	}
+	if decimals > 0 {
+		sb.WriteString(nf.Decimal)
+		sb.WriteString(frac)
+	}
+	return sb.String()
+}
+
+// FormatFloat formats f rounded to the given number of decimals.
+func (nf NumberFormat) FormatFloat(f float64, decimals int) string {
+	if scaled := math.Round(f * math.Pow10(decimals)); math.Abs(scaled) < 1e18 {
+		return nf.FormatDecimal(int64(scaled), decimals)
+	}
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// normalize returns s with group separators and spaces removed and the
+// decimal separator replaced with a dot.
+func (nf NumberFormat) normalize(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, s)
+	if strings.TrimSpace(nf.Group) != "" {
+		s = strings.ReplaceAll(s, nf.Group, "")
+	}
+	return strings.Replace(s, nf.Decimal, ".", 1)
+}
+
+// ParseDecimal parses user input such as "1.234,5" in German into an
+// integer scaled by 10^decimals, 123450 with two decimals. It returns an
+// error wrapping ErrInvalidNumber if s isn't a number, or has more decimals.
+func (nf NumberFormat) ParseDecimal(s string, decimals int) (n int64, err error) {
+	norm := nf.normalize(s)
+	intPart, frac, _ := strings.Cut(norm, ".")
+	neg := strings.HasPrefix(intPart, "-")
+	intPart = strings.TrimPrefix(strings.TrimPrefix(intPart, "-"), "+")
+	if (intPart == "" && frac == "") || len(strings.TrimRight(frac, "0")) > decimals {
+		return 0, errInvalidNumber(s)
+	}
+	if len(frac) > decimals {
+		frac = frac[:decimals]
+	}
+	digits := intPart + frac + strings.Repeat("0", decimals-len(frac))
+	for _, c := range digits {
+		if c < '0' || c > '9' {
+			return 0, errInvalidNumber(s)
+		}
+	}
+	var u uint64
+	if u, err = strconv.ParseUint(digits, 10, 63); err != nil {
+		return 0, errInvalidNumber(s)
+	}
+	n = int64(u)
+	if neg {
+		n = -n
+	}
+	return
+}
+
+// ParseFloat parses user input such as "1.234,5" in German into a float64.
+func (nf NumberFormat) ParseFloat(s string) (f float64, err error) {
+	norm := nf.normalize(s)
+	if norm == "" || strings.ContainsAny(norm, "eEnNiIxX_") {
+		return 0, errInvalidNumber(s)
+	}
+	if f, err = strconv.ParseFloat(norm, 64); err != nil {
+		err = errInvalidNumber(s)
+	}
+	return
+}
+
+func errInvalidNumber(s string) error {
+	return fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+}
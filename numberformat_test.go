@@ -0,0 +1,71 @@
+package jaws
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestLocaleNumberFormat(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(LocaleNumberFormat("de-CH"), numberFormats["de"])
+	th.Equal(LocaleNumberFormat(" SV "), numberFormats["sv"])
+	th.Equal(LocaleNumberFormat("xx"), DefaultNumberFormat)
+}
+
+func TestRequest_NumberFormat(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	th.Equal(tj.NewRequest(nil).NumberFormat(), DefaultNumberFormat)
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "xx-YY, fr-CA;q=0.8, de;q=0.5")
+	th.Equal(tj.NewRequest(hr).NumberFormat(), numberFormats["fr"])
+}
+
+func TestNumberFormat_FormatDecimal(t *testing.T) {
+	th := newTestHelper(t)
+	en, de := DefaultNumberFormat, LocaleNumberFormat("de")
+	th.Equal(en.FormatDecimal(0, 2), "0.00")
+	th.Equal(en.FormatDecimal(5, 2), "0.05")
+	th.Equal(en.FormatDecimal(-123456, 2), "-1,234.56")
+	th.Equal(en.FormatDecimal(1234567, 0), "1,234,567")
+	th.Equal(de.FormatDecimal(123456789, 2), "1.234.567,89")
+	th.Equal(LocaleNumberFormat("sv").FormatDecimal(123456, 1), "12 345,6")
+	th.Equal(en.FormatFloat(1234.565, 1), "1,234.6")
+	th.Equal(de.FormatFloat(-0.5, 0), "-1")
+	th.Equal(en.FormatFloat(1e30, 2), "1e+30")
+}
+
+func TestNumberFormat_Parse(t *testing.T) {
+	th := newTestHelper(t)
+	en, de, sv := DefaultNumberFormat, LocaleNumberFormat("de"), LocaleNumberFormat("sv")
+
+	n, err := en.ParseDecimal(" 1,234.5 ", 2)
+	th.NoErr(err)
+	th.Equal(n, int64(123450))
+	n, err = de.ParseDecimal("-1.234,56", 2)
+	th.NoErr(err)
+	th.Equal(n, int64(-123456))
+	n, err = sv.ParseDecimal("12 345,60", 2)
+	th.NoErr(err)
+	th.Equal(n, int64(1234560))
+	n, err = en.ParseDecimal("7", 0)
+	th.NoErr(err)
+	th.Equal(n, int64(7))
+
+	for _, s := range []string{"", "-", "1.234", "1.2.3", "abc", "1e5", "99999999999999999999"} {
+		_, err = en.ParseDecimal(s, 2)
+		th.True(errors.Is(err, ErrInvalidNumber))
+	}
+
+	f, err := de.ParseFloat("1.234,5")
+	th.NoErr(err)
+	th.Equal(f, 1234.5)
+	for _, s := range []string{"", "NaN", "inf", "0x10", "1e3", "1,2,3"} {
+		_, err = de.ParseFloat(s)
+		th.True(errors.Is(err, ErrInvalidNumber))
+	}
+}
@@ -0,0 +1,123 @@
+package jaws
+
+import (
+	"fmt"
+	"time"
+)
+
+// OverflowPolicy selects what a Request does when it's queue of broadcast
+// messages, of messages for the browser, or of events waiting to be
+// handled, is full. It is set using Jaws.Overflow.
+type OverflowPolicy int8
+
+const (
+	// OverflowDisconnect ends the Request if the broadcast or outbound queue
+	// is full, and drops events arriving while the event queue is full.
+	// This is the default.
+	OverflowDisconnect OverflowPolicy = iota
+	// OverflowBlock waits up to Jaws.OverflowWait for room, and then
+	// behaves like OverflowDisconnect.
+	//
+	// While waiting for room in the outbound or event queue, the Request
+	// processes no other messages, and each message sent may wait again.
+	// Broadcasts are kept in a backlog instead of stalling other Requests,
+	// and the Request is ended if none of them could be delivered for
+	// Jaws.OverflowWait.
+	OverflowBlock
+	// OverflowDropOldest discards the oldest queued message or event to make room.
+	OverflowDropOldest
+	// OverflowDropNewest discards the message or event that doesn't fit.
+	OverflowDropNewest
+	// OverflowGrow keeps what doesn't fit in a backlog without limit
+	// until there is room.
+	OverflowGrow
+)
+
+// DefaultOverflowWait is the OverflowWait used if Jaws.OverflowWait isn't set.
+const DefaultOverflowWait = time.Second
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDisconnect:
+		return "OverflowDisconnect"
+	case OverflowBlock:
+		return "OverflowBlock"
+	case OverflowDropOldest:
+		return "OverflowDropOldest"
+	case OverflowDropNewest:
+		return "OverflowDropNewest"
+	case OverflowGrow:
+		return "OverflowGrow"
+	}
+	return fmt.Sprintf("OverflowPolicy(%d)", int8(p))
+}
+
+// overflowWait returns how long OverflowBlock waits for room.
+func (jw *Jaws) overflowWait() time.Duration {
+	if jw.OverflowWait > 0 {
+		return jw.OverflowWait
+	}
+	return DefaultOverflowWait
+}
+
+// overflowSend sends v on ch, applying policy if ch is full. If the
+// policy is OverflowGrow, v is appended to backlog instead, which must be
+// empty for v to be sent directly. Returns the number of values discarded,
+// including v, and false if v couldn't be queued and the policy is
+// OverflowDisconnect or OverflowBlock.
+func overflowSend[T any](ch chan T, v T, policy OverflowPolicy, wait time.Duration, doneCh <-chan struct{}, backlog *[]T) (dropped int, ok bool) {
+	if len(*backlog) > 0 {
+		*backlog = append(*backlog, v)
+		return 0, true
+	}
+	select {
+	case <-doneCh:
+		return 0, true
+	case ch <- v:
+		return 0, true
+	default:
+	}
+	switch policy {
+	case OverflowBlock:
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-doneCh:
+			return 0, true
+		case ch <- v:
+			return 0, true
+		case <-t.C:
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case <-doneCh:
+				return dropped, true
+			case ch <- v:
+				return dropped, true
+			default:
+				select {
+				case <-ch:
+					dropped++
+				default:
+				}
+			}
+		}
+	case OverflowDropNewest:
+		return 1, true
+	case OverflowGrow:
+		*backlog = append(*backlog, v)
+		return 0, true
+	}
+	return 1, false
+}
+
+// overflowNext returns ch and the first value in backlog if backlog isn't
+// empty, or a nil channel otherwise, for use in a select statement.
+func overflowNext[T any](ch chan T, backlog []T) (chan<- T, T) {
+	var zero T
+	if len(backlog) > 0 {
+		return ch, backlog[0]
+	}
+	return nil, zero
+}
@@ -0,0 +1,188 @@
+package jaws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestOverflowPolicy_String(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(OverflowDisconnect.String(), "OverflowDisconnect")
+	th.Equal(OverflowBlock.String(), "OverflowBlock")
+	th.Equal(OverflowDropOldest.String(), "OverflowDropOldest")
+	th.Equal(OverflowDropNewest.String(), "OverflowDropNewest")
+	th.Equal(OverflowGrow.String(), "OverflowGrow")
+	th.Equal(OverflowPolicy(-1).String(), "OverflowPolicy(-1)")
+}
+
+func Test_overflowSend(t *testing.T) {
+	th := newTestHelper(t)
+	doneCh := make(chan struct{})
+	var backlog []int
+	var dropped int
+	var ok bool
+	full := func() chan int {
+		ch := make(chan int, 2)
+		ch <- 1
+		ch <- 2
+		return ch
+	}
+	drain := func(ch chan int) (got []int) {
+		for len(ch) > 0 {
+			got = append(got, <-ch)
+		}
+		return
+	}
+
+	ch := full()
+	dropped, ok = overflowSend(ch, 3, OverflowDisconnect, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 1)
+	th.Equal(ok, false)
+	th.Equal(drain(ch), []int{1, 2})
+
+	ch = full()
+	dropped, ok = overflowSend(ch, 3, OverflowDropNewest, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 1)
+	th.Equal(ok, true)
+	th.Equal(drain(ch), []int{1, 2})
+
+	ch = full()
+	dropped, ok = overflowSend(ch, 3, OverflowDropOldest, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 1)
+	th.Equal(ok, true)
+	th.Equal(drain(ch), []int{2, 3})
+
+	ch = full()
+	dropped, ok = overflowSend(ch, 3, OverflowBlock, time.Millisecond, doneCh, &backlog)
+	th.Equal(dropped, 1)
+	th.Equal(ok, false)
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		<-ch
+	}()
+	dropped, ok = overflowSend(ch, 3, OverflowBlock, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 0)
+	th.Equal(ok, true)
+	th.Equal(drain(ch), []int{2, 3})
+
+	ch = full()
+	dropped, ok = overflowSend(ch, 3, OverflowGrow, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 0)
+	th.Equal(ok, true)
+	<-ch
+	// keeps order while there is a backlog
+	dropped, ok = overflowSend(ch, 4, OverflowGrow, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 0)
+	th.Equal(ok, true)
+	th.Equal(backlog, []int{3, 4})
+	next, v := overflowNext(ch, backlog)
+	th.True(next != nil)
+	th.Equal(v, 3)
+	backlog = nil
+	next, v = overflowNext(ch, backlog)
+	th.Equal(next, (chan<- int)(nil))
+	th.Equal(v, 0)
+
+	close(doneCh)
+	ch = full()
+	dropped, ok = overflowSend(ch, 3, OverflowDisconnect, time.Second, doneCh, &backlog)
+	th.Equal(dropped, 0)
+	th.Equal(ok, true)
+}
+
+func TestRequest_OverflowGrow(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+	rq.Jaws.Overflow = OverflowGrow
+
+	// more than fits in the outbound queue, without filling the broadcast queue
+	n := cap(rq.outCh) * 3
+	for i := 0; i < n; i++ {
+		rq.Jaws.Alert("info", "x")
+		for len(rq.bcastCh) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-rq.outCh:
+		}
+	}
+	select {
+	case <-rq.doneCh:
+		th.Fatal("request ended")
+	default:
+	}
+}
+
+func Test_subscription(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+	now := time.Now()
+	msg := func(i int) Message { return Message{What: what.Alert, Data: i} }
+
+	sub := &subscription{msgCh: make(chan Message, 1), rq: rq}
+	th.True(sub.send(msg(1), OverflowDropNewest, now))
+	th.True(sub.send(msg(2), OverflowDropNewest, now))
+	th.True(sub.send(msg(3), OverflowDropOldest, now))
+	th.Equal(rq.SendStats().Dropped, uint64(2))
+	th.Equal((<-sub.msgCh).Data, any(3))
+	th.True(sub.send(msg(1), OverflowDisconnect, now))
+	th.Equal(sub.send(msg(2), OverflowDisconnect, now), false)
+
+	sub = &subscription{msgCh: make(chan Message, 1), rq: rq}
+	for i := 1; i <= 3; i++ {
+		th.True(sub.send(msg(i), OverflowGrow, now))
+	}
+	th.Equal(len(sub.backlog), 2)
+	th.Equal(rq.SendStats().Queued, 2)
+	th.True(sub.flush(OverflowGrow, time.Second, now.Add(time.Hour)))
+	for i := 1; i <= 3; i++ {
+		th.Equal((<-sub.msgCh).Data, any(i))
+		th.True(sub.flush(OverflowGrow, time.Second, now))
+	}
+	th.Equal(sub.backlog, []Message(nil))
+
+	sub = &subscription{msgCh: make(chan Message, 1), rq: rq}
+	th.True(sub.send(msg(1), OverflowBlock, now))
+	th.True(sub.send(msg(2), OverflowBlock, now))
+	th.True(sub.flush(OverflowBlock, time.Second, now.Add(time.Second/2)))
+	<-sub.msgCh
+	th.True(sub.flush(OverflowBlock, time.Second, now.Add(time.Second)))
+	th.True(sub.send(msg(3), OverflowBlock, now.Add(time.Second)))
+	th.Equal(sub.flush(OverflowBlock, time.Second, now.Add(time.Second*2)), false)
+}
+
+func TestJaws_BroadcastOverflow(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.Overflow = OverflowDropNewest
+	go jw.Serve()
+	rq := jw.NewRequest(nil)
+	msgCh := jw.subscribe(rq, 1)
+	for i := 0; i <= cap(jw.subCh); i++ {
+		jw.subCh <- subscription{}
+	}
+
+	for i := 0; i < 3; i++ {
+		jw.Broadcast(Message{Dest: rq, What: what.Alert, Data: "info\nx"})
+	}
+	for rq.SendStats().Dropped < 2 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	th.Equal(len(msgCh), 1)
+	th.NoErr(context.Cause(rq.Context()))
+}
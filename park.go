@@ -0,0 +1,184 @@
+package jaws
+
+import (
+	"strings"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultParkLimit is the ParkLimit used if Jaws.ParkLimit isn't set.
+const DefaultParkLimit = 16
+
+// parkedMsg is a formatted message kept for a Request whose WebSocket dropped.
+type parkedMsg struct {
+	jawsKey uint64
+	when    time.Time
+	data    string
+}
+
+// parkable returns true for messages that the browser won't get again
+// when the Elements are updated after reconnecting.
+func parkable(wht what.What) bool {
+	switch wht {
+	case what.Alert, what.Announce, what.Redirect, what.Reload, what.Print:
+		return true
+	}
+	return false
+}
+
+// parkableLines returns the newline terminated messages in s that are parkable.
+func parkableLines(s string) string {
+	var sb strings.Builder
+	for _, line := range strings.SplitAfter(s, "\n") {
+		if wht, _, found := strings.Cut(line, "\t"); found && parkable(what.Parse(wht)) {
+			sb.WriteString(line)
+		}
+	}
+	return sb.String()
+}
+
+// parkLimit returns the maximum number of messages parked per Session.
+func (jw *Jaws) parkLimit() int {
+	if jw.ParkLimit > 0 {
+		return jw.ParkLimit
+	}
+	return DefaultParkLimit
+}
+
+// park keeps data for the Request with the given key, dropping the
+// oldest messages if there are more than Jaws.ParkLimit.
+func (sess *Session) park(jawsKey uint64, data string) {
+	if sess != nil && data != "" {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		sess.pruneParkedLocked(time.Now())
+		sess.parked = append(sess.parked, parkedMsg{jawsKey: jawsKey, when: time.Now(), data: data})
+		if n := len(sess.parked) - sess.jw.parkLimit(); n > 0 {
+			clear(sess.parked[:n])
+			sess.parked = sess.parked[n:]
+		}
+	}
+}
+
+// unpark removes and returns the messages kept for the Request with the given key.
+func (sess *Session) unpark(jawsKey uint64) string {
+	var sb strings.Builder
+	if sess != nil {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		sess.pruneParkedLocked(time.Now())
+		var kept []parkedMsg
+		for _, pm := range sess.parked {
+			if pm.jawsKey == jawsKey {
+				sb.WriteString(pm.data)
+			} else {
+				kept = append(kept, pm)
+			}
+		}
+		sess.parked = kept
+	}
+	return sb.String()
+}
+
+// pruneParkedLocked removes messages parked longer than Jaws.ParkWindow.
+func (sess *Session) pruneParkedLocked(now time.Time) {
+	deadline := now.Add(-sess.jw.ParkWindow)
+	i := 0
+	for i < len(sess.parked) && sess.parked[i].when.Before(deadline) {
+		i++
+	}
+	if i > 0 {
+		clear(sess.parked[:i])
+		sess.parked = sess.parked[i:]
+	}
+}
+
+// parkUnsent parks the parkable messages the WebSocket writer left in
+// outboundCh or the outbound backlog if the connection was lost.
+// outboundCh must be closed.
+func (rq *Request) parkUnsent(outboundCh <-chan string) {
+	backlog := rq.outBacklog
+	rq.outBacklog = nil
+	rq.sendCount.backlog.Store(0)
+	if rq.Jaws.ParkWindow > 0 {
+		var sb strings.Builder
+		for s := range outboundCh {
+			sb.WriteString(parkableLines(s))
+		}
+		for _, s := range backlog {
+			sb.WriteString(parkableLines(s))
+		}
+		rq.session.park(rq.JawsKey, sb.String())
+	}
+}
+
+// startParking parks the parkable broadcasts for the suspended Request
+// in it's Session until it is resumed or recycled.
+func (rq *Request) startParking() {
+	if rq.Jaws.ParkWindow > 0 {
+		rq.mu.RLock()
+		sess, jawsKey := rq.session, rq.JawsKey
+		rq.mu.RUnlock()
+		if sess != nil {
+			if msgCh := rq.Jaws.subscribe(rq, 16); msgCh != nil {
+				stopCh := make(chan struct{})
+				doneCh := make(chan struct{})
+				rq.mu.Lock()
+				parking := rq.JawsKey == jawsKey && !rq.lost.IsZero() && rq.parkStop == nil
+				if parking {
+					rq.parkStop, rq.parkDone = stopCh, doneCh
+				}
+				rq.mu.Unlock()
+				if parking {
+					go rq.parkBroadcasts(sess, jawsKey, msgCh, stopCh, doneCh)
+				} else {
+					rq.Jaws.unsubscribe(msgCh)
+				}
+			}
+		}
+	}
+}
+
+// stopParkingLocked stops parking broadcasts and returns a channel that
+// is closed when that has finished, or nil if not parking.
+func (rq *Request) stopParkingLocked() (doneCh <-chan struct{}) {
+	if rq.parkStop != nil {
+		close(rq.parkStop)
+		doneCh = rq.parkDone
+		rq.parkStop, rq.parkDone = nil, nil
+	}
+	return
+}
+
+func (rq *Request) parkBroadcasts(sess *Session, jawsKey uint64, msgCh chan Message, stopCh <-chan struct{}, doneCh chan<- struct{}) {
+	defer close(doneCh)
+	defer rq.Jaws.unsubscribe(msgCh)
+	for {
+		select {
+		case <-stopCh:
+			return
+		case <-rq.Jaws.Done():
+			return
+		case msg, ok := <-msgCh:
+			if !ok {
+				return
+			}
+			if parkable(msg.What) {
+				wsdata, _ := msg.Data.(string)
+				m := rq.renderAlert(wsMsg{Jid: 0, Data: wsdata, What: msg.What})
+				sess.park(jawsKey, m.Format())
+			}
+		}
+	}
+}
+
+// stopParking stops parking broadcasts for the resumed Request.
+func (rq *Request) stopParking() {
+	rq.mu.Lock()
+	doneCh := rq.stopParkingLocked()
+	rq.mu.Unlock()
+	if doneCh != nil {
+		<-doneCh
+	}
+}
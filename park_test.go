@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func Test_parkableLines(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(parkableLines(""), "")
+	th.Equal(parkableLines("Inner\tJid.1\t\"a\"\nAlert\t\t\"info\\nhi\"\nValue\tJid.2\t\"b\"\nRedirect\t\t\"/\"\n"),
+		"Alert\t\t\"info\\nhi\"\nRedirect\t\t\"/\"\n")
+}
+
+func TestSession_park(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.ParkWindow = time.Minute
+	jw.ParkLimit = 2
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	sess := jw.NewSession(nil, hr)
+
+	sess.park(1, "a\n")
+	sess.park(2, "b\n")
+	sess.park(1, "")
+	sess.park(1, "c\n")
+	th.Equal(sess.unpark(1), "c\n")
+	th.Equal(sess.unpark(2), "b\n")
+	th.Equal(sess.unpark(2), "")
+
+	sess.park(1, "d\n")
+	sess.mu.Lock()
+	sess.parked[0].when = time.Now().Add(-2 * time.Minute)
+	sess.mu.Unlock()
+	th.Equal(sess.unpark(1), "")
+
+	var nilSess *Session
+	nilSess.park(1, "e\n")
+	th.Equal(nilSess.unpark(1), "")
+}
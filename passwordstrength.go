@@ -0,0 +1,80 @@
+package jaws
+
+import (
+	"math"
+	"unicode"
+)
+
+// PasswordStrength is the result of scoring a password, modeled after zxcvbn.
+type PasswordStrength struct {
+	Score    int    // 0 (too guessable) to 4 (very unguessable)
+	Feedback string // advice to the user, may be empty
+}
+
+// PasswordScorer scores a password on the server as the user types.
+// Passing one as a parameter to RequestWriter.Password adds a strength meter.
+//
+// It may wrap a zxcvbn implementation, or use ScorePassword.
+type PasswordScorer func(password string) PasswordStrength
+
+// PasswordReveal is a parameter to RequestWriter.Password that adds a button
+// with the given label that shows or hides the password. The toggle is done
+// in the browser, so the value is never sent anywhere.
+type PasswordReveal string
+
+// ScorePassword is a simple PasswordScorer that estimates the entropy of a
+// password from it's length and the kinds of characters used.
+func ScorePassword(password string) (ps PasswordStrength) {
+	var lower, upper, digit, other bool
+	seen := map[rune]struct{}{}
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			other = true
+		}
+		seen[r] = struct{}{}
+	}
+	var kinds, charset float64
+	for _, k := range []struct {
+		used bool
+		size float64
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {other, 33}} {
+		if k.used {
+			kinds++
+			charset += k.size
+		}
+	}
+	n := float64(len(seen))
+	if length := float64(len([]rune(password))); length > 0 {
+		// repeated characters add little, so count them at half weight
+		n += (length - n) / 2
+	}
+	bits := n * math.Log2(math.Max(charset, 1))
+	switch {
+	case bits >= 90:
+		ps.Score = 4
+	case bits >= 72:
+		ps.Score = 3
+	case bits >= 56:
+		ps.Score = 2
+	case bits >= 40:
+		ps.Score = 1
+	}
+	if ps.Score < 3 {
+		switch {
+		case len(seen) < 12:
+			ps.Feedback = "Use a longer password"
+		case kinds < 3:
+			ps.Feedback = "Mix letters, digits and symbols"
+		default:
+			ps.Feedback = "Avoid repeated characters"
+		}
+	}
+	return
+}
@@ -0,0 +1,15 @@
+package jaws
+
+import "testing"
+
+func TestScorePassword(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(ScorePassword(""), PasswordStrength{Score: 0, Feedback: "Use a longer password"})
+	th.Equal(ScorePassword("password").Score, 0)
+	th.Equal(ScorePassword("aaaaaaaaaaaaaaaaaaaa").Feedback, "Use a longer password")
+	th.Equal(ScorePassword("abcdefghijklmn"), PasswordStrength{Score: 2, Feedback: "Mix letters, digits and symbols"})
+	th.Equal(ScorePassword("Tr0ub4dor&3"), PasswordStrength{Score: 2, Feedback: "Use a longer password"})
+	th.Equal(ScorePassword("Tr0ub4dor&3xyz"), PasswordStrength{Score: 3})
+	th.Equal(ScorePassword("correct horse battery staple"), PasswordStrength{Score: 4})
+	th.Equal(ScorePassword("Xk9#mQ2$vL7!pW4@").Score, 4)
+}
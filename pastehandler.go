@@ -0,0 +1,141 @@
+package jaws
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultMaxPasteSize is the default maximum size in bytes of pasted or dropped data.
+const DefaultMaxPasteSize = 16 * 1024 * 1024
+
+// ErrPasteTooLarge is returned when pasted or dropped data exceeds Jaws.MaxPasteSize,
+// or the data being received for all of a Request's pastes together would.
+var ErrPasteTooLarge = errors.New("pasted data too large")
+
+// ErrTooManyPastes is returned when a browser starts more than maxPastes
+// pastes or drops for a Request without finishing them.
+var ErrTooManyPastes = errors.New("too many pastes in progress")
+
+// maxPastes is how many pastes or drops a Request receives at once.
+const maxPastes = 4
+
+// pasteTimeout is how long incomplete pasted data is kept without receiving more.
+const pasteTimeout = time.Minute
+
+// PasteHandler receives files and images pasted from the clipboard or
+// dragged and dropped onto an Element.
+//
+// The HTML element must have the `data-jaws-paste` attribute for the browser
+// to capture pastes and drops. It is added automatically when a PasteHandler
+// is passed as a parameter when rendering the Element.
+type PasteHandler interface {
+	JawsPaste(e *Element, mime, name string, data []byte) (err error)
+}
+
+type pasteHandlerWrapper struct{ PasteHandler }
+
+func (phw pasteHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Paste {
+		return callPasteHandler(phw.PasteHandler, e, v)
+	}
+	return ErrEventUnhandled
+}
+
+func callPasteHandler(h PasteHandler, e *Element, val string) error {
+	mime, after, _ := strings.Cut(val, "\t")
+	name, data, _ := strings.Cut(after, "\t")
+	return h.JawsPaste(e, mime, name, []byte(data))
+}
+
+// pasteKey identifies the pasted data being received for an Element.
+type pasteKey struct {
+	jid Jid
+	id  string // upload ID chosen by the browser
+}
+
+// pasteBuffers are the pasted data being received for a Request's Elements.
+type pasteBuffers map[pasteKey]*pasteBuffer
+
+type pasteBuffer struct {
+	mime string
+	name string
+	data []byte
+	last time.Time // when data was last received
+}
+
+// pasteChunk accumulates a chunk of pasted data for the Element.
+//
+// The chunk format is "uploadID\tfinal\tmime\tname\tbase64data", where final is "1"
+// for the last chunk. Returns the complete data in the form "mime\tname\tdata" and
+// true once the final chunk has been received.
+//
+// The incomplete data of all the Request's Elements together is limited to
+// maxPastes uploads and MaxPasteSize bytes.
+func (e *Element) pasteChunk(chunk string) (val string, done bool, err error) {
+	parts := strings.SplitN(chunk, "\t", 5)
+	if len(parts) != 5 {
+		return
+	}
+	var b []byte
+	if b, err = base64.StdEncoding.DecodeString(parts[4]); err == nil {
+		rq := e.Request
+		k := pasteKey{jid: e.jid, id: parts[0]}
+		now := time.Now()
+		maxSize := rq.maxPasteSize()
+		rq.mu.Lock()
+		defer rq.mu.Unlock()
+		rq.expirePastesLocked(now)
+		pb := rq.pastes[k]
+		if pb == nil {
+			if len(rq.pastes) >= maxPastes {
+				return "", false, ErrTooManyPastes
+			}
+			if rq.pastes == nil {
+				rq.pastes = make(pasteBuffers)
+			}
+			pb = &pasteBuffer{mime: parts[2], name: parts[3]}
+			rq.pastes[k] = pb
+		}
+		if len(pb.data)+len(b) > maxSize || rq.pasteBytes+len(b) > maxSize {
+			rq.deletePasteLocked(k)
+			return "", false, ErrPasteTooLarge
+		}
+		pb.data = append(pb.data, b...)
+		pb.last = now
+		rq.pasteBytes += len(b)
+		if parts[1] == "1" {
+			rq.deletePasteLocked(k)
+			return pb.mime + "\t" + pb.name + "\t" + string(pb.data), true, nil
+		}
+	}
+	return
+}
+
+func (rq *Request) deletePasteLocked(k pasteKey) {
+	if pb := rq.pastes[k]; pb != nil {
+		rq.pasteBytes -= len(pb.data)
+		delete(rq.pastes, k)
+	}
+}
+
+// deletePastesLocked discards the incomplete pasted data for the Element with the given Jid.
+func (rq *Request) deletePastesLocked(jid Jid) {
+	for k := range rq.pastes {
+		if k.jid == jid {
+			rq.deletePasteLocked(k)
+		}
+	}
+}
+
+// expirePastesLocked discards incomplete pasted data not added to for pasteTimeout.
+func (rq *Request) expirePastesLocked(now time.Time) {
+	for k, pb := range rq.pastes {
+		if now.Sub(pb.last) > pasteTimeout {
+			rq.deletePasteLocked(k)
+		}
+	}
+}
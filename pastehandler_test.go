@@ -0,0 +1,124 @@
+package jaws
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testPaste struct {
+	mime string
+	name string
+	data []byte
+}
+
+type testJawsPaste struct {
+	pasteCh chan testPaste
+}
+
+func (tjp *testJawsPaste) JawsPaste(e *Element, mime, name string, data []byte) (err error) {
+	tjp.pasteCh <- testPaste{mime: mime, name: name, data: data}
+	return
+}
+
+var _ PasteHandler = (*testJawsPaste)(nil)
+
+func Test_pasteHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tjp := &testJawsPaste{pasteCh: make(chan testPaste)}
+	th.NoErr(rq.Div("inner", tjp))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-paste>inner</div>`)
+
+	data := base64.StdEncoding.EncodeToString([]byte("hello world!"))
+	rq.inCh <- wsMsg{Data: "7\t0\timage/png\tx.png\t" + data[:8], Jid: 1, What: what.Paste}
+	rq.inCh <- wsMsg{Data: "7\t1\timage/png\tx.png\t" + data[8:], Jid: 1, What: what.Paste}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case got := <-tjp.pasteCh:
+		th.Equal(got.mime, "image/png")
+		th.Equal(got.name, "x.png")
+		th.Equal(string(got.data), "hello world!")
+	}
+}
+
+func TestElement_pasteChunk(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	elem := rq.NewElement(NewUiDiv(makeHtmlGetter("")))
+
+	val, done, err := elem.pasteChunk("garbage")
+	th.NoErr(err)
+	th.Equal(done, false)
+	th.Equal(val, "")
+
+	_, _, err = elem.pasteChunk("1\t1\t\t\t!!!")
+	th.True(err != nil)
+
+	rq.Jaws.MaxPasteSize = 4
+	_, done, err = elem.pasteChunk("2\t0\ttext/plain\t\t" + base64.StdEncoding.EncodeToString([]byte("abc")))
+	th.NoErr(err)
+	th.Equal(done, false)
+	_, _, err = elem.pasteChunk("2\t1\ttext/plain\t\t" + base64.StdEncoding.EncodeToString([]byte("def")))
+	th.Equal(err, ErrPasteTooLarge)
+	th.Equal(len(rq.pastes), 0)
+
+	val, done, err = elem.pasteChunk("3\t1\ttext/plain\ta\t" + base64.StdEncoding.EncodeToString([]byte("ab")))
+	th.NoErr(err)
+	th.Equal(done, true)
+	th.True(strings.HasSuffix(val, "\tab"))
+	th.Equal(rq.pasteBytes, 0)
+}
+
+func TestElement_pasteChunkLimits(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+	rq.Jaws.MaxPasteSize = 8
+	elem1 := rq.NewElement(NewUiDiv(makeHtmlGetter("")))
+	elem2 := rq.NewElement(NewUiDiv(makeHtmlGetter("")))
+	chunk := func(id string) string {
+		return id + "\t0\ttext/plain\t\t" + base64.StdEncoding.EncodeToString([]byte("abc"))
+	}
+
+	// the total of a Request's pastes is limited
+	_, _, err := elem1.pasteChunk(chunk("1"))
+	th.NoErr(err)
+	_, _, err = elem2.pasteChunk(chunk("1"))
+	th.NoErr(err)
+	_, _, err = elem2.pasteChunk(chunk("2"))
+	th.Equal(err, ErrPasteTooLarge)
+	th.Equal(len(rq.pastes), 2)
+	th.Equal(rq.pasteBytes, 6)
+
+	// deleting the Element discards it's pastes
+	rq.deleteElement(elem2)
+	th.Equal(len(rq.pastes), 1)
+	th.Equal(rq.pasteBytes, 3)
+
+	// and so does not receiving more for a while
+	rq.expirePastesLocked(time.Now().Add(pasteTimeout * 2))
+	th.Equal(len(rq.pastes), 0)
+	th.Equal(rq.pasteBytes, 0)
+
+	// too many pastes at once are refused
+	rq.Jaws.MaxPasteSize = 1024
+	for i := 0; i < maxPastes; i++ {
+		_, _, err = elem1.pasteChunk(chunk(strconv.Itoa(i)))
+		th.NoErr(err)
+	}
+	_, _, err = elem1.pasteChunk(chunk("x"))
+	th.Equal(err, ErrTooManyPastes)
+	th.Equal(len(rq.pastes), maxPastes)
+}
@@ -0,0 +1,83 @@
+package jaws
+
+import (
+	"sync"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultPendingText is the default value of Jaws.PendingText.
+const DefaultPendingText = "Saving…"
+
+// Pending is returned as an error by setters and event handlers that
+// complete asynchronously.
+//
+// While pending, the Element is not updated and has the "aria-busy" and
+// "data-jaws-pending" attributes set, the latter to Jaws.PendingText.
+// Once Done is called the attributes are removed and the Element is
+// updated to show the confirmed value. If the error passed to Done is
+// not nil it is shown as an alert and sent to the Jaws ErrorReporter.
+type Pending struct {
+	once   sync.Once
+	doneCh chan struct{}
+	err    error
+}
+
+// NewPending returns a new Pending. Call Done when the operation completes.
+func NewPending() *Pending {
+	return &Pending{doneCh: make(chan struct{})}
+}
+
+// Async calls fn in a new goroutine and returns a *Pending that
+// completes with the error fn returns.
+//
+//	func (s *Store) JawsSetString(e *jaws.Element, v string) error {
+//		return jaws.Async(func() error { return s.db.Save(e.Context(), v) })
+//	}
+func Async(fn func() error) error {
+	p := NewPending()
+	go func() { p.Done(fn()) }()
+	return p
+}
+
+// Done completes the Pending with the given error, which may be nil.
+// Calls after the first are ignored.
+func (p *Pending) Done(err error) {
+	p.once.Do(func() {
+		p.err = err
+		close(p.doneCh)
+	})
+}
+
+func (p *Pending) Error() string {
+	return "pending"
+}
+
+func (rq *Request) setPending(e *Element, pending bool) {
+	rq.mu.Lock()
+	e.pending = pending
+	rq.mu.Unlock()
+}
+
+// handlePending marks the Element as pending, and waits in a new
+// goroutine for the Pending to complete.
+func (rq *Request) handlePending(e *Element, wht what.What, val string, p *Pending) {
+	rq.setPending(e, true)
+	rq.Jaws.SetAttr(e, "aria-busy", "true")
+	rq.Jaws.SetAttr(e, "data-jaws-pending", rq.Jaws.PendingText)
+	go func() {
+		select {
+		case <-rq.Done():
+			return
+		case <-p.doneCh:
+		}
+		rq.setPending(e, false)
+		rq.Jaws.RemoveAttr(e, "aria-busy")
+		rq.Jaws.RemoveAttr(e, "data-jaws-pending")
+		rq.Jaws.Broadcast(Message{Dest: e, What: what.Update})
+		if p.err != nil {
+			rq.reportError(e, wht, val, p.err, nil, nil)
+			rq.AlertError(p.err)
+		}
+	}()
+}
@@ -0,0 +1,90 @@
+package jaws
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testAsyncSetter struct {
+	*testSetter[string]
+	p *Pending
+}
+
+func (tas *testAsyncSetter) JawsSetString(e *Element, val string) error {
+	return tas.p
+}
+
+func TestPending_Done(t *testing.T) {
+	th := newTestHelper(t)
+	p := NewPending()
+	p.Done(nil)
+	p.Done(errors.New("ignored"))
+	th.NoErr(p.err)
+	th.Equal(p.Error(), "pending")
+
+	err := Async(func() error { return ErrEventUnhandled })
+	p, ok := err.(*Pending)
+	th.True(ok)
+	<-p.doneCh
+	th.Equal(p.err, ErrEventUnhandled)
+}
+
+func TestRequest_Pending(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	reportCh := make(chan *ErrorReport, 1)
+	rq.Jaws.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reportCh <- report })
+
+	tas := &testAsyncSetter{testSetter: newTestSetter("foo"), p: NewPending()}
+	th.NoErr(rq.Text(tas))
+	rq.inCh <- wsMsg{Data: "bar", Jid: 1, What: what.Input}
+
+	for _, want := range []string{
+		"SAttr\tJid.1\t\"aria-busy\\ntrue\"\n",
+		"SAttr\tJid.1\t\"data-jaws-pending\\n" + DefaultPendingText + "\"\n",
+	} {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			th.Equal(s, want)
+		}
+	}
+	elem := rq.getElementByJid(1)
+	rq.mu.RLock()
+	th.True(elem.pending)
+	rq.mu.RUnlock()
+
+	errFailed := errors.New("failed")
+	tas.p.Done(errFailed)
+
+	var got []string
+	for len(got) < 4 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			got = append(got, s)
+		}
+	}
+	sort.Strings(got)
+	th.True(strings.HasPrefix(got[0], "Alert\t\t\"danger\\nfailed"))
+	th.Equal(got[1], "RAttr\tJid.1\t\"aria-busy\"\n")
+	th.Equal(got[2], "RAttr\tJid.1\t\"data-jaws-pending\"\n")
+	th.Equal(got[3], "Value\tJid.1\t\"foo\"\n")
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case report := <-reportCh:
+		th.Equal(report.Err, errFailed)
+		th.Equal(report.Value, "bar")
+	}
+}
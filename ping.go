@@ -0,0 +1,72 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultPingTimeout is the PingTimeout used if Jaws.PingTimeout isn't set.
+const DefaultPingTimeout = 10 * time.Second
+
+// ErrPingTimeout is the cause of a WebSocket connection being closed
+// because the browser didn't answer a ping in time.
+var ErrPingTimeout = errors.New("websocket ping timeout")
+
+// WebSocketPinger is implemented by WebSocketConns that can send pings,
+// which is needed for Jaws.PingInterval to have any effect.
+type WebSocketPinger interface {
+	// Ping sends a ping and waits for the pong, or until ctx is done.
+	Ping(ctx context.Context) error
+}
+
+// pingTimeout returns how long to wait for the response to a ping.
+func (jw *Jaws) pingTimeout() time.Duration {
+	if jw.PingTimeout > 0 {
+		return jw.PingTimeout
+	}
+	return DefaultPingTimeout
+}
+
+// LastSeen returns when the browser was last heard from, which is when it
+// connected, sent a message, long-polled or answered a ping. It is the zero
+// time if the browser hasn't connected.
+func (rq *Request) LastSeen() (when time.Time) {
+	rq.mu.RLock()
+	when = rq.lastSeen
+	rq.mu.RUnlock()
+	return
+}
+
+func (rq *Request) markSeen() {
+	rq.mu.Lock()
+	rq.lastSeen = time.Now()
+	rq.mu.Unlock()
+}
+
+// wsPinger pings the browser every interval until ctx is done. If a ping
+// isn't answered within timeout, ccf is called with an error wrapping ErrPingTimeout.
+func (rq *Request) wsPinger(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-chan struct{}, pinger WebSocketPinger, interval, timeout time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-jawsDoneCh:
+			return
+		case <-t.C:
+			pingCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := pinger.Ping(pingCtx)
+			cancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					ccf(fmt.Errorf("jaws: %v: %w: %v", rq, ErrPingTimeout, err))
+				}
+				return
+			}
+			rq.markSeen()
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type testPinger struct {
+	fail bool
+}
+
+func (p *testPinger) Ping(ctx context.Context) error {
+	if p.fail {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return nil
+}
+
+func TestJaws_pingTimeout(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	th.Equal(jw.pingTimeout(), DefaultPingTimeout)
+	jw.PingTimeout = time.Second
+	th.Equal(jw.pingTimeout(), time.Second)
+}
+
+func TestRequest_wsPinger(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+	th.True(rq.LastSeen().IsZero())
+
+	ctx, ccf := context.WithCancelCause(context.Background())
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		rq.wsPinger(ctx, ccf, jw.Done(), &testPinger{}, time.Millisecond, time.Second)
+	}()
+	for rq.LastSeen().IsZero() {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	ccf(nil)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+
+	ctx, ccf = context.WithCancelCause(context.Background())
+	rq.wsPinger(ctx, ccf, jw.Done(), &testPinger{fail: true}, time.Millisecond, time.Millisecond)
+	th.True(errors.Is(context.Cause(ctx), ErrPingTimeout))
+}
@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"context"
+	"html/template"
+
+	"github.com/linkdata/deadlock"
+)
+
+// Placeholder is a params option giving the HTML to render while the
+// content of an Element is being fetched, such as a skeleton or spinner.
+//
+// Giving a Placeholder makes the Element call it's getter in a new
+// goroutine rather than while rendering, and replace the placeholder
+// when the getter returns. Use it for getters that may be slow.
+type Placeholder template.HTML
+
+// DefaultPlaceholder is rendered for AsyncHtmlGetters if no Placeholder is given.
+const DefaultPlaceholder = Placeholder(`<span class="jaws-placeholder" aria-busy="true"></span>`)
+
+// AsyncHtmlGetter is implemented by getters whose content is slow to
+// produce, such as when it requires querying a remote service.
+//
+// Elements using it always render a Placeholder and call JawsGetHtmlAsync
+// in a new goroutine. The ctx is done when the Request ends. If an error
+// is returned, it is logged and the content is left empty.
+type AsyncHtmlGetter interface {
+	JawsGetHtmlAsync(ctx context.Context, e *Element) (template.HTML, error)
+}
+
+// asyncHtmlGetter adapts an AsyncHtmlGetter to a HtmlGetter.
+type asyncHtmlGetter struct{ v AsyncHtmlGetter }
+
+func (g asyncHtmlGetter) JawsGetHtml(e *Element) (v template.HTML) {
+	v, _ = g.JawsGetHtmlAsync(e.Context(), e)
+	return
+}
+
+func (g asyncHtmlGetter) JawsGetHtmlAsync(ctx context.Context, e *Element) (template.HTML, error) {
+	return g.v.JawsGetHtmlAsync(ctx, e)
+}
+
+func (g asyncHtmlGetter) JawsGetTag(rq *Request) interface{} {
+	if tagger, ok := g.v.(TagGetter); ok {
+		return tagger.JawsGetTag(rq)
+	}
+	return g.v
+}
+
+func extractPlaceholder(params []interface{}) (ph Placeholder, rest []interface{}, found bool) {
+	for i, p := range params {
+		if ph, found = p.(Placeholder); found {
+			rest = append(append(rest, params[:i]...), params[i+1:]...)
+			return
+		}
+	}
+	return "", params, false
+}
+
+// asyncHtml fetches the content of an Element in the background.
+// It is also the Element's tag used to have it updated when done.
+type asyncHtml struct {
+	getter  HtmlGetter
+	mu      deadlock.Mutex // protects following
+	running bool           // if a fetch is running
+	again   bool           // if another fetch should be started when done
+	ready   bool           // if value is ready to be sent
+	value   template.HTML
+}
+
+func (a *asyncHtml) get(e *Element) (v template.HTML) {
+	if ag, ok := a.getter.(AsyncHtmlGetter); ok {
+		var err error
+		ctx := e.Request.Context()
+		if v, err = ag.JawsGetHtmlAsync(ctx, e); err != nil && ctx.Err() == nil {
+			e.Request.Jaws.MustLog(err)
+		}
+		return
+	}
+	return a.getter.JawsGetHtml(e)
+}
+
+func (a *asyncHtml) run(e *Element) {
+	for {
+		v := a.get(e)
+		a.mu.Lock()
+		again := a.again
+		a.again = false
+		if !again {
+			a.running = false
+			a.ready = true
+			a.value = v
+		}
+		a.mu.Unlock()
+		if !again {
+			break
+		}
+	}
+	if e.Request.Context().Err() == nil {
+		e.Dirty(a)
+	}
+}
+
+// start begins fetching the content unless a fetch is already running,
+// in which case that fetch is repeated when done.
+func (a *asyncHtml) start(e *Element) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.running {
+		a.again = true
+		return
+	}
+	a.running = true
+	go a.run(e)
+}
+
+// update sends the fetched content if it is ready, otherwise it starts a new fetch.
+func (a *asyncHtml) update(e *Element) {
+	a.mu.Lock()
+	v, ready := a.value, a.ready
+	a.ready = false
+	a.mu.Unlock()
+	if ready {
+		e.SetInner(v)
+	} else {
+		a.start(e)
+	}
+}
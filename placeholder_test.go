@@ -0,0 +1,108 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+type testAsyncGetter struct {
+	gateCh chan struct{}
+	value  template.HTML
+	err    error
+}
+
+func (g *testAsyncGetter) JawsGetHtmlAsync(ctx context.Context, e *Element) (template.HTML, error) {
+	select {
+	case <-ctx.Done():
+		return "", ctx.Err()
+	case <-g.gateCh:
+	}
+	return g.value, g.err
+}
+
+func (tr *testRequest) waitFor(th *testHelper, s string) (got string) {
+	for !strings.Contains(got, s) {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case msg := <-tr.outCh:
+			got += msg
+		}
+	}
+	return
+}
+
+func TestRequest_Placeholder(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	gateCh := make(chan struct{})
+	slow := newTestSetter(template.HTML("loaded"))
+	getter := &slowHtmlGetter{testSetter: slow, gateCh: gateCh}
+	th.NoErr(rq.Span(getter, Placeholder(`<i class="skeleton"></i>`), `class="x"`))
+	th.Equal(rq.BodyString(), `<span id="Jid.1" class="x"><i class="skeleton"></i></span>`)
+
+	close(gateCh)
+	got := rq.waitFor(th, "loaded")
+	th.True(strings.Contains(got, "Inner\tJid.1\t"))
+
+	slow.Set(template.HTML("reloaded"))
+	rq.Dirty(slow)
+	rq.waitFor(th, "reloaded")
+}
+
+type slowHtmlGetter struct {
+	*testSetter[template.HTML]
+	gateCh chan struct{}
+}
+
+func (g *slowHtmlGetter) JawsGetHtml(e *Element) template.HTML {
+	<-g.gateCh
+	return g.testSetter.JawsGetHtml(e)
+}
+
+func (g *slowHtmlGetter) JawsGetTag(rq *Request) interface{} {
+	return g.testSetter
+}
+
+func TestRequest_AsyncHtmlGetter(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ag := &testAsyncGetter{gateCh: make(chan struct{}), value: "<b>done</b>"}
+	th.NoErr(rq.Div(ag))
+	th.Equal(rq.BodyString(), `<div id="Jid.1">`+string(DefaultPlaceholder)+`</div>`)
+	th.True(rq.getElementByJid(1).HasTag(ag))
+	ag.gateCh <- struct{}{}
+	rq.waitFor(th, "done")
+
+	errAsync := errors.New("backend unavailable")
+	failing := &testAsyncGetter{gateCh: make(chan struct{}), err: errAsync}
+	th.NoErr(rq.Div(failing, Placeholder("wait")))
+	close(failing.gateCh)
+	rq.waitFor(th, "Inner\tJid.2\t\"\"\n")
+	th.True(strings.Contains(rq.jw.log.String(), errAsync.Error()))
+
+	close(ag.gateCh)
+	hg := makeHtmlGetter(ag)
+	th.Equal(hg.JawsGetHtml(rq.getElementByJid(1)), template.HTML("<b>done</b>"))
+	th.Equal(hg.(TagGetter).JawsGetTag(rq.Request), interface{}(ag))
+}
+
+func Test_extractPlaceholder(t *testing.T) {
+	th := newTestHelper(t)
+	ph, rest, found := extractPlaceholder([]interface{}{"a", Placeholder("p"), "b"})
+	th.True(found)
+	th.Equal(ph, Placeholder("p"))
+	th.Equal(rest, []interface{}{"a", "b"})
+	_, rest, found = extractPlaceholder([]interface{}{"a"})
+	th.True(!found)
+	th.Equal(rest, []interface{}{"a"})
+}
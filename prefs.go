@@ -0,0 +1,42 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// UserPrefs are the user's preferences as reported by the browser
+// after the WebSocket connects, and whenever they change.
+type UserPrefs struct {
+	ReducedMotion bool   // user prefers reduced motion, so avoid animations
+	ColorScheme   string // "dark", "light", or empty if not yet reported
+}
+
+// Prefs returns the user preferences reported by the browser.
+//
+// Since they are reported once the WebSocket is connected, Elements
+// tagged with the Request are dirtied when they change.
+func (rq *Request) Prefs() (prefs UserPrefs) {
+	rq.mu.RLock()
+	prefs = rq.prefs
+	rq.mu.RUnlock()
+	return
+}
+
+func (rq *Request) handlePrefs(data string) {
+	motion, scheme, _ := strings.Cut(data, "\t")
+	reduced, err := strconv.ParseBool(motion)
+	if err != nil || (scheme != "dark" && scheme != "light") {
+		_ = rq.Jaws.Log(errors.New("jaws: prefs: " + strconv.Quote(data)))
+		return
+	}
+	prefs := UserPrefs{ReducedMotion: reduced, ColorScheme: scheme}
+	rq.mu.Lock()
+	changed := rq.prefs != prefs
+	rq.prefs = prefs
+	rq.mu.Unlock()
+	if changed {
+		rq.Dirty(rq)
+	}
+}
@@ -0,0 +1,47 @@
+package jaws
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testPrefsGetter struct{}
+
+func (testPrefsGetter) JawsGetHtml(e *Element) template.HTML {
+	return template.HTML(e.Request.Prefs().ColorScheme)
+}
+
+func TestRequest_Prefs(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.Equal(rq.Prefs(), UserPrefs{})
+	th.NoErr(rq.Span(testPrefsGetter{}, rq.Request))
+
+	rq.inCh <- wsMsg{What: what.Prefs, Data: "true\tdark"}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"dark\"\n")
+	}
+	th.Equal(rq.Prefs(), UserPrefs{ReducedMotion: true, ColorScheme: "dark"})
+
+	rq.inCh <- wsMsg{What: what.Prefs, Data: "maybe\tsepia"}
+	rq.inCh <- wsMsg{What: what.Prefs, Data: "true\tdark"}
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case s := <-rq.outCh:
+		t.Errorf("unexpected %q", s)
+	default:
+	}
+	rq.Close()
+	<-rq.doneCh
+	th.True(strings.Contains(rq.jw.log.String(), `jaws: prefs: "maybe\tsepia"`))
+}
@@ -0,0 +1,37 @@
+package jaws
+
+import (
+	"strings"
+)
+
+// Preserve is a parameter listing attributes of the Element that updates
+// from JaWS must not change, so that they can be managed by client-side
+// code without the server overwriting them.
+//
+// Entries starting with a dot are class names, letting client code toggle
+// those classes while the server sets the rest of the class attribute.
+// Entries ending with an asterisk match names with that prefix.
+//
+//	rw.Div(content, jaws.Preserve{"aria-expanded", ".open", "data-ui-*"})
+//
+// The browser merges the preserved attributes and classes into the Element
+// when handling Element.SetAttr, Element.RemoveAttr, Element.SetClass,
+// Element.RemoveClass and Element.Replace, and when the Element is
+// re-created by setting the inner HTML of an Element containing it.
+type Preserve []string
+
+// preserveAttrs returns the "data-jaws-preserve" attribute listing the
+// attributes and classes the browser must not change when updating the
+// Element, or an empty string if there are none.
+func preserveAttrs(preserve []string) string {
+	var names []string
+	for _, name := range preserve {
+		if name != "" && !strings.ContainsAny(name, " \t\n\r\f") {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	return string(Data("jaws-preserve", strings.Join(names, " ")))
+}
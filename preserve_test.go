@@ -0,0 +1,22 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func Test_preserveAttrs(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(preserveAttrs(nil), "")
+	th.Equal(preserveAttrs([]string{"", "a b"}), "")
+	th.Equal(preserveAttrs([]string{"class", "data-x-*", ".open"}), `data-jaws-preserve="class data-x-* .open"`)
+}
+
+func TestRequest_Preserve(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Div("x", Preserve{".open", "aria-expanded"}, Alpine("{}")))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" x-data="{}" data-jaws-preserve=".open aria-expanded x-* :* @* style">x</div>`)
+}
@@ -0,0 +1,23 @@
+package jaws
+
+// PrincipalProvider provides the authenticated principal of a Request,
+// such as the logged in user, set it in Jaws.Principal.
+type PrincipalProvider interface {
+	JawsGetPrincipal(rq *Request) interface{}
+}
+
+// PrincipalFunc adapts an ordinary function to the PrincipalProvider interface.
+type PrincipalFunc func(rq *Request) interface{}
+
+func (fn PrincipalFunc) JawsGetPrincipal(rq *Request) interface{} {
+	return fn(rq)
+}
+
+// Principal returns the authenticated principal of the Request as given
+// by Jaws.Principal, or nil if it isn't set or there is none.
+func (rq *Request) Principal() interface{} {
+	if pp := rq.Jaws.Principal; pp != nil {
+		return pp.JawsGetPrincipal(rq)
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestRequest_Principal(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+
+	rq := tj.NewRequest(nil)
+	th.Equal(rq.Principal(), nil)
+	tj.Principal = PrincipalFunc(func(r *Request) interface{} {
+		if r == rq {
+			return "alice"
+		}
+		return nil
+	})
+	th.Equal(rq.Principal(), "alice")
+	th.Equal(tj.NewRequest(nil).Principal(), nil)
+}
@@ -0,0 +1,108 @@
+package jaws
+
+import (
+	"slices"
+)
+
+type prioritySet = map[interface{}]struct{}
+
+// SetPriority marks the given tags as high priority. Elements having one
+// of them are updated before others, and when a Request is congested,
+// broadcasts to them are delivered before other broadcasts.
+//
+// This keeps things like alarm banners timely on busy pages. Elements
+// are marked when tagged, so call it before rendering.
+func (jw *Jaws) SetPriority(tags ...interface{}) {
+	expanded := MustTagExpand(nil, tags)
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	set := prioritySet{}
+	if old := jw.priority.Load(); old != nil {
+		for k := range *old {
+			set[k] = struct{}{}
+		}
+	}
+	for _, tag := range expanded {
+		set[tag] = struct{}{}
+	}
+	jw.priority.Store(&set)
+}
+
+// isPriority returns true if tag has been marked as high priority.
+func (jw *Jaws) isPriority(tag interface{}) (yes bool) {
+	if set := jw.priority.Load(); set != nil {
+		_, yes = (*set)[tag]
+	}
+	return
+}
+
+// hasPriority returns true if any tags have been marked as high priority.
+func (jw *Jaws) hasPriority() bool {
+	return jw.priority.Load() != nil
+}
+
+// isPriorityMessage returns true if the Message is for high priority Elements.
+func (rq *Request) isPriorityMessage(msg *Message) (yes bool) {
+	switch dest := msg.Dest.(type) {
+	case *Element:
+		rq.mu.RLock()
+		yes = dest.priority
+		rq.mu.RUnlock()
+	case []interface{}:
+		yes = slices.ContainsFunc(dest, rq.Jaws.isPriority)
+	default:
+		yes = rq.Jaws.isPriority(dest)
+	}
+	return
+}
+
+// prioritize drains any messages waiting in msgCh into backlog and
+// returns it with the high priority messages first.
+func (rq *Request) prioritize(msgCh chan Message, backlog []Message) []Message {
+	backlog = drainMessages(msgCh, backlog)
+	slices.SortStableFunc(backlog, func(a, b Message) int {
+		pa, pb := rq.isPriorityMessage(&a), rq.isPriorityMessage(&b)
+		switch {
+		case pa && !pb:
+			return -1
+		case pb && !pa:
+			return 1
+		}
+		return 0
+	})
+	return backlog
+}
+
+// drainMessages appends any messages waiting in msgCh to backlog.
+func drainMessages(msgCh chan Message, backlog []Message) []Message {
+	for n := len(msgCh); n > 0; n-- {
+		msg, ok := <-msgCh
+		if !ok {
+			break
+		}
+		backlog = append(backlog, msg)
+	}
+	return backlog
+}
+
+// drainQueued moves any messages waiting in msgCh to backlog,
+// keeping the high priority messages first if there are any.
+func (rq *Request) drainQueued(msgCh chan Message, backlog []Message) []Message {
+	if rq.Jaws.hasPriority() {
+		return rq.prioritize(msgCh, backlog)
+	}
+	return drainMessages(msgCh, backlog)
+}
+
+// sortPriorityElements stably moves high priority Elements first.
+func sortPriorityElements(elems []*Element) {
+	slices.SortStableFunc(elems, func(a, b *Element) int {
+		switch {
+		case a.priority && !b.priority:
+			return -1
+		case b.priority && !a.priority:
+			return 1
+		}
+		return 0
+	})
+}
@@ -0,0 +1,45 @@
+package jaws
+
+import (
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestJaws_SetPriority(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.Equal(rq.Jaws.hasPriority(), false)
+	th.Equal(rq.Jaws.isPriority(Tag("alarm")), false)
+	rq.Jaws.SetPriority(Tag("alarm"))
+	rq.Jaws.SetPriority(Tag("banner"))
+	th.True(rq.Jaws.hasPriority())
+	th.True(rq.Jaws.isPriority(Tag("alarm")))
+	th.True(rq.Jaws.isPriority(Tag("banner")))
+
+	bulk := rq.NewElement(NewUiSpan(makeHtmlGetter("bulk")))
+	bulk.Tag(Tag("bulk"))
+	alarm := rq.NewElement(NewUiSpan(makeHtmlGetter("alarm")))
+	alarm.Tag(Tag("alarm"))
+	th.Equal(bulk.priority, false)
+	th.Equal(alarm.priority, true)
+
+	rq.appendDirtyTags([]interface{}{Tag("bulk"), Tag("alarm")})
+	th.Equal(rq.makeUpdateList(), []*Element{alarm, bulk})
+
+	msgCh := make(chan Message, 4)
+	msgCh <- Message{Dest: Tag("bulk"), What: what.Inner, Data: "2"}
+	msgCh <- Message{Dest: []interface{}{Tag("bulk"), Tag("alarm")}, What: what.Inner, Data: "3"}
+	msgCh <- Message{Dest: bulk, What: what.Inner, Data: "4"}
+	msgCh <- Message{Dest: alarm, What: what.Inner, Data: "5"}
+	backlog := rq.prioritize(msgCh, []Message{{Dest: Tag("bulk"), What: what.Inner, Data: "1"}})
+	var order []interface{}
+	for _, msg := range backlog {
+		order = append(order, msg.Data)
+	}
+	th.Equal(order, []interface{}{"3", "5", "1", "2", "4"})
+	th.Equal(len(msgCh), 0)
+}
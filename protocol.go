@@ -0,0 +1,67 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// ProtocolVersion is the version of the message format spoken between JaWS
+// and the JaWS Javascript library. It is increased when the format changes
+// in ways older scripts can't handle.
+//
+// The script sends it as the WebSocket subprotocol "jaws.v<version>", or in
+// the X-Jaws-Protocol header when long-polling. Scripts that send none
+// predate version 2. Browsers running a script with another version, or
+// with none, are told to reload the page, which must therefore always work.
+const ProtocolVersion = 2
+
+// ProtocolHeader is the HTTP header long-polling requests send ProtocolVersion in.
+const ProtocolHeader = "X-Jaws-Protocol"
+
+// ErrProtocolVersion is returned when the browser's JaWS Javascript
+// library speaks another ProtocolVersion, usually because it is cached.
+var ErrProtocolVersion = errors.New("protocol version mismatch")
+
+// protocolVersion returns the protocol version sent by the browser in r,
+// or zero if it sent none.
+func protocolVersion(r *http.Request) int {
+	for _, line := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, proto := range strings.Split(line, ",") {
+			if s, ok := strings.CutPrefix(strings.TrimSpace(proto), WebSocketProtocol+".v"); ok {
+				if v, err := strconv.Atoi(s); err == nil {
+					return v
+				}
+			}
+		}
+	}
+	if v, err := strconv.Atoi(r.Header.Get(ProtocolHeader)); err == nil {
+		return v
+	}
+	return 0
+}
+
+// checkProtocol returns an error wrapping ErrProtocolVersion if the browser
+// speaks another version of the protocol.
+func (rq *Request) checkProtocol(r *http.Request) (err error) {
+	if v := protocolVersion(r); v != ProtocolVersion {
+		err = fmt.Errorf("jaws: %v: %w: browser has %d, want %d", rq, ErrProtocolVersion, v, ProtocolVersion)
+	}
+	return
+}
+
+// connectErrorMsg returns the message telling the browser why it couldn't
+// connect. Browsers speaking another protocol version are told to reload.
+func (rq *Request) connectErrorMsg(err error) (msg wsMsg) {
+	err = rq.Jaws.Log(err)
+	if errors.Is(err, ErrProtocolVersion) {
+		msg.What = what.Reload
+	} else {
+		msg.FillAlert(err)
+	}
+	return
+}
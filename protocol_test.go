@@ -0,0 +1,92 @@
+package jaws
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// newProtocolRequest returns a new incoming server Request that sends
+// ProtocolVersion the way jaws.js does.
+func newProtocolRequest(method, target string, body io.Reader) *http.Request {
+	hr := httptest.NewRequest(method, target, body)
+	hr.Header.Set(ProtocolHeader, strconv.Itoa(ProtocolVersion))
+	return hr
+}
+
+func Test_protocolVersion(t *testing.T) {
+	th := newTestHelper(t)
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	th.Equal(protocolVersion(hr), 0)
+	hr.Header.Set("Sec-WebSocket-Protocol", "jaws, jaws.bearer.x, jaws.v7")
+	th.Equal(protocolVersion(hr), 7)
+	hr = httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set(ProtocolHeader, "3")
+	th.Equal(protocolVersion(hr), 3)
+}
+
+func TestJavascript_ProtocolVersion(t *testing.T) {
+	if !strings.Contains(string(JavascriptText), "var jawsProtocolVersion = "+strconv.Itoa(ProtocolVersion)+";") {
+		t.Error("jaws.js protocol version differs from ProtocolVersion")
+	}
+}
+
+func TestWS_ProtocolMismatch(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	conn := &testWebSocketConn{inCh: make(chan []byte), outCh: make(chan []byte), closedCh: make(chan struct{})}
+	ts.jw.WebSocket = &testWebSocketTransport{conn: conn}
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		hr := httptest.NewRequest(http.MethodGet, ts.Path(), nil)
+		hr.Header.Set("Sec-WebSocket-Protocol", "jaws, jaws.v0")
+		ts.rq.ServeHTTP(httptest.NewRecorder(), hr)
+	}()
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case b := <-conn.outCh:
+		th.Equal(string(b), (&wsMsg{What: what.Reload}).Format())
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+}
+
+func TestLongPoll_ProtocolMismatch(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	rr := httptest.NewRecorder()
+	hr := httptest.NewRequest(http.MethodGet, PollPath+rq.JawsKeyString(), nil)
+	hr.Header.Set(ProtocolHeader, strconv.Itoa(ProtocolVersion+1))
+	jw.ServeHTTP(rr, hr)
+	th.Equal(rr.Body.String(), (&wsMsg{What: what.Reload}).Format())
+}
+
+func TestLongPoll_ProtocolMissing(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	rr := httptest.NewRecorder()
+	jw.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, PollPath+rq.JawsKeyString(), nil))
+	th.Equal(rr.Body.String(), (&wsMsg{What: what.Reload}).Format())
+}
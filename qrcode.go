@@ -0,0 +1,447 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+)
+
+// QRLevel is the error correction level of a QR code.
+type QRLevel uint8
+
+const (
+	QRLevelL QRLevel = iota // recovers about 7% of damaged data
+	QRLevelM                // recovers about 15% of damaged data
+	QRLevelQ                // recovers about 25% of damaged data
+	QRLevelH                // recovers about 30% of damaged data
+)
+
+// ErrQRCodeTooLong is returned by NewQRCode if the data doesn't fit in a QR code.
+var ErrQRCodeTooLong = errors.New("data too long for QR code")
+
+var qrEccCodewordsPerBlock = [4][41]int8{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+var qrNumErrorCorrectionBlocks = [4][41]int8{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+var qrFormatLevelBits = [4]int{1, 0, 3, 2}
+
+// QRCode is an encoded QR code symbol.
+type QRCode struct {
+	Version int // 1 to 40
+	Size    int // width and height in modules, 17 + 4 * Version
+	Level   QRLevel
+	Mask    int
+	modules []bool
+	isFunc  []bool
+}
+
+// NewQRCode encodes data in byte mode as a QR code with the given error correction
+// level, using the smallest version that fits and the mask with the lowest penalty.
+func NewQRCode(data []byte, level QRLevel) (qr *QRCode, err error) {
+	err = ErrQRCodeTooLong
+	for ver := 1; ver <= 40; ver++ {
+		ccbits := 8
+		if ver > 9 {
+			ccbits = 16
+		}
+		if len(data) < 1<<ccbits && 4+ccbits+len(data)*8 <= qrNumDataCodewords(ver, level)*8 {
+			qr = &QRCode{Version: ver, Size: ver*4 + 17, Level: level}
+			qr.encode(data, ccbits)
+			err = nil
+			break
+		}
+	}
+	return
+}
+
+// Get returns true if the module at x, y is dark. Coordinates outside the symbol are light.
+func (qr *QRCode) Get(x, y int) bool {
+	return x >= 0 && y >= 0 && x < qr.Size && y < qr.Size && qr.modules[y*qr.Size+x]
+}
+
+// AppendSVG appends the QR code as a SVG image with the given border width
+// to b and returns the result. Dark modules are drawn using currentColor.
+func (qr *QRCode) AppendSVG(b []byte, border int) []byte {
+	dim := strconv.Itoa(qr.Size + border*2)
+	b = append(b, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 `+dim+` `+dim+`" shape-rendering="crispEdges">`...)
+	b = append(b, `<rect width="100%" height="100%" fill="#fff"/><path fill="currentColor" d="`...)
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			if qr.Get(x, y) {
+				b = append(b, 'M')
+				b = strconv.AppendInt(b, int64(x+border), 10)
+				b = append(b, ',')
+				b = strconv.AppendInt(b, int64(y+border), 10)
+				b = append(b, "h1v1h-1z"...)
+			}
+		}
+	}
+	b = append(b, `"/></svg>`...)
+	return b
+}
+
+func (qr *QRCode) set(x, y int, dark bool) {
+	qr.modules[y*qr.Size+x] = dark
+}
+
+func (qr *QRCode) setFunc(x, y int, dark bool) {
+	qr.modules[y*qr.Size+x] = dark
+	qr.isFunc[y*qr.Size+x] = true
+}
+
+func qrNumRawDataModules(ver int) int {
+	n := (16*ver+128)*ver + 64
+	if ver >= 2 {
+		numAlign := ver/7 + 2
+		n -= (25*numAlign-10)*numAlign - 55
+		if ver >= 7 {
+			n -= 36
+		}
+	}
+	return n
+}
+
+func qrNumDataCodewords(ver int, level QRLevel) int {
+	return qrNumRawDataModules(ver)/8 - int(qrEccCodewordsPerBlock[level][ver])*int(qrNumErrorCorrectionBlocks[level][ver])
+}
+
+func qrAlignmentPositions(ver, size int) (pos []int) {
+	if ver > 1 {
+		numAlign := ver/7 + 2
+		step := 26
+		if ver != 32 {
+			step = (ver*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+		}
+		pos = make([]int, numAlign)
+		pos[0] = 6
+		for i, p := numAlign-1, size-7; i >= 1; i, p = i-1, p-step {
+			pos[i] = p
+		}
+	}
+	return
+}
+
+func (qr *QRCode) encode(data []byte, ccbits int) {
+	// build the bit stream
+	var bits []bool
+	appendBits := func(v, n int) {
+		for i := n - 1; i >= 0; i-- {
+			bits = append(bits, (v>>i)&1 != 0)
+		}
+	}
+	appendBits(0x4, 4)
+	appendBits(len(data), ccbits)
+	for _, b := range data {
+		appendBits(int(b), 8)
+	}
+	capacity := qrNumDataCodewords(qr.Version, qr.Level) * 8
+	appendBits(0, min(4, capacity-len(bits)))
+	appendBits(0, (8-len(bits)%8)%8)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		appendBits(pad, 8)
+	}
+	codewords := make([]byte, len(bits)/8)
+	for i, b := range bits {
+		if b {
+			codewords[i>>3] |= 1 << (7 - (i & 7))
+		}
+	}
+
+	qr.modules = make([]bool, qr.Size*qr.Size)
+	qr.isFunc = make([]bool, qr.Size*qr.Size)
+	qr.drawFunctionPatterns()
+	qr.drawCodewords(qr.addEccAndInterleave(codewords))
+
+	qr.Mask = 0
+	minPenalty := -1
+	for mask := 0; mask < 8; mask++ {
+		qr.applyMask(mask)
+		qr.drawFormatBits(mask)
+		if penalty := qr.penalty(); minPenalty < 0 || penalty < minPenalty {
+			qr.Mask = mask
+			minPenalty = penalty
+		}
+		qr.applyMask(mask) // XOR undoes the mask
+	}
+	qr.applyMask(qr.Mask)
+	qr.drawFormatBits(qr.Mask)
+	qr.isFunc = nil
+}
+
+func (qr *QRCode) drawFunctionPatterns() {
+	for i := 0; i < qr.Size; i++ {
+		qr.setFunc(6, i, i%2 == 0)
+		qr.setFunc(i, 6, i%2 == 0)
+	}
+	qr.drawFinderPattern(3, 3)
+	qr.drawFinderPattern(qr.Size-4, 3)
+	qr.drawFinderPattern(3, qr.Size-4)
+	pos := qrAlignmentPositions(qr.Version, qr.Size)
+	n := len(pos)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			if !(i == 0 && j == 0 || i == 0 && j == n-1 || i == n-1 && j == 0) {
+				for dy := -2; dy <= 2; dy++ {
+					for dx := -2; dx <= 2; dx++ {
+						qr.setFunc(pos[i]+dx, pos[j]+dy, max(abs(dx), abs(dy)) != 1)
+					}
+				}
+			}
+		}
+	}
+	qr.drawFormatBits(0) // reserve the area, overwritten later
+	qr.drawVersion()
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (qr *QRCode) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx >= 0 && xx < qr.Size && yy >= 0 && yy < qr.Size {
+				dist := max(abs(dx), abs(dy))
+				qr.setFunc(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+func qrFormatBits(level QRLevel, mask int) int {
+	data := qrFormatLevelBits[level]<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+func (qr *QRCode) drawFormatBits(mask int) {
+	bits := qrFormatBits(qr.Level, mask)
+	bit := func(i int) bool { return (bits>>i)&1 != 0 }
+	for i := 0; i <= 5; i++ {
+		qr.setFunc(8, i, bit(i))
+	}
+	qr.setFunc(8, 7, bit(6))
+	qr.setFunc(8, 8, bit(7))
+	qr.setFunc(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		qr.setFunc(14-i, 8, bit(i))
+	}
+	for i := 0; i < 8; i++ {
+		qr.setFunc(qr.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		qr.setFunc(8, qr.Size-15+i, bit(i))
+	}
+	qr.setFunc(8, qr.Size-8, true)
+}
+
+func (qr *QRCode) drawVersion() {
+	if qr.Version >= 7 {
+		rem := qr.Version
+		for i := 0; i < 12; i++ {
+			rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+		}
+		bits := qr.Version<<12 | rem
+		for i := 0; i < 18; i++ {
+			dark := (bits>>i)&1 != 0
+			a, b := qr.Size-11+i%3, i/3
+			qr.setFunc(a, b, dark)
+			qr.setFunc(b, a, dark)
+		}
+	}
+}
+
+func qrMultiply(x, y byte) (z byte) {
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x1D)
+		z ^= ((y >> i) & 1) * x
+	}
+	return
+}
+
+func qrReedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		for j := range result {
+			result[j] = qrMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = qrMultiply(root, 0x02)
+	}
+	return result
+}
+
+func qrReedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i := range result {
+			result[i] ^= qrMultiply(divisor[i], factor)
+		}
+	}
+	return result
+}
+
+func (qr *QRCode) addEccAndInterleave(data []byte) (result []byte) {
+	numBlocks := int(qrNumErrorCorrectionBlocks[qr.Level][qr.Version])
+	blockEccLen := int(qrEccCodewordsPerBlock[qr.Level][qr.Version])
+	rawCodewords := qrNumRawDataModules(qr.Version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := qrReedSolomonDivisor(blockEccLen)
+	blocks := make([][]byte, numBlocks)
+	for i, k := 0, 0; i < numBlocks; i++ {
+		n := shortBlockLen - blockEccLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dat := data[k : k+n : k+n]
+		k += n
+		ecc := qrReedSolomonRemainder(dat, divisor)
+		if i < numShortBlocks {
+			dat = append(dat, 0)
+		}
+		blocks[i] = append(dat, ecc...)
+	}
+	for i := range blocks[0] {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockEccLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return
+}
+
+func (qr *QRCode) drawCodewords(data []byte) {
+	i := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = qr.Size - 1 - vert
+				}
+				if !qr.isFunc[y*qr.Size+x] && i < len(data)*8 {
+					qr.set(x, y, (data[i>>3]>>(7-(i&7)))&1 != 0)
+					i++
+				}
+			}
+		}
+	}
+}
+
+func (qr *QRCode) applyMask(mask int) {
+	for y := 0; y < qr.Size; y++ {
+		for x := 0; x < qr.Size; x++ {
+			var invert bool
+			switch mask {
+			case 0:
+				invert = (x+y)%2 == 0
+			case 1:
+				invert = y%2 == 0
+			case 2:
+				invert = x%3 == 0
+			case 3:
+				invert = (x+y)%3 == 0
+			case 4:
+				invert = (x/3+y/2)%2 == 0
+			case 5:
+				invert = x*y%2+x*y%3 == 0
+			case 6:
+				invert = (x*y%2+x*y%3)%2 == 0
+			case 7:
+				invert = ((x+y)%2+x*y%3)%2 == 0
+			}
+			if invert && !qr.isFunc[y*qr.Size+x] {
+				qr.modules[y*qr.Size+x] = !qr.modules[y*qr.Size+x]
+			}
+		}
+	}
+}
+
+// penalty computes the mask penalty score as described in ISO/IEC 18004.
+func (qr *QRCode) penalty() (result int) {
+	finder := [2][11]bool{
+		{true, false, true, true, true, false, true, false, false, false, false},
+		{false, false, false, false, true, false, true, true, true, false, true},
+	}
+	get := func(x, y int, transpose bool) bool {
+		if transpose {
+			x, y = y, x
+		}
+		return qr.modules[y*qr.Size+x]
+	}
+	dark := 0
+	for _, transpose := range []bool{false, true} {
+		for y := 0; y < qr.Size; y++ {
+			runColor, runLen := false, 0
+			for x := 0; x < qr.Size; x++ {
+				c := get(x, y, transpose)
+				if !transpose && c {
+					dark++
+				}
+				if x > 0 && c == runColor {
+					runLen++
+					if runLen == 5 {
+						result += 3
+					} else if runLen > 5 {
+						result++
+					}
+				} else {
+					runColor, runLen = c, 1
+				}
+				if x+11 <= qr.Size {
+					for _, pat := range finder {
+						match := true
+						for k := 0; k < 11 && match; k++ {
+							match = get(x+k, y, transpose) == pat[k]
+						}
+						if match {
+							result += 40
+						}
+					}
+				}
+			}
+		}
+	}
+	for y := 0; y < qr.Size-1; y++ {
+		for x := 0; x < qr.Size-1; x++ {
+			c := qr.Get(x, y)
+			if c == qr.Get(x+1, y) && c == qr.Get(x, y+1) && c == qr.Get(x+1, y+1) {
+				result += 3
+			}
+		}
+	}
+	total := qr.Size * qr.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * 10
+	return
+}
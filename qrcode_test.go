@@ -0,0 +1,146 @@
+package jaws
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func Test_qrReedSolomonRemainder(t *testing.T) {
+	th := newTestHelper(t)
+	// version 1-M "HELLO WORLD" in alphanumeric mode
+	data := []byte{32, 91, 11, 120, 209, 114, 220, 77, 67, 64, 236, 17, 236, 17, 236, 17}
+	th.Equal(qrReedSolomonRemainder(data, qrReedSolomonDivisor(10)), []byte{196, 35, 39, 119, 235, 215, 231, 226, 93, 23})
+}
+
+func Test_qrFormatBits(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(qrFormatBits(QRLevelL, 4), 0b110011000101111)
+	th.Equal(qrFormatBits(QRLevelM, 0), 0b101010000010010)
+	th.Equal(qrFormatBits(QRLevelH, 7), 0b000100000111011)
+}
+
+func Test_qrAlignmentPositions(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(len(qrAlignmentPositions(1, 21)), 0)
+	th.Equal(qrAlignmentPositions(7, 45), []int{6, 22, 38})
+	th.Equal(qrAlignmentPositions(32, 145), []int{6, 34, 60, 86, 112, 138})
+	th.Equal(qrAlignmentPositions(40, 177), []int{6, 30, 58, 86, 114, 142, 170})
+}
+
+func TestNewQRCode_Capacity(t *testing.T) {
+	th := newTestHelper(t)
+	for _, tc := range []struct {
+		level   QRLevel
+		n       int
+		version int
+	}{
+		{QRLevelM, 14, 1},
+		{QRLevelM, 15, 2},
+		{QRLevelL, 17, 1},
+		{QRLevelH, 7, 1},
+		{QRLevelL, 2953, 40},
+	} {
+		qr, err := NewQRCode(bytes.Repeat([]byte("x"), tc.n), tc.level)
+		th.NoErr(err)
+		th.Equal(qr.Version, tc.version)
+		th.Equal(qr.Size, tc.version*4+17)
+	}
+	_, err := NewQRCode(make([]byte, 2954), QRLevelL)
+	th.Equal(err, ErrQRCodeTooLong)
+}
+
+// readCodewords extracts the interleaved codewords from the symbol
+// by undoing the mask and reading the modules in placement order.
+func (qr *QRCode) readCodewords() (data []byte) {
+	fn := &QRCode{Version: qr.Version, Size: qr.Size, Level: qr.Level}
+	fn.modules = make([]bool, qr.Size*qr.Size)
+	fn.isFunc = make([]bool, qr.Size*qr.Size)
+	fn.drawFunctionPatterns()
+	fn.modules = append([]bool(nil), qr.modules...)
+	fn.applyMask(qr.Mask)
+	var cur byte
+	n := 0
+	for right := qr.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < qr.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x, y := right-j, vert
+				if (right+1)&2 == 0 {
+					y = qr.Size - 1 - vert
+				}
+				if !fn.isFunc[y*qr.Size+x] {
+					cur <<= 1
+					if fn.modules[y*qr.Size+x] {
+						cur |= 1
+					}
+					if n++; n%8 == 0 {
+						data = append(data, cur)
+					}
+				}
+			}
+		}
+	}
+	return
+}
+
+func TestNewQRCode_Codewords(t *testing.T) {
+	th := newTestHelper(t)
+	for _, level := range []QRLevel{QRLevelL, QRLevelM, QRLevelQ, QRLevelH} {
+		for _, n := range []int{0, 20, 100, 300, 1000} {
+			text := []byte(strings.Repeat("https://example.com/", n/20+1)[:n])
+			qr, err := NewQRCode(text, level)
+			th.NoErr(err)
+			th.Equal(qr.Get(-1, 0), false)
+			th.Equal(qr.Get(0, 0), true)
+			th.Equal(qr.Get(8, qr.Size-8), true)
+
+			// de-interleave and verify each block
+			numBlocks := int(qrNumErrorCorrectionBlocks[level][qr.Version])
+			eccLen := int(qrEccCodewordsPerBlock[level][qr.Version])
+			raw := qrNumRawDataModules(qr.Version) / 8
+			numShort := numBlocks - raw%numBlocks
+			shortLen := raw / numBlocks
+			cw := qr.readCodewords()[:raw]
+			blocks := make([][]byte, numBlocks)
+			k := 0
+			for i := 0; i < shortLen+1; i++ {
+				for j := range blocks {
+					if i != shortLen-eccLen || j >= numShort {
+						blocks[j] = append(blocks[j], cw[k])
+						k++
+					}
+				}
+			}
+			th.Equal(k, raw)
+			var payload []byte
+			divisor := qrReedSolomonDivisor(eccLen)
+			for _, block := range blocks {
+				dat := block[:len(block)-eccLen]
+				th.Equal(qrReedSolomonRemainder(dat, divisor), block[len(block)-eccLen:])
+				payload = append(payload, dat...)
+			}
+			skip := 2
+			if qr.Version > 9 {
+				skip = 3
+			}
+			th.Equal(payload[0]>>4, byte(4))
+			got := make([]byte, n)
+			for i := range got {
+				got[i] = payload[skip+i-1]<<4 | payload[skip+i]>>4
+			}
+			th.Equal(got, text)
+		}
+	}
+}
+
+func TestQRCode_AppendSVG(t *testing.T) {
+	th := newTestHelper(t)
+	qr, err := NewQRCode([]byte("jaws"), QRLevelL)
+	th.NoErr(err)
+	svg := string(qr.AppendSVG(nil, 2))
+	th.True(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 25 25" shape-rendering="crispEdges"><rect width="100%" height="100%" fill="#fff"/><path fill="currentColor" d="M2,2h1v1h-1z`))
+	th.True(strings.HasSuffix(svg, `"/></svg>`))
+}
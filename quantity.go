@@ -0,0 +1,139 @@
+package jaws
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+)
+
+// Quantity is a physical quantity, such as 1500 W, in the base unit
+// without any SI prefix.
+type Quantity struct {
+	Value float64 // value in the base unit
+	Unit  string  // unit symbol, such as "W", "m" or "B"
+}
+
+var siPrefixes = []struct {
+	prefix string
+	exp    int
+}{
+	{"P", 15}, {"T", 12}, {"G", 9}, {"M", 6}, {"k", 3}, {"", 0},
+	{"m", -3}, {"µ", -6}, {"n", -9}, {"p", -12},
+}
+
+// Format returns the quantity scaled with the SI prefix that gives a value
+// of at least 1 and less than 1000 where possible, with the given number of
+// decimals as written in the locale described by nf, such as "1.5 kW".
+// Trailing zero decimals are omitted.
+func (q Quantity) Format(nf NumberFormat, decimals int) string {
+	v, prefix := q.Value, ""
+	if abs := math.Abs(v); abs != 0 && !math.IsInf(abs, 0) && !math.IsNaN(abs) {
+		i := len(siPrefixes) - 1
+		for j, si := range siPrefixes {
+			if abs >= math.Pow10(si.exp) {
+				i = j
+				break
+			}
+		}
+		// rounding may carry the value to 1000, as with 999.96 and one decimal
+		if i > 0 && roundFloat(abs/math.Pow10(siPrefixes[i].exp), decimals) >= 1000 {
+			i--
+		}
+		v, prefix = v/math.Pow10(siPrefixes[i].exp), siPrefixes[i].prefix
+	}
+	s := nf.FormatFloat(v, decimals)
+	if decimals > 0 {
+		s = strings.TrimSuffix(strings.TrimRight(s, "0"), nf.Decimal)
+	}
+	if q.Unit == "" && prefix == "" {
+		return s
+	}
+	return s + " " + prefix + q.Unit
+}
+
+func roundFloat(v float64, decimals int) float64 {
+	p := math.Pow10(decimals)
+	return math.Round(v*p) / p
+}
+
+// String returns the quantity formatted using DefaultNumberFormat and three decimals.
+func (q Quantity) String() string {
+	return q.Format(DefaultNumberFormat, 3)
+}
+
+// ParseQuantity parses user input in the locale described by nf, such as
+// "1,5 kW" in German, as a quantity in the given unit. The unit may be
+// omitted or have a SI prefix. It returns an error wrapping ErrInvalidNumber
+// if the input can't be parsed.
+func ParseQuantity(s string, nf NumberFormat, unit string) (q Quantity, err error) {
+	txt := strings.TrimSpace(s)
+	exp := 0
+	if cut, ok := strings.CutSuffix(txt, unit); ok && unit != "" {
+		txt = strings.TrimSpace(cut)
+		for _, si := range siPrefixes {
+			if si.prefix != "" {
+				if cut, ok = strings.CutSuffix(txt, si.prefix); ok {
+					txt, exp = strings.TrimSpace(cut), si.exp
+					break
+				}
+			}
+		}
+		if cut, ok = strings.CutSuffix(txt, "u"); ok && exp == 0 {
+			txt, exp = strings.TrimSpace(cut), -6
+		}
+	}
+	var v float64
+	if v, err = nf.ParseFloat(txt); err == nil {
+		q = Quantity{Value: v * math.Pow10(exp), Unit: unit}
+	} else {
+		err = fmt.Errorf("%w: %q", ErrInvalidNumber, s)
+	}
+	return
+}
+
+// QuantityValue holds a Quantity, and is a StringSetter that formats and
+// parses it in the locale of the Element's Request, see Request.NumberFormat.
+//
+//	power := jaws.NewQuantityValue(jaws.Quantity{Value: 1500, Unit: "W"}, 1)
+//	rw.Text(power)
+type QuantityValue struct {
+	Decimals int              // (read-only) maximum number of decimals shown
+	mu       deadlock.RWMutex // protects following
+	v        Quantity
+}
+
+func NewQuantityValue(v Quantity, decimals int) *QuantityValue {
+	return &QuantityValue{Decimals: decimals, v: v}
+}
+
+// Get returns the current value.
+func (qv *QuantityValue) Get() (v Quantity) {
+	qv.mu.RLock()
+	v = qv.v
+	qv.mu.RUnlock()
+	return
+}
+
+// Set sets the current value.
+func (qv *QuantityValue) Set(v Quantity) {
+	qv.mu.Lock()
+	qv.v = v
+	qv.mu.Unlock()
+}
+
+func (qv *QuantityValue) JawsGetString(e *Element) string {
+	return qv.Get().Format(e.Request.NumberFormat(), qv.Decimals)
+}
+
+// JawsSetString parses the input as a quantity in the current unit.
+func (qv *QuantityValue) JawsSetString(e *Element, s string) (err error) {
+	qv.mu.Lock()
+	defer qv.mu.Unlock()
+	var q Quantity
+	if q, err = ParseQuantity(s, e.Request.NumberFormat(), qv.v.Unit); err == nil {
+		qv.v = q
+	}
+	return
+}
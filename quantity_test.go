@@ -0,0 +1,81 @@
+package jaws
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestQuantity_Format(t *testing.T) {
+	th := newTestHelper(t)
+	en, de := DefaultNumberFormat, LocaleNumberFormat("de")
+	th.Equal(Quantity{1500, "W"}.String(), "1.5 kW")
+	th.Equal(Quantity{1500, "W"}.Format(de, 2), "1,5 kW")
+	th.Equal(Quantity{0, "W"}.String(), "0 W")
+	th.Equal(Quantity{12, "m"}.String(), "12 m")
+	th.Equal(Quantity{0.0042, "m"}.String(), "4.2 mm")
+	th.Equal(Quantity{-2.5e-6, "F"}.String(), "-2.5 µF")
+	th.Equal(Quantity{999.96, "W"}.Format(en, 1), "1 kW")
+	th.Equal(Quantity{999.94, "W"}.Format(en, 1), "999.9 W")
+	th.Equal(Quantity{3e18, "B"}.Format(en, 0), "3,000 PB")
+	th.Equal(Quantity{1e-15, "s"}.Format(en, 3), "0.001 ps")
+	th.Equal(Quantity{42, ""}.String(), "42")
+	th.Equal(Quantity{4200, ""}.String(), "4.2 k")
+	th.Equal(Quantity{math.Inf(1), "W"}.String(), "+Inf W")
+}
+
+func TestParseQuantity(t *testing.T) {
+	th := newTestHelper(t)
+	de := LocaleNumberFormat("de")
+	for s, want := range map[string]float64{
+		"1500":     1500,
+		"1500 W":   1500,
+		"1.5kW":    1500,
+		" 2 MW ":   2e6,
+		"3 mW":     0.003,
+		"4.7 µW":   4.7e-6,
+		"4.7 uW":   4.7e-6,
+		"-1,200 W": -1200,
+	} {
+		q, err := ParseQuantity(s, DefaultNumberFormat, "W")
+		th.NoErr(err)
+		th.True(math.Abs(q.Value-want) < 1e-12*math.Max(1, math.Abs(want)))
+		th.Equal(q.Unit, "W")
+	}
+	q, err := ParseQuantity("1,5 km", de, "m")
+	th.NoErr(err)
+	th.Equal(q, Quantity{1500, "m"})
+	q, err = ParseQuantity("5 mm", de, "m")
+	th.NoErr(err)
+	th.Equal(q, Quantity{0.005, "m"})
+
+	for _, s := range []string{"", "W", "1.5 kX", "kW", "five W"} {
+		_, err = ParseQuantity(s, DefaultNumberFormat, "W")
+		th.True(errors.Is(err, ErrInvalidNumber))
+	}
+}
+
+func TestQuantityValue(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "de")
+	rq := tj.newRequest(hr)
+	defer rq.Close()
+
+	qv := NewQuantityValue(Quantity{1500, "W"}, 1)
+	th.NoErr(rq.Text(qv))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" value="1,5 kW">`)
+
+	e := rq.getElementByJid(1)
+	th.NoErr(qv.JawsSetString(e, "2,25 MW"))
+	th.Equal(qv.Get(), Quantity{2.25e6, "W"})
+	th.Equal(qv.JawsGetString(e), "2,3 MW")
+	th.True(errors.Is(qv.JawsSetString(e, "2 kV"), ErrInvalidNumber))
+	qv.Set(Quantity{1, "W"})
+	th.Equal(qv.JawsGetString(e), "1 W")
+}
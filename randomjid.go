@@ -0,0 +1,90 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrUnknownJid is logged when Jaws.RandomJids is set and an event arrives for a
+// Jid that was issued, but that the Request doesn't have an Element for.
+// This is normal if the Element was recently removed.
+var ErrUnknownJid = errors.New("event for unknown Jid")
+
+// ErrForgedJid is logged when Jaws.RandomJids is set and an event arrives for a
+// Jid that was never issued to the Request, meaning it was probably guessed.
+var ErrForgedJid = errors.New("event for forged Jid")
+
+const jidMask = 1<<31 - 1
+
+// jidMixer is a keyed permutation of the positive 31-bit integers,
+// used to make a Request's Jids hard to guess.
+type jidMixer struct {
+	key, mul1, mul2 uint32 // mul1 and mul2 must be odd
+}
+
+func newJidMixer(random1, random2 uint64) jidMixer {
+	return jidMixer{
+		key:  uint32(random1),
+		mul1: uint32(random1>>32) | 1,
+		mul2: uint32(random2) | 1,
+	}
+}
+
+// mulInverse returns the multiplicative inverse of the odd number x modulo 2^32.
+func mulInverse(x uint32) (inv uint32) {
+	inv = x
+	for i := 0; i < 5; i++ {
+		inv *= 2 - x*inv
+	}
+	return
+}
+
+// unshift inverts y = x ^ (x >> s) for 31-bit x.
+func unshift(y uint32, s uint) (x uint32) {
+	x = y
+	for i := uint(0); i < 31; i += s {
+		x = y ^ (x >> s)
+	}
+	return
+}
+
+func (m jidMixer) mix(x uint32) uint32 {
+	x = (x ^ m.key) & jidMask
+	x = (x * m.mul1) & jidMask
+	x ^= x >> 16
+	x = (x * m.mul2) & jidMask
+	x ^= x >> 13
+	return x
+}
+
+func (m jidMixer) unmix(x uint32) uint32 {
+	x = unshift(x, 13)
+	x = (x * mulInverse(m.mul2)) & jidMask
+	x = unshift(x, 16)
+	x = (x * mulInverse(m.mul1)) & jidMask
+	return (x ^ m.key) & jidMask
+}
+
+// newRandomJidLocked returns a new Jid that is unique within the Request.
+//
+// Only the low 31 bits of the global Jid counter are mixed, so once it
+// wraps a Jid may repeat one still in use, in which case the next is tried.
+func (rq *Request) newRandomJidLocked() (jid Jid) {
+	for {
+		jid = Jid(rq.jidMix.mix(uint32(atomic.AddInt64((*int64)(&nextJid), 1))))
+		if _, taken := rq.jids[jid]; jid != 0 && !taken {
+			return
+		}
+	}
+}
+
+// unknownJidErr returns the error to log for an event for a Jid that
+// the Request doesn't have an Element for.
+func (rq *Request) unknownJidErr(jid Jid) error {
+	err := ErrForgedJid
+	if jid >= 0 && jid <= jidMask && int64(rq.jidMix.unmix(uint32(jid))) <= atomic.LoadInt64((*int64)(&nextJid)) {
+		err = ErrUnknownJid
+	}
+	return fmt.Errorf("jaws: %v: %w: %v", rq, err, jid)
+}
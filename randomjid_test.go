@@ -0,0 +1,75 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_jidMixer(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(mulInverse(3)*3, uint32(1))
+	th.Equal(mulInverse(0xdeadbeef)*0xdeadbeef, uint32(1))
+	for _, m := range []jidMixer{
+		newJidMixer(1, 1),
+		newJidMixer(0x0123456789abcdef, 0xfedcba9876543210),
+		newJidMixer(0xffffffffffffffff, 0xffffffffffffffff),
+	} {
+		seen := map[uint32]struct{}{}
+		for _, x := range []uint32{0, 1, 2, 3, 1000, 1 << 20, jidMask - 1, jidMask} {
+			y := m.mix(x)
+			th.True(y <= jidMask)
+			th.Equal(m.unmix(y), x)
+			seen[y] = struct{}{}
+		}
+		th.Equal(len(seen), 8)
+	}
+}
+
+func TestJaws_RandomJids(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.RandomJids = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	var elems []*Element
+	for i := 0; i < 3; i++ {
+		e := rq.NewElement(NewUiSpan(makeHtmlGetter("x")))
+		th.True(e.jid > 0)
+		th.Equal(rq.getElementByJid(e.jid), e)
+		elems = append(elems, e)
+	}
+	th.True(elems[1].jid != elems[0].jid+1 || elems[2].jid != elems[1].jid+1)
+
+	removed := elems[2].jid
+	rq.deleteElement(elems[2])
+	th.Equal(rq.getElementByJid(removed), nil)
+	th.Equal(len(rq.Request.jids), 2)
+
+	th.NoErr(rq.callAllEventHandlers(removed, what.Input, "x"))
+	th.True(strings.Contains(tj.log.String(), ErrUnknownJid.Error()))
+	th.Equal(strings.Contains(tj.log.String(), ErrForgedJid.Error()), false)
+
+	forged := Jid(rq.Request.jidMix.mix(jidMask))
+	th.NoErr(rq.callAllEventHandlers(forged, what.Input, "x"))
+	th.True(strings.Contains(tj.log.String(), ErrForgedJid.Error()))
+}
+
+func TestJaws_RandomJidsWrapped(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.RandomJids = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	nextJid = 0
+	e1 := rq.NewElement(NewUiSpan(makeHtmlGetter("x")))
+	nextJid = jidMask + 1
+	e2 := rq.NewElement(NewUiSpan(makeHtmlGetter("x")))
+	th.True(e1.jid != e2.jid)
+	th.Equal(rq.getElementByJid(e1.jid), e1)
+	th.Equal(rq.getElementByJid(e2.jid), e2)
+	nextJid = 0
+}
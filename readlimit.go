@@ -0,0 +1,67 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultMaxMessageSize is the MaxMessageSize used if Jaws.MaxMessageSize isn't set.
+// Pasted data and mirrored pages are sent in chunks that fit within it.
+const DefaultMaxMessageSize = 32 * 1024
+
+// ErrMessageTooLarge is the cause of a connection being closed because
+// the browser sent a message larger than Jaws.MaxMessageSize.
+var ErrMessageTooLarge = errors.New("message too large")
+
+// maxMessageSize returns the largest message accepted from a browser.
+func (jw *Jaws) maxMessageSize() int {
+	if jw.MaxMessageSize > 0 {
+		return jw.MaxMessageSize
+	}
+	return DefaultMaxMessageSize
+}
+
+// readLimits are the limits on the messages read from a browser.
+type readLimits struct {
+	maxSize int          // largest message accepted, if positive
+	limiter *rateLimiter // if not nil, limits how many messages are read per second
+}
+
+// readLimits returns the limits for a new connection from the Request's browser.
+func (rq *Request) readLimits() (rl readLimits) {
+	rl.maxSize = rq.maxMessageSize()
+	if rate := rq.maxEventRate(); rate > 0 {
+		rl.limiter = newRateLimiter(rate, 0)
+	}
+	return
+}
+
+// checkSize returns an error wrapping ErrMessageTooLarge if txt is too large.
+func (rl readLimits) checkSize(txt []byte) (err error) {
+	if rl.maxSize > 0 && len(txt) > rl.maxSize {
+		err = fmt.Errorf("jaws: %w: %d bytes, limit is %d", ErrMessageTooLarge, len(txt), rl.maxSize)
+	}
+	return
+}
+
+// wait delays reading the next message if the browser sends them faster
+// than allowed, so a flooding browser is slowed down rather than served.
+// Returns false if ctx or doneCh was done first.
+func (rl readLimits) wait(ctx context.Context, doneCh <-chan struct{}) bool {
+	if rl.limiter != nil {
+		if wait := rl.limiter.reserve(time.Now()); wait > 0 {
+			t := time.NewTimer(wait)
+			defer t.Stop()
+			select {
+			case <-ctx.Done():
+				return false
+			case <-doneCh:
+				return false
+			case <-t.C:
+			}
+		}
+	}
+	return true
+}
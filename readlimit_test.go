@@ -0,0 +1,86 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_readLimits(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+
+	rl := rq.readLimits()
+	th.Equal(rl.maxSize, DefaultMaxMessageSize)
+	th.Equal(rl.limiter, (*rateLimiter)(nil))
+	th.NoErr(rl.checkSize(make([]byte, DefaultMaxMessageSize)))
+	th.True(errors.Is(rl.checkSize(make([]byte, DefaultMaxMessageSize+1)), ErrMessageTooLarge))
+	th.True(rl.wait(context.Background(), nil))
+
+	jw.MaxMessageSize = 10
+	jw.MaxEventRate = 20
+	rl = rq.readLimits()
+	th.Equal(rl.maxSize, 10)
+	for i := 0; i < 20; i++ {
+		th.True(rl.wait(context.Background(), nil))
+	}
+	start := time.Now()
+	th.True(rl.wait(context.Background(), nil))
+	th.True(time.Since(start) >= time.Second/20/2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	th.Equal(rl.wait(ctx, nil), false)
+}
+
+func TestWS_MessageTooLarge(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	ts.jw.MaxMessageSize = 64
+
+	conn := &testWebSocketConn{inCh: make(chan []byte), outCh: make(chan []byte, 4), closedCh: make(chan struct{})}
+	tr := &testWebSocketTransport{conn: conn}
+	ts.jw.WebSocket = tr
+
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		ts.rq.ServeHTTP(httptest.NewRecorder(), newProtocolRequest(http.MethodGet, ts.Path(), nil))
+	}()
+
+	msg := wsMsg{What: what.Input, Data: strings.Repeat("x", 64)}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case conn.inCh <- msg.Append(nil):
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+	th.Equal(tr.opts.ReadLimit, 64)
+}
+
+func TestLongPoll_MessageTooLarge(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+	jw.MaxMessageSize = 64
+
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	path := PollPath + rq.JawsKeyString()
+	th.Equal(servePollTest(jw, http.MethodGet, path, "").Code, http.StatusOK)
+	msg := wsMsg{What: what.Input, Data: strings.Repeat("x", 64)}
+	th.Equal(servePollTest(jw, http.MethodPost, path, msg.Format()).Code, http.StatusRequestEntityTooLarge)
+}
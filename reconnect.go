@@ -0,0 +1,85 @@
+package jaws
+
+import (
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultReconnectDelay = time.Millisecond * 500 // Default delay before the browser first tries to reconnect
+	DefaultReconnectMax   = time.Second * 10       // Default maximum delay between the browser's reconnection attempts
+)
+
+// reconnectScript returns the Javascript setting the browser's reconnect
+// policy, or an empty string if Requests can't be reconnected to.
+func (jw *Jaws) reconnectScript() string {
+	if jw.ReconnectWindow <= 0 {
+		return ""
+	}
+	delay, maxDelay := jw.ReconnectDelay, jw.ReconnectMax
+	if delay <= 0 {
+		delay = DefaultReconnectDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultReconnectMax
+	}
+	return "var jawsRetry=[" + strconv.FormatInt(delay.Milliseconds(), 10) +
+		"," + strconv.FormatInt(maxDelay.Milliseconds(), 10) +
+		"," + strconv.FormatInt(jw.ReconnectWindow.Milliseconds(), 10) + "];"
+}
+
+// suspend keeps the Request for Jaws.ReconnectWindow after it's WebSocket
+// connection was lost, so that the browser can reconnect to it.
+// Returns false if the Request was cancelled or the Jaws is closed.
+func (rq *Request) suspend() (ok bool) {
+	select {
+	case <-rq.Jaws.Done():
+		return false
+	default:
+	}
+	rq.mu.Lock()
+	if ok = rq.resumableLocked(); ok {
+		rq.running = false
+		rq.claimed = false
+		rq.lost = time.Now()
+	}
+	rq.mu.Unlock()
+	return
+}
+
+// resumableLocked returns true if the browser may reconnect to the
+// Request once it's WebSocket connection is lost.
+func (rq *Request) resumableLocked() bool {
+	return rq.Jaws.ReconnectWindow > 0 && rq.JawsKey != 0 && rq.running && !rq.cancelled && rq.poll == nil
+}
+
+// resync updates all the Elements after the browser has reconnected,
+// since updates sent while the connection was lost are missing, and
+// then sends the messages parked while it was disconnected.
+func (rq *Request) resync(outboundCh chan string) {
+	var todo []*Element
+	rq.mu.RLock()
+	for _, elem := range rq.elems {
+		if !elem.pending && !elem.detached {
+			todo = append(todo, elem)
+		}
+	}
+	rq.mu.RUnlock()
+	sortPriorityElements(todo)
+	var wsQueue []wsMsg
+	for _, elem := range todo {
+		rq.updateElement(elem)
+	}
+	rq.mu.RLock()
+	for _, elem := range todo {
+		wsQueue = append(wsQueue, elem.wsQueue...)
+		elem.wsQueue = elem.wsQueue[:0]
+	}
+	rq.mu.RUnlock()
+	if len(wsQueue) > 0 {
+		rq.sendQueue(outboundCh, wsQueue)
+	}
+	if s := rq.session.unpark(rq.JawsKey); s != "" {
+		rq.wsSend(outboundCh, s)
+	}
+}
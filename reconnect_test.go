@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJaws_reconnectScript(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	th.Equal(jw.reconnectScript(), "")
+	jw.ReconnectWindow = time.Minute
+	th.Equal(jw.reconnectScript(), "var jawsRetry=[500,10000,60000];")
+	jw.ReconnectDelay = time.Second
+	jw.ReconnectMax = time.Second * 30
+	th.Equal(jw.reconnectScript(), "var jawsRetry=[1000,30000,60000];")
+
+	jw.LongPoll = true
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	var sb strings.Builder
+	th.NoErr(rq.HeadHTML(&sb))
+	th.True(strings.Contains(sb.String(), `";var jawsRetry=[1000,30000,60000];var jawsPoll=true;</script>`))
+}
+
+func TestRequest_maintenance_Suspended(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.ReconnectWindow = time.Minute
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/", nil))
+	rq.mu.Lock()
+	rq.lost = time.Now()
+	rq.mu.Unlock()
+	th.Equal(rq.maintenance(time.Now()), false)
+	rq.mu.Lock()
+	rq.lost = time.Now().Add(-2 * time.Minute)
+	rq.mu.Unlock()
+	th.Equal(rq.maintenance(time.Now()), true)
+}
@@ -0,0 +1,65 @@
+package jaws
+
+import (
+	"slices"
+	"strings"
+)
+
+// RedactedText replaces sensitive event values in logs and error reports.
+const RedactedText = "[redacted]"
+
+// Redact is a parameter that marks the Element's values as sensitive, so
+// they are replaced with RedactedText in logs and error reports.
+type Redact struct{}
+
+// RedactPolicy selects Elements whose values are sensitive, in addition to
+// those marked with the Redact parameter and password inputs, which are
+// always redacted.
+type RedactPolicy struct {
+	InputTypes []string      // HTML input types, such as "email" or "tel"
+	Tags       []interface{} // Elements having any of these tags
+	Attrs      []string      // attributes, either a name or a name with value, such as `autocomplete="cc-number"`
+}
+
+// matchAttrs returns true if any of attrs matches the policy Attrs.
+func (p *RedactPolicy) matchAttrs(attrs []string) bool {
+	for _, want := range p.Attrs {
+		for _, attr := range attrs {
+			if attr == want || (!strings.ContainsRune(want, '=') && strings.HasPrefix(attr, want+"=")) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// redactInput marks the Element as sensitive if the HTML input type is
+// password or selected by the Jaws RedactPolicy.
+func (e *Element) redactInput(htmltype string) {
+	if htmltype == "password" || slices.Contains(e.Request.Jaws.Redaction.InputTypes, htmltype) {
+		e.redact = true
+	}
+}
+
+// isRedacted returns true if the Element's values are sensitive.
+func (e *Element) isRedacted() bool {
+	if e.redact {
+		return true
+	}
+	for _, tag := range e.Request.Jaws.Redaction.Tags {
+		if e.HasTag(tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue returns RedactedText if any of the Elements has sensitive values, otherwise val.
+func redactValue(val string, elems ...*Element) string {
+	for _, e := range elems {
+		if e != nil && e.isRedacted() {
+			return RedactedText
+		}
+	}
+	return val
+}
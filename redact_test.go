@@ -0,0 +1,64 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRedactPolicy_matchAttrs(t *testing.T) {
+	th := newTestHelper(t)
+	p := RedactPolicy{Attrs: []string{"data-secret", `autocomplete="cc-number"`}}
+	th.True(p.matchAttrs([]string{"data-secret"}))
+	th.True(p.matchAttrs([]string{`class="x"`, `data-secret="1"`}))
+	th.True(p.matchAttrs([]string{`autocomplete="cc-number"`}))
+	th.Equal(p.matchAttrs([]string{`autocomplete="email"`}), false)
+	th.Equal(p.matchAttrs([]string{"data-secretive"}), false)
+	th.Equal(p.matchAttrs(nil), false)
+}
+
+func TestJaws_Redaction(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	tj.StrictEvents = true
+	tj.Redaction = RedactPolicy{
+		InputTypes: []string{"number"},
+		Tags:       []interface{}{Tag("secret")},
+		Attrs:      []string{"data-secret"},
+	}
+	var reports []*ErrorReport
+	tj.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reports = append(reports, report) })
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	errBoom := errors.New("boom")
+	newSetter := func() *testSetter[string] {
+		tss := newTestSetter("")
+		tss.err = errBoom
+		return tss
+	}
+	th.NoErr(rq.Text(newSetter()))
+	th.NoErr(rq.Password(newSetter()))
+	th.NoErr(rq.Text(newSetter(), Redact{}))
+	th.NoErr(rq.Text(newSetter(), Tag("secret")))
+	th.NoErr(rq.Text(newSetter(), "data-secret"))
+	th.NoErr(rq.Textarea(newSetter(), "data-secret"))
+	th.NoErr(rq.Number(newTestSetter(float64(0))))
+
+	for i := 1; i <= 6; i++ {
+		th.True(errors.Is(rq.callAllEventHandlers(Jid(i), what.Input, "hunter2"), errBoom))
+	}
+	th.Equal(len(reports), 6)
+	th.Equal(reports[0].Value, "hunter2")
+	for _, report := range reports[1:] {
+		th.Equal(report.Value, RedactedText)
+	}
+	th.True(rq.getElementByJid(7).isRedacted())
+
+	th.NoErr(rq.callAllEventHandlers(2, what.Click, "hunter2"))
+	th.True(strings.Contains(tj.log.String(), `Click "`+RedactedText+`"`))
+	th.Equal(strings.Contains(tj.log.String(), "hunter2"), false)
+}
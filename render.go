@@ -0,0 +1,117 @@
+package jaws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// ErrRenderTimeout is logged and reported to the Jaws ErrorReporter when
+// rendering an Element takes longer than Jaws.RenderTimeout.
+var ErrRenderTimeout = errors.New("render timeout")
+
+// DefaultRenderFallback is the default value of Jaws.RenderFallback.
+const DefaultRenderFallback = template.HTML(`<span class="jaws-render-timeout"></span>`)
+
+// renderContext returns the Context to render Elements with. Until the
+// WebSocket connects, it is the Context of the initial HTTP request, so
+// that rendering stops if the page load is aborted.
+func (rq *Request) renderContext() (ctx context.Context) {
+	rq.mu.RLock()
+	ctx = rq.ctx
+	if !rq.claimed && rq.Initial != nil {
+		ctx = rq.Initial.Context()
+	}
+	rq.mu.RUnlock()
+	return
+}
+
+func (rq *Request) setElementContext(elem *Element, ctx context.Context) {
+	rq.mu.Lock()
+	elem.ctx = ctx
+	rq.mu.Unlock()
+}
+
+type renderResult struct {
+	err error
+	x   interface{} // value recovered from a panic
+}
+
+// renderElement calls the Element's JawsRender with Element.Context()
+// returning the render Context. If a render timeout is set, JawsRender
+// runs in a new goroutine, and if it doesn't finish in time the
+// RenderFallback is written instead.
+func (rq *Request) renderElement(elem *Element, w io.Writer, params []interface{}) (err error) {
+	parent := rq.renderContext()
+	if err = parent.Err(); err != nil {
+		return
+	}
+	if rq.Jaws.ProfileRender {
+		defer rq.recordRender(elem, false, time.Now())
+	}
+	d := rq.renderTimeout()
+	if d <= 0 {
+		rq.setElementContext(elem, parent)
+		defer rq.setElementContext(elem, nil)
+		return elem.ui.JawsRender(elem, w, params)
+	}
+
+	ctx, cancel := context.WithTimeout(parent, d)
+	rq.setElementContext(elem, ctx)
+	buf := getRenderBuf()
+	doneCh := make(chan renderResult, 1)
+	go func() {
+		var res renderResult
+		defer func() {
+			res.x = recover()
+			doneCh <- res
+		}()
+		res.err = elem.ui.JawsRender(elem, buf, params)
+	}()
+
+	select {
+	case res := <-doneCh:
+		cancel()
+		rq.setElementContext(elem, nil)
+		if res.x != nil {
+			panic(res.x)
+		}
+		if err = res.err; err == nil {
+			return writeRenderBuf(w, buf)
+		}
+		putRenderBuf(buf)
+		return
+	case <-ctx.Done():
+	}
+
+	// the browser never sees the Element, so keep it from being updated
+	// while JawsRender finishes, and then delete it
+	rq.mu.Lock()
+	elem.detached = true
+	rq.mu.Unlock()
+	desc := elem.String()
+	go rq.abandonRender(elem, desc, buf, doneCh, cancel)
+
+	if err = parent.Err(); err == nil {
+		err = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w after %v", desc, ErrRenderTimeout, d))
+		rq.reportError(elem, what.Update, "", err, nil, nil)
+		_, err = w.Write([]byte(rq.Jaws.RenderFallback))
+	}
+	return
+}
+
+func (rq *Request) abandonRender(elem *Element, desc string, buf *bytes.Buffer, doneCh <-chan renderResult, cancel context.CancelFunc) {
+	res := <-doneCh
+	cancel()
+	putRenderBuf(buf)
+	if res.x != nil {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: render panic: %v", desc, res.x))
+	}
+	rq.deleteElement(elem)
+}
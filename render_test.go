@@ -0,0 +1,139 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testRenderUI struct {
+	UiSpan
+	gateCh chan struct{}
+	ctxCh  chan context.Context
+}
+
+func (ui *testRenderUI) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ctx := e.Context()
+	if ui.ctxCh != nil {
+		ui.ctxCh <- ctx
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-ui.gateCh:
+	}
+	return ui.UiSpan.JawsRender(e, w, params)
+}
+
+func TestRequest_RenderTimeout(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	reportCh := make(chan *ErrorReport, 1)
+	rq.Jaws.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reportCh <- report })
+	rq.Jaws.RenderTimeout = time.Millisecond * 10
+	th.Equal(rq.Jaws.RenderFallback, DefaultRenderFallback)
+
+	fast := &testRenderUI{UiSpan: *NewUiSpan(makeHtmlGetter("fast")), gateCh: make(chan struct{})}
+	close(fast.gateCh)
+	th.NoErr(rq.UI(fast))
+	th.Equal(rq.BodyString(), `<span id="Jid.1">fast</span>`)
+	th.Equal(rq.getElementByJid(1).Context(), rq.Request.Context())
+
+	slow := &testRenderUI{UiSpan: *NewUiSpan(makeHtmlGetter("slow")), gateCh: make(chan struct{}), ctxCh: make(chan context.Context, 1)}
+	th.NoErr(rq.UI(slow, "hidden"))
+	th.Equal(rq.BodyString(), `<span id="Jid.1">fast</span>`+string(DefaultRenderFallback))
+	ctx := <-slow.ctxCh
+	th.True(errors.Is(ctx.Err(), context.DeadlineExceeded))
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case report := <-reportCh:
+		th.True(errors.Is(report, ErrRenderTimeout))
+		th.Equal(report.Element.Ui(), UI(slow))
+	}
+	th.True(strings.Contains(rq.jw.log.String(), ErrRenderTimeout.Error()))
+
+	for rq.getElementByJid(2) != nil {
+		select {
+		case <-th.C:
+			th.Timeout()
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
+func TestRequest_RenderTimeoutPanic(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	rq.Jaws.RenderTimeout = time.Minute
+
+	defer func() {
+		th.Equal(recover(), "boom")
+	}()
+	_ = rq.UI(&testRenderPanicUI{})
+	th.Fatal("expected panic")
+}
+
+type testRenderPanicUI struct{ UiSpan }
+
+func (*testRenderPanicUI) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	panic("boom")
+}
+
+func TestRequest_RenderAborted(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	nextJid = 0
+
+	ctx, cancel := context.WithCancel(context.Background())
+	hr := httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx)
+	rq := jw.NewRequestWithOptions(hr, &RequestOptions{RenderTimeout: time.Minute})
+	th.Equal(rq.renderTimeout(), time.Minute)
+
+	ui := &testRenderUI{UiSpan: *NewUiSpan(makeHtmlGetter("x")), gateCh: make(chan struct{}), ctxCh: make(chan context.Context, 1)}
+	var sb strings.Builder
+	errCh := make(chan error, 1)
+	go func() { errCh <- rq.NewElement(ui).Render(&sb, nil) }()
+	renderCtx := <-ui.ctxCh
+	_, hasDeadline := renderCtx.Deadline()
+	th.True(hasDeadline)
+	cancel()
+	th.Equal(<-errCh, context.Canceled)
+	th.Equal(sb.String(), "")
+
+	th.Equal(rq.NewElement(ui).Render(&sb, nil), context.Canceled)
+}
+
+func BenchmarkRequest_RenderTimeout(b *testing.B) {
+	jw := New()
+	defer jw.Close()
+	jw.RenderTimeout = time.Minute
+	rq := jw.NewRequest(nil)
+	defer jw.recycle(rq)
+	rw := rq.Writer(io.Discard)
+	inner := template.HTML(strings.Repeat("<p>paragraph</p>", 64))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%1000 == 0 {
+			rq.mu.Lock()
+			rq.elems = rq.elems[:0]
+			clear(rq.tagMap)
+			rq.mu.Unlock()
+		}
+		_ = rw.Div(inner)
+	}
+}
@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RenderStats is the time spent rendering and updating the Elements of a
+// UI type having a tag, as returned by Jaws.RenderStats.
+type RenderStats struct {
+	Type       string        // the UI type, like "*jaws.UiSpan"
+	Tag        interface{}   // the tag, nil for Elements without tags
+	Renders    uint64        // calls to JawsRender
+	RenderTime time.Duration // time spent in JawsRender
+	Updates    uint64        // calls to JawsUpdate
+	UpdateTime time.Duration // time spent in JawsUpdate
+}
+
+// Total returns the time spent in both JawsRender and JawsUpdate.
+func (rs RenderStats) Total() time.Duration {
+	return rs.RenderTime + rs.UpdateTime
+}
+
+// String returns the statistics in a form suitable for debug output.
+func (rs RenderStats) String() string {
+	return fmt.Sprintf("%s %s: renders %d in %v, updates %d in %v",
+		rs.Type, TagString(rs.Tag), rs.Renders, rs.RenderTime, rs.Updates, rs.UpdateTime)
+}
+
+type profKey struct {
+	typ string
+	tag interface{}
+}
+
+// profTotal is the tag of the totals for a UI type.
+type profTotal struct{}
+
+// RenderStats returns the time spent rendering and updating Elements while
+// ProfileRender is set, for each UI type and tag, the slowest first.
+// An Element with several tags counts towards each of them.
+//
+// Entries are kept until ResetRenderStats is called, so don't leave
+// ProfileRender set if the application creates many short-lived tags.
+func (jw *Jaws) RenderStats() []RenderStats {
+	return jw.renderStatsList(false)
+}
+
+// RenderTypeStats returns the time spent rendering and updating Elements
+// while ProfileRender is set, for each UI type regardless of tags, the
+// slowest first. The Tag of the entries is nil.
+func (jw *Jaws) RenderTypeStats() []RenderStats {
+	return jw.renderStatsList(true)
+}
+
+func (jw *Jaws) renderStatsList(totals bool) (stats []RenderStats) {
+	jw.mu.RLock()
+	for k, rs := range jw.renderStats {
+		if _, isTotal := k.tag.(profTotal); isTotal == totals {
+			stats = append(stats, *rs)
+		}
+	}
+	jw.mu.RUnlock()
+	slices.SortFunc(stats, func(a, b RenderStats) int {
+		if c := cmp.Compare(b.Total(), a.Total()); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Type, b.Type); c != 0 {
+			return c
+		}
+		return strings.Compare(TagString(a.Tag), TagString(b.Tag))
+	})
+	return
+}
+
+// ResetRenderStats forgets the time recorded rendering and updating Elements.
+func (jw *Jaws) ResetRenderStats() {
+	jw.mu.Lock()
+	jw.renderStats = nil
+	jw.mu.Unlock()
+}
+
+// recordRender adds the time since start to the statistics of the
+// Element's UI type and tags. If update is false, it was spent rendering.
+func (rq *Request) recordRender(elem *Element, update bool, start time.Time) {
+	elapsed := time.Since(start)
+	typ := fmt.Sprintf("%T", elem.Ui())
+	tags := rq.TagsOf(elem)
+	if len(tags) == 0 {
+		tags = append(tags, nil)
+	}
+	tags = append(tags, profTotal{})
+	jw := rq.Jaws
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	if jw.renderStats == nil {
+		jw.renderStats = make(map[profKey]*RenderStats)
+	}
+	for _, tag := range tags {
+		k := profKey{typ: typ, tag: tag}
+		rs := jw.renderStats[k]
+		if rs == nil {
+			rs = &RenderStats{Type: typ, Tag: tag}
+			if _, isTotal := tag.(profTotal); isTotal {
+				rs.Tag = nil
+			}
+			jw.renderStats[k] = rs
+		}
+		if update {
+			rs.Updates++
+			rs.UpdateTime += elapsed
+		} else {
+			rs.Renders++
+			rs.RenderTime += elapsed
+		}
+	}
+}
+
+// updateElement calls the Element's JawsUpdate, recording the time it
+// takes if ProfileRender is set.
+func (rq *Request) updateElement(elem *Element) {
+	if rq.Jaws.ProfileRender {
+		defer rq.recordRender(elem, true, time.Now())
+	}
+	elem.Ui().JawsUpdate(elem)
+}
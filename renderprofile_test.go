@@ -0,0 +1,62 @@
+package jaws
+
+import (
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJaws_RenderStats(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+
+	slow := &testUi{
+		renderFn: func(e *Element, w io.Writer, params []any) error {
+			time.Sleep(time.Millisecond * 5)
+			return nil
+		},
+		updateFn: func(e *Element) {
+			time.Sleep(time.Millisecond * 5)
+		},
+	}
+	var sb strings.Builder
+	th.NoErr(rq.JawsRender(rq.NewElement(slow), &sb, nil))
+	th.Equal(len(jw.RenderStats()), 0)
+
+	jw.ProfileRender = true
+	elem := rq.NewElement(slow)
+	elem.Tag(Tag("slow"))
+	th.NoErr(rq.JawsRender(elem, &sb, nil))
+	rq.updateElement(elem)
+	th.NoErr(rq.JawsRender(rq.NewElement(NewUiSpan(makeHtmlGetter("fast"))), &sb, nil))
+
+	stats := jw.RenderStats()
+	th.Equal(len(stats), 3) // testUi is tagged with itself and "slow"
+	for _, rs := range stats[:2] {
+		th.Equal(rs.Type, "*jaws.testUi")
+		th.Equal(rs.Renders, uint64(1))
+		th.Equal(rs.Updates, uint64(1))
+		th.True(rs.RenderTime >= time.Millisecond*5)
+		th.True(rs.Total() >= time.Millisecond*10)
+	}
+	th.True(stats[0].Tag == Tag("slow") || stats[1].Tag == Tag("slow"))
+	th.Equal(stats[2].Type, "*jaws.UiSpan")
+	th.Equal(stats[2].Tag, nil)
+	th.Equal(stats[2].Updates, uint64(0))
+	th.True(strings.HasPrefix(stats[2].String(), "*jaws.UiSpan <nil>: renders 1 in "))
+
+	types := jw.RenderTypeStats()
+	th.Equal(len(types), 2)
+	th.Equal(types[0].Type, "*jaws.testUi")
+	th.Equal(types[0].Tag, nil)
+	th.Equal(types[0].Renders, uint64(1))
+	th.Equal(types[0].Total(), stats[0].Total())
+	th.Equal(types[1].Type, "*jaws.UiSpan")
+
+	jw.ResetRenderStats()
+	th.Equal(len(jw.RenderStats()), 0)
+	th.Equal(len(jw.RenderTypeStats()), 0)
+}
@@ -29,21 +29,50 @@ type ConnectFn = func(rq *Request) error
 // Note that we have to store the context inside the struct because there is no call chain
 // between the Request being created and it being used once the WebSocket is created.
 type Request struct {
-	Jaws      *Jaws                   // (read-only) the JaWS instance the Request belongs to
-	JawsKey   uint64                  // (read-only) a random number used in the WebSocket URI to identify this Request
-	Created   time.Time               // (read-only) when the Request was created, used for automatic cleanup
-	Initial   *http.Request           // (read-only) initial HTTP request passed to Jaws.NewRequest
-	remoteIP  netip.Addr              // (read-only) remote IP, or nil
-	session   *Session                // (read-only) session, if established
-	mu        deadlock.RWMutex        // protects following
-	claimed   bool                    // if UseRequest() has been called for it
-	running   bool                    // if ServeHTTP() is running
-	todoDirt  []interface{}           // dirty tags
-	ctx       context.Context         // current context, derived from either Jaws or WS HTTP req
-	cancelFn  context.CancelCauseFunc // cancel function
-	connectFn ConnectFn               // a ConnectFn to call before starting message processing for the Request
-	elems     []*Element
-	tagMap    map[interface{}][]*Element
+	Jaws         *Jaws                   // (read-only) the JaWS instance the Request belongs to
+	JawsKey      uint64                  // (read-only) a random number used in the WebSocket URI to identify this Request
+	Created      time.Time               // (read-only) when the Request was created, used for automatic cleanup
+	Initial      *http.Request           // (read-only) initial HTTP request passed to Jaws.NewRequest
+	remoteIP     netip.Addr              // (read-only) remote IP, or nil
+	session      *Session                // (read-only) session, if established
+	assetVersion string                  // (read-only) Jaws asset version when the Request was created
+	mu           deadlock.RWMutex        // protects following
+	claimed      bool                    // if UseRequest() has been called for it
+	running      bool                    // if ServeHTTP() is running
+	poll         *poller                 // long-polling transport state, if polling
+	cancelled    bool                    // if cancelled with an error, so it can't be reconnected to
+	lost         time.Time               // when the WebSocket was lost, if waiting for the browser to reconnect
+	compress     Compression             // compression negotiated for the WebSocket connection
+	wsCount      *wsCounters             // traffic of the WebSocket connection
+	parkStop     chan struct{}           // closed to stop parking broadcasts, see Jaws.ParkWindow
+	parkDone     chan struct{}           // closed when parking broadcasts has stopped
+	inert        bool                    // if the browser tab can't be used because of Jaws.Tabs or Mirror
+	viewers      []*Request              // Requests that may be mirroring this one, see Mirror
+	mirrorOf     uint64                  // JawsKey of the Request being mirrored, if a viewer
+	mirrorSnap   strings.Builder         // page HTML being received from the browser, see Mirror
+	mirrorNonce  string                  // nonce of the page HTML asked for, see Mirror
+	pastes       pasteBuffers            // incomplete pasted data, see Element.pasteChunk
+	pasteBytes   int                     // total size of the incomplete pasted data
+	todoDirt     []interface{}           // dirty tags
+	ctx          context.Context         // current context, derived from either Jaws or WS HTTP req
+	cancelFn     context.CancelCauseFunc // cancel function
+	connectFn    ConnectFn               // a ConnectFn to call before starting message processing for the Request
+	awake        bool                    // if the browser reports holding a screen wake lock
+	lastInput    time.Time               // when the last input event was received from the browser
+	lastSeen     time.Time               // when the browser was last heard from
+	prefs        UserPrefs               // user preferences reported by the browser
+	alerts       []AlertEntry            // alert history, oldest first
+	opts         *RequestOptions         // overrides of Jaws settings, may be nil
+	once         onceKeys                // idempotency keys used, if there is no session
+	elems        []*Element
+	jids         map[Jid]*Element // Elements by Jid, if Jaws.RandomJids is set
+	jidMix       jidMixer         // makes Jids random, if Jaws.RandomJids is set
+	tagMap       map[interface{}][]*Element
+	outBacklog   []string      // outbound messages waiting for room, see OverflowGrow
+	evtBacklog   []eventFnCall // events waiting for room, see OverflowGrow
+	outbound     chan string   // outbound message queue while running, see SendStats
+	sendCount    sendCounters  // backpressure counters, see SendStats
+	flow         *flowControl  // WebSocket credits granted by the browser, see Jaws.FlowCredits
 }
 
 type eventFnCall struct {
@@ -109,12 +138,40 @@ func (rq *Request) clearLocked() *Request {
 	rq.connectFn = nil
 	rq.Created = time.Time{}
 	rq.Initial = nil
+	rq.assetVersion = ""
 	rq.claimed = false
 	rq.running = false
+	rq.poll = nil
+	rq.cancelled = false
+	rq.lost = time.Time{}
+	rq.compress = Compression{}
+	rq.wsCount = nil
+	rq.stopParkingLocked()
+	rq.inert = false
+	rq.outBacklog = nil
+	rq.evtBacklog = nil
+	rq.outbound = nil
+	rq.sendCount.reset()
+	rq.flow = nil
+	rq.viewers = nil
+	rq.mirrorOf = 0
+	rq.mirrorSnap.Reset()
+	rq.mirrorNonce = ""
+	rq.pastes = nil
+	rq.pasteBytes = 0
+	rq.awake = false
+	rq.lastInput = time.Time{}
+	rq.lastSeen = time.Time{}
+	rq.prefs = UserPrefs{}
+	rq.alerts = rq.alerts[:0]
+	rq.opts = nil
+	rq.once = onceKeys{}
 	rq.ctx, rq.cancelFn = context.WithCancelCause(context.Background())
 	rq.todoDirt = rq.todoDirt[:0]
 	rq.remoteIP = netip.Addr{}
 	rq.elems = rq.elems[:0]
+	rq.jids = nil
+	rq.jidMix = jidMixer{}
 	rq.killSessionLocked()
 	clear(rq.tagMap)
 	return rq
@@ -124,7 +181,14 @@ func (rq *Request) clearLocked() *Request {
 func (rq *Request) HeadHTML(w io.Writer) (err error) {
 	if _, err = w.Write([]byte(rq.Jaws.headPrefix)); err == nil {
 		if _, err = w.Write([]byte(rq.JawsKeyString())); err == nil {
-			_, err = w.Write([]byte(`";</script><noscript><div class="jaws-alert">This site requires Javascript for full functionality.</div></noscript>`))
+			script := `";` + rq.Jaws.reconnectScript()
+			if rq.Jaws.LongPoll {
+				script += `var jawsPoll=true;`
+			}
+			script += `</script>`
+			if _, err = w.Write([]byte(script)); err == nil {
+				_, err = w.Write([]byte(`<noscript><div class="jaws-alert">This site requires Javascript for full functionality.</div></noscript>`))
+			}
 		}
 	}
 	return
@@ -173,9 +237,13 @@ func (rq *Request) Context() (ctx context.Context) {
 }
 
 func (rq *Request) maintenance(deadline time.Time) bool {
-	rq.mu.RLock()
-	defer rq.mu.RUnlock()
+	rq.mu.Lock()
+	defer rq.mu.Unlock()
+	rq.expirePastesLocked(time.Now())
 	if !rq.running {
+		if !rq.lost.IsZero() {
+			return time.Since(rq.lost) > rq.Jaws.ReconnectWindow
+		}
 		if rq.ctx.Err() != nil {
 			return true
 		}
@@ -192,6 +260,7 @@ func (rq *Request) cancelLocked(err error) {
 		if !rq.running {
 			err = newErrPendingCancelled(rq, err)
 		}
+		rq.cancelled = err != nil
 		rq.cancelFn(rq.Jaws.Log(err))
 	}
 }
@@ -206,7 +275,13 @@ func (rq *Request) cancel(err error) {
 // The lvl argument should be one of Bootstraps alert levels: primary, secondary, success, danger, warning, info, light or dark.
 //
 // The default JaWS javascript only supports Bootstrap.js dismissable alerts.
+//
+// The alert is added to the Request's alert history, and is not shown if an
+// identical alert was shown within Jaws.AlertDedup.
 func (rq *Request) Alert(lvl, msg string) {
+	if !rq.recordAlert(lvl, msg) {
+		return
+	}
 	rq.Jaws.Broadcast(Message{
 		Dest: rq,
 		What: what.Alert,
@@ -214,6 +289,20 @@ func (rq *Request) Alert(lvl, msg string) {
 	})
 }
 
+// Announce sends text to be read out by screen readers using a visually
+// hidden aria-live region. The politeness should be "polite" to wait until
+// the user is idle, or "assertive" to interrupt. The text is not HTML.
+func (rq *Request) Announce(politeness, text string) {
+	if politeness != "assertive" {
+		politeness = "polite"
+	}
+	rq.Jaws.Broadcast(Message{
+		Dest: rq,
+		What: what.Announce,
+		Data: politeness + "\n" + text,
+	})
+}
+
 // AlertError calls Alert if the given error is not nil.
 func (rq *Request) AlertError(err error) {
 	if rq.Jaws.Log(err) != nil {
@@ -230,6 +319,55 @@ func (rq *Request) Redirect(url string) {
 	})
 }
 
+// Print requests the current Request to open the browser print dialog.
+func (rq *Request) Print() {
+	rq.Jaws.Broadcast(Message{
+		Dest: rq,
+		What: what.Print,
+	})
+}
+
+// KeepAwake requests the browser to acquire (or release) a screen wake lock,
+// preventing the screen from dimming or locking while the page is visible.
+// The lock is automatically reacquired when the page becomes visible again.
+//
+// Use IsAwake() to see if the browser has acquired the lock.
+func (rq *Request) KeepAwake(keep bool) {
+	rq.Jaws.Broadcast(Message{
+		Dest: rq,
+		What: what.WakeLock,
+		Data: strconv.FormatBool(keep),
+	})
+}
+
+// IsAwake returns true if the browser has reported holding a screen wake lock.
+func (rq *Request) IsAwake() (yes bool) {
+	rq.mu.RLock()
+	yes = rq.awake
+	rq.mu.RUnlock()
+	return
+}
+
+// LastInput returns when the last input event (such as a click) was received
+// from the browser, or the zero time if none has been received.
+func (rq *Request) LastInput() (when time.Time) {
+	rq.mu.RLock()
+	when = rq.lastInput
+	rq.mu.RUnlock()
+	return
+}
+
+func (rq *Request) handleWakeLock(data string) {
+	awake, err := strconv.ParseBool(data)
+	if err != nil {
+		err = errors.New("wake lock: " + data)
+	}
+	rq.mu.Lock()
+	rq.awake = awake
+	rq.mu.Unlock()
+	_ = rq.Jaws.Log(err)
+}
+
 func (rq *Request) TagsOf(elem *Element) (tags []interface{}) {
 	if elem != nil {
 		rq.mu.RLock()
@@ -285,6 +423,10 @@ func (rq *Request) wantMessage(msg *Message) (yes bool) {
 	switch dest := msg.Dest.(type) {
 	case *Request:
 		yes = dest == rq
+	case *Element:
+		rq.mu.RLock()
+		yes = dest.Request == rq
+		rq.mu.RUnlock()
 	case string: // HTML id
 		yes = true
 	case []any: // more than one tag
@@ -301,10 +443,15 @@ var nextJid Jid
 
 func (rq *Request) newElementLocked(ui UI) (elem *Element) {
 	elem = &Element{
-		jid:     Jid(atomic.AddInt64((*int64)(&nextJid), 1)),
 		ui:      ui,
 		Request: rq,
 	}
+	if rq.jids != nil {
+		elem.jid = rq.newRandomJidLocked()
+		rq.jids[elem.jid] = elem
+	} else {
+		elem.jid = Jid(atomic.AddInt64((*int64)(&nextJid), 1))
+	}
 	rq.elems = append(rq.elems, elem)
 	return
 }
@@ -316,6 +463,9 @@ func (rq *Request) NewElement(ui UI) *Element {
 }
 
 func (rq *Request) getElementByJidLocked(jid Jid) (elem *Element) {
+	if rq.jids != nil {
+		return rq.jids[jid]
+	}
 	for _, e := range rq.elems {
 		if e.jid == jid {
 			elem = e
@@ -361,6 +511,9 @@ func (rq *Request) tagExpanded(elem *Element, expandedtags []interface{}) {
 	for _, tag := range expandedtags {
 		if !rq.hasTagLocked(elem, tag) {
 			rq.tagMap[tag] = append(rq.tagMap[tag], elem)
+			if rq.Jaws.isPriority(tag) {
+				elem.priority = true
+			}
 		}
 	}
 }
@@ -383,7 +536,7 @@ func (rq *Request) GetElements(tagitem interface{}) (elems []*Element) {
 			for _, e := range el {
 				if _, ok = seen[e]; !ok {
 					seen[e] = struct{}{}
-					elems = append(elems, el...)
+					elems = append(elems, e)
 				}
 			}
 		}
@@ -400,17 +553,26 @@ func (rq *Request) Done() (ch <-chan struct{}) {
 }
 
 // process is the main message processing loop. Will unsubscribe broadcastMsgCh and close outboundMsgCh on exit.
-func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsMsg, outboundCh chan<- string) {
+func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsMsg, outboundCh chan string) {
 	jawsDoneCh := rq.Jaws.Done()
 	ctxDoneCh := rq.Done()
 	eventDoneCh := make(chan struct{})
 	eventCallCh := make(chan eventFnCall, cap(outboundCh))
-	go rq.eventCaller(eventCallCh, outboundCh, eventDoneCh)
+	events := rq.newEventRunner(eventCallCh, outboundCh, eventDoneCh)
+	if events == nil {
+		go rq.eventCaller(eventCallCh, outboundCh, eventDoneCh)
+	}
 
 	defer func() {
 		rq.Jaws.unsubscribe(broadcastMsgCh)
-		rq.killSession()
+		rq.mu.Lock()
+		if !rq.resumableLocked() {
+			rq.killSessionLocked()
+		}
+		rq.mu.Unlock()
+		rq.evtBacklog = nil
 		close(eventCallCh)
+		events.close()
 		for {
 			select {
 			case <-eventCallCh:
@@ -431,6 +593,7 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 	}()
 
 	var wsQueue []wsMsg
+	var backlog []Message
 
 	for {
 		var tagmsg Message
@@ -445,15 +608,20 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 		// for identified elements. this queues up wsMsg
 		// in rq.wsQueue.
 		for _, elem := range rq.makeUpdateList() {
-			elem.Ui().JawsUpdate(elem)
+			rq.updateElement(elem)
 		}
 
 		// append pending WS messages to the queue
-		// in the order of Element creation
+		// in the order of Element creation,
+		// high priority Elements first
 		rq.mu.RLock()
-		for _, elem := range rq.elems {
-			wsQueue = append(wsQueue, elem.wsQueue...)
-			elem.wsQueue = elem.wsQueue[:0]
+		for _, priority := range [...]bool{true, false} {
+			for _, elem := range rq.elems {
+				if elem.priority == priority {
+					wsQueue = append(wsQueue, elem.wsQueue...)
+					elem.wsQueue = elem.wsQueue[:0]
+				}
+			}
 		}
 		rq.mu.RUnlock()
 
@@ -461,22 +629,70 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 			wsQueue = rq.sendQueue(outboundCh, wsQueue)
 		}
 
-		select {
-		case <-jawsDoneCh:
-		case <-ctxDoneCh:
-		case tagmsg, ok = <-broadcastMsgCh:
-		case wsmsg, ok = <-incomingMsgCh:
-			if ok {
-				// incoming event message from the websocket
-				if wsmsg.Jid.IsValid() {
-					switch wsmsg.What {
-					case what.Input, what.Click:
-						rq.queueEvent(eventCallCh, eventFnCall{jid: wsmsg.Jid, wht: wsmsg.What, data: wsmsg.Data})
-					case what.Remove:
-						rq.handleRemove(wsmsg.Data)
+		if len(backlog) > 0 {
+			// congested, deliver messages from the prioritized backlog first
+			tagmsg, backlog, ok = backlog[0], backlog[1:], true
+		} else {
+			outCh, nextOut := overflowNext(outboundCh, rq.outBacklog)
+			evtCh, nextEvt := overflowNext(eventCallCh, rq.evtBacklog)
+			select {
+			case <-jawsDoneCh:
+			case <-ctxDoneCh:
+			case outCh <- nextOut:
+				rq.outBacklog = rq.outBacklog[1:]
+				rq.sendCount.backlog.Store(int64(len(rq.outBacklog)))
+				continue
+			case evtCh <- nextEvt:
+				rq.evtBacklog = rq.evtBacklog[1:]
+				events.schedule()
+				continue
+			case tagmsg, ok = <-broadcastMsgCh:
+				if ok && len(broadcastMsgCh) > 0 && rq.Jaws.hasPriority() {
+					backlog = rq.prioritize(broadcastMsgCh, append(backlog, tagmsg))
+					tagmsg, backlog = backlog[0], backlog[1:]
+				}
+			case wsmsg, ok = <-incomingMsgCh:
+				if ok {
+					rq.markSeen()
+					// incoming event message from the websocket
+					if wsmsg.Jid.IsValid() {
+						switch wsmsg.What {
+						case what.Input, what.Change, what.Key, what.Click, what.Paste, what.Capture, what.Custom:
+							if rq.Inert() {
+								continue
+							}
+							if wsmsg.What == what.Click && wsmsg.Jid != 0 {
+								if e := rq.getElementByJid(wsmsg.Jid); e != nil {
+									if err := rq.clickTokenErr(e); err != nil {
+										_ = rq.Jaws.Log(fmt.Errorf("jaws: %v: %w", e, err))
+										continue
+									}
+								}
+							}
+							rq.mu.Lock()
+							rq.lastInput = time.Now()
+							rq.mu.Unlock()
+							rq.queueEvent(eventCallCh, eventFnCall{jid: wsmsg.Jid, wht: wsmsg.What, data: wsmsg.Data})
+							events.schedule()
+						case what.Fullscreen:
+							rq.queueEvent(eventCallCh, eventFnCall{jid: wsmsg.Jid, wht: wsmsg.What, data: wsmsg.Data})
+							events.schedule()
+						case what.Remove:
+							rq.handleRemove(wsmsg.Data)
+						case what.WakeLock:
+							rq.handleWakeLock(wsmsg.Data)
+						case what.Prefs:
+							rq.handlePrefs(wsmsg.Data)
+						case what.Widget:
+							wsQueue = append(wsQueue, rq.handleWidget(wsmsg.Data)...)
+						case what.Mirror:
+							rq.handleMirror(wsmsg.Data)
+						case what.Credit:
+							rq.handleCredit(wsmsg.Data)
+						}
 					}
+					continue
 				}
-				continue
 			}
 		}
 
@@ -485,6 +701,14 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 			return
 		}
 
+		if tagmsg.isExpired(rq.Jaws.MessageTTL) {
+			// sat in a congested queue, drop it if a fresher one is waiting
+			backlog = rq.drainQueued(broadcastMsgCh, backlog)
+			if tagmsg.isStale(rq.Jaws.MessageTTL, backlog) {
+				continue
+			}
+		}
+
 		// prepare the data to send in the WS message
 		var wsdata string
 		switch data := tagmsg.Data.(type) {
@@ -500,6 +724,10 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 		case nil:
 			// matches no elements
 		case *Request:
+		case *Element:
+			if rq.getElementByJid(v.jid) == v {
+				todo = append(todo, v)
+			}
 		case string:
 			// target is a regular HTML ID
 			wsQueue = append(wsQueue, wsMsg{
@@ -512,12 +740,14 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 		}
 
 		switch tagmsg.What {
-		case what.Reload, what.Redirect, what.Order, what.Alert:
-			wsQueue = append(wsQueue, wsMsg{
+		case what.Mirror:
+			wsQueue = rq.mirrorMessage(outboundCh, wsQueue, tagmsg.Data)
+		case what.Reload, what.Redirect, what.Order, what.Alert, what.Print, what.WakeLock, what.Announce, what.Inert:
+			wsQueue = append(wsQueue, rq.renderAlert(wsMsg{
 				Jid:  0,
 				Data: wsdata,
 				What: tagmsg.What,
-			})
+			}))
 		default:
 			for _, elem := range todo {
 				switch tagmsg.What {
@@ -527,12 +757,13 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 						What: what.Delete,
 					})
 					rq.deleteElement(elem)
-				case what.Input, what.Click:
-					// Input or Click messages recieved here are from Request.Send() or broadcasts.
+				case what.Input, what.Change, what.Key, what.Click, what.Paste, what.Capture, what.Custom:
+					// Input, Change, Key, Click, Paste, Capture or Custom messages recieved here are from Request.Send() or broadcasts.
 					// they won't be sent out on the WebSocket, but will queue up a
 					// call to the event function (if any).
 					// primary usecase is tests.
 					rq.queueEvent(eventCallCh, eventFnCall{jid: elem.jid, wht: tagmsg.What, data: wsdata})
+					events.schedule()
 				case what.Hook:
 					// "hook" messages are used to synchronously call an event function.
 					// the function must not send any messages itself, but may return
@@ -546,8 +777,11 @@ func (rq *Request) process(broadcastMsgCh chan Message, incomingMsgCh <-chan wsM
 						})
 					}
 				case what.Update:
-					elem.Ui().JawsUpdate(elem)
+					rq.updateElement(elem)
 				default:
+					if tagmsg.What == what.Inner || tagmsg.What == what.Replace {
+						elem.innerOk = false
+					}
 					wsQueue = append(wsQueue, wsMsg{
 						Data: wsdata,
 						Jid:  elem.jid,
@@ -571,6 +805,8 @@ func (rq *Request) handleRemove(data string) {
 
 func (rq *Request) callAllEventHandlers(id Jid, wht what.What, val string) (err error) {
 	var elems []*Element
+	var tokens, keys []string
+	var unknown []error
 	rq.mu.RLock()
 	if id == 0 {
 		if wht == what.Click {
@@ -580,9 +816,15 @@ func (rq *Request) callAllEventHandlers(id Jid, wht what.What, val string) (err
 			for found {
 				var jidStr string
 				jidStr, after, found = strings.Cut(after, "\t")
+				jidStr, key, _ := strings.Cut(jidStr, "!")
+				jidStr, token, _ := strings.Cut(jidStr, ":")
 				if id = jid.ParseString(jidStr); id > 0 {
 					if e := rq.getElementByJidLocked(id); e != nil {
 						elems = append(elems, e)
+						tokens = append(tokens, token)
+						keys = append(keys, key)
+					} else if rq.jids != nil {
+						unknown = append(unknown, rq.unknownJidErr(id))
 					}
 				}
 			}
@@ -590,45 +832,72 @@ func (rq *Request) callAllEventHandlers(id Jid, wht what.What, val string) (err
 	} else {
 		if e := rq.getElementByJidLocked(id); e != nil {
 			elems = append(elems, e)
+		} else if rq.jids != nil {
+			unknown = append(unknown, rq.unknownJidErr(id))
 		}
 	}
 	rq.mu.RUnlock()
 
-	for _, e := range elems {
-		if err = callEventHandler(e.ui, e, wht, val); err != ErrEventUnhandled {
+	for _, err := range unknown {
+		_ = rq.Jaws.Log(err)
+	}
+
+	if tokens != nil {
+		var checked []*Element
+		for i, e := range elems {
+			if rq.checkConfirm(e, tokens[i]) && rq.checkOnce(e, keys[i]) {
+				checked = append(checked, e)
+			}
+		}
+		elems = delegatesFirst(checked)
+	}
+
+	if (wht == what.Paste || wht == what.Capture) && len(elems) == 1 {
+		var done bool
+		if val, done, err = elems[0].pasteChunk(val); !done {
 			return
 		}
-		for _, h := range e.handlers {
-			if err = h.JawsEvent(e, wht, val); err != ErrEventUnhandled {
-				return
-			}
+	}
+
+	var handled bool
+	for _, e := range elems {
+		e.handled = false
+		if err = rq.callElementHandlers(e, wht, val); err != ErrEventUnhandled {
+			return
 		}
+		handled = handled || e.handled
 	}
 	if err == ErrEventUnhandled {
 		err = nil
+		if !handled {
+			err = rq.unhandledEvent(elems, wht, val)
+		}
 	}
 	return
 }
 
+// queueEvent queues call for the eventCaller, applying Jaws.Overflow if the queue is full.
 func (rq *Request) queueEvent(eventCallCh chan eventFnCall, call eventFnCall) {
-	select {
-	case eventCallCh <- call:
-	default:
+	dropped, ok := overflowSend(eventCallCh, call, rq.Jaws.Overflow, rq.Jaws.overflowWait(), rq.Done(), &rq.evtBacklog)
+	rq.sendCount.droppedEvents.Add(uint64(dropped))
+	if !ok {
+		call.data = redactValue(call.data, rq.getElementByJid(call.jid))
 		rq.Jaws.MustLog(fmt.Errorf("jaws: %v: eventCallCh is full sending %v", rq, call))
-		return
 	}
 }
 
-func (rq *Request) wsSend(outboundCh chan<- string, s string) {
-	select {
-	case <-rq.Done():
-	case outboundCh <- s:
-	default:
-		panic(fmt.Errorf("jaws: %v: outbound message channel is full (%d) sending %s", rq, len(outboundCh), s))
+// wsSend queues s to be sent to the browser, applying Jaws.Overflow if the queue is full.
+func (rq *Request) wsSend(outboundCh chan string, s string) {
+	dropped, ok := overflowSend(outboundCh, s, rq.Jaws.Overflow, rq.Jaws.overflowWait(), rq.Done(), &rq.outBacklog)
+	rq.sendCount.dropped.Add(uint64(dropped))
+	rq.sendCount.backlog.Store(int64(len(rq.outBacklog)))
+	rq.mirrorSend(s)
+	if !ok {
+		panic(fmt.Errorf("jaws: %v: %w: outbound message channel is full (%d) sending %s", rq, ErrWebsocketQueueOverflow, len(outboundCh), s))
 	}
 }
 
-func (rq *Request) sendQueue(outboundCh chan<- string, wsQueue []wsMsg) []wsMsg {
+func (rq *Request) sendQueue(outboundCh chan string, wsQueue []wsMsg) []wsMsg {
 	var sb strings.Builder
 	for _, msg := range wsQueue {
 		sb.WriteString(msg.Format())
@@ -638,8 +907,12 @@ func (rq *Request) sendQueue(outboundCh chan<- string, wsQueue []wsMsg) []wsMsg
 }
 
 func (rq *Request) deleteElementLocked(e *Element) {
+	rq.deletePastesLocked(e.jid)
 	e.Request = nil
 	rq.elems = slices.DeleteFunc(rq.elems, func(elem *Element) bool { return elem == e })
+	if rq.jids != nil && rq.jids[e.jid] == e {
+		delete(rq.jids, e.jid)
+	}
 	for k := range rq.tagMap {
 		rq.tagMap[k] = slices.DeleteFunc(rq.tagMap[k], func(elem *Element) bool { return elem == e })
 	}
@@ -656,7 +929,7 @@ func (rq *Request) makeUpdateList() (todo []*Element) {
 	defer rq.mu.Unlock()
 	for _, tag := range rq.todoDirt {
 		for _, elem := range rq.tagMap[tag] {
-			if !elem.updating {
+			if !elem.updating && !elem.pending && !elem.detached {
 				elem.updating = true
 				todo = append(todo, elem)
 			}
@@ -665,6 +938,7 @@ func (rq *Request) makeUpdateList() (todo []*Element) {
 	for _, elem := range todo {
 		elem.updating = false
 	}
+	sortPriorityElements(todo)
 	rq.todoDirt = rq.todoDirt[:0]
 	return
 }
@@ -673,14 +947,23 @@ func (rq *Request) makeUpdateList() (todo []*Element) {
 func (rq *Request) eventCaller(eventCallCh <-chan eventFnCall, outboundCh chan<- string, eventDoneCh chan<- struct{}) {
 	defer close(eventDoneCh)
 	for call := range eventCallCh {
-		if err := rq.callAllEventHandlers(call.jid, call.wht, call.data); err != nil {
-			var m wsMsg
-			m.FillAlert(err)
-			select {
-			case outboundCh <- m.Format():
-			default:
-				_ = rq.Jaws.Log(fmt.Errorf("jaws: outboundMsgCh full sending event error '%s'", err.Error()))
-			}
+		rq.callEvent(call, outboundCh)
+	}
+}
+
+// callEvent calls the event functions for call, sending any error as an alert.
+func (rq *Request) callEvent(call eventFnCall, outboundCh chan<- string) {
+	if err := rq.callAllEventHandlers(call.jid, call.wht, call.data); err != nil {
+		var m wsMsg
+		m.FillAlert(err)
+		if lvl, msg, _ := strings.Cut(m.Data, "\n"); !rq.recordAlert(lvl, msg) {
+			return
+		}
+		m = rq.renderAlert(m)
+		select {
+		case outboundCh <- m.Format():
+		default:
+			_ = rq.Jaws.Log(fmt.Errorf("jaws: outboundMsgCh full sending event error '%s'", err.Error()))
 		}
 	}
 }
@@ -697,6 +980,14 @@ func (rq *Request) onConnect() (err error) {
 	return
 }
 
+// checkAssetVersion notifies the Request if the asset version
+// has changed since it was rendered.
+func (rq *Request) checkAssetVersion() {
+	if rq.Jaws.AssetVersion() != rq.assetVersion {
+		rq.Jaws.newVersion(rq)
+	}
+}
+
 func (rq *Request) Writer(w io.Writer) RequestWriter {
 	return RequestWriter{rq: rq, Writer: w}
 }
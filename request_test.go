@@ -405,6 +405,27 @@ func TestRequest_Alert(t *testing.T) {
 	}
 }
 
+func TestRequest_Announce(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+
+	rq.Announce("assertive", "3 new messages")
+	rq.Announce("rude", "<b>x</b>")
+	for _, want := range []string{
+		"Announce\t\t\"assertive\\n3 new messages\"\n",
+		"Announce\t\t\"polite\\n<b>x</b>\"\n",
+	} {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			th.Equal(s, want)
+		}
+	}
+}
+
 func TestRequest_Redirect(t *testing.T) {
 	th := newTestHelper(t)
 	tj := newTestJaws()
@@ -676,6 +697,70 @@ func TestRequest_IncomingClick(t *testing.T) {
 	}
 }
 
+func TestRequest_IncomingFullscreen(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	gotCh := make(chan string, 1)
+	rq.Div("1", func(e *Element, wht what.What, val string) error {
+		if wht == what.Fullscreen {
+			gotCh <- val
+		}
+		return nil
+	})
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case rq.inCh <- wsMsg{What: what.Fullscreen, Jid: 1, Data: "true"}:
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-gotCh:
+		th.Equal(s, "true")
+	}
+}
+
+func TestRequest_KeepAwake(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+
+	rq.KeepAwake(true)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "WakeLock\t\t\"true\"\n")
+	}
+	th.Equal(rq.IsAwake(), false)
+
+	rq.inCh <- wsMsg{What: what.WakeLock, Data: "true"}
+	for !rq.IsAwake() {
+		select {
+		case <-th.C:
+			th.Timeout()
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	rq.inCh <- wsMsg{What: what.WakeLock, Data: "not allowed"}
+	for rq.IsAwake() {
+		select {
+		case <-th.C:
+			th.Timeout()
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+}
+
 func TestRequest_CustomErrors(t *testing.T) {
 	th := newTestHelper(t)
 	rq := newTestRequest()
@@ -690,3 +775,41 @@ func TestRequest_CustomErrors(t *testing.T) {
 	var target2 ErrPendingCancelled
 	th.Equal(errors.As(cause, &target2), false)
 }
+
+func TestRequest_processKillsSession(t *testing.T) {
+	for _, window := range []time.Duration{0, time.Minute} {
+		th := newTestHelper(t)
+		tj := newTestJaws()
+		tj.ReconnectWindow = window
+		hr := httptest.NewRequest(http.MethodGet, "/", nil)
+		sess := tj.NewSession(nil, hr)
+		rq := tj.NewRequest(hr)
+		th.True(rq.Session() == sess)
+		rq.mu.Lock()
+		rq.running = true
+		rq.mu.Unlock()
+
+		inCh := make(chan wsMsg)
+		outCh := make(chan string, 1)
+		doneCh := make(chan struct{})
+		go func() {
+			defer close(doneCh)
+			rq.process(tj.subscribe(rq, 1), inCh, outCh)
+		}()
+		close(inCh)
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-doneCh:
+		}
+
+		if window > 0 {
+			th.True(rq.Session() == sess)
+			th.Equal(len(sess.Requests()), 1)
+		} else {
+			th.True(rq.Session() == nil)
+			th.Equal(len(sess.Requests()), 0)
+		}
+		tj.Close()
+	}
+}
@@ -0,0 +1,114 @@
+package jaws
+
+import (
+	"html/template"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// RequestOptions override Jaws settings for a single Request, letting
+// handlers for different parts of a site, such as an admin area and
+// public pages, use different policies.
+//
+// Zero valued fields use the Jaws setting.
+type RequestOptions struct {
+	EventTimeout   time.Duration      // overrides Jaws.EventTimeout
+	RenderTimeout  time.Duration      // overrides Jaws.RenderTimeout
+	MaxPasteSize   int                // overrides Jaws.MaxPasteSize
+	MaxMessageSize int                // overrides Jaws.MaxMessageSize
+	MaxEventRate   int                // overrides Jaws.MaxEventRate
+	SendRate       int                // overrides Jaws.SendRate
+	SendBurst      int                // overrides Jaws.SendBurst if SendRate is set
+	Template       *template.Template // overrides Jaws.Template when looking up templates by name
+	TokenValidator TokenValidator     // overrides Jaws.TokenValidator
+	OriginPatterns []string           // host patterns for other origins allowed to connect the WebSocket, see path.Match
+	Tabs           TabPolicy          // overrides Jaws.Tabs
+	View           string             // name of the view for Jaws.Tabs, defaults to the path of the initial HTTP request
+}
+
+// NewRequestWithOptions is like NewRequest, but the Request uses the
+// given options instead of the Jaws settings. The options must not be
+// modified after the call. If opts is nil, it is the same as NewRequest.
+func (jw *Jaws) NewRequestWithOptions(hr *http.Request, opts *RequestOptions) (rq *Request) {
+	rq = jw.NewRequest(hr)
+	rq.mu.Lock()
+	rq.opts = opts
+	rq.mu.Unlock()
+	return
+}
+
+// Options returns the RequestOptions the Request was created with, or nil.
+func (rq *Request) Options() (opts *RequestOptions) {
+	rq.mu.RLock()
+	opts = rq.opts
+	rq.mu.RUnlock()
+	return
+}
+
+func (rq *Request) eventTimeout() time.Duration {
+	if opts := rq.Options(); opts != nil && opts.EventTimeout > 0 {
+		return opts.EventTimeout
+	}
+	return rq.Jaws.EventTimeout
+}
+
+func (rq *Request) renderTimeout() time.Duration {
+	if opts := rq.Options(); opts != nil && opts.RenderTimeout > 0 {
+		return opts.RenderTimeout
+	}
+	return rq.Jaws.RenderTimeout
+}
+
+func (rq *Request) maxPasteSize() int {
+	if opts := rq.Options(); opts != nil && opts.MaxPasteSize > 0 {
+		return opts.MaxPasteSize
+	}
+	return rq.Jaws.MaxPasteSize
+}
+
+func (rq *Request) maxMessageSize() int {
+	if opts := rq.Options(); opts != nil && opts.MaxMessageSize > 0 {
+		return opts.MaxMessageSize
+	}
+	return rq.Jaws.maxMessageSize()
+}
+
+func (rq *Request) maxEventRate() int {
+	if opts := rq.Options(); opts != nil && opts.MaxEventRate > 0 {
+		return opts.MaxEventRate
+	}
+	return rq.Jaws.MaxEventRate
+}
+
+func (rq *Request) sendRate() (rate, burst int) {
+	if opts := rq.Options(); opts != nil && opts.SendRate > 0 {
+		return opts.SendRate, opts.SendBurst
+	}
+	return rq.Jaws.SendRate, rq.Jaws.SendBurst
+}
+
+func (rq *Request) template() *template.Template {
+	if opts := rq.Options(); opts != nil && opts.Template != nil {
+		return opts.Template
+	}
+	return rq.Jaws.Template
+}
+
+func (rq *Request) tokenValidator() TokenValidator {
+	if opts := rq.Options(); opts != nil && opts.TokenValidator != nil {
+		return opts.TokenValidator
+	}
+	return rq.Jaws.TokenValidator
+}
+
+func (rq *Request) originPatterns() (patterns []string) {
+	if opts := rq.Options(); opts != nil {
+		patterns = opts.OriginPatterns
+	}
+	if rq.Jaws.WebSocketURL != "" && rq.Initial != nil {
+		// the WebSocket is served separately, so allow the page's origin
+		patterns = append(slices.Clip(patterns), rq.Initial.Host)
+	}
+	return
+}
@@ -0,0 +1,63 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+	"time"
+)
+
+func TestJaws_NewRequestWithOptions(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.EventTimeout = time.Second
+	jw.Template = template.Must(template.New("page").Parse("global"))
+
+	rq := jw.NewRequestWithOptions(nil, nil)
+	th.Equal(rq.Options(), (*RequestOptions)(nil))
+	th.Equal(rq.eventTimeout(), time.Second)
+	th.Equal(rq.maxPasteSize(), DefaultMaxPasteSize)
+	th.Equal(rq.template(), jw.Template)
+	th.Equal(rq.tokenValidator(), nil)
+	th.Equal(rq.originPatterns(), []string(nil))
+	th.Equal(rq.maxMessageSize(), DefaultMaxMessageSize)
+	th.Equal(rq.maxEventRate(), 0)
+	rate, burst := rq.sendRate()
+	th.Equal(rate, 0)
+	th.Equal(burst, 0)
+
+	admin := template.Must(template.New("page").Parse("admin"))
+	tv := TokenValidatorFunc(func(rq *Request, token string) error { return nil })
+	opts := &RequestOptions{
+		EventTimeout:   time.Minute,
+		MaxPasteSize:   10,
+		Template:       admin,
+		TokenValidator: tv,
+		OriginPatterns: []string{"*.example.com"},
+		MaxMessageSize: 100,
+		MaxEventRate:   5,
+		SendRate:       20,
+		SendBurst:      40,
+	}
+	rq = jw.NewRequestWithOptions(nil, opts)
+	th.Equal(rq.Options(), opts)
+	th.Equal(rq.eventTimeout(), time.Minute)
+	th.Equal(rq.maxPasteSize(), 10)
+	th.Equal(rq.MustTemplate("page"), admin)
+	th.True(rq.tokenValidator() != nil)
+	th.Equal(rq.originPatterns(), []string{"*.example.com"})
+	th.Equal(rq.maxMessageSize(), 100)
+	th.Equal(rq.maxEventRate(), 5)
+	rate, burst = rq.sendRate()
+	th.Equal(rate, 20)
+	th.Equal(burst, 40)
+	th.Equal(rq.readLimits().maxSize, 100)
+	th.True(rq.readLimits().limiter != nil)
+	_, limited := rq.limitSends(nil).(rateLimitedWebSocketConn)
+	th.True(limited)
+
+	jw.mu.Lock()
+	jw.recycleLocked(rq)
+	jw.mu.Unlock()
+	th.Equal(rq.Options(), (*RequestOptions)(nil))
+}
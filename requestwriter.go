@@ -11,6 +11,12 @@ type RequestWriter struct {
 }
 
 func (rw RequestWriter) UI(ui UI, params ...interface{}) error {
+	if flag, rest, found := extractFlag(params); found {
+		ui, params = NewUiIfFlag(flag, ui), rest
+	}
+	if rest, found := extractLazy(params); found {
+		ui, params = NewUiLazy(ui), rest
+	}
 	return rw.rq.JawsRender(rw.rq.NewElement(ui), rw.Writer, params)
 }
 
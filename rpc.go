@@ -0,0 +1,84 @@
+package jaws
+
+import (
+	"context"
+	"html/template"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// RPC is an EventHandler that bridges UI events to an unary remote procedure
+// call, such as a method on a gRPC or Connect client, letting thin UI services
+// front existing backend APIs with little handler code.
+//
+// For each event, Request builds the request message, Call invokes the RPC
+// using the Element's Context, and the response is stored in the RPC, which
+// is then dirtied along with Tags so that Elements showing it are updated.
+// An error from Call is returned from the event handler, and so shown as an alert.
+//
+//	rpc := jaws.NewRPC(
+//		func(ctx context.Context, req *pb.LookupRequest) (*pb.LookupReply, error) {
+//			return client.Lookup(ctx, req)
+//		},
+//		func(e *jaws.Element, wht what.What, val string) (*pb.LookupRequest, error) {
+//			return &pb.LookupRequest{Name: val}, nil
+//		})
+//	rw.Text(name, rpc)
+//	rw.Span(rpc.Html(func(resp *pb.LookupReply, err error) template.HTML { ... }))
+//
+// The last response is shared by all Elements using the RPC, so create an
+// RPC for each Request unless the responses may be shown to everyone.
+type RPC[Req, Resp any] struct {
+	Call    func(ctx context.Context, req Req) (Resp, error)                 // the unary call
+	Request func(e *Element, wht what.What, val string) (req Req, err error) // returns the request for an event, or ErrEventUnhandled to ignore it
+	Tags    []interface{}                                                    // additional tags to dirty when a response arrives
+	mu      deadlock.RWMutex
+	resp    Resp
+	err     error
+}
+
+// NewRPC returns a new RPC calling call with the requests built by request.
+func NewRPC[Req, Resp any](call func(ctx context.Context, req Req) (Resp, error), request func(e *Element, wht what.What, val string) (Req, error), tags ...interface{}) *RPC[Req, Resp] {
+	return &RPC[Req, Resp]{Call: call, Request: request, Tags: tags}
+}
+
+func (r *RPC[Req, Resp]) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	var req Req
+	if req, err = r.Request(e, wht, val); err == nil {
+		var resp Resp
+		resp, err = r.Call(e.Context(), req)
+		r.mu.Lock()
+		r.resp, r.err = resp, err
+		r.mu.Unlock()
+		e.Request.Jaws.Dirty(append([]interface{}{r}, r.Tags...)...)
+	}
+	return
+}
+
+// Response returns the last response and error from the RPC.
+func (r *RPC[Req, Resp]) Response() (resp Resp, err error) {
+	r.mu.RLock()
+	resp, err = r.resp, r.err
+	r.mu.RUnlock()
+	return
+}
+
+// Html returns a HtmlGetter tagged with the RPC that renders the last
+// response and error from the RPC using fn.
+func (r *RPC[Req, Resp]) Html(fn func(resp Resp, err error) template.HTML) HtmlGetter {
+	return rpcHtmlGetter[Req, Resp]{r, fn}
+}
+
+type rpcHtmlGetter[Req, Resp any] struct {
+	r  *RPC[Req, Resp]
+	fn func(resp Resp, err error) template.HTML
+}
+
+func (g rpcHtmlGetter[Req, Resp]) JawsGetHtml(e *Element) template.HTML {
+	return g.fn(g.r.Response())
+}
+
+func (g rpcHtmlGetter[Req, Resp]) JawsGetTag(rq *Request) interface{} {
+	return g.r
+}
@@ -0,0 +1,74 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"html/template"
+	"strconv"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRPC(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	var gotCtx context.Context
+	extra := &gotCtx
+	errNegative := errors.New("negative")
+	rpc := NewRPC(
+		func(ctx context.Context, req int) (string, error) {
+			gotCtx = ctx
+			if req < 0 {
+				return "", errNegative
+			}
+			return strconv.Itoa(req * 2), nil
+		},
+		func(e *Element, wht what.What, val string) (int, error) {
+			if wht != what.Input {
+				return 0, ErrEventUnhandled
+			}
+			return strconv.Atoi(val)
+		}, extra)
+
+	th.NoErr(rq.Div("", rpc))
+	th.NoErr(rq.Span(rpc.Html(func(resp string, err error) template.HTML {
+		if err != nil {
+			return template.HTML("error: " + err.Error())
+		}
+		return template.HTML(resp)
+	})))
+	th.Equal(rq.BodyString(), `<div id="Jid.1"></div><span id="Jid.2"></span>`)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "21"))
+	th.Equal(gotCtx, nil)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "21"))
+	th.True(gotCtx != nil)
+	resp, err := rpc.Response()
+	th.Equal(resp, "42")
+	th.NoErr(err)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"\"\nInner\tJid.2\t\"42\"\n")
+	}
+
+	th.Equal(rq.callAllEventHandlers(1, what.Input, "-1"), errNegative)
+	resp, err = rpc.Response()
+	th.Equal(resp, "")
+	th.Equal(err, errNegative)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"\"\nInner\tJid.2\t\"error: negative\"\n")
+	}
+
+	th.True(errors.Is(rq.callAllEventHandlers(1, what.Input, "x"), strconv.ErrSyntax))
+	th.Equal(rpc.Tags, []interface{}{extra})
+}
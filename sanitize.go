@@ -0,0 +1,229 @@
+package jaws
+
+import (
+	"html"
+	"strings"
+)
+
+// sanitizeElems are the elements kept by sanitizeHTML.
+var sanitizeElems = map[string]bool{
+	"a": true, "abbr": true, "address": true, "article": true, "aside": true,
+	"b": true, "bdi": true, "bdo": true, "blockquote": true, "br": true,
+	"button": true, "caption": true, "cite": true, "code": true, "col": true,
+	"colgroup": true, "data": true, "dd": true, "del": true, "details": true,
+	"dfn": true, "div": true, "dl": true, "dt": true, "em": true,
+	"fieldset": true, "figcaption": true, "figure": true, "footer": true, "form": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+	"header": true, "hr": true, "i": true, "img": true, "input": true,
+	"ins": true, "kbd": true, "label": true, "legend": true, "li": true,
+	"main": true, "mark": true, "meter": true, "nav": true, "ol": true,
+	"optgroup": true, "option": true, "output": true, "p": true, "pre": true,
+	"progress": true, "q": true, "s": true, "samp": true, "section": true,
+	"select": true, "small": true, "span": true, "strong": true, "sub": true,
+	"summary": true, "sup": true, "table": true, "tbody": true, "td": true,
+	"textarea": true, "tfoot": true, "th": true, "thead": true, "time": true,
+	"tr": true, "u": true, "ul": true, "var": true, "wbr": true,
+}
+
+// sanitizeVoid are the kept elements that have no end tag.
+var sanitizeVoid = map[string]bool{
+	"br": true, "col": true, "hr": true, "img": true, "input": true, "wbr": true,
+}
+
+// sanitizeDropped are the elements removed by sanitizeHTML along with their content.
+// If true, their content is raw text that ends only with their end tag.
+var sanitizeDropped = map[string]bool{
+	"script": true, "style": true, "xmp": true, "iframe": true, "noembed": true,
+	"noframes": true, "noscript": true, "plaintext": true, "title": true,
+	"template": false, "svg": false, "math": false, "object": false,
+	"applet": false, "frameset": false, "head": false,
+}
+
+// sanitizeAttrs are the attributes kept by sanitizeHTML, along with
+// aria-* and data-* attributes. If true, the value is a URL.
+var sanitizeAttrs = map[string]bool{
+	"id": false, "class": false, "title": false, "lang": false, "dir": false,
+	"role": false, "hidden": false, "alt": false, "width": false, "height": false,
+	"type": false, "name": false, "value": false, "checked": false, "selected": false,
+	"disabled": false, "readonly": false, "multiple": false, "placeholder": false,
+	"min": false, "max": false, "step": false, "low": false, "high": false,
+	"optimum": false, "size": false, "rows": false, "cols": false, "label": false,
+	"for": false, "colspan": false, "rowspan": false, "scope": false, "span": false,
+	"start": false, "reversed": false, "open": false, "datetime": false,
+	"href": true, "src": true,
+}
+
+// sanitizeURL returns true if u is a relative URL on the same host.
+func sanitizeURL(u string) bool {
+	u = strings.TrimSpace(u)
+	if strings.HasPrefix(u, "//") || strings.HasPrefix(u, `\`) {
+		return false
+	}
+	if i := strings.IndexAny(u, ":/?#"); i >= 0 {
+		return u[i] != ':'
+	}
+	return true
+}
+
+// sanitizeHTML returns the HTML in s with only the elements and attributes
+// used to show a page's content, dropping scripts, styles, event handlers
+// and links to other sites. Text and attribute values are re-escaped, so the
+// result can't contain markup that wasn't allowed, however s is formed.
+func sanitizeHTML(s string) string {
+	var sb strings.Builder
+	var skipName string
+	var skipDepth int
+	text := func(t string) {
+		if skipDepth == 0 {
+			sb.WriteString(html.EscapeString(html.UnescapeString(t)))
+		}
+	}
+	for len(s) > 0 {
+		i := strings.IndexByte(s, '<')
+		if i < 0 {
+			text(s)
+			break
+		}
+		text(s[:i])
+		s = s[i:]
+		switch {
+		case strings.HasPrefix(s, "<!--"):
+			s = sanitizeSkipPast(s[4:], "-->")
+		case strings.HasPrefix(s, "<!"), strings.HasPrefix(s, "<?"):
+			s = sanitizeSkipPast(s[2:], ">")
+		case strings.HasPrefix(s, "</") && len(s) > 2 && isASCIILetter(s[2]):
+			var name string
+			name, s = sanitizeName(s[2:])
+			s = sanitizeSkipPast(s, ">")
+			if skipDepth > 0 {
+				if name == skipName {
+					skipDepth--
+				}
+			} else if sanitizeElems[name] && !sanitizeVoid[name] {
+				sb.WriteString("</" + name + ">")
+			}
+		case len(s) > 1 && isASCIILetter(s[1]):
+			var name string
+			var attrs [][2]string
+			name, s = sanitizeName(s[1:])
+			attrs, s = sanitizeTagAttrs(s)
+			if skipDepth > 0 {
+				if name == skipName {
+					skipDepth++
+				}
+				continue
+			}
+			if rawText, dropped := sanitizeDropped[name]; dropped {
+				if rawText {
+					s = sanitizeSkipEndTag(s, name)
+				} else {
+					skipName, skipDepth = name, 1
+				}
+				continue
+			}
+			if sanitizeElems[name] {
+				sb.WriteString("<" + name)
+				for _, attr := range attrs {
+					isURL, ok := sanitizeAttrs[attr[0]]
+					ok = ok || strings.HasPrefix(attr[0], "aria-") || strings.HasPrefix(attr[0], "data-")
+					if ok && !(isURL && !sanitizeURL(attr[1])) {
+						sb.WriteString(" " + attr[0] + `="` + html.EscapeString(attr[1]) + `"`)
+					}
+				}
+				sb.WriteByte('>')
+				if name == "textarea" {
+					end := sanitizeEndTag(s, name)
+					text(s[:end])
+					s = sanitizeSkipPast(s[end:], ">")
+					sb.WriteString("</textarea>")
+				}
+			}
+		default:
+			text("<")
+			s = s[1:]
+		}
+	}
+	return sb.String()
+}
+
+func isASCIILetter(ch byte) bool {
+	return (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z')
+}
+
+// sanitizeName returns the lower case tag or attribute name at the start of s and the rest of s.
+func sanitizeName(s string) (name, rest string) {
+	i := strings.IndexAny(s, " \t\n\f\r/>=")
+	if i < 0 {
+		i = len(s)
+	}
+	return strings.ToLower(s[:i]), s[i:]
+}
+
+// sanitizeTagAttrs returns the attributes of the start tag at the start of s
+// and the rest of s following the tag.
+func sanitizeTagAttrs(s string) (attrs [][2]string, rest string) {
+	for {
+		s = strings.TrimLeft(s, " \t\n\f\r/")
+		if s == "" || s[0] == '>' {
+			if s != "" {
+				s = s[1:]
+			}
+			return attrs, s
+		}
+		var name, val string
+		if s[0] == '=' {
+			s = s[1:] // stray '=', treat as part of a nameless attribute
+		}
+		name, s = sanitizeName(s)
+		s = strings.TrimLeft(s, " \t\n\f\r")
+		if strings.HasPrefix(s, "=") {
+			s = strings.TrimLeft(s[1:], " \t\n\f\r")
+			if s != "" && (s[0] == '"' || s[0] == '\'') {
+				q := s[0]
+				s = s[1:]
+				i := strings.IndexByte(s, q)
+				if i < 0 {
+					i = len(s)
+				}
+				val, s = s[:i], s[min(i+1, len(s)):]
+			} else {
+				i := strings.IndexAny(s, " \t\n\f\r>")
+				if i < 0 {
+					i = len(s)
+				}
+				val, s = s[:i], s[i:]
+			}
+		}
+		if name != "" {
+			attrs = append(attrs, [2]string{name, html.UnescapeString(val)})
+		}
+	}
+}
+
+// sanitizeSkipPast returns s following the first occurrence of sep, or "" if it's not found.
+func sanitizeSkipPast(s, sep string) string {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[i+len(sep):]
+	}
+	return ""
+}
+
+// sanitizeEndTag returns the index in s of the end tag for name, or len(s) if it's not found.
+func sanitizeEndTag(s, name string) int {
+	for i := 0; i < len(s); {
+		j := strings.Index(s[i:], "</")
+		if j < 0 {
+			break
+		}
+		i += j + 2
+		if len(s)-i >= len(name) && strings.EqualFold(s[i:i+len(name)], name) {
+			return i - 2
+		}
+	}
+	return len(s)
+}
+
+// sanitizeSkipEndTag returns s following the end tag for name.
+func sanitizeSkipEndTag(s, name string) string {
+	return sanitizeSkipPast(s[sanitizeEndTag(s, name):], ">")
+}
@@ -0,0 +1,36 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func Test_sanitizeHTML(t *testing.T) {
+	th := newTestHelper(t)
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{`<p class="a">x &amp; y</p>`, `<p class="a">x &amp; y</p>`},
+		{`<P ID=x>a<BR/>b</P>`, `<p id="x">a<br>b</p>`},
+		{`<script>alert(1)</script>ok`, `ok`},
+		{`<SCRIPT>alert(1)</scripT >ok`, `ok`},
+		{`<style>p{}</style><p>ok</p>`, `<p>ok</p>`},
+		{`<img src=x onerror=alert(1)>`, `<img src="x">`},
+		{`<a href="javascript:alert(1)">x</a>`, `<a>x</a>`},
+		{`<a href=" JavaScript:alert(1)">x</a>`, `<a>x</a>`},
+		{`<a href="//evil.example/">x</a>`, `<a>x</a>`},
+		{`<a href="/page?q=a:b#c">x</a>`, `<a href="/page?q=a:b#c">x</a>`},
+		{`<svg><svg><script>alert(1)</script></svg><p>no</p></svg>ok`, `ok`},
+		{`<jaws-widget data-jaws-x="1"><b>in</b></jaws-widget>`, `<b>in</b>`},
+		{`<div style="background:url(//x)" aria-label="l" data-v='"><script>'>`, `<div aria-label="l" data-v="&#34;&gt;&lt;script&gt;">`},
+		{`<textarea><script>x</script></textarea>`, `<textarea>&lt;script&gt;x&lt;/script&gt;</textarea>`},
+		{`<!-- <script> -->a<!doctype html>b<?x?>c`, `abc`},
+		{`a < b > c`, `a &lt; b &gt; c`},
+		{`<p title="unterminated`, `<p title="unterminated">`},
+		{`<iframe src="x"></iframe>`, ``},
+		{`<input value="v" checked formaction="x" type=text>`, `<input value="v" checked="" type="text">`},
+	}
+	for _, tt := range tests {
+		th.Equal(sanitizeHTML(tt.in), tt.want)
+	}
+}
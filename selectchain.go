@@ -0,0 +1,182 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+
+	"github.com/linkdata/deadlock"
+)
+
+// ErrInvalidChainKey is returned when setting a SelectChain level to a key
+// that isn't one of it's options.
+var ErrInvalidChainKey = errors.New("invalid chain key")
+
+// ChainOption is an option in a level of a SelectChain.
+type ChainOption struct {
+	Key   string
+	Label template.HTML
+}
+
+// ChainOptionsFn returns the options for a level of a SelectChain given the
+// keys chosen in the levels before it. It isn't called while any of those
+// keys are empty. It must not call methods of the SelectChain.
+type ChainOptionsFn func(rq *Request, parents []string) (opts []ChainOption, err error)
+
+// SelectChain is a sequence of dependent selects, such as country, region
+// and city, where choosing a key in one level clears the levels following
+// it and repopulates their options from the server. All affected levels
+// are updated together.
+//
+// It is safe to use from multiple goroutines concurrently.
+type SelectChain struct {
+	mu     deadlock.RWMutex // protects following
+	levels []*ChainLevel
+}
+
+// ChainLevel is a level in a SelectChain. It is a SelectHandler, so it is
+// rendered using RequestWriter.Select.
+type ChainLevel struct {
+	chain  *SelectChain          // (read-only) SelectChain this is part of
+	index  int                   // (read-only) index in the SelectChain
+	fn     ChainOptionsFn        // (read-only) options provider
+	setter StringSetter          // (read-only) bound StringSetter, may be nil
+	key    string                // protected by chain.mu
+	nbs    map[string]*NamedBool // protected by chain.mu
+}
+
+var _ SelectHandler = (*ChainLevel)(nil)
+
+// NewSelectChain returns a new SelectChain without levels.
+func NewSelectChain() *SelectChain {
+	return &SelectChain{}
+}
+
+// Add appends a level with options from fn and returns it.
+//
+// If ss is not nil, the level is bound to it. The initial key is read from
+// it using a nil Element, and it is set whenever the key of the level changes,
+// including when cleared because a level before it changed.
+func (sc *SelectChain) Add(fn ChainOptionsFn, ss StringSetter) (cl *ChainLevel) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	cl = &ChainLevel{
+		chain:  sc,
+		index:  len(sc.levels),
+		fn:     fn,
+		setter: ss,
+		nbs:    map[string]*NamedBool{},
+	}
+	if ss != nil {
+		cl.key = ss.JawsGetString(nil)
+	}
+	sc.levels = append(sc.levels, cl)
+	return
+}
+
+// Keys returns the keys chosen in all levels, in order.
+func (sc *SelectChain) Keys() (keys []string) {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+	return sc.keysLocked(len(sc.levels))
+}
+
+func (sc *SelectChain) keysLocked(n int) (keys []string) {
+	for _, cl := range sc.levels[:n] {
+		keys = append(keys, cl.key)
+	}
+	return
+}
+
+// optionsLocked returns the current options of the level, or nil if
+// a level before it has no key.
+func (cl *ChainLevel) optionsLocked(rq *Request) (opts []ChainOption, err error) {
+	parents := cl.chain.keysLocked(cl.index)
+	for _, k := range parents {
+		if k == "" {
+			return
+		}
+	}
+	return cl.fn(rq, parents)
+}
+
+// Get returns the key chosen in the level.
+func (cl *ChainLevel) Get() (key string) {
+	cl.chain.mu.RLock()
+	key = cl.key
+	cl.chain.mu.RUnlock()
+	return
+}
+
+func (cl *ChainLevel) JawsContains(rq *Request) (contents []UI) {
+	sc := cl.chain
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	opts, err := cl.optionsLocked(rq)
+	if err != nil {
+		rq.Jaws.MustLog(err)
+		return
+	}
+	nbs := make(map[string]*NamedBool, len(opts))
+	for _, opt := range opts {
+		nb := cl.nbs[opt.Key]
+		if nb == nil || nb.Html() != opt.Label {
+			nb = NewNamedBool(nil, opt.Key, opt.Label, false)
+		}
+		nb.Set(opt.Key == cl.key)
+		nbs[opt.Key] = nb
+		contents = append(contents, UiOption{nb})
+	}
+	cl.nbs = nbs
+	return
+}
+
+func (cl *ChainLevel) JawsGetString(e *Element) string {
+	return cl.Get()
+}
+
+// JawsSetString sets the key of the level, which must be one of it's
+// options or empty, and clears the keys of the levels following it.
+// The changed levels are marked dirty together.
+func (cl *ChainLevel) JawsSetString(e *Element, key string) (err error) {
+	sc := cl.chain
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	if key == cl.key {
+		return
+	}
+	if key != "" {
+		var opts []ChainOption
+		if opts, err = cl.optionsLocked(e.Request); err != nil {
+			return
+		}
+		found := false
+		for _, opt := range opts {
+			found = found || opt.Key == key
+		}
+		if !found {
+			return fmt.Errorf("%w: %q", ErrInvalidChainKey, key)
+		}
+	}
+	if cl.setter != nil {
+		if err = cl.setter.JawsSetString(e, key); err != nil {
+			return
+		}
+	}
+	cl.key = key
+	changed := []interface{}{cl}
+	for _, dep := range sc.levels[cl.index+1:] {
+		if dep.key != "" {
+			dep.key = ""
+			if dep.setter != nil {
+				if seterr := dep.setter.JawsSetString(e, ""); err == nil {
+					err = seterr
+				}
+			}
+		}
+		// options depend on the parent keys, so always refresh them
+		changed = append(changed, dep)
+	}
+	e.Dirty(changed...)
+	return
+}
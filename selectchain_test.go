@@ -0,0 +1,95 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func testChainOptions(rq *Request, parents []string) (opts []ChainOption, err error) {
+	tree := map[string][]string{
+		"":         {"se", "no"},
+		"se":       {"se-ab", "se-m"},
+		"no":       {"no-03"},
+		"se/se-ab": {"stockholm", "solna"},
+		"se/se-m":  {"malmo"},
+		"no/no-03": {"oslo"},
+	}
+	for _, k := range tree[strings.Join(parents, "/")] {
+		opts = append(opts, ChainOption{Key: k, Label: template.HTML(strings.ToUpper(k))})
+	}
+	return
+}
+
+func TestSelectChain(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	country := newTestSetter("se")
+	city := newTestSetter("")
+	sc := NewSelectChain()
+	lvCountry := sc.Add(testChainOptions, country)
+	lvRegion := sc.Add(testChainOptions, nil)
+	lvCity := sc.Add(testChainOptions, city)
+	th.Equal(sc.Keys(), []string{"se", "", ""})
+
+	th.NoErr(rq.Select(lvCountry))
+	th.NoErr(rq.Select(lvRegion))
+	th.NoErr(rq.Select(lvCity))
+	th.Equal(rq.BodyString(), `<select id="Jid.1"><option id="Jid.2" value="se" selected>SE</option><option id="Jid.3" value="no">NO</option></select>`+
+		`<select id="Jid.4"><option id="Jid.5" value="se-ab">SE-AB</option><option id="Jid.6" value="se-m">SE-M</option></select>`+
+		`<select id="Jid.7"></select>`)
+
+	th.NoErr(rq.callAllEventHandlers(4, what.Input, "se-ab"))
+	th.NoErr(rq.callAllEventHandlers(7, what.Input, "solna"))
+	th.Equal(sc.Keys(), []string{"se", "se-ab", "solna"})
+	th.Equal(city.Get(), "solna")
+	th.Equal(lvCity.Get(), "solna")
+
+	err := rq.callAllEventHandlers(7, what.Input, "oslo")
+	th.True(errors.Is(err, ErrInvalidChainKey))
+	th.Equal(err.Error(), `invalid chain key: "oslo"`)
+	th.Equal(city.Get(), "solna")
+
+	// changing the country clears the region and city
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "no"))
+	th.Equal(sc.Keys(), []string{"no", "", ""})
+	th.Equal(country.Get(), "no")
+	th.Equal(city.Get(), "")
+	th.Equal(city.SetCount(), 2)
+	th.True(errors.Is(rq.callAllEventHandlers(7, what.Input, "oslo"), ErrInvalidChainKey))
+
+	var opts []string
+	for _, ui := range lvRegion.JawsContains(rq.Request) {
+		opts = append(opts, ui.(UiOption).Name())
+	}
+	th.Equal(opts, []string{"no-03"})
+	th.Equal(len(lvCity.JawsContains(rq.Request)), 0)
+
+	// unchanged keys are no-ops, and keys may be cleared
+	th.NoErr(lvCountry.JawsSetString(rq.getElementByJid(1), "no"))
+	th.Equal(country.SetCount(), 1)
+	th.NoErr(lvCountry.JawsSetString(rq.getElementByJid(1), ""))
+	th.Equal(sc.Keys(), []string{"", "", ""})
+	th.Equal(len(lvRegion.JawsContains(rq.Request)), 0)
+}
+
+func TestSelectChain_OptionsError(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	errFail := errors.New("fail")
+	sc := NewSelectChain()
+	lv := sc.Add(func(*Request, []string) ([]ChainOption, error) { return nil, errFail }, nil)
+	th.NoErr(rq.Select(lv))
+	th.Equal(rq.BodyString(), `<select id="Jid.1"></select>`)
+	th.True(strings.Contains(rq.jw.log.String(), "fail"))
+	th.Equal(rq.callAllEventHandlers(1, what.Input, "x"), errFail)
+}
@@ -0,0 +1,139 @@
+package jaws
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// SendStats reports how well a Request's browser keeps up with the
+// messages sent to it.
+type SendStats struct {
+	Queued        int           // entries waiting in the outbound queue and backlogs, each holding one or more messages
+	Dropped       uint64        // outbound messages discarded because the queue was full, see Jaws.Overflow
+	DroppedEvents uint64        // events from the browser discarded because the event queue was full
+	Throttled     uint64        // WebSocket writes delayed by Jaws.SendRate
+	WriteLatency  time.Duration // moving average of how long WebSocket writes take
+	FlushInterval time.Duration // how long messages are currently collected before a WebSocket write, see Jaws.FlushMax
+	Credits       int           // WebSocket messages the browser has room for, or -1 if not using Jaws.FlowCredits
+}
+
+// SendStats returns the outbound queue depth, the counts of dropped and
+// delayed messages and the WebSocket write timing for the Request. A growing
+// Queued or Dropped indicates a slow client, and the application may want
+// to send less.
+func (rq *Request) SendStats() (st SendStats) {
+	rq.mu.RLock()
+	outboundCh := rq.outbound
+	fc := rq.flow
+	rq.mu.RUnlock()
+	st.Queued = len(outboundCh) + int(rq.sendCount.backlog.Load()) + int(rq.sendCount.bcastBacklog.Load())
+	st.Dropped = rq.sendCount.dropped.Load()
+	st.DroppedEvents = rq.sendCount.droppedEvents.Load()
+	st.Throttled = rq.sendCount.throttled.Load()
+	st.WriteLatency = time.Duration(rq.sendCount.writeLatency.Load())
+	st.FlushInterval = time.Duration(rq.sendCount.flushInterval.Load())
+	st.Credits = fc.available()
+	return
+}
+
+// backlogged returns true if the Request is running and more messages
+// wait in either of it's backlogs than fit in it's outbound queue.
+func (rq *Request) backlogged() bool {
+	rq.mu.RLock()
+	outboundCh := rq.outbound
+	rq.mu.RUnlock()
+	limit := int64(cap(outboundCh))
+	return outboundCh != nil && (rq.sendCount.backlog.Load() > limit || rq.sendCount.bcastBacklog.Load() > limit)
+}
+
+// sendCounters counts the backpressure on a Request.
+type sendCounters struct {
+	backlog       atomic.Int64 // length of Request.outBacklog
+	bcastBacklog  atomic.Int64 // broadcasts waiting for room in the subscription, see Jaws.Overflow
+	dropped       atomic.Uint64
+	droppedEvents atomic.Uint64
+	throttled     atomic.Uint64
+	writeLatency  atomic.Int64 // time.Duration
+	flushInterval atomic.Int64 // time.Duration
+}
+
+func (c *sendCounters) reset() {
+	c.backlog.Store(0)
+	c.bcastBacklog.Store(0)
+	c.dropped.Store(0)
+	c.droppedEvents.Store(0)
+	c.throttled.Store(0)
+	c.writeLatency.Store(0)
+	c.flushInterval.Store(0)
+}
+
+// setOutbound records the outbound queue of the running Request, or nil when it stops.
+func (rq *Request) setOutbound(outboundCh chan string) {
+	rq.mu.Lock()
+	rq.outbound = outboundCh
+	rq.mu.Unlock()
+}
+
+// rateLimiter is a token bucket limiting the rate of messages.
+// It is not safe for concurrent use.
+type rateLimiter struct {
+	interval time.Duration // time to earn one token
+	burst    time.Duration // interval times the bucket size
+	next     time.Time     // when the bucket is empty if nothing more is sent
+}
+
+func newRateLimiter(rate, burst int) *rateLimiter {
+	if burst < 1 {
+		burst = rate
+	}
+	interval := time.Second / time.Duration(rate)
+	return &rateLimiter{interval: interval, burst: interval * time.Duration(burst)}
+}
+
+// reserve takes a token and returns how long to wait before using it.
+func (l *rateLimiter) reserve(now time.Time) (wait time.Duration) {
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(l.interval)
+	if wait = l.next.Sub(now) - l.burst; wait < 0 {
+		wait = 0
+	}
+	return
+}
+
+// limitSends returns ws with writes limited to Jaws.SendRate, or ws if it isn't set.
+func (rq *Request) limitSends(ws WebSocketConn) WebSocketConn {
+	if rate, burst := rq.sendRate(); rate > 0 {
+		return rateLimitedWebSocketConn{
+			WebSocketConn: ws,
+			limiter:       newRateLimiter(rate, burst),
+			counters:      &rq.sendCount,
+		}
+	}
+	return ws
+}
+
+// rateLimitedWebSocketConn is a WebSocketConn delaying writes that exceed
+// the rate of it's rateLimiter. Messages queued meanwhile are batched into
+// the next write by wsWriter.
+type rateLimitedWebSocketConn struct {
+	WebSocketConn
+	limiter  *rateLimiter
+	counters *sendCounters
+}
+
+func (c rateLimitedWebSocketConn) Write(ctx context.Context, msg []byte) (err error) {
+	if wait := c.limiter.reserve(time.Now()); wait > 0 {
+		c.counters.throttled.Add(1)
+		t := time.NewTimer(wait)
+		defer t.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	return c.WebSocketConn.Write(ctx, msg)
+}
@@ -0,0 +1,80 @@
+package jaws
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_rateLimiter(t *testing.T) {
+	th := newTestHelper(t)
+	now := time.Now()
+	l := newRateLimiter(10, 2)
+	th.Equal(l.reserve(now), time.Duration(0))
+	th.Equal(l.reserve(now), time.Duration(0))
+	th.Equal(l.reserve(now), time.Second/10)
+	th.Equal(l.reserve(now), time.Second/10*2)
+	now = now.Add(time.Second)
+	th.Equal(l.reserve(now), time.Duration(0))
+
+	l = newRateLimiter(10, 0)
+	for i := 0; i < 10; i++ {
+		th.Equal(l.reserve(now), time.Duration(0))
+	}
+	th.Equal(l.reserve(now), time.Second/10)
+}
+
+func Test_rateLimitedWebSocketConn(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(nil)
+
+	conn := &testWebSocketConn{outCh: make(chan []byte, 4), closedCh: make(chan struct{})}
+	th.Equal(rq.limitSends(conn), WebSocketConn(conn))
+
+	jw.SendRate = 20
+	jw.SendBurst = 1
+	ws := rq.limitSends(conn)
+	start := time.Now()
+	th.NoErr(ws.Write(context.Background(), []byte("1")))
+	th.NoErr(ws.Write(context.Background(), []byte("2")))
+	th.True(time.Since(start) >= time.Second/20)
+	th.Equal(len(conn.outCh), 2)
+	th.Equal(rq.SendStats().Throttled, uint64(1))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	th.Equal(ws.Write(ctx, []byte("3")), context.Canceled)
+	th.Equal(len(conn.outCh), 2)
+}
+
+func TestRequest_SendStats(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+	rq.Jaws.Overflow = OverflowDropNewest
+	rq.setOutbound(rq.outCh)
+
+	n := cap(rq.outCh) + 3
+	for i := 0; i < n; i++ {
+		rq.Jaws.Alert("info", "x")
+		for len(rq.bcastCh) > 0 {
+			time.Sleep(time.Millisecond)
+		}
+	}
+	for rq.SendStats().Dropped < 3 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	st := rq.SendStats()
+	th.Equal(st.Queued, cap(rq.outCh))
+	th.Equal(st.Dropped, uint64(3))
+	th.Equal(st.DroppedEvents, uint64(0))
+
+	rq.setOutbound(nil)
+	th.Equal(rq.SendStats().Queued, 0)
+}
@@ -0,0 +1,76 @@
+package jaws
+
+import (
+	"github.com/linkdata/deadlock"
+)
+
+// DefaultSeriesCapacity is the capacity of a Series if none is given.
+const DefaultSeriesCapacity = 60
+
+// Series is a ring buffer retaining the most recent float samples, such
+// as those shown by UiSparkline. It is safe to use from multiple goroutines
+// concurrently.
+//
+// A Series is used as the tag for Elements showing it, so call Jaws.Dirty()
+// with the Series after adding samples to update them.
+type Series struct {
+	mu    deadlock.RWMutex // protects following
+	buf   []float64
+	start int // index of the oldest sample
+	n     int // number of samples
+}
+
+// NewSeries returns a Series retaining at most capacity samples.
+// If capacity is less than one, DefaultSeriesCapacity is used.
+func NewSeries(capacity int) *Series {
+	if capacity < 1 {
+		capacity = DefaultSeriesCapacity
+	}
+	return &Series{buf: make([]float64, capacity)}
+}
+
+// Add appends samples, discarding the oldest ones if the Series is full.
+func (s *Series) Add(vals ...float64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, v := range vals {
+		if s.n < len(s.buf) {
+			s.buf[(s.start+s.n)%len(s.buf)] = v
+			s.n++
+		} else {
+			s.buf[s.start] = v
+			s.start = (s.start + 1) % len(s.buf)
+		}
+	}
+}
+
+// Values returns the samples, oldest first.
+func (s *Series) Values() (vals []float64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	vals = make([]float64, s.n)
+	for i := range vals {
+		vals[i] = s.buf[(s.start+i)%len(s.buf)]
+	}
+	return
+}
+
+// Len returns the number of samples.
+func (s *Series) Len() (n int) {
+	s.mu.RLock()
+	n = s.n
+	s.mu.RUnlock()
+	return
+}
+
+// Cap returns the maximum number of samples retained.
+func (s *Series) Cap() int {
+	return len(s.buf)
+}
+
+// Clear removes all samples.
+func (s *Series) Clear() {
+	s.mu.Lock()
+	s.start, s.n = 0, 0
+	s.mu.Unlock()
+}
@@ -0,0 +1,31 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestSeries(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(NewSeries(0).Cap(), DefaultSeriesCapacity)
+
+	s := NewSeries(3)
+	th.Equal(s.Cap(), 3)
+	th.Equal(s.Len(), 0)
+	th.Equal(s.Values(), []float64{})
+
+	s.Add(1, 2)
+	th.Equal(s.Len(), 2)
+	th.Equal(s.Values(), []float64{1, 2})
+
+	s.Add(3, 4)
+	th.Equal(s.Len(), 3)
+	th.Equal(s.Values(), []float64{2, 3, 4})
+
+	s.Add(5, 6, 7, 8)
+	th.Equal(s.Values(), []float64{6, 7, 8})
+
+	s.Clear()
+	th.Equal(s.Len(), 0)
+	s.Add(9)
+	th.Equal(s.Values(), []float64{9})
+}
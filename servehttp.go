@@ -12,27 +12,57 @@ var headerAcceptEncoding = []string{"Accept-Encoding"}
 var headerContentType = []string{"application/javascript; charset=utf-8"}
 var headerContentGZip = []string{"gzip"}
 
-// ServeHTTP can handle the required JaWS endpoints, which all start with "/jaws/".
+// basePath returns BasePath without any trailing slash.
+func (jw *Jaws) basePath() string {
+	return strings.TrimSuffix(jw.BasePath, "/")
+}
+
+// baseURL returns the URL prefix of the JaWS endpoints as seen by browsers,
+// which is empty if neither BaseURL nor BasePath is set.
+func (jw *Jaws) baseURL() string {
+	return strings.TrimSuffix(jw.BaseURL, "/") + jw.basePath()
+}
+
+// requestPath returns the request URI of r without the BasePath prefix,
+// so the JaWS endpoints are found whether or not a proxy strips it.
+func (jw *Jaws) requestPath(r *http.Request) string {
+	return strings.TrimPrefix(r.RequestURI, jw.basePath())
+}
+
+// acceptsGZip returns true if the client accepts gzip encoded responses.
+func acceptsGZip(r *http.Request) bool {
+	for _, s := range r.Header["Accept-Encoding"] {
+		for _, v := range strings.Split(s, ",") {
+			if strings.TrimSpace(v) == "gzip" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ServeHTTP can handle the required JaWS endpoints, which all start with "/jaws/",
+// optionally prefixed with BasePath.
 func (jw *Jaws) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	uri := jw.requestPath(r)
+	if strings.HasPrefix(uri, PollPath) {
+		jw.servePoll(w, r, uri)
+		return
+	}
 	if r.Method != http.MethodGet {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
-	switch r.RequestURI {
+	switch uri {
 	case JavascriptPath:
 		hdr := w.Header()
 		hdr["Cache-Control"] = headerCacheStatic
 		hdr["Content-Type"] = headerContentType
 		hdr["Vary"] = headerAcceptEncoding
 		js := JavascriptText
-		for _, s := range r.Header["Accept-Encoding"] {
-			for _, v := range strings.Split(s, ",") {
-				if strings.TrimSpace(v) == "gzip" {
-					js = JavascriptGZip
-					hdr["Content-Encoding"] = headerContentGZip
-					break
-				}
-			}
+		if acceptsGZip(r) {
+			js = JavascriptGZip
+			hdr["Content-Encoding"] = headerContentGZip
 		}
 		hdr["Content-Length"] = []string{strconv.Itoa(len(js))}
 		_, _ = w.Write(js) // #nosec G104
@@ -47,7 +77,7 @@ func (jw *Jaws) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		return
 	}
-	if rq := jw.UseRequest(JawsKeyValue(strings.TrimPrefix(r.RequestURI, "/jaws/")), r); rq != nil {
+	if rq := jw.UseRequest(JawsKeyValue(strings.TrimPrefix(uri, "/jaws/")), r); rq != nil {
 		rq.ServeHTTP(w, r)
 		return
 	}
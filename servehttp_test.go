@@ -113,6 +113,34 @@ func TestServeHTTP_GetKey(t *testing.T) {
 	rq := jw.NewRequest(req)
 	req = httptest.NewRequest("", "/jaws/"+rq.JawsKeyString(), nil)
 	jw.ServeHTTP(w, req)
-	is.Equal(w.Code, http.StatusUpgradeRequired)
+	is.Equal(w.Code, testUpgradeStatus)
 	is.Equal(w.Header()["Cache-Control"], nil)
 }
+
+func TestServeHTTP_BasePath(t *testing.T) {
+	is := newTestHelper(t)
+	jw := New()
+	go jw.Serve()
+	defer jw.Close()
+	jw.BasePath = "/app"
+
+	serve := func(uri string) int {
+		w := httptest.NewRecorder()
+		jw.ServeHTTP(w, httptest.NewRequest("", uri, nil))
+		return w.Code
+	}
+	is.Equal(serve("/app"+JavascriptPath), http.StatusOK)
+	is.Equal(serve(JavascriptPath), http.StatusOK)
+	is.Equal(serve("/app/jaws/.ping"), http.StatusNoContent)
+	is.Equal(serve("/other/jaws/.ping"), http.StatusNotFound)
+
+	rq := jw.NewRequest(httptest.NewRequest("", "/app/", nil))
+	is.Equal(serve("/app/jaws/"+rq.JawsKeyString()), testUpgradeStatus)
+	rq = jw.NewRequest(httptest.NewRequest("", "/app/", nil))
+	is.Equal(serve("/app"+PollPath+rq.JawsKeyString()), http.StatusOK)
+
+	h := jw.Handler("missing")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("", "/app/jaws/.ping", nil))
+	is.Equal(w.Code, http.StatusNoContent)
+}
@@ -1,6 +1,7 @@
 package jaws
 
 import (
+	"fmt"
 	"net/http"
 	"net/netip"
 	"time"
@@ -18,6 +19,12 @@ type Session struct {
 	deadline  time.Time
 	cookie    http.Cookie
 	data      map[string]interface{}
+	entries   []sessionEntry // keys in data, least recently set first
+	size      int            // approximate total size of data
+	ips       []netip.Addr   // additional remote IPs allowed by Jaws.Handoff
+	once      onceKeys       // idempotency keys used
+	parked    []parkedMsg    // messages kept for disconnected Requests, oldest first
+	tabsMu    deadlock.Mutex // serializes applying Jaws.Tabs to the Requests
 }
 
 func newSession(jw *Jaws, sessionID uint64, remoteIP netip.Addr) *Session {
@@ -28,11 +35,13 @@ func newSession(jw *Jaws, sessionID uint64, remoteIP netip.Addr) *Session {
 		deadline:  time.Now().Add(time.Minute),
 		cookie: http.Cookie{
 			Name:     jw.CookieName,
-			Path:     "/",
+			Path:     jw.CookiePath,
+			Domain:   jw.CookieDomain,
+			MaxAge:   max(jw.CookieMaxAge, 0),
 			Value:    JawsKeyString(sessionID),
-			Secure:   true,
+			Secure:   jw.CookieSecure,
 			HttpOnly: true,
-			SameSite: http.SameSiteLaxMode,
+			SameSite: jw.CookieSameSite,
 		},
 		data: make(map[string]interface{}),
 	}
@@ -86,17 +95,28 @@ func (sess *Session) Get(key string) (val interface{}) {
 
 // Set sets a value to be associated with the key.
 // If value is nil, the key is removed from the session.
+// If the value can't be stored because of the session quotas, the error is logged.
 // It is safe to call on a nil Session.
 func (sess *Session) Set(key string, val interface{}) {
+	if err := sess.TrySet(key, val); err != nil {
+		_ = sess.jw.Log(fmt.Errorf("jaws: session %s: %w", sess.CookieValue(), err))
+	}
+}
+
+// TrySet is like Set, but returns an error wrapping ErrSessionQuota if the
+// value can't be stored because of Jaws.SessionMaxKeys or Jaws.SessionMaxBytes.
+// It is safe to call on a nil Session.
+func (sess *Session) TrySet(key string, val interface{}) (err error) {
 	if sess != nil {
 		sess.mu.Lock()
 		if val == nil {
-			delete(sess.data, key)
+			sess.deleteLocked(key)
 		} else {
-			sess.data[key] = val
+			err = sess.setLocked(key, val)
 		}
 		sess.mu.Unlock()
 	}
+	return
 }
 
 // ID returns the session ID, a 64-bit random value.
@@ -141,6 +161,15 @@ func (sess *Session) Cookie() (cookie *http.Cookie) {
 	return
 }
 
+// SetCookie sets the session cookie in w, as returned by Cookie().
+// Use it when Jaws.CookieDeferred is set.
+// It is safe to call on a nil Session.
+func (sess *Session) SetCookie(w http.ResponseWriter) {
+	if cookie := sess.Cookie(); cookie != nil {
+		http.SetCookie(w, cookie)
+	}
+}
+
 // Close invalidates and expires the Session.
 // Future Requests won't be able to associate with it, and Cookie() will return a deletion cookie.
 //
@@ -173,9 +202,9 @@ func (sess *Session) Reload() {
 func (sess *Session) Clear() {
 	if sess != nil {
 		sess.mu.Lock()
-		for k := range sess.data {
-			delete(sess.data, k)
-		}
+		clear(sess.data)
+		sess.entries = sess.entries[:0]
+		sess.size = 0
 		sess.mu.Unlock()
 	}
 }
@@ -1,18 +1,12 @@
 package jaws
 
 import (
-	"context"
-	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/netip"
-	"slices"
 	"strings"
 	"testing"
 	"time"
-
-	"github.com/linkdata/jaws/what"
-	"nhooyr.io/websocket"
 )
 
 func TestSession_Object(t *testing.T) {
@@ -212,170 +206,6 @@ func TestSession_Requests(t *testing.T) {
 	}
 }
 
-func TestSession_Delete(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-	go ts.jw.ServeWithTimeout(time.Second)
-
-	// the test session is there
-	sl := ts.jw.Sessions()
-	if x := len(sl); x != 1 {
-		t.Fatal(x)
-	}
-	if x := sl[0]; x != ts.sess {
-		t.Fatal(x)
-	}
-
-	// session cookie seems ok
-	cookie1 := &ts.sess.cookie
-	if cookie1 != nil {
-		if x := cookie1.Name; x != ts.jw.CookieName {
-			t.Error(x)
-		}
-	} else {
-		t.Fatal(cookie1)
-	}
-
-	// trying to get the session from another IP fails
-	hr2 := httptest.NewRequest("GET", "/", nil)
-	hr2.AddCookie(&ts.sess.cookie)
-	hr2.RemoteAddr = "10.5.6.7:89"
-	sess := ts.jw.GetSession(hr2)
-	if x := sess; x != nil {
-		t.Error(x)
-	}
-
-	// accessing from same IP but other port works
-	host, port, _ := net.SplitHostPort(ts.hr.RemoteAddr)
-	if port == "1" {
-		port = "2"
-	} else {
-		port = "1"
-	}
-	hr2.RemoteAddr = net.JoinHostPort(host, port)
-	sess = ts.jw.GetSession(hr2)
-	if x := sess; x != ts.sess {
-		t.Error(x)
-	}
-
-	rq2 := ts.jw.NewRequest(hr2)
-	if x := rq2.Session(); x != ts.sess {
-		t.Error(x)
-	}
-
-	// session should now have both requests listed
-	rl := sess.Requests()
-	if len(rl) != 2 {
-		t.Error(len(rl))
-	}
-	if !slices.Contains(rl, ts.rq) {
-		t.Errorf("%v missing from %v", ts.rq, rl)
-	}
-	if !slices.Contains(rl, rq2) {
-		t.Errorf("%v missing from %v", rq2, rl)
-	}
-
-	ts.rq.Register("byebye", func(e *Element, evt what.What, val string) error {
-		sess2 := ts.jw.GetSession(e.Request.Initial)
-		if x := sess2; x != ts.sess {
-			t.Error(x)
-		}
-		if x := sess2.cookie.MaxAge; x < 0 {
-			t.Error(x)
-		}
-
-		cookie2 := sess2.Close()
-		if x := cookie2; x == nil {
-			t.Fatal(x)
-		}
-		if x := cookie2.MaxAge; x != -1 {
-			t.Error(x)
-		}
-		if x := cookie2.Expires.IsZero(); !x {
-			t.Error(x)
-		}
-		if x := cookie2.Name; x != cookie1.Name {
-			t.Error(x)
-		}
-		if x := cookie2.Value; x != cookie1.Value {
-			t.Error(x)
-		}
-		return nil
-	})
-
-	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if x := resp.StatusCode; x != http.StatusSwitchingProtocols {
-		t.Error(x)
-	}
-	defer conn.Close(websocket.StatusNormalClosure, "")
-
-	msg := wsMsg{Jid: jidForTag(ts.rq, Tag("byebye")), What: what.Input}
-	ctx, cancel := context.WithCancel(ts.ctx)
-	defer cancel()
-
-	err = conn.Write(ctx, websocket.MessageText, msg.Append(nil))
-	if err != nil {
-		t.Fatal(err)
-	}
-	if x := ctx.Err(); x != nil {
-		t.Fatal(x)
-	}
-	if x := ts.ctx.Err(); x != nil {
-		t.Fatal(x)
-	}
-
-	type readResult struct {
-		mt  websocket.MessageType
-		b   []byte
-		err error
-	}
-
-	resultChan := make(chan readResult)
-
-	go func() {
-		var rr readResult
-		defer close(resultChan)
-		rr.mt, rr.b, rr.err = conn.Read(ctx)
-		resultChan <- rr
-	}()
-
-	if x := ts.ctx.Err(); x != nil {
-		t.Fatal(x)
-	}
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case rr, ok := <-resultChan:
-		if ok {
-			if x := rr.err; x != nil {
-				t.Fatal(x)
-			}
-			if x := ctx.Err(); x != nil {
-				t.Fatal(x)
-			}
-			if x := ts.ctx.Err(); x != nil {
-				t.Fatal(x)
-			}
-			if x := sess.cookie.MaxAge; x != -1 {
-				t.Error(x)
-			}
-			if x := rr.mt; x != websocket.MessageText {
-				t.Error(x)
-			}
-			if x := string(rr.b); x != "Reload\t\t\"\"\n" {
-				t.Error(x)
-			}
-		} else {
-			t.Error("resultChan closed")
-		}
-	}
-}
-
 func TestSession_Cleanup(t *testing.T) {
 	jw := New()
 	defer jw.Close()
@@ -511,3 +341,52 @@ func TestSession_ReplacesOld(t *testing.T) {
 		t.Error(x)
 	}
 }
+
+func TestJaws_CookieAttributes(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.CookiePath = "/app"
+	jw.CookieDomain = "example.com"
+	jw.CookieSameSite = http.SameSiteNoneMode
+	jw.CookieMaxAge = 3600
+
+	rr := httptest.NewRecorder()
+	sess := jw.NewSession(rr, httptest.NewRequest("GET", "/", nil))
+	cookies := rr.Result().Cookies()
+	th.Equal(len(cookies), 1)
+	th.Equal(cookies[0].Value, sess.CookieValue())
+	th.Equal(cookies[0].Path, "/app")
+	th.Equal(cookies[0].Domain, "example.com")
+	th.Equal(cookies[0].SameSite, http.SameSiteNoneMode)
+	th.Equal(cookies[0].MaxAge, 3600)
+	th.True(cookies[0].Secure)
+	th.True(cookies[0].HttpOnly)
+}
+
+func TestJaws_CookieDeferred(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.CookieDeferred = true
+
+	hr := httptest.NewRequest("GET", "/", nil)
+	rr := httptest.NewRecorder()
+	sess := jw.NewSession(rr, hr)
+	th.Equal(len(rr.Result().Cookies()), 0)
+	th.Equal(jw.GetSession(hr), sess)
+
+	rr = httptest.NewRecorder()
+	sess.SetCookie(rr)
+	cookies := rr.Result().Cookies()
+	th.Equal(len(cookies), 1)
+	th.Equal(cookies[0].Name, jw.CookieName)
+	th.Equal(cookies[0].Value, sess.CookieValue())
+	th.Equal(cookies[0].Path, "/")
+	th.Equal(cookies[0].SameSite, http.SameSiteLaxMode)
+
+	var nilSess *Session
+	rr = httptest.NewRecorder()
+	nilSess.SetCookie(rr)
+	th.Equal(len(rr.Result().Cookies()), 0)
+}
@@ -0,0 +1,43 @@
+package jaws
+
+// GetAs returns the value associated with the key in the Session and true
+// if there is one of type T, otherwise the zero value of T and false.
+// It is safe to call on a nil Session.
+func GetAs[T any](sess *Session, key string) (val T, ok bool) {
+	val, ok = sess.Get(key).(T)
+	return
+}
+
+// SetTyped sets a value of type T to be associated with the key in the Session.
+// It is safe to call on a nil Session.
+func SetTyped[T any](sess *Session, key string, val T) {
+	sess.Set(key, val)
+}
+
+// SessionKey is a Session key whose values are of type T, making
+// access type-safe. Declare them once, for example:
+//
+//	var CartKey = jaws.SessionKey[*Cart]("cart")
+//
+// Values set using the key can also be accessed with Session.Get
+// and Session.Set using the key string.
+type SessionKey[T any] string
+
+// Get returns the value associated with the key in the Session and true
+// if there is one of type T, otherwise the zero value of T and false.
+// It is safe to call on a nil Session.
+func (k SessionKey[T]) Get(sess *Session) (val T, ok bool) {
+	return GetAs[T](sess, string(k))
+}
+
+// Set sets the value associated with the key in the Session.
+// It is safe to call on a nil Session.
+func (k SessionKey[T]) Set(sess *Session, val T) {
+	SetTyped(sess, string(k), val)
+}
+
+// Delete removes the key from the Session.
+// It is safe to call on a nil Session.
+func (k SessionKey[T]) Delete(sess *Session) {
+	sess.Set(string(k), nil)
+}
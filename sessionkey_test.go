@@ -0,0 +1,55 @@
+package jaws
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestSession_GetAs(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	sess := newSession(jw, 0x12345, netip.Addr{})
+
+	SetTyped(sess, "n", 42)
+	n, ok := GetAs[int](sess, "n")
+	th.True(ok)
+	th.Equal(n, 42)
+	s, ok := GetAs[string](sess, "n")
+	th.Equal(ok, false)
+	th.Equal(s, "")
+	_, ok = GetAs[int](sess, "missing")
+	th.Equal(ok, false)
+
+	var nilSess *Session
+	SetTyped(nilSess, "n", 1)
+	_, ok = GetAs[int](nilSess, "n")
+	th.Equal(ok, false)
+}
+
+func TestSessionKey(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	sess := newSession(jw, 0x12345, netip.Addr{})
+
+	type cart struct{ items int }
+	key := SessionKey[*cart]("cart")
+	_, ok := key.Get(sess)
+	th.Equal(ok, false)
+
+	c := &cart{items: 2}
+	key.Set(sess, c)
+	got, ok := key.Get(sess)
+	th.True(ok)
+	th.Equal(got, c)
+	th.Equal(sess.Get("cart"), c)
+
+	sess.Set("cart", "not a cart")
+	got, ok = key.Get(sess)
+	th.Equal(ok, false)
+	th.Equal(got, (*cart)(nil))
+
+	key.Delete(sess)
+	th.Equal(sess.Get("cart"), nil)
+}
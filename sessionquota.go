@@ -0,0 +1,208 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"reflect"
+)
+
+// ErrSessionQuota is returned by Session.TrySet if storing the value would
+// exceed Jaws.SessionMaxKeys or Jaws.SessionMaxBytes and eviction is not
+// enabled, or if the value alone is larger than Jaws.SessionMaxBytes.
+var ErrSessionQuota = errors.New("session quota exceeded")
+
+// SessionSizer may be implemented by values stored in a Session to
+// report their approximate size in bytes for Jaws.SessionMaxBytes.
+//
+// Jaws.SessionMaxBytes counts the length of each key plus the size of its
+// value. Values not implementing SessionSizer count as their length if they
+// are strings or byte slices. Other values are estimated by walking them
+// with reflection, counting the in-memory size of each value plus whatever
+// it references through pointers, slices, maps, strings and interfaces,
+// counting each pointer target at most once. Nested values implementing
+// SessionSizer count as they report, except in unexported struct fields.
+// Channels and functions count only as their own size.
+type SessionSizer interface {
+	JawsSessionSize() int
+}
+
+var sessionSizerType = reflect.TypeOf((*SessionSizer)(nil)).Elem()
+
+// SessionQuotaStats counts the values that exceeded the session quotas.
+type SessionQuotaStats struct {
+	Rejected uint64 // values not stored because of the quotas
+	Evicted  uint64 // values removed to make room for newer ones
+}
+
+// SessionQuotaStats returns the number of session values rejected and
+// evicted because of Jaws.SessionMaxKeys and Jaws.SessionMaxBytes.
+func (jw *Jaws) SessionQuotaStats() SessionQuotaStats {
+	return SessionQuotaStats{
+		Rejected: jw.sessRejected.Load(),
+		Evicted:  jw.sessEvicted.Load(),
+	}
+}
+
+// sessionEntry records the approximate size of a Session value.
+type sessionEntry struct {
+	key  string
+	size int
+}
+
+func sessionValueSize(key string, val interface{}) (n int) {
+	n = len(key)
+	switch v := val.(type) {
+	case SessionSizer:
+		n += v.JawsSessionSize()
+	case string:
+		n += len(v)
+	case []byte:
+		n += len(v)
+	case template.HTML:
+		n += len(v)
+	default:
+		n += sessionSizeOf(reflect.ValueOf(val), map[uintptr]struct{}{})
+	}
+	return
+}
+
+// sessionSizeOf returns the estimated size of v including what it references.
+func sessionSizeOf(v reflect.Value, seen map[uintptr]struct{}) int {
+	if !v.IsValid() {
+		return 0
+	}
+	return int(v.Type().Size()) + sessionSizeRefs(v, seen)
+}
+
+// sessionSizeRefs returns the estimated size of what v references,
+// excluding the size of v itself.
+func sessionSizeRefs(v reflect.Value, seen map[uintptr]struct{}) (n int) {
+	if v.CanInterface() && !(v.Kind() == reflect.Pointer && v.IsNil()) {
+		if sz, ok := v.Interface().(SessionSizer); ok {
+			return sz.JawsSessionSize() - int(v.Type().Size())
+		}
+	}
+	switch v.Kind() {
+	case reflect.String:
+		n = v.Len()
+	case reflect.Interface:
+		if !v.IsNil() {
+			n = sessionSizeOf(v.Elem(), seen)
+		}
+	case reflect.Pointer:
+		if !v.IsNil() && sessionSeen(v.Pointer(), seen) {
+			n = sessionSizeOf(v.Elem(), seen)
+		}
+	case reflect.Map:
+		if !v.IsNil() && sessionSeen(v.Pointer(), seen) {
+			iter := v.MapRange()
+			for iter.Next() {
+				n += sessionSizeOf(iter.Key(), seen) + sessionSizeOf(iter.Value(), seen)
+			}
+		}
+	case reflect.Slice:
+		if v.Len() > 0 && sessionSeen(v.Pointer(), seen) {
+			n = v.Len() * int(v.Type().Elem().Size())
+			if sessionHasRefs(v.Type().Elem()) {
+				for i := 0; i < v.Len(); i++ {
+					n += sessionSizeRefs(v.Index(i), seen)
+				}
+			}
+		}
+	case reflect.Array:
+		if sessionHasRefs(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				n += sessionSizeRefs(v.Index(i), seen)
+			}
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			n += sessionSizeRefs(v.Field(i), seen)
+		}
+	}
+	return
+}
+
+// sessionSeen records p and returns true if it was not seen before.
+func sessionSeen(p uintptr, seen map[uintptr]struct{}) (isNew bool) {
+	if _, ok := seen[p]; !ok {
+		seen[p] = struct{}{}
+		isNew = true
+	}
+	return
+}
+
+// sessionHasRefs returns true if values of type t may reference other memory.
+func sessionHasRefs(t reflect.Type) bool {
+	if t.Implements(sessionSizerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+		return true
+	case reflect.Array:
+		return t.Len() > 0 && sessionHasRefs(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if sessionHasRefs(t.Field(i).Type) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (sess *Session) overQuota(keys, size int) bool {
+	jw := sess.jw
+	return (jw.SessionMaxKeys > 0 && keys > jw.SessionMaxKeys) ||
+		(jw.SessionMaxBytes > 0 && size > jw.SessionMaxBytes)
+}
+
+func (sess *Session) deleteLocked(key string) {
+	for i := range sess.entries {
+		if sess.entries[i].key == key {
+			sess.size -= sess.entries[i].size
+			sess.entries = append(sess.entries[:i], sess.entries[i+1:]...)
+			break
+		}
+	}
+	delete(sess.data, key)
+}
+
+// setLocked stores the value, evicting the least recently set values if
+// needed and enabled, and returns ErrSessionQuota if it can't be stored.
+func (sess *Session) setLocked(key string, val interface{}) error {
+	jw := sess.jw
+	size := sessionValueSize(key, val)
+	keys := len(sess.entries) + 1
+	total := sess.size + size
+	for _, e := range sess.entries {
+		if e.key == key {
+			keys--
+			total -= e.size
+			break
+		}
+	}
+	if sess.overQuota(keys, total) {
+		if !jw.SessionEvict || sess.overQuota(1, size) {
+			jw.sessRejected.Add(1)
+			return fmt.Errorf("%w: %q", ErrSessionQuota, key)
+		}
+		for i := 0; sess.overQuota(keys, total); {
+			if e := sess.entries[i]; e.key != key {
+				sess.deleteLocked(e.key)
+				keys--
+				total -= e.size
+				jw.sessEvicted.Add(1)
+			} else {
+				i++
+			}
+		}
+	}
+	sess.deleteLocked(key)
+	sess.entries = append(sess.entries, sessionEntry{key: key, size: size})
+	sess.size += size
+	sess.data[key] = val
+	return nil
+}
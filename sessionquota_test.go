@@ -0,0 +1,101 @@
+package jaws
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+type testSessionSizer int
+
+func (s testSessionSizer) JawsSessionSize() int {
+	return int(s)
+}
+
+func Test_sessionValueSize(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(sessionValueSize("k", "abc"), 4)
+	th.Equal(sessionValueSize("k", []byte("ab")), 3)
+	th.Equal(sessionValueSize("k", testSessionSizer(100)), 101)
+	th.Equal(sessionValueSize("k", int64(1)), 9)
+	th.Equal(sessionValueSize("k", nil), 1)
+
+	big := strings.Repeat("x", 1000)
+	th.True(sessionValueSize("k", []string{big}) > 1000)
+	th.True(sessionValueSize("k", map[string]int{big: 1}) > 1000)
+	th.True(sessionValueSize("k", &struct{ s string }{big}) > 1000)
+	th.True(sessionValueSize("k", [2]interface{}{1, big}) > 1000)
+	th.True(sessionValueSize("k", []testSessionSizer{1000}) >= 1000)
+	th.True(sessionValueSize("k", map[string]testSessionSizer{"a": 1000}) > 1000)
+
+	type node struct {
+		next *node
+		data []byte
+	}
+	n := &node{data: make([]byte, 1000)}
+	n.next = n
+	sz := sessionValueSize("k", n)
+	th.True(sz > 1000 && sz < 2000)
+	th.True(sessionValueSize("k", []*node{n, n}) < 2000)
+}
+
+func TestSession_MaxKeys(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.SessionMaxKeys = 2
+	sess := newSession(jw, 0x12345, netip.Addr{})
+
+	th.NoErr(sess.TrySet("a", 1))
+	th.NoErr(sess.TrySet("b", 2))
+	th.NoErr(sess.TrySet("a", 3))
+	err := sess.TrySet("c", 4)
+	th.True(errors.Is(err, ErrSessionQuota))
+	th.True(strings.Contains(err.Error(), `"c"`))
+	th.Equal(sess.Get("a"), 3)
+	th.Equal(sess.Get("c"), nil)
+	th.Equal(jw.SessionQuotaStats(), SessionQuotaStats{Rejected: 1})
+
+	jw.SessionEvict = true
+	th.NoErr(sess.TrySet("c", 4))
+	th.Equal(sess.Get("b"), nil)
+	th.Equal(sess.Get("a"), 3)
+	th.Equal(sess.Get("c"), 4)
+	th.Equal(jw.SessionQuotaStats(), SessionQuotaStats{Rejected: 1, Evicted: 1})
+
+	sess.Set("a", nil)
+	th.NoErr(sess.TrySet("d", 5))
+	th.Equal(jw.SessionQuotaStats().Evicted, uint64(1))
+
+	sess.Clear()
+	th.Equal(len(sess.entries), 0)
+	th.Equal(sess.size, 0)
+}
+
+func TestSession_MaxBytes(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	tj.SessionMaxBytes = 10
+	sess := newSession(tj.Jaws, 0x12345, netip.Addr{})
+
+	sess.Set("a", "1234")
+	sess.Set("b", "1234")
+	th.Equal(sess.size, 10)
+	sess.Set("c", "1")
+	th.Equal(sess.Get("c"), nil)
+	th.True(strings.Contains(tj.log.String(), ErrSessionQuota.Error()))
+	sess.Set("a", "12")
+	th.Equal(sess.size, 8)
+
+	tj.SessionEvict = true
+	th.True(errors.Is(sess.TrySet("x", testSessionSizer(10)), ErrSessionQuota))
+	th.Equal(sess.Get("a"), "12")
+	th.NoErr(sess.TrySet("c", "12345"))
+	th.Equal(sess.Get("b"), nil)
+	th.Equal(sess.Get("a"), "12")
+	th.Equal(sess.Get("c"), "12345")
+	th.Equal(sess.size, 9)
+	th.Equal(tj.SessionQuotaStats(), SessionQuotaStats{Rejected: 2, Evicted: 1})
+}
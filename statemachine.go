@@ -0,0 +1,175 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// ErrInvalidTransition is returned when an event is not allowed in the current state.
+var ErrInvalidTransition = errors.New("invalid state transition")
+
+// Transition declares that Event moves a StateMachine from the From state
+// to the To state, if Guard is nil or returns true.
+type Transition struct {
+	From  string
+	Event string
+	To    string
+	Guard func(rq *Request) bool // rq is nil if the event was fired by server code
+}
+
+// StateMachine is a finite state machine whose state is shared by all
+// Elements bound to it, for example an order status.
+//
+// Use it as a tag to have Elements updated when the state changes.
+type StateMachine struct {
+	jw           *Jaws
+	Transitions  []Transition                              // (read-only) allowed transitions
+	OnTransition func(rq *Request, from, event, to string) // (read-only) if not nil, called after each transition
+	mu           deadlock.RWMutex
+	state        string
+}
+
+// NewStateMachine returns a new StateMachine in the initial state.
+func NewStateMachine(jw *Jaws, initial string, transitions ...Transition) *StateMachine {
+	return &StateMachine{jw: jw, Transitions: transitions, state: initial}
+}
+
+// State returns the current state.
+func (sm *StateMachine) State() (state string) {
+	sm.mu.RLock()
+	state = sm.state
+	sm.mu.RUnlock()
+	return
+}
+
+func (sm *StateMachine) findLocked(rq *Request, event string) (t *Transition) {
+	for i := range sm.Transitions {
+		if t = &sm.Transitions[i]; t.From == sm.state && t.Event == event {
+			if t.Guard == nil || t.Guard(rq) {
+				return
+			}
+		}
+	}
+	return nil
+}
+
+// Can returns true if the event is allowed in the current state.
+func (sm *StateMachine) Can(rq *Request, event string) (yes bool) {
+	sm.mu.RLock()
+	yes = sm.findLocked(rq, event) != nil
+	sm.mu.RUnlock()
+	return
+}
+
+// Events returns the events allowed in the current state.
+func (sm *StateMachine) Events(rq *Request) (events []string) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	for _, t := range sm.Transitions {
+		if t.From == sm.state && (t.Guard == nil || t.Guard(rq)) {
+			events = append(events, t.Event)
+		}
+	}
+	return
+}
+
+// Fire performs the transition for the event, updating the Elements
+// tagged with the StateMachine. Returns ErrInvalidTransition if the
+// event is not allowed in the current state.
+//
+// The rq argument is passed to guards and OnTransition, and may be nil.
+func (sm *StateMachine) Fire(rq *Request, event string) (err error) {
+	sm.mu.Lock()
+	from := sm.state
+	t := sm.findLocked(rq, event)
+	if t != nil {
+		sm.state = t.To
+	}
+	sm.mu.Unlock()
+	if t == nil {
+		return fmt.Errorf("%w: %q in state %q", ErrInvalidTransition, event, from)
+	}
+	if sm.OnTransition != nil {
+		sm.OnTransition(rq, from, event, t.To)
+	}
+	sm.jw.Dirty(sm)
+	return
+}
+
+// UiStateMachine renders the view for the current state of a StateMachine.
+// Click events for the Element fire the StateMachine event named by the
+// clicked HTML element's name attribute.
+type UiStateMachine struct {
+	UiHtml
+	Machine *StateMachine
+	Views   map[string]UI // view for each state, missing states render nothing
+	mu      deadlock.Mutex
+	shown   string   // state of the view in child
+	child   *Element // Element of the currently shown view
+}
+
+func NewUiStateMachine(sm *StateMachine, views map[string]UI) *UiStateMachine {
+	return &UiStateMachine{Machine: sm, Views: views}
+}
+
+// renderChild creates a new Element for the view of the given state and renders it.
+// Returns the previous child Element, if any.
+func (ui *UiStateMachine) renderChild(e *Element, w io.Writer, state string) (old *Element, err error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	old = ui.child
+	ui.child = nil
+	ui.shown = state
+	if view, ok := ui.Views[state]; ok {
+		ui.child = e.Request.NewElement(view)
+		err = ui.child.Render(w, nil)
+	}
+	return
+}
+
+func (ui *UiStateMachine) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	e.Tag(ui.Machine)
+	state := ui.Machine.State()
+	attrs := append(ui.parseParams(e, params), `data-jaws-state="`+html.EscapeString(state)+`"`)
+	var sb strings.Builder
+	if _, err = ui.renderChild(e, &sb, state); err == nil {
+		err = WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(sb.String()), attrs...) // #nosec G203
+	}
+	return
+}
+
+func (ui *UiStateMachine) JawsUpdate(e *Element) {
+	state := ui.Machine.State()
+	ui.mu.Lock()
+	same := ui.shown == state
+	ui.mu.Unlock()
+	if !same {
+		var sb strings.Builder
+		old, err := ui.renderChild(e, &sb, state)
+		maybePanic(err)
+		if old != nil {
+			e.Request.deleteElement(old)
+		}
+		e.SetAttr("data-jaws-state", state)
+		e.SetInner(template.HTML(sb.String())) // #nosec G203
+	}
+}
+
+func (ui *UiStateMachine) JawsEvent(e *Element, wht what.What, val string) error {
+	if wht == what.Click {
+		return ui.Machine.Fire(e.Request, val)
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+// StateMachine renders a HTML div element showing the view for the current state of sm.
+func (rq RequestWriter) StateMachine(sm *StateMachine, views map[string]UI, params ...interface{}) error {
+	return rq.UI(NewUiStateMachine(sm, views), params...)
+}
@@ -0,0 +1,84 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func newTestOrderMachine(jw *Jaws, paid *bool) *StateMachine {
+	return NewStateMachine(jw, "new",
+		Transition{From: "new", Event: "pay", To: "paid"},
+		Transition{From: "paid", Event: "ship", To: "shipped", Guard: func(rq *Request) bool { return *paid }},
+		Transition{From: "new", Event: "cancel", To: "cancelled"},
+	)
+}
+
+func TestStateMachine_Fire(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+
+	var paid bool
+	var log []string
+	sm := newTestOrderMachine(jw, &paid)
+	sm.OnTransition = func(rq *Request, from, event, to string) {
+		log = append(log, from+"-"+event+"-"+to)
+	}
+	th.Equal(sm.State(), "new")
+	th.Equal(sm.Events(nil), []string{"pay", "cancel"})
+	th.True(sm.Can(nil, "pay"))
+	th.True(!sm.Can(nil, "ship"))
+
+	err := sm.Fire(nil, "ship")
+	th.True(errors.Is(err, ErrInvalidTransition))
+	th.Equal(sm.State(), "new")
+
+	th.NoErr(sm.Fire(nil, "pay"))
+	th.Equal(sm.State(), "paid")
+	th.Equal(sm.Events(nil), []string(nil))
+	th.True(errors.Is(sm.Fire(nil, "ship"), ErrInvalidTransition))
+	paid = true
+	th.Equal(sm.Events(nil), []string{"ship"})
+	th.NoErr(sm.Fire(nil, "ship"))
+	th.Equal(sm.State(), "shipped")
+	th.Equal(log, []string{"new-pay-paid", "paid-ship-shipped"})
+}
+
+func TestRequest_StateMachine(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	sm := newTestOrderMachine(rq.Jaws, new(bool))
+	views := map[string]UI{
+		"new":  NewUiSpan(makeHtmlGetter(`<button name="pay">Pay</button>`)),
+		"paid": NewUiSpan(makeHtmlGetter("paid")),
+	}
+	th.NoErr(rq.StateMachine(sm, views, "hidden"))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" hidden data-jaws-state="new"><span id="Jid.2"><button name="pay">Pay</button></span></div>`)
+
+	// invalid events are rejected
+	rq.inCh <- wsMsg{Data: "ship\tJid.2\tJid.1", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.True(strings.HasPrefix(s, "Alert\t\t\"danger\\n"))
+		th.True(strings.Contains(s, ErrInvalidTransition.Error()))
+	}
+
+	rq.inCh <- wsMsg{Data: "pay\tJid.2\tJid.1", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "SAttr\tJid.1\t\"data-jaws-state\\npaid\"\n"+
+			"Inner\tJid.1\t\"<span id=\\\"Jid.3\\\">paid</span>\"\n")
+	}
+	th.Equal(sm.State(), "paid")
+	th.Equal(rq.getElementByJid(2), (*Element)(nil))
+}
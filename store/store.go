@@ -0,0 +1,125 @@
+// Package store provides a typed state container for JaWS.
+//
+// UI elements bind to Selectors, which pick a value out of the state.
+// Mutations made through the Store dirty exactly the Selectors whose
+// selected value changed, so only the affected elements are updated.
+//
+//	type AppState struct{ User string; Cart []Item }
+//
+//	var st = store.New(jw, AppState{})
+//	var userName = store.Select(st, func(s AppState) string { return s.User })
+//
+//	rw.Span(userName)                                  // in a template or handler
+//	st.Update(func(s *AppState) { s.User = "alice" }) // updates the span
+package store
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws"
+)
+
+// Store holds a state value of type S.
+type Store[S any] struct {
+	jw    *jaws.Jaws
+	mu    deadlock.RWMutex // protects following
+	state S
+	sels  []selector[S]
+}
+
+// selector is the part of a Selector that doesn't depend on it's value type.
+type selector[S any] interface {
+	// refresh recomputes the selected value from the state and returns
+	// true if it changed.
+	refresh(state S) bool
+}
+
+// New returns a new Store with the given initial state. Changes are
+// signalled using jw.Dirty().
+func New[S any](jw *jaws.Jaws, initial S) *Store[S] {
+	return &Store[S]{jw: jw, state: initial}
+}
+
+// Get returns the current state.
+func (st *Store[S]) Get() (state S) {
+	st.mu.RLock()
+	state = st.state
+	st.mu.RUnlock()
+	return
+}
+
+// Update calls fn to mutate the state, then dirties and returns the
+// Selectors whose selected value changed.
+//
+// Since Get returns a shallow copy, fn must not modify data shared
+// with previous states (such as slice elements) in place.
+func (st *Store[S]) Update(fn func(state *S)) (changed []interface{}) {
+	st.mu.Lock()
+	fn(&st.state)
+	for _, sel := range st.sels {
+		if sel.refresh(st.state) {
+			changed = append(changed, sel)
+		}
+	}
+	st.mu.Unlock()
+	if len(changed) > 0 {
+		st.jw.Dirty(changed...)
+	}
+	return
+}
+
+// Set replaces the state, returning the Selectors that changed.
+func (st *Store[S]) Set(state S) []interface{} {
+	return st.Update(func(s *S) { *s = state })
+}
+
+// Selector selects a value of type T from a Store's state.
+//
+// It is a tag that is dirtied when the selected value changes,
+// and a jaws.HtmlGetter rendering the value as escaped text.
+type Selector[S, T any] struct {
+	store *Store[S]
+	fn    func(state S) T
+	eq    func(a, b T) bool
+	value T // protected by store.mu
+}
+
+// Select registers and returns a new Selector for the Store,
+// comparing selected values using ==.
+func Select[S any, T comparable](st *Store[S], fn func(state S) T) *Selector[S, T] {
+	return SelectFunc(st, fn, func(a, b T) bool { return a == b })
+}
+
+// SelectFunc registers and returns a new Selector for the Store,
+// comparing selected values using eq.
+func SelectFunc[S, T any](st *Store[S], fn func(state S) T, eq func(a, b T) bool) (sel *Selector[S, T]) {
+	sel = &Selector[S, T]{store: st, fn: fn, eq: eq}
+	st.mu.Lock()
+	sel.value = fn(st.state)
+	st.sels = append(st.sels, sel)
+	st.mu.Unlock()
+	return
+}
+
+func (sel *Selector[S, T]) refresh(state S) (changed bool) {
+	v := sel.fn(state)
+	if changed = !sel.eq(sel.value, v); changed {
+		sel.value = v
+	}
+	return
+}
+
+// Get returns the selected value.
+func (sel *Selector[S, T]) Get() (v T) {
+	sel.store.mu.RLock()
+	v = sel.value
+	sel.store.mu.RUnlock()
+	return
+}
+
+func (sel *Selector[S, T]) JawsGetHtml(e *jaws.Element) template.HTML {
+	return template.HTML(html.EscapeString(fmt.Sprint(sel.Get()))) // #nosec G203
+}
@@ -0,0 +1,80 @@
+package store_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws"
+	"github.com/linkdata/jaws/store"
+)
+
+type testState struct {
+	User  string
+	Items []string
+	Count int
+}
+
+func TestStore_Update(t *testing.T) {
+	jw := jaws.New()
+	defer jw.Close()
+
+	st := store.New(jw, testState{User: "bob"})
+	user := store.Select(st, func(s testState) string { return s.User })
+	count := store.Select(st, func(s testState) int { return s.Count })
+	items := store.SelectFunc(st, func(s testState) []string { return s.Items }, slices.Equal[[]string])
+
+	if got := user.Get(); got != "bob" {
+		t.Error(got)
+	}
+
+	changed := st.Update(func(s *testState) { s.Count++ })
+	if len(changed) != 1 || changed[0] != count {
+		t.Error(changed)
+	}
+	if got := count.Get(); got != 1 {
+		t.Error(got)
+	}
+
+	changed = st.Update(func(s *testState) { s.User = "bob" })
+	if len(changed) != 0 {
+		t.Error(changed)
+	}
+
+	changed = st.Update(func(s *testState) { s.Items = []string{"a"} })
+	if len(changed) != 1 || changed[0] != items {
+		t.Error(changed)
+	}
+	changed = st.Update(func(s *testState) { s.Items = []string{"a"} })
+	if len(changed) != 0 {
+		t.Error(changed)
+	}
+
+	changed = st.Set(testState{User: "alice", Items: []string{"a"}})
+	if len(changed) != 2 || changed[0] != user || changed[1] != count {
+		t.Error(changed)
+	}
+	if got := st.Get(); got.User != "alice" || got.Count != 0 {
+		t.Error(got)
+	}
+}
+
+func TestSelector_Render(t *testing.T) {
+	jw := jaws.New()
+	defer jw.Close()
+
+	st := store.New(jw, testState{User: "<bob>"})
+	user := store.Select(st, func(s testState) string { return s.User })
+
+	rq := jw.NewRequest(nil)
+	var sb strings.Builder
+	if err := rq.Writer(&sb).Span(user); err != nil {
+		t.Fatal(err)
+	}
+	if got := sb.String(); !strings.HasSuffix(got, `>&lt;bob&gt;</span>`) {
+		t.Error(got)
+	}
+	if elems := rq.GetElements(user); len(elems) != 1 {
+		t.Error(elems)
+	}
+}
@@ -0,0 +1,40 @@
+package jaws
+
+import (
+	"fmt"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// maxUnhandledValLen is the maximum length of the event value logged for unhandled events.
+const maxUnhandledValLen = 64
+
+// Handled marks the event currently being handled as consumed.
+//
+// Call it from JawsEvent if the Element acted on the event but still returns
+// ErrEventUnhandled to let other handlers see it. It only matters if
+// Jaws.StrictEvents is set.
+func (e *Element) Handled() {
+	e.handled = true
+}
+
+// unhandledEvent logs input events that reached Elements but that no
+// handler consumed if Jaws.StrictEvents is set, as that usually means an
+// UI is missing it's handler. If Jaws.Debug is also set, the error is returned.
+func (rq *Request) unhandledEvent(elems []*Element, wht what.What, val string) (err error) {
+	if rq.Jaws.StrictEvents && len(elems) > 0 {
+		switch wht {
+		case what.Input, what.Change, what.Key, what.Click, what.Paste, what.Capture, what.Custom:
+			val = redactValue(val, elems...)
+			if len(val) > maxUnhandledValLen {
+				val = val[:maxUnhandledValLen] + "…"
+			}
+			err = fmt.Errorf("jaws: %v: %w: %v %q for %v", rq, ErrEventUnhandled, wht, val, elems)
+			_ = rq.Jaws.Log(err)
+			if !rq.Jaws.Debug {
+				err = nil
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,39 @@
+package jaws
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestJaws_StrictEvents(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	tj.StrictEvents = true
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	span := rq.NewElement(NewUiSpan(makeHtmlGetter("x")))
+	text := rq.NewElement(NewUiText(newTestSetter("")))
+
+	th.NoErr(rq.callAllEventHandlers(text.jid, what.Input, "foo"))
+	th.Equal(tj.log.String(), "")
+
+	th.NoErr(rq.callAllEventHandlers(span.jid, what.Update, ""))
+	th.Equal(tj.log.String(), "")
+
+	th.NoErr(rq.callAllEventHandlers(span.jid, what.Click, "btn"))
+	th.True(strings.Contains(tj.log.String(), ErrEventUnhandled.Error()))
+	th.True(strings.Contains(tj.log.String(), `Click "btn"`))
+
+	tj.log.Reset()
+	th.NoErr(rq.callAllEventHandlers(text.jid, what.Click, strings.Repeat("x", 100)))
+	th.True(strings.Contains(tj.log.String(), strings.Repeat("x", maxUnhandledValLen)+"…"))
+	th.Equal(strings.Contains(tj.log.String(), strings.Repeat("x", maxUnhandledValLen+1)), false)
+
+	tj.Debug = true
+	err := rq.callAllEventHandlers(span.jid, what.Input, "bar")
+	th.True(errors.Is(err, ErrEventUnhandled))
+}
@@ -1,6 +1,56 @@
 package jaws
 
+import (
+	"slices"
+	"time"
+)
+
 type subscription struct {
-	msgCh chan Message
-	rq    *Request
+	msgCh   chan Message
+	rq      *Request
+	backlog []Message // broadcasts waiting for room in msgCh, see Jaws.Overflow
+	since   time.Time // when the backlog was last sent from or started
+}
+
+// send queues msg for the subscribed Request, applying policy if msgCh
+// is full. The broadcast loop must never wait, so OverflowBlock keeps msg
+// in the backlog like OverflowGrow, and flush enforces the wait.
+// Returns false if the Request must be disconnected.
+func (sub *subscription) send(msg Message, policy OverflowPolicy, now time.Time) bool {
+	if policy == OverflowBlock {
+		policy = OverflowGrow
+	}
+	hadBacklog := len(sub.backlog) > 0
+	dropped, ok := overflowSend(sub.msgCh, msg, policy, 0, nil, &sub.backlog)
+	sub.rq.sendCount.dropped.Add(uint64(dropped))
+	if !hadBacklog && len(sub.backlog) > 0 {
+		sub.since = now
+	}
+	sub.rq.sendCount.bcastBacklog.Store(int64(len(sub.backlog)))
+	return ok
+}
+
+// flush sends as much of the backlog as there is room for. Returns false
+// if the policy is OverflowBlock and nothing could be sent for wait.
+func (sub *subscription) flush(policy OverflowPolicy, wait time.Duration, now time.Time) bool {
+	sent := 0
+loop:
+	for _, msg := range sub.backlog {
+		select {
+		case sub.msgCh <- msg:
+			sent++
+		default:
+			break loop
+		}
+	}
+	if sent > 0 {
+		sub.backlog = slices.Delete(sub.backlog, 0, sent)
+		sub.since = now
+	}
+	sub.rq.sendCount.bcastBacklog.Store(int64(len(sub.backlog)))
+	if len(sub.backlog) == 0 {
+		sub.backlog = nil
+		return true
+	}
+	return policy != OverflowBlock || now.Sub(sub.since) < wait
 }
@@ -0,0 +1,177 @@
+package jaws
+
+import (
+	"fmt"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// TabPolicy selects what happens when a Session has more than one browser
+// tab showing the same view. It is set using Jaws.Tabs or RequestOptions.Tabs.
+//
+// Tabs that aren't allowed to be used show a banner with Jaws.TabsText,
+// become inert and their input events are ignored, but they are still
+// updated. If the tab in use is closed, the most recently opened of the
+// others can be used again.
+type TabPolicy int8
+
+const (
+	// TabsAllow lets all tabs be used. This is the default.
+	TabsAllow TabPolicy = iota
+	// TabsMirror lets only the first tab be used, later ones mirror it.
+	TabsMirror
+	// TabsTakeOver lets only the last tab be used, earlier ones mirror it.
+	TabsTakeOver
+)
+
+// DefaultTabsText is the banner shown in tabs that can't be used if Jaws.TabsText isn't set.
+const DefaultTabsText = "This page is open in another tab."
+
+func (tp TabPolicy) String() string {
+	switch tp {
+	case TabsAllow:
+		return "TabsAllow"
+	case TabsMirror:
+		return "TabsMirror"
+	case TabsTakeOver:
+		return "TabsTakeOver"
+	}
+	return fmt.Sprintf("TabPolicy(%d)", int8(tp))
+}
+
+// Inert returns true if the Request's tab can't be used because of Jaws.Tabs,
+// or because it is mirroring another Request.
+func (rq *Request) Inert() (yes bool) {
+	rq.mu.RLock()
+	yes = rq.inert
+	rq.mu.RUnlock()
+	return
+}
+
+// View returns the name of the view shown by the Request, which is
+// RequestOptions.View if set, otherwise the path of the initial HTTP request.
+func (rq *Request) View() string {
+	if opts := rq.Options(); opts != nil && opts.View != "" {
+		return opts.View
+	}
+	if rq.Initial != nil && rq.Initial.URL != nil {
+		return rq.Initial.URL.Path
+	}
+	return ""
+}
+
+func (rq *Request) tabPolicy() TabPolicy {
+	if opts := rq.Options(); opts != nil && opts.Tabs != TabsAllow {
+		return opts.Tabs
+	}
+	return rq.Jaws.Tabs
+}
+
+func (jw *Jaws) tabsText() string {
+	if jw.TabsText != "" {
+		return jw.TabsText
+	}
+	return DefaultTabsText
+}
+
+// setInert marks the Request as inert or not and tells the browser.
+func (rq *Request) setInert(inert bool) {
+	rq.mu.Lock()
+	rq.inert = inert
+	rq.mu.Unlock()
+	var text string
+	if inert {
+		text = rq.Jaws.tabsText()
+	}
+	rq.Jaws.Broadcast(Message{Dest: rq, What: what.Inert, Data: text})
+}
+
+// sameViewTabs returns the other connected Requests of the Session showing
+// the same view, and the number of those that are in use. Requests waiting
+// for their browser to reconnect count as connected.
+func (rq *Request) sameViewTabs() (tabs []*Request, active int) {
+	view := rq.View()
+	for _, other := range rq.session.Requests() {
+		if other != rq && other.View() == view {
+			other.mu.RLock()
+			connected := other.running || !other.lost.IsZero()
+			inert := other.inert
+			other.mu.RUnlock()
+			if connected {
+				tabs = append(tabs, other)
+				if !inert {
+					active++
+				}
+			}
+		}
+	}
+	return
+}
+
+// lockTabs locks the Request's Session so that the tabs in use can be
+// counted and changed without others doing the same in between.
+// Returns the Session to pass to unlockTabs, which may be nil.
+func (rq *Request) lockTabs() (sess *Session) {
+	if sess = rq.Session(); sess != nil {
+		sess.tabsMu.Lock()
+	}
+	return
+}
+
+func (sess *Session) unlockTabs() {
+	if sess != nil {
+		sess.tabsMu.Unlock()
+	}
+}
+
+// checkTabs applies Jaws.Tabs when the Request's browser tab connects.
+// If the browser reconnected, it is told if the tab is still inert.
+func (rq *Request) checkTabs(resumed bool) {
+	if resumed {
+		if rq.Inert() {
+			rq.setInert(true)
+		}
+		return
+	}
+	defer rq.lockTabs().unlockTabs()
+	switch rq.tabPolicy() {
+	case TabsMirror:
+		if _, active := rq.sameViewTabs(); active > 0 {
+			rq.setInert(true)
+		}
+	case TabsTakeOver:
+		tabs, _ := rq.sameViewTabs()
+		for _, other := range tabs {
+			if !other.Inert() {
+				other.setInert(true)
+			}
+		}
+		if rq.Inert() {
+			// taken over by a tab that connected at the same time
+			rq.setInert(false)
+		}
+	}
+}
+
+// releaseTab lets the most recently created of the Request's inert tabs
+// showing the same view be used if the Request was the one in use.
+func (rq *Request) releaseTab() {
+	if rq.tabPolicy() == TabsAllow {
+		return
+	}
+	defer rq.lockTabs().unlockTabs()
+	if !rq.Inert() {
+		tabs, active := rq.sameViewTabs()
+		if active == 0 {
+			var newest *Request
+			for _, other := range tabs {
+				if newest == nil || other.Created.After(newest.Created) {
+					newest = other
+				}
+			}
+			if newest != nil {
+				newest.setInert(false)
+			}
+		}
+	}
+}
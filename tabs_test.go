@@ -0,0 +1,177 @@
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestTabPolicy_String(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(TabsAllow.String(), "TabsAllow")
+	th.Equal(TabsMirror.String(), "TabsMirror")
+	th.Equal(TabsTakeOver.String(), "TabsTakeOver")
+	th.Equal(TabPolicy(-1).String(), "TabPolicy(-1)")
+}
+
+func TestRequest_View(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq := jw.NewRequest(httptest.NewRequest(http.MethodGet, "/edit?id=1", nil))
+	th.Equal(rq.View(), "/edit")
+	rq = jw.NewRequestWithOptions(httptest.NewRequest(http.MethodGet, "/edit?id=1", nil), &RequestOptions{View: "edit-1"})
+	th.Equal(rq.View(), "edit-1")
+	rq = jw.NewRequest(nil)
+	th.Equal(rq.View(), "")
+}
+
+func TestRequest_checkTabs(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	go jw.Serve()
+
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	sess := jw.NewSession(nil, hr)
+	newTab := func(path string) *Request {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		r.AddCookie(&sess.cookie)
+		rq := jw.NewRequest(r)
+		th.Equal(rq.Session(), sess)
+		rq.mu.Lock()
+		rq.running = true
+		rq.mu.Unlock()
+		time.Sleep(time.Millisecond) // Created differs
+		return rq
+	}
+
+	jw.Tabs = TabsMirror
+	rq1 := newTab("/")
+	rq1.checkTabs(false)
+	th.Equal(rq1.Inert(), false)
+	rq2 := newTab("/")
+	rq2.checkTabs(false)
+	th.Equal(rq1.Inert(), false)
+	th.Equal(rq2.Inert(), true)
+	other := newTab("/other")
+	other.checkTabs(false)
+	th.Equal(other.Inert(), false)
+
+	jw.Tabs = TabsTakeOver
+	rq3 := newTab("/")
+	rq3.checkTabs(false)
+	th.Equal(rq1.Inert(), true)
+	th.Equal(rq2.Inert(), true)
+	th.Equal(rq3.Inert(), false)
+	th.Equal(other.Inert(), false)
+
+	rq3.releaseTab()
+	th.Equal(rq2.Inert(), false)
+	th.Equal(rq1.Inert(), true)
+
+	rq1.checkTabs(true)
+	th.Equal(rq1.Inert(), true)
+
+	opts := &RequestOptions{Tabs: TabsAllow}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&sess.cookie)
+	rq4 := jw.NewRequestWithOptions(r, opts)
+	th.Equal(rq4.tabPolicy(), TabsTakeOver)
+	opts.Tabs = TabsMirror
+	th.Equal(rq4.tabPolicy(), TabsMirror)
+}
+
+func TestRequest_checkTabsConcurrent(t *testing.T) {
+	const numTabs = 20
+	for _, policy := range []TabPolicy{TabsMirror, TabsTakeOver} {
+		th := newTestHelper(t)
+		jw := New()
+		go jw.Serve()
+		jw.Tabs = policy
+
+		hr := httptest.NewRequest(http.MethodGet, "/", nil)
+		sess := jw.NewSession(nil, hr)
+		var tabs []*Request
+		for i := 0; i < numTabs; i++ {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.AddCookie(&sess.cookie)
+			rq := jw.NewRequest(r)
+			rq.mu.Lock()
+			rq.running = true
+			rq.mu.Unlock()
+			tabs = append(tabs, rq)
+		}
+
+		var wg sync.WaitGroup
+		for _, rq := range tabs {
+			wg.Add(1)
+			go func(rq *Request) {
+				defer wg.Done()
+				rq.checkTabs(false)
+			}(rq)
+		}
+		wg.Wait()
+
+		active := 0
+		for _, rq := range tabs {
+			if !rq.Inert() {
+				active++
+			}
+		}
+		th.Equal(active, 1)
+		jw.Close()
+	}
+}
+
+func TestRequest_InertIgnoresInput(t *testing.T) {
+	th := newTestHelper(t)
+	rq := newTestRequest()
+	defer rq.Close()
+
+	var called int32
+	rq.Register(Tag("foo"), func(e *Element, evt what.What, val string) error {
+		atomic.AddInt32(&called, 1)
+		return nil
+	})
+	id := jidForTag(rq.Request, Tag("foo"))
+
+	rq.setInert(true)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, (&wsMsg{What: what.Inert, Data: DefaultTabsText}).Format())
+	}
+	th.True(rq.Inert())
+
+	rq.inCh <- wsMsg{Jid: id, What: what.Input, Data: "x"}
+	rq.setInert(true) // handled after the input
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-rq.outCh:
+	}
+	rq.setInert(false)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, (&wsMsg{What: what.Inert}).Format())
+	}
+	th.Equal(atomic.LoadInt32(&called), int32(0))
+
+	rq.inCh <- wsMsg{Jid: id, What: what.Input, Data: "x"}
+	for atomic.LoadInt32(&called) == 0 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+}
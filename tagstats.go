@@ -0,0 +1,119 @@
+package jaws
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// TagStats is how often a tag was dirtied and what that cost, as returned by Jaws.TagStats.
+type TagStats struct {
+	Tag      interface{} // the tag
+	Dirtied  uint64      // times the tag was dirtied
+	Updates  uint64      // times the tag was sent to the Requests, several Dirtied between updates count once
+	Requests uint64      // Requests that had Elements with the tag, summed over all updates
+	Elements uint64      // Elements with the tag, summed over all updates
+}
+
+// String returns the statistics in a form suitable for debug output.
+func (ts TagStats) String() string {
+	return fmt.Sprintf("%s: dirtied %d, updates %d, requests %d, elements %d",
+		TagString(ts.Tag), ts.Dirtied, ts.Updates, ts.Requests, ts.Elements)
+}
+
+// maxTagStats is the number of tags Jaws.TagStats keeps statistics for.
+const maxTagStats = 4096
+
+// compareTagStats orders the tags that updated the most Elements first.
+func compareTagStats(a, b *TagStats) int {
+	if c := cmp.Compare(b.Elements, a.Elements); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Dirtied, a.Dirtied); c != 0 {
+		return c
+	}
+	return strings.Compare(TagString(a.Tag), TagString(b.Tag))
+}
+
+// TagStats returns the statistics recorded for each tag while TrackTags is
+// set, the ones that updated the most Elements first. Tags that are dirtied
+// often or update many Elements are candidates for splitting or throttling.
+//
+// Statistics are kept for at most maxTagStats tags. When more are seen,
+// those for the half that updated the fewest Elements are dropped, so
+// tags that are only used briefly may be missing or undercounted.
+func (jw *Jaws) TagStats() (stats []TagStats) {
+	jw.mu.RLock()
+	stats = make([]TagStats, 0, len(jw.tagStats))
+	for _, ts := range jw.tagStats {
+		stats = append(stats, *ts)
+	}
+	jw.mu.RUnlock()
+	slices.SortFunc(stats, func(a, b TagStats) int { return compareTagStats(&a, &b) })
+	return
+}
+
+// ResetTagStats forgets the statistics recorded for all tags.
+func (jw *Jaws) ResetTagStats() {
+	jw.mu.Lock()
+	jw.tagStats = nil
+	jw.mu.Unlock()
+}
+
+func (jw *Jaws) tagStatsLocked(tag interface{}) (ts *TagStats) {
+	if ts = jw.tagStats[tag]; ts == nil {
+		if jw.tagStats == nil {
+			jw.tagStats = make(map[interface{}]*TagStats)
+		}
+		if len(jw.tagStats) >= maxTagStats {
+			jw.pruneTagStatsLocked()
+		}
+		ts = &TagStats{Tag: tag}
+		jw.tagStats[tag] = ts
+	}
+	return
+}
+
+// pruneTagStatsLocked drops the statistics of the half of the tags
+// that updated the fewest Elements.
+func (jw *Jaws) pruneTagStatsLocked() {
+	stats := make([]*TagStats, 0, len(jw.tagStats))
+	for _, ts := range jw.tagStats {
+		stats = append(stats, ts)
+	}
+	slices.SortFunc(stats, compareTagStats)
+	for _, ts := range stats[len(stats)/2:] {
+		delete(jw.tagStats, ts.Tag)
+	}
+}
+
+func (jw *Jaws) recordDirtyLocked(tags []interface{}) {
+	for _, tag := range tags {
+		jw.tagStatsLocked(tag).Dirtied++
+	}
+}
+
+// recordFanout records how many of reqs and their Elements have each of the tags.
+func (jw *Jaws) recordFanout(tags []interface{}, reqs []*Request) {
+	elems := make([]int, len(tags))
+	rqs := make([]int, len(tags))
+	for _, rq := range reqs {
+		rq.mu.RLock()
+		for i, tag := range tags {
+			if n := len(rq.tagMap[tag]); n > 0 {
+				elems[i] += n
+				rqs[i]++
+			}
+		}
+		rq.mu.RUnlock()
+	}
+	jw.mu.Lock()
+	for i, tag := range tags {
+		ts := jw.tagStatsLocked(tag)
+		ts.Updates++
+		ts.Requests += uint64(rqs[i])
+		ts.Elements += uint64(elems[i])
+	}
+	jw.mu.Unlock()
+}
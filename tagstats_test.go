@@ -0,0 +1,56 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestJaws_TagStats(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rq1 := jw.NewRequest(nil)
+	rq2 := jw.NewRequest(nil)
+
+	for _, rq := range []*Request{rq1, rq2} {
+		rq.NewElement(NewUiSpan(makeHtmlGetter("hot"))).Tag(Tag("hot"))
+	}
+	rq1.NewElement(NewUiSpan(makeHtmlGetter("hot"))).Tag(Tag("hot"))
+	rq1.NewElement(NewUiSpan(makeHtmlGetter("cold"))).Tag(Tag("cold"))
+
+	jw.Dirty(Tag("hot"))
+	jw.distributeDirt()
+	th.Equal(len(jw.TagStats()), 0)
+
+	jw.TrackTags = true
+	jw.Dirty(Tag("hot"))
+	jw.Dirty(Tag("hot"), Tag("cold"))
+	jw.distributeDirt()
+	jw.Dirty(Tag("hot"), Tag("unbound"))
+	jw.distributeDirt()
+
+	stats := jw.TagStats()
+	th.Equal(stats, []TagStats{
+		{Tag: Tag("hot"), Dirtied: 3, Updates: 2, Requests: 4, Elements: 6},
+		{Tag: Tag("cold"), Dirtied: 1, Updates: 1, Requests: 1, Elements: 1},
+		{Tag: Tag("unbound"), Dirtied: 1, Updates: 1},
+	})
+	th.Equal(stats[0].String(), `"hot": dirtied 3, updates 2, requests 4, elements 6`)
+
+	jw.ResetTagStats()
+	th.Equal(len(jw.TagStats()), 0)
+}
+
+func TestJaws_TagStatsLimit(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	jw.mu.Lock()
+	jw.tagStatsLocked(Tag("hot")).Elements = 100
+	for i := 0; i < maxTagStats; i++ {
+		jw.recordDirtyLocked([]interface{}{i})
+	}
+	jw.mu.Unlock()
+	stats := jw.TagStats()
+	th.Equal(len(stats), maxTagStats/2+1)
+	th.Equal(stats[0].Tag, Tag("hot"))
+}
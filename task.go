@@ -0,0 +1,162 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// TaskDoneText is the alert message shown when a Task completes without a progress message.
+const TaskDoneText = "Done"
+
+// TaskCancelledText is the alert message shown when a Task is cancelled.
+const TaskCancelledText = "Cancelled"
+
+// TaskFn is a long running function. It should call progress to report
+// how far it has come (from 0 to 1) along with a status message, and
+// return when ctx is done.
+type TaskFn = func(ctx context.Context, progress func(fraction float64, msg string)) error
+
+// Task is a TaskFn running in it's own goroutine.
+//
+// Use it as a tag to have Elements updated when it's progress changes,
+// or render it using RequestWriter.Task.
+type Task struct {
+	rq       *Request
+	cancelFn context.CancelFunc
+	doneCh   chan struct{}
+	mu       deadlock.RWMutex // protects following
+	fraction float64
+	msg      string
+	err      error
+}
+
+// RunTask runs fn in a new goroutine with a Context derived from the
+// Request's Context. When fn returns, an alert is shown with the
+// error, or with the last progress message if it succeeded.
+func (rq *Request) RunTask(fn TaskFn) (t *Task) {
+	ctx, cancel := context.WithCancel(rq.Context())
+	t = &Task{rq: rq, cancelFn: cancel, doneCh: make(chan struct{})}
+	go t.run(ctx, fn)
+	return
+}
+
+func (t *Task) run(ctx context.Context, fn TaskFn) {
+	err := fn(ctx, t.progress)
+	t.cancelFn()
+	t.mu.Lock()
+	t.err = err
+	msg := t.msg
+	t.mu.Unlock()
+	close(t.doneCh)
+	t.rq.Dirty(t)
+	switch {
+	case errors.Is(err, context.Canceled):
+		t.rq.Alert("info", TaskCancelledText)
+	case err != nil:
+		t.rq.AlertError(err)
+	default:
+		if msg == "" {
+			msg = TaskDoneText
+		}
+		t.rq.Alert("success", html.EscapeString(msg))
+	}
+}
+
+func (t *Task) progress(fraction float64, msg string) {
+	t.mu.Lock()
+	t.fraction = min(max(fraction, 0), 1)
+	t.msg = msg
+	t.mu.Unlock()
+	t.rq.Dirty(t)
+}
+
+// Progress returns the last reported progress and status message.
+func (t *Task) Progress() (fraction float64, msg string) {
+	t.mu.RLock()
+	fraction, msg = t.fraction, t.msg
+	t.mu.RUnlock()
+	return
+}
+
+// Cancel cancels the Task's Context.
+func (t *Task) Cancel() {
+	t.cancelFn()
+}
+
+// Done returns a channel that is closed when the Task has finished.
+func (t *Task) Done() <-chan struct{} {
+	return t.doneCh
+}
+
+// Err returns the error the TaskFn returned, or nil if it is still running.
+func (t *Task) Err() (err error) {
+	t.mu.RLock()
+	err = t.err
+	t.mu.RUnlock()
+	return
+}
+
+func (t *Task) isDone() bool {
+	select {
+	case <-t.doneCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// UiTask renders the progress of a Task along with a button to cancel it.
+type UiTask struct {
+	UiHtml
+	Task *Task
+}
+
+func NewUiTask(t *Task) *UiTask {
+	return &UiTask{Task: t}
+}
+
+func (ui *UiTask) innerHTML() template.HTML {
+	fraction, msg := ui.Task.Progress()
+	var b strings.Builder
+	b.WriteString(`<progress value="`)
+	b.WriteString(strconv.FormatFloat(fraction, 'f', -1, 64))
+	b.WriteString(`" max="1"></progress> <span>`)
+	b.WriteString(html.EscapeString(msg))
+	b.WriteString(`</span> <button name="cancel" type="button"`)
+	if ui.Task.isDone() {
+		b.WriteString(` disabled`)
+	}
+	b.WriteString(`>Cancel</button>`)
+	return template.HTML(b.String()) // #nosec G203
+}
+
+func (ui *UiTask) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	e.Tag(ui.Task)
+	attrs := append(ui.parseParams(e, params), `class="jaws-task"`)
+	return WriteHtmlInner(w, e.Jid(), "div", "", ui.innerHTML(), attrs...)
+}
+
+func (ui *UiTask) JawsUpdate(e *Element) {
+	e.SetInner(ui.innerHTML())
+}
+
+func (ui *UiTask) JawsEvent(e *Element, wht what.What, val string) error {
+	if wht == what.Click && val == "cancel" {
+		ui.Task.Cancel()
+		return nil
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+// Task renders a HTML div element showing the progress of t and a button to cancel it.
+func (rq RequestWriter) Task(t *Task, params ...interface{}) error {
+	return rq.UI(NewUiTask(t), params...)
+}
@@ -0,0 +1,86 @@
+package jaws
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_RunTask(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	stepCh := make(chan struct{})
+	task := rq.Request.RunTask(func(ctx context.Context, progress func(float64, string)) error {
+		progress(0.5, "half <way>")
+		stepCh <- struct{}{}
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	<-stepCh
+	fraction, msg := task.Progress()
+	th.Equal(fraction, 0.5)
+	th.Equal(msg, "half <way>")
+	th.NoErr(task.Err())
+
+	th.NoErr(rq.Task(task))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" class="jaws-task"><progress value="0.5" max="1"></progress> `+
+		`<span>half &lt;way&gt;</span> <button name="cancel" type="button">Cancel</button></div>`)
+
+	rq.inCh <- wsMsg{Data: "cancel\tJid.1", What: what.Click}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-task.Done():
+	}
+	th.True(errors.Is(task.Err(), context.Canceled))
+
+	var got string
+	for !strings.Contains(got, "Alert") || !strings.Contains(got, "disabled") {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			got += s
+		}
+	}
+	th.True(strings.Contains(got, "Alert\t\t\"info\\n"+TaskCancelledText+"\""))
+}
+
+func TestRequest_RunTaskResult(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	errFailed := errors.New("failed")
+	for _, tc := range []struct {
+		err  error
+		msg  string
+		want string
+	}{
+		{nil, "", "success\\n" + TaskDoneText},
+		{nil, "all <done>", "success\\nall &lt;done&gt;"},
+		{errFailed, "", "danger\\nfailed"},
+	} {
+		task := rq.Request.RunTask(func(ctx context.Context, progress func(float64, string)) error {
+			progress(2, tc.msg)
+			return tc.err
+		})
+		<-task.Done()
+		th.Equal(task.Err(), tc.err)
+		fraction, _ := task.Progress()
+		th.Equal(fraction, 1.0)
+		select {
+		case <-th.C:
+			th.Timeout()
+		case s := <-rq.outCh:
+			th.Equal(s, "Alert\t\t\""+tc.want+"\"\n")
+		}
+	}
+}
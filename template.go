@@ -1,9 +1,11 @@
 package jaws
 
 import (
+	"bytes"
 	"fmt"
 	"html/template"
 	"io"
+	"strconv"
 	"strings"
 
 	"github.com/linkdata/jaws/what"
@@ -23,7 +25,7 @@ func (rq *Request) MustTemplate(v interface{}) (tp *template.Template) {
 	case *template.Template:
 		tp = v
 	case string:
-		tp = rq.Jaws.Template.Lookup(v)
+		tp = rq.template().Lookup(v)
 	}
 	if tp == nil {
 		panic(fmt.Errorf("expected template, not %v", v))
@@ -39,24 +41,77 @@ func (t Template) String() string {
 	return fmt.Sprintf("{%q, %s}", t.Template.Name(), TagString(t.Dot))
 }
 
-func (t Template) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+// JawsRender executes the template. If that fails, the output is discarded
+// and the TemplateError is logged and sent to the Jaws ErrorReporter. If
+// Jaws.Debug is set, an error box showing it is rendered instead.
+func (t Template) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	return t.render(e, w, params, true)
+}
+
+// render executes the template for the Element, as described for JawsRender.
+// If withId is false, the error box doesn't have the Element's HTML ID.
+func (t Template) render(e *Element, w io.Writer, params []interface{}, withId bool) (err error) {
 	if expandedtags, err := TagExpand(e.Request, t.Dot); err != ErrIllegalTagType {
 		e.Request.tagExpanded(e, expandedtags)
 	}
-	var sb strings.Builder
-	for _, s := range parseParams(e, params) {
-		sb.WriteByte(' ')
-		sb.WriteString(s)
+	buf := getRenderBuf()
+	if err = t.execute(e, buf, params); err != nil {
+		te := newTemplateError(t, err)
+		_ = e.Request.Jaws.Log(te)
+		e.Request.reportError(e, what.Update, "", te, nil, nil)
+		buf.Reset()
+		if e.Request.Jaws.Debug {
+			buf.WriteString(string(templateErrorHTML(e, te, withId)))
+		}
 	}
-	attrs := template.HTMLAttr(sb.String()) // #nosec G203
-	return t.Execute(w, With{
+	t.markDuplicateJid(e, buf)
+	return writeRenderBuf(w, buf)
+}
+
+// execute executes the template, returning panics as errors.
+func (t Template) execute(e *Element, buf *bytes.Buffer, params []interface{}) (err error) {
+	defer func() {
+		if x := recover(); x != nil {
+			err = fmt.Errorf("panic: %v", x)
+		}
+	}()
+	return t.Execute(buf, With{
 		Element:       e,
-		RequestWriter: e.Request.Writer(w),
+		RequestWriter: e.Request.Writer(buf),
 		Dot:           t.Dot,
-		Attrs:         attrs,
+		Attrs:         internAttrs(parseParams(e, params)),
+		tmpl:          t.Template,
 	})
 }
 
+// markDuplicateJid checks if the rendered HTML in buf contains the Element's HTML ID
+// more than once. If so, it logs where they were found and adds a
+// "data-jaws-dup" attribute to each so that the browser updates all of them.
+func (t Template) markDuplicateJid(e *Element, buf *bytes.Buffer) {
+	b := buf.Bytes()
+	idAttr := e.Jid().AppendQuote([]byte("id="))
+	if bytes.Count(b, idAttr) > 1 {
+		var lines []string
+		var marked []byte
+		for {
+			i := bytes.Index(b, idAttr)
+			if i < 0 {
+				break
+			}
+			i += len(idAttr)
+			lines = append(lines, strconv.Itoa(1+bytes.Count(marked, []byte{'\n'})+bytes.Count(b[:i], []byte{'\n'})))
+			marked = append(marked, b[:i]...)
+			marked = append(marked, " data-jaws-dup"...)
+			b = b[i:]
+		}
+		_ = e.Request.Jaws.Log(fmt.Errorf("jaws: %v: template %q rendered id %q %d times, at output lines %s",
+			e.Request, t.Template.Name(), e.Jid().String(), len(lines), strings.Join(lines, ", ")))
+		marked = append(marked, b...)
+		buf.Reset()
+		buf.Write(marked)
+	}
+}
+
 func (t Template) JawsUpdate(e *Element) {
 	// does nothing
 }
@@ -2,6 +2,8 @@ package jaws
 
 import (
 	"html/template"
+	"io"
+	"strconv"
 	"strings"
 	"testing"
 )
@@ -66,3 +68,44 @@ func TestRequest_MustTemplate_Panics(t *testing.T) {
 		})
 	}
 }
+
+func TestTemplate_DuplicateJid(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tmpl := template.Must(template.New("dup").Parse("<div id=\"{{$.Jid}}\">{{$.Dot}}</div>\n<span id=\"{{$.Jid}}\"></span>"))
+	th.NoErr(rq.UI(Template{Template: tmpl, Dot: 1}))
+	th.Equal(rq.BodyString(), "<div id=\"Jid.1\" data-jaws-dup>1</div>\n<span id=\"Jid.1\" data-jaws-dup></span>")
+	th.True(strings.Contains(rq.jw.log.String(), `template "dup" rendered id "Jid.1" 2 times, at output lines 1, 2`))
+
+	rq.jw.log.Reset()
+	th.NoErr(rq.UI(Template{Template: tmpl.Lookup("dup"), Dot: 2}))
+	th.True(strings.Contains(rq.jw.log.String(), `"Jid.2" 2 times`))
+	th.NoErr(rq.Template("testtemplate", 3))
+	th.Equal(strings.Contains(rq.jw.log.String(), "Jid.3"), false)
+}
+
+func BenchmarkTemplate_Render(b *testing.B) {
+	jw := New()
+	defer jw.Close()
+	jw.Template = template.Must(template.New("rows").Parse(`{{range $.Dot}}<p>{{.}}</p>{{end}}`))
+	rq := jw.NewRequest(nil)
+	defer jw.recycle(rq)
+	rw := rq.Writer(io.Discard)
+	rows := make([]string, 64)
+	for i := range rows {
+		rows[i] = "row " + strconv.Itoa(i)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if i%1000 == 0 {
+			rq.mu.Lock()
+			rq.elems = rq.elems[:0]
+			clear(rq.tagMap)
+			rq.mu.Unlock()
+		}
+		_ = rw.Template("rows", rows)
+	}
+}
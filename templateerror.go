@@ -0,0 +1,70 @@
+package jaws
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"regexp"
+	"strconv"
+	ttemplate "text/template"
+)
+
+// TemplateError describes an error executing a Template, or a panic while
+// doing so. It is logged, and if Jaws.Debug is set, shown in place of the
+// Template's output.
+type TemplateError struct {
+	Template string // name of the Template executed
+	Name     string // name of the template the error occurred in, may be a nested one
+	Line     int    // line of the failing node in the parsed template source, or zero if unknown
+	Column   int    // column of the failing node, or zero if unknown
+	Node     string // the failing node, such as ".User.Name", or empty if unknown
+	DotType  string // Go type of the Template's Dot
+	Err      error  // the error returned by the template package
+}
+
+func (te *TemplateError) Error() string {
+	return fmt.Sprintf("jaws: template %q with dot %s: %v", te.Template, te.DotType, te.Err)
+}
+
+func (te *TemplateError) Unwrap() error {
+	return te.Err
+}
+
+// execErrorRx matches the position and node in text/template.ExecError messages.
+var execErrorRx = regexp.MustCompile(`^template: [^:]*:(\d+):(\d+): executing "([^"]*)" at <([^>]*)>`)
+
+func newTemplateError(t Template, err error) (te *TemplateError) {
+	te = &TemplateError{
+		Template: t.Template.Name(),
+		Name:     t.Template.Name(),
+		DotType:  fmt.Sprintf("%T", t.Dot),
+		Err:      err,
+	}
+	var execErr ttemplate.ExecError
+	var escErr *template.Error
+	if errors.As(err, &execErr) {
+		te.Name = execErr.Name
+		if m := execErrorRx.FindStringSubmatch(execErr.Error()); m != nil {
+			te.Line, _ = strconv.Atoi(m[1])
+			te.Column, _ = strconv.Atoi(m[2])
+			te.Name = m[3]
+			te.Node = m[4]
+		}
+	} else if errors.As(err, &escErr) {
+		te.Name = escErr.Name
+		te.Line = escErr.Line
+	}
+	return
+}
+
+// templateErrorHTML returns the error box shown in place of a Template's output when Jaws.Debug is set.
+// If withId is false, it doesn't have the Element's HTML ID.
+func templateErrorHTML(e *Element, te *TemplateError, withId bool) template.HTML {
+	var id string
+	if withId {
+		id = `id="` + e.Jid().String() + `" `
+	}
+	return template.HTML(`<div ` + id + `class="jaws-template-error" role="alert"` + // #nosec G203
+		` style="border:2px solid #c00;color:#c00;padding:0.5em"><pre>` + html.EscapeString(te.Error()) + `</pre></div>`)
+}
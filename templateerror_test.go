@@ -0,0 +1,79 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+type testTemplateDot struct{ Name string }
+
+func (testTemplateDot) Fail() string { panic("dot exploded") }
+
+func TestTemplate_ExecError(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	reportCh := make(chan *ErrorReport, 1)
+	rq.Jaws.ErrorReporter = ErrorReporterFunc(func(report *ErrorReport) { reportCh <- report })
+
+	tmpl := template.Must(template.New("page").Parse("<p>\n  {{template \"row\" .}}</p>" +
+		`{{define "row"}}<b>{{$.Dot.Name}} {{$.Dot.Missing}}</b>{{end}}`))
+	th.NoErr(rq.UI(Template{Template: tmpl, Dot: &testTemplateDot{Name: "x"}}))
+	th.Equal(rq.BodyString(), "")
+
+	var te *TemplateError
+	th.True(errors.As((<-reportCh).Err, &te))
+	th.Equal(te.Template, "page")
+	th.Equal(te.Name, "row")
+	th.Equal(te.Line, 2)
+	th.Equal(te.Node, "$.Dot.Missing")
+	th.True(te.Column > 0)
+	th.Equal(te.DotType, "*jaws.testTemplateDot")
+	th.True(strings.Contains(te.Error(), `jaws: template "page" with dot *jaws.testTemplateDot: template: page:2:`))
+	th.True(strings.Contains(rq.jw.log.String(), te.Error()))
+
+	rq.Jaws.Debug = true
+	th.NoErr(rq.UI(Template{Template: tmpl, Dot: &testTemplateDot{}}))
+	body := rq.BodyString()
+	th.True(strings.HasPrefix(body, `<div id="Jid.2" class="jaws-template-error" role="alert"`))
+	th.True(strings.Contains(body, `<pre>jaws: template &#34;page&#34; with dot *jaws.testTemplateDot: `))
+	<-reportCh
+}
+
+func TestTemplate_ExecPanic(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tmpl := template.Must(template.New("boom").Parse(`{{$.Dot.Fail}}`))
+	th.NoErr(rq.UI(Template{Template: tmpl, Dot: testTemplateDot{}}))
+	th.True(strings.Contains(rq.jw.log.String(), "dot exploded"))
+
+	panicking := Template{Template: tmpl, Dot: testTemplateDot{}}
+	te := newTemplateError(panicking, errors.New("panic: x"))
+	th.Equal(te.Name, "boom")
+	th.Equal(te.Line, 0)
+	th.Equal(errors.Unwrap(te).Error(), "panic: x")
+}
+
+func TestTemplate_EscapeError(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tmpl := template.Must(template.New("esc").Parse("<a href=\"\n{{.}}"))
+	th.NoErr(rq.UI(Template{Template: tmpl, Dot: 1}))
+	th.True(strings.Contains(rq.jw.log.String(), `template "esc"`))
+	var escErr *template.Error
+	err := tmpl.Execute(&strings.Builder{}, nil)
+	th.True(errors.As(err, &escErr))
+	te := newTemplateError(Template{Template: tmpl}, err)
+	th.Equal(te.Name, "esc")
+	th.Equal(te.DotType, "<nil>")
+}
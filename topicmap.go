@@ -0,0 +1,148 @@
+package jaws
+
+import (
+	"fmt"
+	"html"
+	"html/template"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+)
+
+// TopicTransform converts the payload of a message published on a MQTT
+// topic to the value to show, such as a float64 parsed from the payload.
+type TopicTransform func(topic string, payload []byte) (v interface{}, err error)
+
+// TopicFloat is a TopicTransform that parses the payload as a float64.
+func TopicFloat(topic string, payload []byte) (interface{}, error) {
+	return strconv.ParseFloat(strings.TrimSpace(string(payload)), 64)
+}
+
+// TopicMap maps MQTT topics to JaWS tags, so that device telemetry
+// appears live in the Elements bound to them.
+//
+// It doesn't depend on a MQTT client library. Instead, call Handle
+// from the client's message handler. For example, using paho.mqtt.golang:
+//
+//	tm := jaws.NewTopicMap(jw)
+//	temp := tm.Bind("plant/+/temperature", jaws.TopicFloat)
+//	client.Subscribe("plant/#", 0, func(_ mqtt.Client, m mqtt.Message) {
+//		tm.Handle(m.Topic(), m.Payload())
+//	})
+//	...
+//	rw.Span(temp)
+type TopicMap struct {
+	jw     *Jaws
+	mu     deadlock.RWMutex
+	values []*TopicValue
+}
+
+// NewTopicMap returns a new TopicMap dirtying tags in the given Jaws.
+func NewTopicMap(jw *Jaws) *TopicMap {
+	return &TopicMap{jw: jw}
+}
+
+// Bind returns a TopicValue holding the latest value of the messages
+// published on topics matching the MQTT topic filter, which may contain
+// the "+" and "#" wildcards. If transform is nil, the value is the
+// payload as a string.
+//
+// When a message is handled, the TopicValue and the given tags are dirtied.
+func (tm *TopicMap) Bind(filter string, transform TopicTransform, tags ...interface{}) (tv *TopicValue) {
+	tv = &TopicValue{filter: filter, transform: transform, tags: tags}
+	tm.mu.Lock()
+	tm.values = append(tm.values, tv)
+	tm.mu.Unlock()
+	return
+}
+
+// Handle updates the TopicValues with filters matching the topic with the
+// payload, and returns the number of matches.
+//
+// Transform errors are logged and stored in the TopicValue.
+func (tm *TopicMap) Handle(topic string, payload []byte) (n int) {
+	var tags []interface{}
+	tm.mu.RLock()
+	for _, tv := range tm.values {
+		if topicMatch(tv.filter, topic) {
+			n++
+			if err := tv.set(topic, payload); err != nil {
+				_ = tm.jw.Log(fmt.Errorf("jaws: topic %q: %w", topic, err))
+			}
+			tags = append(append(tags, tv), tv.tags...)
+		}
+	}
+	tm.mu.RUnlock()
+	if len(tags) > 0 {
+		tm.jw.Dirty(tags...)
+	}
+	return
+}
+
+// topicMatch returns true if the MQTT topic filter matches the topic.
+func topicMatch(filter, topic string) bool {
+	if strings.HasPrefix(topic, "$") && !strings.HasPrefix(filter, "$") {
+		return false
+	}
+	fl := strings.Split(filter, "/")
+	tl := strings.Split(topic, "/")
+	for i, f := range fl {
+		if f == "#" {
+			return i == len(fl)-1
+		}
+		if i >= len(tl) || (f != "+" && f != tl[i]) {
+			return false
+		}
+	}
+	return len(fl) == len(tl)
+}
+
+// TopicValue is the latest value published on the topics matching a
+// MQTT topic filter, see TopicMap.Bind. It is a HtmlGetter rendering the
+// value using fmt.Sprint, and tags Elements with itself.
+type TopicValue struct {
+	filter    string
+	transform TopicTransform
+	tags      []interface{}
+	mu        deadlock.RWMutex
+	topic     string
+	value     interface{}
+	err       error
+}
+
+func (tv *TopicValue) set(topic string, payload []byte) (err error) {
+	var v interface{} = string(payload)
+	if tv.transform != nil {
+		v, err = tv.transform(topic, payload)
+	}
+	tv.mu.Lock()
+	tv.topic = topic
+	if tv.err = err; err == nil {
+		tv.value = v
+	}
+	tv.mu.Unlock()
+	return
+}
+
+// Get returns the latest value, the topic it was published on, and the
+// error from transforming the latest payload, in which case the value
+// is from the last payload that was transformed without error.
+func (tv *TopicValue) Get() (v interface{}, topic string, err error) {
+	tv.mu.RLock()
+	v, topic, err = tv.value, tv.topic, tv.err
+	tv.mu.RUnlock()
+	return
+}
+
+func (tv *TopicValue) JawsGetHtml(e *Element) template.HTML {
+	v, _, _ := tv.Get()
+	if v == nil {
+		return ""
+	}
+	return template.HTML(html.EscapeString(fmt.Sprint(v))) // #nosec G203
+}
+
+func (tv *TopicValue) JawsGetTag(rq *Request) interface{} {
+	return tv
+}
@@ -0,0 +1,68 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func Test_topicMatch(t *testing.T) {
+	th := newTestHelper(t)
+	th.True(topicMatch("a/b", "a/b"))
+	th.True(!topicMatch("a/b", "a/c"))
+	th.True(!topicMatch("a/b", "a/b/c"))
+	th.True(!topicMatch("a/b/c", "a/b"))
+	th.True(topicMatch("a/+/c", "a/b/c"))
+	th.True(!topicMatch("a/+", "a/b/c"))
+	th.True(topicMatch("a/#", "a/b/c"))
+	th.True(topicMatch("a/#", "a"))
+	th.True(topicMatch("#", "a/b"))
+	th.True(!topicMatch("#/a", "b/a"))
+	th.True(!topicMatch("#", "$SYS/uptime"))
+	th.True(topicMatch("$SYS/#", "$SYS/uptime"))
+}
+
+func TestTopicMap(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tm := NewTopicMap(rq.jw.Jaws)
+	extra := &struct{}{}
+	temp := tm.Bind("plant/+/temperature", TopicFloat, extra)
+	status := tm.Bind("plant/pump/status", nil)
+
+	th.NoErr(rq.Span(temp))
+	th.NoErr(rq.Span(status))
+	th.Equal(rq.BodyString(), `<span id="Jid.1"></span><span id="Jid.2"></span>`)
+
+	th.Equal(tm.Handle("plant/boiler/temperature", []byte(" 71.5\n")), 1)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"71.5\"\n")
+	}
+	v, topic, err := temp.Get()
+	th.Equal(v, 71.5)
+	th.Equal(topic, "plant/boiler/temperature")
+	th.NoErr(err)
+
+	th.Equal(tm.Handle("plant/pump/status", []byte("<on>")), 1)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.2\t\"&lt;on&gt;\"\n")
+	}
+
+	th.Equal(tm.Handle("plant/boiler/temperature", []byte("n/a")), 1)
+	v, _, err = temp.Get()
+	th.Equal(v, 71.5)
+	th.True(errors.Is(err, strconv.ErrSyntax))
+	th.True(strings.Contains(rq.jw.log.String(), `jaws: topic "plant/boiler/temperature": `))
+
+	th.Equal(tm.Handle("office/temperature", []byte("20")), 0)
+}
@@ -0,0 +1,110 @@
+package jaws
+
+import (
+	"fmt"
+
+	"github.com/linkdata/deadlock"
+)
+
+// TriState is the state of a checkbox that may be indeterminate, as used
+// in hierarchical selections where some but not all children are checked.
+type TriState int8
+
+const (
+	Unchecked TriState = iota
+	Checked
+	Indeterminate
+)
+
+func (ts TriState) String() string {
+	switch ts {
+	case Unchecked:
+		return "Unchecked"
+	case Checked:
+		return "Checked"
+	case Indeterminate:
+		return "Indeterminate"
+	}
+	return fmt.Sprintf("TriState(%d)", int8(ts))
+}
+
+// TriStateOf returns Indeterminate if b is nil, otherwise Checked or Unchecked.
+func TriStateOf(b *bool) TriState {
+	if b == nil {
+		return Indeterminate
+	}
+	if *b {
+		return Checked
+	}
+	return Unchecked
+}
+
+// Bool returns the state as a nullable bool, nil if Indeterminate.
+func (ts TriState) Bool() *bool {
+	if ts == Indeterminate {
+		return nil
+	}
+	b := ts == Checked
+	return &b
+}
+
+type TriStateSetter interface {
+	JawsGetTriState(e *Element) TriState
+	JawsSetTriState(e *Element, v TriState) (err error)
+}
+
+func (ts TriState) JawsGetTriState(*Element) TriState {
+	return ts
+}
+
+func (ts TriState) JawsSetTriState(*Element, TriState) error {
+	return ErrValueNotSettable
+}
+
+func (ts TriState) JawsGetTag(*Request) interface{} {
+	return nil
+}
+
+// NullBool is a TriStateSetter holding a nullable bool, where nil is
+// Indeterminate. It is safe to use from multiple goroutines concurrently.
+type NullBool struct {
+	mu deadlock.RWMutex // protects following
+	v  *bool
+}
+
+func NewNullBool(v *bool) *NullBool {
+	return &NullBool{v: TriStateOf(v).Bool()}
+}
+
+// Get returns a copy of the current value, nil if Indeterminate.
+func (nb *NullBool) Get() *bool {
+	nb.mu.RLock()
+	defer nb.mu.RUnlock()
+	return TriStateOf(nb.v).Bool()
+}
+
+// Set sets the current value, nil for Indeterminate.
+func (nb *NullBool) Set(v *bool) {
+	nb.mu.Lock()
+	nb.v = TriStateOf(v).Bool()
+	nb.mu.Unlock()
+}
+
+func (nb *NullBool) JawsGetTriState(*Element) TriState {
+	return TriStateOf(nb.Get())
+}
+
+func (nb *NullBool) JawsSetTriState(_ *Element, v TriState) error {
+	nb.Set(v.Bool())
+	return nil
+}
+
+func makeTriStateSetter(v interface{}) TriStateSetter {
+	switch v := v.(type) {
+	case TriStateSetter:
+		return v
+	case *bool:
+		return TriStateOf(v)
+	}
+	panic(fmt.Errorf("expected jaws.TriStateSetter, jaws.TriState or *bool, not %T", v))
+}
@@ -0,0 +1,48 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestTriState(t *testing.T) {
+	th := newTestHelper(t)
+	yes, no := true, false
+	th.Equal(TriStateOf(nil), Indeterminate)
+	th.Equal(TriStateOf(&yes), Checked)
+	th.Equal(TriStateOf(&no), Unchecked)
+	th.Equal(Indeterminate.Bool() == nil, true)
+	th.Equal(*Checked.Bool(), true)
+	th.Equal(*Unchecked.Bool(), false)
+	th.Equal(Checked.String(), "Checked")
+	th.Equal(Unchecked.String(), "Unchecked")
+	th.Equal(Indeterminate.String(), "Indeterminate")
+	th.Equal(TriState(9).String(), "TriState(9)")
+	th.Equal(Checked.JawsSetTriState(nil, Unchecked), ErrValueNotSettable)
+}
+
+func TestNullBool(t *testing.T) {
+	th := newTestHelper(t)
+	yes := true
+	nb := NewNullBool(&yes)
+	th.Equal(nb.JawsGetTriState(nil), Checked)
+	yes = false // NullBool keeps it's own copy
+	th.Equal(nb.JawsGetTriState(nil), Checked)
+	th.NoErr(nb.JawsSetTriState(nil, Indeterminate))
+	th.True(nb.Get() == nil)
+	th.NoErr(nb.JawsSetTriState(nil, Unchecked))
+	th.Equal(*nb.Get(), false)
+	nb.Set(&yes)
+	th.Equal(nb.JawsGetTriState(nil), Unchecked)
+}
+
+func Test_makeTriStateSetter(t *testing.T) {
+	th := newTestHelper(t)
+	nb := NewNullBool(nil)
+	th.Equal(makeTriStateSetter(nb), TriStateSetter(nb))
+	th.Equal(makeTriStateSetter(Checked), TriStateSetter(Checked))
+	th.Equal(makeTriStateSetter((*bool)(nil)), TriStateSetter(Indeterminate))
+	defer func() {
+		th.True(recover() != nil)
+	}()
+	makeTriStateSetter(1)
+}
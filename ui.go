@@ -14,7 +14,7 @@ type UI interface {
 }
 
 func (rq *Request) JawsRender(elem *Element, w io.Writer, params []interface{}) (err error) {
-	if err = elem.ui.JawsRender(elem, w, params); err == nil {
+	if err = rq.renderElement(elem, w, params); err == nil {
 		if rq.Jaws.Debug {
 			var sb strings.Builder
 			_, _ = fmt.Fprintf(&sb, "<!-- id=%q %T tags=[", elem.jid, elem.ui)
@@ -0,0 +1,37 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// UiBlock renders a Template inside a div, and renders it again whenever
+// the Element is dirtied. This makes a block of a layout a live region,
+// independent of the rest of the page.
+//
+// Elements rendered by the template are created anew each time.
+type UiBlock struct {
+	Template
+	Name BlockName
+}
+
+func NewUiBlock(name string, t Template) *UiBlock {
+	return &UiBlock{Template: t, Name: BlockName(name)}
+}
+
+func (ui *UiBlock) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	e.Tag(ui.Name)
+	attrs := append(parseParams(e, params), string(Attr("data-jaws-block", string(ui.Name))))
+	var sb strings.Builder
+	if err = ui.Template.render(e, &sb, nil, false); err == nil {
+		err = WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(sb.String()), attrs...) // #nosec G203
+	}
+	return
+}
+
+func (ui *UiBlock) JawsUpdate(e *Element) {
+	var sb strings.Builder
+	maybePanic(ui.Template.render(e, &sb, nil, false))
+	e.SetInner(template.HTML(sb.String())) // #nosec G203
+}
@@ -0,0 +1,35 @@
+package jaws
+
+import (
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestUiBlock_JawsUpdate(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ts := newTestSetter("one")
+	tmpl := template.Must(template.New("count").Parse(`<b>{{$.Dot.Get}}</b>{{if eq $.Dot.Get "bad"}}{{$.Dot.Nope}}{{end}}`))
+	th.NoErr(rq.UI(NewUiBlock("count", Template{Template: tmpl, Dot: ts})))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-block="count"><b>one</b></div>`)
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(ts))
+	th.True(elem.HasTag(BlockName("count")))
+
+	ts.Set("two")
+	ui := elem.Ui().(*UiBlock)
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{{Data: `<b>two</b>`, Jid: 1, What: what.Inner}})
+
+	rq.Jaws.Debug = true
+	ts.Set("bad")
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 2)
+	th.True(strings.HasPrefix(elem.wsQueue[1].Data, `<div class="jaws-template-error" role="alert"`))
+}
@@ -0,0 +1,130 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// CaptureHandler receives data captured by a UiCapture.
+//
+// For snapshots, each call receives one complete image. When recording,
+// each call receives the next chunk of the recording, and the chunks
+// must be concatenated in order to form the complete media file.
+type CaptureHandler interface {
+	JawsCapture(e *Element, mime string, data []byte) (err error)
+}
+
+// CaptureErrorHandler is optionally implemented by a CaptureHandler to be
+// notified when the browser fails to start capturing, for example if the
+// user denies permission to use the camera.
+//
+// If not implemented, the CaptureError is returned as the event error.
+type CaptureErrorHandler interface {
+	JawsCaptureError(e *Element, err *CaptureError) error
+}
+
+// CaptureError is a browser error from starting or running a capture.
+// Name is the DOMException name, e.g. "NotAllowedError" or "NotFoundError".
+type CaptureError struct {
+	Name    string
+	Message string
+}
+
+func (ce *CaptureError) Error() string {
+	return "capture " + ce.Name + ": " + ce.Message
+}
+
+// Capture modes for UiCapture.
+const (
+	CaptureSnapshot = "snapshot" // send still images from the video stream
+	CaptureRecord   = "record"   // send recorded media in chunks
+)
+
+// UiCapture requests access to the users camera and/or microphone,
+// shows a preview and sends captured data to a CaptureHandler.
+//
+// In CaptureSnapshot mode, an image is captured every Interval, or when the
+// element is clicked if Interval is zero. In CaptureRecord mode, a chunk of
+// the recording is sent every Interval, or once when the element is removed
+// if Interval is zero.
+type UiCapture struct {
+	UiHtml
+	CaptureHandler
+	Video    bool          // capture from camera
+	Audio    bool          // capture from microphone
+	Mode     string        // CaptureSnapshot or CaptureRecord
+	Mime     string        // requested MIME type of captured data, may be empty
+	Interval time.Duration // time between snapshots or recording chunks
+}
+
+func NewUiCapture(h CaptureHandler) *UiCapture {
+	return &UiCapture{
+		CaptureHandler: h,
+		Video:          true,
+		Mode:           CaptureSnapshot,
+		Mime:           "image/jpeg",
+	}
+}
+
+func (ui *UiCapture) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.CaptureHandler)
+	var kind string
+	if ui.Video {
+		kind = "video"
+	}
+	if ui.Audio {
+		if kind != "" {
+			kind += " "
+		}
+		kind += "audio"
+	}
+	attrs := append(ui.parseParams(e, params),
+		`data-jaws-capture="`+kind+`"`,
+		`data-jaws-capture-mode=`+strconv.Quote(ui.Mode),
+	)
+	if ui.Mime != "" {
+		attrs = append(attrs, `data-jaws-capture-mime=`+strconv.Quote(ui.Mime))
+	}
+	if ui.Interval > 0 {
+		attrs = append(attrs, `data-jaws-capture-interval="`+strconv.FormatInt(ui.Interval.Milliseconds(), 10)+`"`)
+	}
+	var inner template.HTML
+	if ui.Video {
+		inner = `<video autoplay muted playsinline></video>`
+	}
+	return WriteHtmlInner(w, e.Jid(), "div", "", inner, attrs...)
+}
+
+func (ui *UiCapture) JawsUpdate(e *Element) {}
+
+func (ui *UiCapture) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	if wht != what.Capture {
+		return callEventHandler(ui.CaptureHandler, e, wht, val)
+	}
+	return callPasteHandler(capturePasteHandler{ui.CaptureHandler}, e, val)
+}
+
+type capturePasteHandler struct{ CaptureHandler }
+
+// JawsPaste is called with the reassembled captured data.
+// Capture errors are sent from the browser with an empty mime type.
+func (cph capturePasteHandler) JawsPaste(e *Element, mime, name string, data []byte) (err error) {
+	if mime == "" {
+		ce := &CaptureError{Name: name, Message: string(data)}
+		if h, ok := cph.CaptureHandler.(CaptureErrorHandler); ok {
+			return h.JawsCaptureError(e, ce)
+		}
+		return ce
+	}
+	return cph.JawsCapture(e, mime, data)
+}
+
+// Capture renders a HTML div element that captures snapshots from the camera
+// and sends them to the given CaptureHandler.
+func (rq RequestWriter) Capture(h CaptureHandler, params ...interface{}) error {
+	return rq.UI(NewUiCapture(h), params...)
+}
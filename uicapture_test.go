@@ -0,0 +1,88 @@
+package jaws
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testCapture struct {
+	mime string
+	data []byte
+	err  *CaptureError
+}
+
+type testCaptureHandler struct {
+	captureCh chan testCapture
+}
+
+func (tch *testCaptureHandler) JawsCapture(e *Element, mime string, data []byte) (err error) {
+	tch.captureCh <- testCapture{mime: mime, data: data}
+	return
+}
+
+type testCaptureErrorHandler struct {
+	testCaptureHandler
+}
+
+func (tceh *testCaptureErrorHandler) JawsCaptureError(e *Element, err *CaptureError) error {
+	tceh.captureCh <- testCapture{err: err}
+	return nil
+}
+
+func TestRequest_Capture(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tch := &testCaptureErrorHandler{testCaptureHandler{captureCh: make(chan testCapture)}}
+	th.NoErr(rq.Capture(tch))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-capture="video" data-jaws-capture-mode="snapshot" data-jaws-capture-mime="image/jpeg"><video autoplay muted playsinline></video></div>`)
+
+	data := base64.StdEncoding.EncodeToString([]byte("jpeg"))
+	rq.inCh <- wsMsg{Data: "1\t1\timage/jpeg\tsnapshot\t" + data, Jid: 1, What: what.Capture}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case got := <-tch.captureCh:
+		th.Equal(got.mime, "image/jpeg")
+		th.Equal(string(got.data), "jpeg")
+	}
+
+	msg := base64.StdEncoding.EncodeToString([]byte("Permission denied"))
+	rq.inCh <- wsMsg{Data: "0\t1\t\tNotAllowedError\t" + msg, Jid: 1, What: what.Capture}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case got := <-tch.captureCh:
+		th.Equal(got.err, &CaptureError{Name: "NotAllowedError", Message: "Permission denied"})
+		th.Equal(got.err.Error(), "capture NotAllowedError: Permission denied")
+	}
+}
+
+func TestUiCapture_Record(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tch := &testCaptureHandler{captureCh: make(chan testCapture, 1)}
+	ui := NewUiCapture(tch)
+	ui.Audio = true
+	ui.Video = false
+	ui.Mode = CaptureRecord
+	ui.Mime = ""
+	ui.Interval = time.Second
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" data-jaws-capture="audio" data-jaws-capture-mode="record" data-jaws-capture-interval="1000"></div>`)
+
+	elem := rq.getElementByJid(1)
+	err := ui.JawsEvent(elem, what.Capture, "\tNotFoundError\tx")
+	th.Equal(err, &CaptureError{Name: "NotFoundError", Message: "x"})
+	th.Equal(ui.JawsEvent(elem, what.Click, "x"), ErrEventUnhandled)
+	th.NoErr(ui.JawsEvent(elem, what.Capture, "audio/webm\t\tabc"))
+	th.Equal(string((<-tch.captureCh).data), "abc")
+}
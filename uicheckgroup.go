@@ -0,0 +1,99 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"strings"
+)
+
+// DefaultCheckTemplate renders a CheckElement as a checkbox followed by it's label.
+var DefaultCheckTemplate = template.Must(template.New("jaws-check").Parse(`{{.Checkbox}}{{.Label}}`))
+
+// CheckElement is a checkbox and it's label in a UiCheckGroup, and is the
+// dot when executing the UiCheckGroup template.
+type CheckElement struct {
+	checkbox *Element
+	label    *Element
+	name     string
+}
+
+// Name returns the name of the item, or the empty string for the "select all" checkbox.
+func (ce CheckElement) Name() string {
+	return ce.name
+}
+
+// Checkbox renders a HTML input element of type 'checkbox'.
+func (ce CheckElement) Checkbox(params ...interface{}) template.HTML {
+	var sb strings.Builder
+	maybePanic(ce.checkbox.Render(&sb, params))
+	return template.HTML(sb.String()) // #nosec G203
+}
+
+// Label renders a HTML label element for the checkbox.
+func (ce CheckElement) Label(params ...interface{}) template.HTML {
+	var sb strings.Builder
+	forAttr := string(ce.checkbox.jid.AppendQuote([]byte("for=")))
+	maybePanic(ce.label.Render(&sb, append(params, forAttr)))
+	return template.HTML(sb.String()) // #nosec G203
+}
+
+// UiCheckGroup renders the checkboxes of a CheckGroup in a HTML div element,
+// executing a template with a CheckElement as dot for each of them.
+//
+// If SelectAll is set, a checkbox that checks or unchecks all items is
+// rendered first. It is indeterminate when some but not all items are checked.
+type UiCheckGroup struct {
+	UiHtml
+	Group     *CheckGroup
+	Template  *template.Template // template for each checkbox, defaults to DefaultCheckTemplate
+	SelectAll template.HTML      // if not empty, label of the "select all" checkbox
+}
+
+func NewUiCheckGroup(cg *CheckGroup) *UiCheckGroup {
+	return &UiCheckGroup{Group: cg, Template: DefaultCheckTemplate}
+}
+
+func (ui *UiCheckGroup) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	ui.parseGetter(e, ui.Group)
+	attrs := ui.parseParams(e, params)
+	b := e.jid.AppendStartTagAttr(nil, "div")
+	for _, attr := range attrs {
+		b = append(b, ' ')
+		b = append(b, attr...)
+	}
+	b = append(b, '>')
+	if _, err = w.Write(b); err == nil {
+		var ces []CheckElement
+		if ui.SelectAll != "" {
+			ces = append(ces, CheckElement{
+				checkbox: e.Request.NewElement(NewUiTriState(ui.Group.all)),
+				label:    e.Request.NewElement(NewUiLabel(makeHtmlGetter(ui.SelectAll))),
+			})
+		}
+		for _, item := range ui.Group.items {
+			ces = append(ces, CheckElement{
+				checkbox: e.Request.NewElement(NewUiCheckbox(item)),
+				label:    e.Request.NewElement(NewUiLabel(item)),
+				name:     item.Name,
+			})
+		}
+		for _, ce := range ces {
+			if err == nil {
+				err = ui.Template.Execute(w, ce)
+			}
+		}
+		if err == nil {
+			_, err = io.WriteString(w, "</div>")
+		}
+	}
+	return
+}
+
+func (ui *UiCheckGroup) JawsUpdate(e *Element) {
+	// the checkboxes update themselves
+}
+
+// CheckGroup renders the checkboxes of cg with their labels in a HTML div element.
+func (rq RequestWriter) CheckGroup(cg *CheckGroup, params ...interface{}) error {
+	return rq.UI(NewUiCheckGroup(cg), params...)
+}
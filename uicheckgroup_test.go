@@ -0,0 +1,61 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_CheckGroup(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	set := []string{"g"}
+	cg := NewCheckGroupSet(&set, testCheckItems[:2]...)
+	ui := NewUiCheckGroup(cg)
+	ui.SelectAll = "All"
+	th.NoErr(rq.UI(ui, `class="colors"`))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" class="colors">`+
+		`<input id="Jid.2" type="checkbox" data-jaws-indeterminate><label id="Jid.3" for="Jid.2">All</label>`+
+		`<input id="Jid.4" type="checkbox"><label id="Jid.5" for="Jid.4">Red</label>`+
+		`<input id="Jid.6" type="checkbox" checked><label id="Jid.7" for="Jid.6">Green</label>`+
+		`</div>`)
+
+	th.NoErr(rq.callAllEventHandlers(4, what.Input, "true"))
+	th.Equal(set, []string{"g", "r"})
+
+	all := rq.getElementByJid(2)
+	all.Ui().JawsUpdate(all)
+	th.Equal(len(all.wsQueue), 2)
+	th.Equal(all.wsQueue[0], wsMsg{Jid: 2, What: what.RAttr, Data: "data-jaws-indeterminate"})
+	th.Equal(all.wsQueue[1], wsMsg{Jid: 2, What: what.Value, Data: "true"})
+
+	th.NoErr(rq.callAllEventHandlers(2, what.Input, "false"))
+	th.Equal(len(set), 0)
+	green := rq.getElementByJid(6)
+	green.Ui().JawsUpdate(green)
+	th.Equal(len(green.wsQueue), 1)
+	th.Equal(green.wsQueue[0], wsMsg{Jid: 6, What: what.Value, Data: "false"})
+}
+
+func TestRequest_CheckGroupTemplate(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	m := map[string]bool{"b": true}
+	th.NoErr(rq.CheckGroup(NewCheckGroupMap(m, testCheckItems[2:]...)))
+	th.Equal(rq.BodyString(), `<div id="Jid.1"><input id="Jid.2" type="checkbox" checked><label id="Jid.3" for="Jid.2">Blue</label></div>`)
+
+	nextJid = 0
+	rq2 := newTestRequest()
+	defer rq2.Close()
+	ui := NewUiCheckGroup(NewCheckGroupMap(m, testCheckItems[2:]...))
+	ui.Template = template.Must(template.New("").Parse(`<label>{{.Checkbox "name=\"c\""}} {{.Name}}</label>`))
+	th.NoErr(rq2.UI(ui))
+	th.Equal(rq2.BodyString(), `<div id="Jid.1"><label><input id="Jid.2" type="checkbox" name="c" checked> b</label></div>`)
+}
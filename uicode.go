@@ -0,0 +1,129 @@
+package jaws
+
+import (
+	"encoding/json"
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/linkdata/deadlock"
+)
+
+// DefaultCodeDebounce is the default delay the browser waits after the last
+// change in a code editor before sending the new content.
+const DefaultCodeDebounce = time.Millisecond * 300
+
+// CodeAnnotation is a message shown in a code editor at the given position.
+//
+// Line and Col are 1-based. Severity is typically "error", "warning" or "info".
+type CodeAnnotation struct {
+	Line     int    `json:"line"`
+	Col      int    `json:"col"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// CodeAnnotationGetter may be implemented by the StringSetter bound to a
+// UiCode in order to have annotations pushed to the editor.
+type CodeAnnotationGetter interface {
+	JawsGetAnnotations(e *Element) []CodeAnnotation
+}
+
+// UiCode renders a HTML textarea that the browser upgrades to a code editor.
+//
+// The upgrade is done by setting the Javascript variable `jawsCodeAdapter` to a
+// function that takes the textarea and returns an object implementing
+// setValue(str), setReadOnly(bool) and annotate(list). Without an adapter
+// a plain textarea is used.
+type UiCode struct {
+	UiInputText
+	Language string        // language name passed to the editor in the data-jaws-code attribute
+	Debounce time.Duration // delay before sending changes, defaults to DefaultCodeDebounce
+	ReadOnly BoolSetter    // if not nil, controls whether the editor is read-only
+	mu       deadlock.Mutex
+	lastRO   bool
+	lastAnn  string
+}
+
+func NewUiCode(language string, g StringSetter) *UiCode {
+	return &UiCode{
+		UiInputText: UiInputText{
+			StringSetter: g,
+		},
+		Language: language,
+		Debounce: DefaultCodeDebounce,
+	}
+}
+
+func (ui *UiCode) readOnly(e *Element) bool {
+	return ui.ReadOnly != nil && ui.ReadOnly.JawsGetBool(e)
+}
+
+func (ui *UiCode) annotations(e *Element) string {
+	if ag, ok := ui.StringSetter.(CodeAnnotationGetter); ok {
+		if anns := ag.JawsGetAnnotations(e); len(anns) > 0 {
+			b, err := json.Marshal(anns)
+			maybePanic(err)
+			return string(b)
+		}
+	}
+	return ""
+}
+
+func (ui *UiCode) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.StringSetter)
+	if ui.ReadOnly != nil {
+		e.Tag(ui.ReadOnly)
+	}
+	attrs := ui.parseParams(e, params)
+	attrs = append(attrs, `data-jaws-code=`+strconv.Quote(html.EscapeString(ui.Language)))
+	if ui.Debounce > 0 {
+		attrs = append(attrs, `data-jaws-debounce="`+strconv.FormatInt(ui.Debounce.Milliseconds(), 10)+`"`)
+	}
+	v := ui.JawsGetString(e)
+	ui.Last.Store(v)
+	ro := ui.readOnly(e)
+	if ro {
+		attrs = append(attrs, "readonly")
+	}
+	ann := ui.annotations(e)
+	if ann != "" {
+		attrs = append(attrs, `data-jaws-annotations="`+html.EscapeString(ann)+`"`)
+	}
+	ui.mu.Lock()
+	ui.lastRO, ui.lastAnn = ro, ann
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "textarea", "", template.HTML(html.EscapeString(v)), e.auditLabel(attrs)...) // #nosec G203
+}
+
+func (ui *UiCode) JawsUpdate(e *Element) {
+	ui.UiInputText.JawsUpdate(e)
+	ro := ui.readOnly(e)
+	ann := ui.annotations(e)
+	ui.mu.Lock()
+	roChanged := ui.lastRO != ro
+	annChanged := ui.lastAnn != ann
+	ui.lastRO, ui.lastAnn = ro, ann
+	ui.mu.Unlock()
+	if roChanged {
+		if ro {
+			e.SetAttr("readonly", "")
+		} else {
+			e.RemoveAttr("readonly")
+		}
+	}
+	if annChanged {
+		if ann == "" {
+			e.RemoveAttr("data-jaws-annotations")
+		} else {
+			e.SetAttr("data-jaws-annotations", ann)
+		}
+	}
+}
+
+// Code renders a HTML textarea to be upgraded to a code editor for the given language.
+func (rq RequestWriter) Code(language string, value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiCode(language, makeStringSetter(value)), params...)
+}
@@ -0,0 +1,70 @@
+package jaws
+
+import (
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testCodeSetter struct {
+	*testSetter[string]
+	anns []CodeAnnotation
+}
+
+func (tcs *testCodeSetter) JawsGetAnnotations(e *Element) []CodeAnnotation {
+	return tcs.anns
+}
+
+func TestRequest_Code(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ro := newTestSetter(true)
+	ts := &testCodeSetter{testSetter: newTestSetter("a < b")}
+	ui := NewUiCode("go", ts)
+	ui.ReadOnly = ro
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<textarea id="Jid.1" data-jaws-code="go" data-jaws-debounce="300" readonly>a &lt; b</textarea>`)
+
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(ro))
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	ro.Set(false)
+	ts.Set("x")
+	ts.anns = []CodeAnnotation{{Line: 1, Col: 2, Severity: "error", Message: "bad"}}
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{
+		{Data: "x", Jid: 1, What: what.Value},
+		{Data: "readonly", Jid: 1, What: what.RAttr},
+		{Data: "data-jaws-annotations\n" + `[{"line":1,"col":2,"severity":"error","message":"bad"}]`, Jid: 1, What: what.SAttr},
+	})
+	elem.wsQueue = elem.wsQueue[:0]
+
+	ro.Set(true)
+	ts.anns = nil
+	ui.JawsUpdate(elem)
+	th.Equal(elem.wsQueue, []wsMsg{
+		{Data: "readonly\n", Jid: 1, What: what.SAttr},
+		{Data: "data-jaws-annotations", Jid: 1, What: what.RAttr},
+	})
+}
+
+func TestRequest_CodeNoDebounce(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ts := &testCodeSetter{
+		testSetter: newTestSetter(""),
+		anns:       []CodeAnnotation{{Line: 1, Message: `"`}},
+	}
+	ui := NewUiCode("", ts)
+	ui.Debounce = 0
+	th.NoErr(rq.UI(ui))
+	th.Equal(rq.BodyString(), `<textarea id="Jid.1" data-jaws-code="" data-jaws-annotations="[{&#34;line&#34;:1,&#34;col&#34;:0,&#34;severity&#34;:&#34;&#34;,&#34;message&#34;:&#34;\&#34;&#34;}]"></textarea>`)
+}
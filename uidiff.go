@@ -0,0 +1,172 @@
+package jaws
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+
+	"github.com/linkdata/deadlock"
+)
+
+// DefaultDiffContext is the default number of unchanged lines shown around changes.
+const DefaultDiffContext = 3
+
+// DiffGetter provides the lines of a diff to render.
+type DiffGetter interface {
+	JawsGetDiff(e *Element) []DiffLine
+}
+
+type diffStrings struct{ a, b StringSetter }
+
+func (d diffStrings) JawsGetDiff(e *Element) []DiffLine {
+	return DiffStrings(d.a.JawsGetString(e), d.b.JawsGetString(e))
+}
+
+func (d diffStrings) JawsGetTag(rq *Request) interface{} {
+	return []interface{}{d.a, d.b}
+}
+
+// NewDiffGetter returns a DiffGetter that computes the line diff between
+// the two StringSetters, and is tagged with both of them.
+func NewDiffGetter(a, b StringSetter) DiffGetter {
+	return diffStrings{a: a, b: b}
+}
+
+// UiDiff renders a line diff as a HTML table, either unified
+// (the default) or side-by-side.
+type UiDiff struct {
+	UiHtml
+	DiffGetter
+	SideBySide bool // render old and new text in separate columns
+	Context    int  // number of unchanged lines to show around changes
+	mu         deadlock.Mutex
+	last       string // last rendered inner HTML
+}
+
+func NewUiDiff(dg DiffGetter) *UiDiff {
+	return &UiDiff{
+		DiffGetter: dg,
+		Context:    DefaultDiffContext,
+	}
+}
+
+func appendDiffCell(b []byte, class string, lineNo int, text string) []byte {
+	b = append(b, `<td class="jaws-diff-num">`...)
+	if lineNo > 0 {
+		b = strconv.AppendInt(b, int64(lineNo), 10)
+	}
+	b = append(b, `</td><td class="`...)
+	b = append(b, class...)
+	b = append(b, `">`...)
+	b = append(b, html.EscapeString(text)...)
+	b = append(b, `</td>`...)
+	return b
+}
+
+func diffClass(k DiffKind) string {
+	switch k {
+	case DiffDelete:
+		return "jaws-diff-del"
+	case DiffInsert:
+		return "jaws-diff-ins"
+	}
+	return "jaws-diff-eq"
+}
+
+func (ui *UiDiff) inner(e *Element) string {
+	var b []byte
+	cols := "3"
+	if ui.SideBySide {
+		cols = "4"
+	}
+	for _, h := range DiffHunks(ui.JawsGetDiff(e), ui.Context) {
+		b = append(b, `<tr class="jaws-diff-hunk"><td colspan="`+cols+`">`...)
+		b = append(b, h.Header()...)
+		b = append(b, "</td></tr>"...)
+		if ui.SideBySide {
+			lines := h.Lines
+			for len(lines) > 0 {
+				if lines[0].Kind == DiffEqual {
+					b = append(b, `<tr>`...)
+					b = appendDiffCell(b, "jaws-diff-eq", lines[0].OldLine, lines[0].Text)
+					b = appendDiffCell(b, "jaws-diff-eq", lines[0].NewLine, lines[0].Text)
+					b = append(b, `</tr>`...)
+					lines = lines[1:]
+					continue
+				}
+				// pair up a run of deletions with the following run of insertions
+				var dels, inss []DiffLine
+				for len(lines) > 0 && lines[0].Kind == DiffDelete {
+					dels = append(dels, lines[0])
+					lines = lines[1:]
+				}
+				for len(lines) > 0 && lines[0].Kind == DiffInsert {
+					inss = append(inss, lines[0])
+					lines = lines[1:]
+				}
+				for i := 0; i < len(dels) || i < len(inss); i++ {
+					b = append(b, `<tr>`...)
+					if i < len(dels) {
+						b = appendDiffCell(b, "jaws-diff-del", dels[i].OldLine, dels[i].Text)
+					} else {
+						b = appendDiffCell(b, "jaws-diff-eq", 0, "")
+					}
+					if i < len(inss) {
+						b = appendDiffCell(b, "jaws-diff-ins", inss[i].NewLine, inss[i].Text)
+					} else {
+						b = appendDiffCell(b, "jaws-diff-eq", 0, "")
+					}
+					b = append(b, `</tr>`...)
+				}
+			}
+		} else {
+			for _, l := range h.Lines {
+				b = append(b, `<tr class="`...)
+				b = append(b, diffClass(l.Kind)...)
+				b = append(b, `"><td class="jaws-diff-num">`...)
+				if l.OldLine > 0 {
+					b = strconv.AppendInt(b, int64(l.OldLine), 10)
+				}
+				b = append(b, `</td>`...)
+				b = appendDiffCell(b, "jaws-diff-text", l.NewLine, string(l.Kind)+l.Text)
+				b = append(b, `</tr>`...)
+			}
+		}
+	}
+	return string(b)
+}
+
+func (ui *UiDiff) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.DiffGetter)
+	attrs := append(ui.parseParams(e, params), `class="jaws-diff"`)
+	inner := ui.inner(e)
+	ui.mu.Lock()
+	ui.last = inner
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "table", "", template.HTML(inner), attrs...) // #nosec G203
+}
+
+func (ui *UiDiff) JawsUpdate(e *Element) {
+	inner := ui.inner(e)
+	ui.mu.Lock()
+	changed := ui.last != inner
+	ui.last = inner
+	ui.mu.Unlock()
+	if changed {
+		e.SetInner(template.HTML(inner)) // #nosec G203
+	}
+}
+
+// Diff renders a HTML table showing the unified line diff between oldText and newText,
+// which may be strings or StringSetters. It is updated when either of them is dirtied.
+func (rq RequestWriter) Diff(oldText, newText interface{}, params ...interface{}) error {
+	return rq.UI(NewUiDiff(NewDiffGetter(makeStringSetter(oldText), makeStringSetter(newText))), params...)
+}
+
+// DiffSideBySide is like Diff, but renders the old and new text in separate columns.
+func (rq RequestWriter) DiffSideBySide(oldText, newText interface{}, params ...interface{}) error {
+	ui := NewUiDiff(NewDiffGetter(makeStringSetter(oldText), makeStringSetter(newText)))
+	ui.SideBySide = true
+	return rq.UI(ui, params...)
+}
@@ -0,0 +1,48 @@
+package jaws
+
+import (
+	"testing"
+)
+
+func TestRequest_Diff(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	a := newTestSetter("a\nb")
+	b := newTestSetter("a\nb")
+	th.NoErr(rq.Diff(a, b))
+	th.Equal(rq.BodyString(), `<table id="Jid.1" class="jaws-diff"></table>`)
+
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(a))
+	th.True(elem.HasTag(b))
+
+	b.Set("a\n<c>")
+	elem.Ui().JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 1)
+	th.Equal(elem.wsQueue[0].Data, `<tr class="jaws-diff-hunk"><td colspan="3">@@ -1,2 +1,2 @@</td></tr>`+
+		`<tr class="jaws-diff-eq"><td class="jaws-diff-num">1</td><td class="jaws-diff-num">1</td><td class="jaws-diff-text"> a</td></tr>`+
+		`<tr class="jaws-diff-del"><td class="jaws-diff-num">2</td><td class="jaws-diff-num"></td><td class="jaws-diff-text">-b</td></tr>`+
+		`<tr class="jaws-diff-ins"><td class="jaws-diff-num"></td><td class="jaws-diff-num">2</td><td class="jaws-diff-text">+&lt;c&gt;</td></tr>`)
+
+	elem.wsQueue = elem.wsQueue[:0]
+	elem.Ui().JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+}
+
+func TestRequest_DiffSideBySide(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.DiffSideBySide("a\nb\nc", "a\nx\ny"))
+	th.Equal(rq.BodyString(), `<table id="Jid.1" class="jaws-diff">`+
+		`<tr class="jaws-diff-hunk"><td colspan="4">@@ -1,3 +1,3 @@</td></tr>`+
+		`<tr><td class="jaws-diff-num">1</td><td class="jaws-diff-eq">a</td><td class="jaws-diff-num">1</td><td class="jaws-diff-eq">a</td></tr>`+
+		`<tr><td class="jaws-diff-num">2</td><td class="jaws-diff-del">b</td><td class="jaws-diff-num">2</td><td class="jaws-diff-ins">x</td></tr>`+
+		`<tr><td class="jaws-diff-num">3</td><td class="jaws-diff-del">c</td><td class="jaws-diff-num">3</td><td class="jaws-diff-ins">y</td></tr>`+
+		`</table>`)
+}
@@ -18,6 +18,9 @@ func (ui *UiHtml) parseGetter(e *Element, getter interface{}) {
 			if ch, ok := getter.(ClickHandler); ok {
 				e.handlers = append(e.handlers, clickHandlerWapper{ch})
 			}
+			if ph, ok := getter.(PasteHandler); ok {
+				e.handlers = append(e.handlers, pasteHandlerWrapper{ph})
+			}
 			if eh, ok := getter.(EventHandler); ok {
 				e.handlers = append(e.handlers, eh)
 			}
@@ -29,6 +32,7 @@ func (ui *UiHtml) parseGetter(e *Element, getter interface{}) {
 }
 
 func parseParams(elem *Element, params []interface{}) (attrs []string) {
+	var preserve []string
 	for i := range params {
 		switch data := params[i].(type) {
 		case template.HTML:
@@ -41,6 +45,28 @@ func parseParams(elem *Element, params []interface{}) (attrs []string) {
 			attrs = append(attrs, data)
 		case []string:
 			attrs = append(attrs, data...)
+		case Confirm:
+			attrs = append(attrs, elem.confirmAttrs(data)...)
+		case Idempotent:
+			attrs = append(attrs, elem.onceAttrs(data)...)
+		case Label:
+			attrs = append(attrs, string(Attr("aria-label", string(data))))
+		case Delta:
+			elem.delta = true
+		case Redact:
+			elem.redact = true
+		case Delegate:
+			elem.delegate = true
+		case Alpine:
+			attrs = append(attrs, string(Attr("x-data", string(data))))
+			preserve = append(preserve, alpinePreserve...)
+		case Stimulus:
+			attrs = append(attrs, string(Attr("data-controller", string(data))))
+			preserve = append(preserve, stimulusPreserve(string(data))...)
+		case Preserve:
+			preserve = append(preserve, data...)
+		case Animate:
+			attrs = append(attrs, data.attr())
 		case EventFn:
 			if data != nil {
 				elem.handlers = append(elem.handlers, eventFnWrapper{data})
@@ -49,12 +75,39 @@ func parseParams(elem *Element, params []interface{}) (attrs []string) {
 			if h, ok := data.(ClickHandler); ok {
 				elem.handlers = append(elem.handlers, clickHandlerWapper{h})
 			}
+			if h, ok := data.(InputHandler); ok {
+				elem.handlers = append(elem.handlers, inputHandlerWrapper{h})
+			}
+			if h, ok := data.(ChangeHandler); ok {
+				elem.handlers = append(elem.handlers, changeHandlerWrapper{h})
+				attrs = append(attrs, "data-jaws-change")
+			}
+			if h, ok := data.(KeyHandler); ok {
+				elem.handlers = append(elem.handlers, keyHandlerWrapper{h})
+				attrs = append(attrs, "data-jaws-keys")
+			}
+			if h, ok := data.(FullscreenHandler); ok {
+				elem.handlers = append(elem.handlers, fullscreenHandlerWrapper{h})
+			}
+			if h, ok := data.(CustomHandler); ok {
+				elem.handlers = append(elem.handlers, customHandlerWrapper{h})
+			}
+			if h, ok := data.(PasteHandler); ok {
+				elem.handlers = append(elem.handlers, pasteHandlerWrapper{h})
+				attrs = append(attrs, "data-jaws-paste")
+			}
 			if h, ok := data.(EventHandler); ok {
 				elem.handlers = append(elem.handlers, h)
 			}
 			elem.Tag(data)
 		}
 	}
+	if s := preserveAttrs(preserve); s != "" {
+		attrs = append(attrs, s)
+	}
+	if elem.Request.Jaws.Redaction.matchAttrs(attrs) {
+		elem.redact = true
+	}
 	return
 }
 
@@ -1,20 +1,37 @@
 package jaws
 
 import (
+	"html/template"
 	"io"
 )
 
 type UiHtmlInner struct {
 	UiHtml
 	HtmlGetter
+	async *asyncHtml // non-nil if the content is fetched in the background
 }
 
 func (ui *UiHtmlInner) renderInner(e *Element, w io.Writer, htmltag, htmltype string, params []interface{}) error {
 	ui.parseGetter(e, ui.HtmlGetter)
+	ph, params, found := extractPlaceholder(params)
 	attrs := ui.parseParams(e, params)
+	attrs = clickableAttrs(htmltag, e.isClickable(ui.Tag), attrs)
+	if _, ok := ui.HtmlGetter.(AsyncHtmlGetter); ok && !found {
+		ph, found = DefaultPlaceholder, true
+	}
+	if found {
+		ui.async = &asyncHtml{getter: ui.HtmlGetter}
+		e.Tag(ui.async)
+		ui.async.start(e)
+		return WriteHtmlInner(w, e.Jid(), htmltag, htmltype, template.HTML(ph), attrs...) // #nosec G203
+	}
 	return WriteHtmlInner(w, e.Jid(), htmltag, htmltype, ui.JawsGetHtml(e), attrs...)
 }
 
 func (ui *UiHtmlInner) JawsUpdate(e *Element) {
+	if ui.async != nil {
+		ui.async.update(e)
+		return
+	}
 	e.SetInner(ui.JawsGetHtml(e))
 }
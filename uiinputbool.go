@@ -20,7 +20,8 @@ func (ui *UiInputBool) renderBoolInput(e *Element, w io.Writer, htmltype string,
 	if v {
 		attrs = append(attrs, "checked")
 	}
-	return WriteHtmlInput(w, e.Jid(), htmltype, "", attrs...)
+	e.redactInput(htmltype)
+	return WriteHtmlInput(w, e.Jid(), htmltype, "", e.auditLabel(attrs)...)
 }
 
 func (ui *UiInputBool) JawsUpdate(e *Element) {
@@ -48,6 +49,7 @@ func (ui *UiInputBool) JawsEvent(e *Element, wht what.What, val string) (err err
 		if err != nil {
 			return
 		}
+		e.Handled()
 	}
 	return ui.UiHtml.JawsEvent(e, wht, val)
 }
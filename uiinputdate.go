@@ -20,7 +20,8 @@ func (ui *UiInputDate) renderDateInput(e *Element, w io.Writer, jid Jid, htmltyp
 	ui.parseGetter(e, ui.TimeSetter)
 	attrs := ui.parseParams(e, params)
 	ui.Last.Store(ui.JawsGetTime(e))
-	return WriteHtmlInput(w, e.Jid(), htmltype, ui.str(), attrs...)
+	e.redactInput(htmltype)
+	return WriteHtmlInput(w, e.Jid(), htmltype, ui.str(), e.auditLabel(attrs)...)
 }
 
 func (ui *UiInputDate) JawsUpdate(e *Element) {
@@ -43,6 +44,7 @@ func (ui *UiInputDate) JawsEvent(e *Element, wht what.What, val string) (err err
 		if err != nil {
 			return
 		}
+		e.Handled()
 	}
 	return ui.UiHtml.JawsEvent(e, wht, val)
 }
@@ -20,7 +20,8 @@ func (ui *UiInputFloat) renderFloatInput(e *Element, w io.Writer, htmltype strin
 	ui.parseGetter(e, ui.FloatSetter)
 	attrs := ui.parseParams(e, params)
 	ui.Last.Store(ui.JawsGetFloat(e))
-	return WriteHtmlInput(w, e.Jid(), htmltype, ui.str(), attrs...)
+	e.redactInput(htmltype)
+	return WriteHtmlInput(w, e.Jid(), htmltype, ui.str(), e.auditLabel(attrs)...)
 }
 
 func (ui *UiInputFloat) JawsUpdate(e *Element) {
@@ -43,6 +44,7 @@ func (ui *UiInputFloat) JawsEvent(e *Element, wht what.What, val string) (err er
 		if err != nil {
 			return
 		}
+		e.Handled()
 	}
 	return ui.UiHtml.JawsEvent(e, wht, val)
 }
@@ -9,14 +9,19 @@ import (
 type UiInputText struct {
 	UiInput
 	StringSetter
+	MaxLength int // if positive, the maximum number of characters, see MaxLength
 }
 
-func (ui *UiInputText) renderStringInput(e *Element, w io.Writer, htmltype string, params ...interface{}) error {
+func (ui *UiInputText) renderStringInput(e *Element, w io.Writer, htmltype string, params ...interface{}) (err error) {
 	ui.parseGetter(e, ui.StringSetter)
-	attrs := ui.parseParams(e, params)
+	attrs := ui.parseParams(e, ui.extractMaxLength(params))
 	v := ui.JawsGetString(e)
 	ui.Last.Store(v)
-	return WriteHtmlInput(w, e.Jid(), htmltype, v, attrs...)
+	e.redactInput(htmltype)
+	if err = WriteHtmlInput(w, e.Jid(), htmltype, v, e.auditLabel(attrs)...); err == nil {
+		err = ui.writeCounter(w, v)
+	}
+	return
 }
 
 func (ui *UiInputText) JawsUpdate(e *Element) {
@@ -28,11 +33,14 @@ func (ui *UiInputText) JawsUpdate(e *Element) {
 func (ui *UiInputText) JawsEvent(e *Element, wht what.What, val string) (err error) {
 	if wht == what.Input {
 		ui.Last.Store(val)
-		err = ui.StringSetter.JawsSetString(e, val)
+		if err = ui.checkMaxLength(val); err == nil {
+			err = ui.StringSetter.JawsSetString(e, val)
+		}
 		e.Dirty(ui.Tag)
 		if err != nil {
 			return
 		}
+		e.Handled()
 	}
 	return ui.UiHtml.JawsEvent(e, wht, val)
 }
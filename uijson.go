@@ -0,0 +1,301 @@
+package jaws
+
+import (
+	"bytes"
+	"encoding/json"
+	"html"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/deadlock"
+)
+
+// DefaultJsonLimit is the default number of array or object members rendered
+// before a "more" link is shown.
+const DefaultJsonLimit = 100
+
+const jsonClickPrefix = "jaws.json:"
+const jsonMorePrefix = "jaws.json.more:"
+
+// JsonGetter provides a value to be rendered by UiJson.
+type JsonGetter interface {
+	JawsGetJson(e *Element) interface{}
+}
+
+// UiJson renders a Go value as a collapsible tree, using it's encoding/json representation.
+//
+// Collapsed arrays and objects are not rendered until expanded, and at most
+// Limit members of an array or object are rendered at a time. Each node has
+// a button that copies it's path (e.g. `$.items[2].name`) to the clipboard.
+type UiJson struct {
+	UiHtml
+	Value    interface{} // the value to render, if it is a JsonGetter it's result is used instead
+	Limit    int         // number of members to render at a time, DefaultJsonLimit if not positive
+	Depth    int         // nodes above this depth start out expanded
+	mu       deadlock.Mutex
+	expanded map[string]int // >0 open showing that many members, <0 explicitly closed
+}
+
+func NewUiJson(value interface{}) *UiJson {
+	return &UiJson{
+		Value:    value,
+		Limit:    DefaultJsonLimit,
+		Depth:    1,
+		expanded: make(map[string]int),
+	}
+}
+
+func (ui *UiJson) limit() int {
+	if ui.Limit > 0 {
+		return ui.Limit
+	}
+	return DefaultJsonLimit
+}
+
+func (ui *UiJson) setExpandedLocked(path string, shown int) {
+	if ui.expanded == nil {
+		ui.expanded = make(map[string]int)
+	}
+	ui.expanded[path] = shown
+}
+
+func (ui *UiJson) value(e *Element) (v interface{}, err error) {
+	v = ui.Value
+	if g, ok := v.(JsonGetter); ok {
+		v = g.JawsGetJson(e)
+	}
+	var b []byte
+	if b, err = json.Marshal(v); err == nil {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		v = nil
+		err = dec.Decode(&v)
+	}
+	return
+}
+
+func jsonPathKey(path, key string) string {
+	ident := key != ""
+	for i, ch := range key {
+		if !(ch == '_' || (ch >= 'a' && ch <= 'z') || (ch >= 'A' && ch <= 'Z') || (i > 0 && ch >= '0' && ch <= '9')) {
+			ident = false
+			break
+		}
+	}
+	if ident {
+		return path + "." + key
+	}
+	return path + "[" + strconv.Quote(key) + "]"
+}
+
+// jsonContainer returns the array or object at path in v, or nil if
+// there is none. Only paths as generated by jsonPathKey are found.
+func jsonContainer(v interface{}, path string) interface{} {
+	rest, ok := strings.CutPrefix(path, "$")
+	if !ok {
+		return nil
+	}
+	canon := "$"
+	for rest != "" {
+		switch node := v.(type) {
+		case map[string]interface{}:
+			var key string
+			if strings.HasPrefix(rest, "[") {
+				quoted, err := strconv.QuotedPrefix(rest[1:])
+				if err != nil || !strings.HasPrefix(rest[1+len(quoted):], "]") {
+					return nil
+				}
+				key, _ = strconv.Unquote(quoted)
+				rest = rest[2+len(quoted):]
+			} else if after, ok := strings.CutPrefix(rest, "."); ok {
+				n := strings.IndexAny(after, ".[")
+				if n < 0 {
+					n = len(after)
+				}
+				key, rest = after[:n], after[n:]
+			} else {
+				return nil
+			}
+			if v, ok = node[key]; !ok {
+				return nil
+			}
+			canon = jsonPathKey(canon, key)
+		case []interface{}:
+			end := strings.IndexByte(rest, ']')
+			if !strings.HasPrefix(rest, "[") || end < 0 {
+				return nil
+			}
+			i, err := strconv.Atoi(rest[1:end])
+			if err != nil || i < 0 || i >= len(node) {
+				return nil
+			}
+			v, rest = node[i], rest[end+1:]
+			canon += "[" + strconv.Itoa(i) + "]"
+		default:
+			return nil
+		}
+	}
+	switch v.(type) {
+	case map[string]interface{}, []interface{}:
+		if canon == path {
+			return v
+		}
+	}
+	return nil
+}
+
+func appendJsonCopy(b []byte, path string) []byte {
+	b = append(b, `<button type="button" class="jaws-json-copy" title="`...)
+	b = append(b, html.EscapeString(path)...)
+	b = append(b, `" data-jaws-copy="`...)
+	b = append(b, html.EscapeString(path)...)
+	b = append(b, `">&#x2398;</button>`...)
+	return b
+}
+
+func appendJsonScalar(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		b = append(b, `<span class="jaws-json-null">null</span>`...)
+	case bool:
+		b = append(b, `<span class="jaws-json-bool">`...)
+		b = strconv.AppendBool(b, v)
+		b = append(b, `</span>`...)
+	case json.Number:
+		b = append(b, `<span class="jaws-json-number">`...)
+		b = append(b, html.EscapeString(v.String())...)
+		b = append(b, `</span>`...)
+	case string:
+		b = append(b, `<span class="jaws-json-string">`...)
+		b = append(b, html.EscapeString(strconv.Quote(v))...)
+		b = append(b, `</span>`...)
+	}
+	return b
+}
+
+func (ui *UiJson) appendNode(b []byte, depth int, path, label string, v interface{}) []byte {
+	var keys []string
+	var count int
+	var openCh, closeCh byte
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		count = len(keys)
+		openCh, closeCh = '{', '}'
+	case []interface{}:
+		count = len(v)
+		openCh, closeCh = '[', ']'
+	default:
+		b = append(b, `<div class="jaws-json-node">`...)
+		b = append(b, label...)
+		b = appendJsonScalar(b, v)
+		b = appendJsonCopy(b, path)
+		b = append(b, `</div>`...)
+		return b
+	}
+
+	shown := ui.expanded[path]
+	if shown == 0 && depth < ui.Depth {
+		shown = ui.limit()
+		ui.setExpandedLocked(path, shown)
+	}
+	b = append(b, `<details class="jaws-json-node"`...)
+	if shown > 0 {
+		b = append(b, ` open`...)
+	}
+	b = append(b, `><summary name="`...)
+	b = append(b, html.EscapeString(jsonClickPrefix+path)...)
+	b = append(b, `">`...)
+	b = append(b, label...)
+	b = append(b, openCh)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(count), 10)
+	b = append(b, ' ', closeCh)
+	b = appendJsonCopy(b, path)
+	b = append(b, `</summary>`...)
+	if shown > 0 {
+		for i := 0; i < count && i < shown; i++ {
+			switch v := v.(type) {
+			case map[string]interface{}:
+				k := keys[i]
+				b = ui.appendNode(b, depth+1, jsonPathKey(path, k), `<span class="jaws-json-key">`+html.EscapeString(k)+`</span>: `, v[k])
+			case []interface{}:
+				b = ui.appendNode(b, depth+1, path+"["+strconv.Itoa(i)+"]", `<span class="jaws-json-key">`+strconv.Itoa(i)+`</span>: `, v[i])
+			}
+		}
+		if count > shown {
+			b = append(b, `<div class="jaws-json-more" name="`...)
+			b = append(b, html.EscapeString(jsonMorePrefix+path)...)
+			b = append(b, `">&hellip; `...)
+			b = strconv.AppendInt(b, int64(count-shown), 10)
+			b = append(b, ` more</div>`...)
+		}
+	}
+	b = append(b, `</details>`...)
+	return b
+}
+
+func (ui *UiJson) inner(e *Element) template.HTML {
+	v, err := ui.value(e)
+	if err != nil {
+		return template.HTML(`<span class="jaws-json-error">` + html.EscapeString(err.Error()) + `</span>`) // #nosec G203
+	}
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	return template.HTML(ui.appendNode(nil, 0, "$", "", v)) // #nosec G203
+}
+
+func (ui *UiJson) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	if g, ok := ui.Value.(JsonGetter); ok {
+		ui.parseGetter(e, g)
+	}
+	e.Tag(ui)
+	attrs := append(ui.parseParams(e, params), `class="jaws-json"`)
+	return WriteHtmlInner(w, e.Jid(), "div", "", ui.inner(e), attrs...)
+}
+
+func (ui *UiJson) JawsUpdate(e *Element) {
+	e.SetInner(ui.inner(e))
+}
+
+// JawsClick handles expanding, collapsing and showing more members of nodes.
+// Paths that don't name an array or object in the current value are ignored.
+func (ui *UiJson) JawsClick(e *Element, name string) (err error) {
+	err = ErrEventUnhandled
+	var path string
+	var ok, more bool
+	if path, ok = strings.CutPrefix(name, jsonClickPrefix); !ok {
+		path, more = strings.CutPrefix(name, jsonMorePrefix)
+	}
+	if !ok && !more {
+		return
+	}
+	var v interface{}
+	if v, err = ui.value(e); err != nil || jsonContainer(v, path) == nil {
+		return
+	}
+	ui.mu.Lock()
+	limit := ui.limit()
+	switch {
+	case more:
+		ui.setExpandedLocked(path, max(ui.expanded[path], limit)+limit)
+	case ui.expanded[path] > 0:
+		ui.setExpandedLocked(path, -1)
+	default:
+		ui.setExpandedLocked(path, limit)
+	}
+	ui.mu.Unlock()
+	e.Dirty(ui)
+	return
+}
+
+// Json renders a HTML div element containing a collapsible tree of the given value.
+func (rq RequestWriter) Json(value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiJson(value), params...)
+}
@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJsonGetter struct{ v interface{} }
+
+func (g *testJsonGetter) JawsGetJson(e *Element) interface{} {
+	return g.v
+}
+
+func TestRequest_Json(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Json(map[string]interface{}{"a": 1, "b c": []string{"x"}, "d": nil, "e": true}))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" class="jaws-json">`+
+		`<details class="jaws-json-node" open><summary name="jaws.json:$">{ 4 }<button type="button" class="jaws-json-copy" title="$" data-jaws-copy="$">&#x2398;</button></summary>`+
+		`<div class="jaws-json-node"><span class="jaws-json-key">a</span>: <span class="jaws-json-number">1</span><button type="button" class="jaws-json-copy" title="$.a" data-jaws-copy="$.a">&#x2398;</button></div>`+
+		`<details class="jaws-json-node"><summary name="jaws.json:$[&#34;b c&#34;]"><span class="jaws-json-key">b c</span>: [ 1 ]<button type="button" class="jaws-json-copy" title="$[&#34;b c&#34;]" data-jaws-copy="$[&#34;b c&#34;]">&#x2398;</button></summary></details>`+
+		`<div class="jaws-json-node"><span class="jaws-json-key">d</span>: <span class="jaws-json-null">null</span><button type="button" class="jaws-json-copy" title="$.d" data-jaws-copy="$.d">&#x2398;</button></div>`+
+		`<div class="jaws-json-node"><span class="jaws-json-key">e</span>: <span class="jaws-json-bool">true</span><button type="button" class="jaws-json-copy" title="$.e" data-jaws-copy="$.e">&#x2398;</button></div>`+
+		`</details></div>`)
+}
+
+func TestUiJson_Expand(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	g := &testJsonGetter{v: []interface{}{"s", []int{1, 2, 3}}}
+	ui := NewUiJson(g)
+	ui.Limit = 2
+	th.NoErr(rq.UI(ui))
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(g))
+	th.True(elem.HasTag(ui))
+	th.True(!strings.Contains(rq.BodyString(), `jaws-json-number`))
+
+	th.Equal(ui.JawsClick(elem, "something else"), ErrEventUnhandled)
+	th.NoErr(ui.JawsClick(elem, jsonClickPrefix+"$[1]"))
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 1)
+	th.Equal(elem.wsQueue[0].What, what.Inner)
+	inner := elem.wsQueue[0].Data
+	th.Equal(strings.Count(inner, `jaws-json-number`), 2)
+	th.True(strings.Contains(inner, `name="jaws.json.more:$[1]">&hellip; 1 more</div>`))
+
+	th.NoErr(ui.JawsClick(elem, jsonMorePrefix+"$[1]"))
+	th.Equal(strings.Count(string(ui.inner(elem)), `jaws-json-number`), 3)
+
+	th.NoErr(ui.JawsClick(elem, jsonClickPrefix+"$"))
+	th.True(strings.HasPrefix(string(ui.inner(elem)), `<details class="jaws-json-node"><summary`))
+
+	g.v = func() {}
+	th.True(strings.Contains(string(ui.inner(elem)), `jaws-json-error`))
+}
+
+func TestUiJson_ZeroValue(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ui := &UiJson{Value: []interface{}{1, 2}}
+	th.NoErr(rq.UI(ui))
+	elem := rq.getElementByJid(1)
+	th.True(!strings.Contains(rq.BodyString(), `jaws-json-number`))
+	th.NoErr(ui.JawsClick(elem, jsonClickPrefix+"$"))
+	th.Equal(strings.Count(string(ui.inner(elem)), `jaws-json-number`), 2)
+
+	ui = &UiJson{Value: []interface{}{1, 2}}
+	th.NoErr(ui.JawsClick(elem, jsonMorePrefix+"$"))
+	th.Equal(ui.expanded["$"], DefaultJsonLimit*2)
+
+	ui = &UiJson{Value: []interface{}{1, 2}, Depth: 1}
+	th.Equal(strings.Count(string(ui.inner(elem)), `jaws-json-number`), 2)
+}
+
+func Test_jsonContainer(t *testing.T) {
+	th := newTestHelper(t)
+	ui := &UiJson{Value: map[string]interface{}{"a": []interface{}{1, map[string]interface{}{"b c": []int{}}}, "s": "x"}}
+	v, err := ui.value(nil)
+	th.NoErr(err)
+	for path, want := range map[string]bool{
+		"$":             true,
+		"$.a":           true,
+		"$.a[1]":        true,
+		`$.a[1]["b c"]`: true,
+		`$["a"]`:        false,
+		"$.a[0]":        false,
+		"$.a[2]":        false,
+		"$.a[-1]":       false,
+		"$.a[01]":       false,
+		"$.s":           false,
+		"$.x":           false,
+		"$.a[1":         false,
+		`$.a[1]["b c"`:  false,
+		"x":             false,
+		"":              false,
+	} {
+		th.Equal(jsonContainer(v, path) != nil, want)
+	}
+}
+
+func TestUiJson_ClickUnknownPath(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ui := NewUiJson([]interface{}{[]interface{}{1}})
+	th.NoErr(rq.UI(ui))
+	elem := rq.getElementByJid(1)
+	n := len(ui.expanded)
+	th.NoErr(ui.JawsClick(elem, jsonClickPrefix+"$[7]"))
+	th.NoErr(ui.JawsClick(elem, jsonMorePrefix+"$.nope"))
+	th.Equal(len(ui.expanded), n)
+	th.NoErr(ui.JawsClick(elem, jsonClickPrefix+"$[0]"))
+	th.Equal(len(ui.expanded), n+1)
+}
@@ -0,0 +1,128 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/linkdata/deadlock"
+)
+
+// DefaultKioskInterval is the default time each view of a UiKiosk is shown.
+const DefaultKioskInterval = time.Second * 30
+
+// DefaultKioskIdle is the default time without user input before a UiKiosk resumes rotation.
+const DefaultKioskIdle = time.Minute * 2
+
+// UiKiosk renders one of a list of views at a time, switching to the next
+// view every Interval. This is intended for unattended pages such as
+// wall-mounted dashboards.
+//
+// Rotation pauses when the user interacts with the page, and resumes once
+// no input has been received for Idle.
+type UiKiosk struct {
+	UiHtml
+	Views    []UI          // the views to cycle through, e.g. Templates
+	Interval time.Duration // time each view is shown
+	Idle     time.Duration // time without user input before rotation resumes
+	mu       deadlock.Mutex
+	index    int      // index of the view to show
+	shown    int      // index of the view in child
+	child    *Element // Element of the currently shown view
+}
+
+func NewUiKiosk(views ...UI) *UiKiosk {
+	return &UiKiosk{
+		Views:    views,
+		Interval: DefaultKioskInterval,
+		Idle:     DefaultKioskIdle,
+	}
+}
+
+// Index returns the index of the view currently shown.
+func (ui *UiKiosk) Index() (index int) {
+	ui.mu.Lock()
+	index = ui.index
+	ui.mu.Unlock()
+	return
+}
+
+// Show switches to the view with the given index, wrapping around if out of range.
+func (ui *UiKiosk) Show(rq *Request, index int) {
+	if n := len(ui.Views); n > 0 {
+		ui.mu.Lock()
+		ui.index = ((index % n) + n) % n
+		ui.mu.Unlock()
+		rq.Dirty(ui)
+	}
+}
+
+// renderChild creates a new Element for the current view and renders it.
+// Returns the previous child Element, if any.
+func (ui *UiKiosk) renderChild(e *Element, w io.Writer) (old *Element, err error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+	old = ui.child
+	ui.child = nil
+	ui.shown = ui.index
+	if ui.index < len(ui.Views) {
+		ui.child = e.Request.NewElement(ui.Views[ui.index])
+		err = ui.child.Render(w, nil)
+	}
+	return
+}
+
+func (ui *UiKiosk) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	e.Tag(ui)
+	attrs := append(ui.parseParams(e, params), `class="jaws-kiosk"`)
+	var sb strings.Builder
+	if _, err = ui.renderChild(e, &sb); err == nil {
+		if err = WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(sb.String()), attrs...); err == nil { // #nosec G203
+			go ui.rotate(e, e.Request)
+		}
+	}
+	return
+}
+
+func (ui *UiKiosk) JawsUpdate(e *Element) {
+	ui.mu.Lock()
+	same := ui.child != nil && ui.shown == ui.index
+	ui.mu.Unlock()
+	if !same {
+		var sb strings.Builder
+		old, err := ui.renderChild(e, &sb)
+		maybePanic(err)
+		if old != nil {
+			e.Request.deleteElement(old)
+		}
+		e.SetInner(template.HTML(sb.String())) // #nosec G203
+	}
+}
+
+func (ui *UiKiosk) rotate(e *Element, rq *Request) {
+	jid := e.jid
+	for {
+		timer := time.NewTimer(ui.Interval)
+		select {
+		case <-rq.Jaws.Done():
+			timer.Stop()
+			return
+		case <-rq.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+		if rq.getElementByJid(jid) != e {
+			return
+		}
+		if time.Since(rq.LastInput()) >= ui.Idle {
+			ui.Show(rq, ui.Index()+1)
+		}
+	}
+}
+
+// Kiosk renders a HTML div element that cycles through the given views.
+func (rq RequestWriter) Kiosk(views []UI, params ...interface{}) error {
+	return rq.UI(NewUiKiosk(views...), params...)
+}
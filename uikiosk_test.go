@@ -0,0 +1,73 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_Kiosk(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ui := NewUiKiosk(NewUiSpan(makeHtmlGetter("one")), NewUiSpan(makeHtmlGetter("two")))
+	ui.Interval = time.Millisecond
+	ui.Idle = time.Hour
+	rq.mu.Lock()
+	rq.lastInput = time.Now()
+	rq.mu.Unlock()
+	th.NoErr(rq.UI(ui, "hidden"))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" hidden class="jaws-kiosk"><span id="Jid.2">one</span></div>`)
+
+	// recent input pauses rotation
+	before := rq.LastInput()
+	rq.inCh <- wsMsg{What: what.Click, Jid: 1, Data: "x"}
+	for rq.LastInput() == before {
+		select {
+		case <-th.C:
+			th.Timeout()
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+	time.Sleep(time.Millisecond * 10)
+	th.Equal(ui.Index(), 0)
+
+	ui.mu.Lock()
+	ui.Idle = 0
+	ui.mu.Unlock()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.True(strings.HasPrefix(s, "Inner\tJid.1\t"))
+		th.True(strings.Contains(s, `>two</span>`) || strings.Contains(s, `>one</span>`))
+	}
+	th.Equal(rq.getElementByJid(2), (*Element)(nil))
+}
+
+func TestUiKiosk_Show(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ui := NewUiKiosk(NewUiSpan(makeHtmlGetter("one")), NewUiSpan(makeHtmlGetter("two")))
+	th.NoErr(rq.UI(ui))
+	elem := rq.getElementByJid(1)
+
+	ui.Show(rq.Request, -1)
+	th.Equal(ui.Index(), 1)
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tJid.1\t\"<span id=\\\"Jid.3\\\">two</span>\"\n")
+	}
+
+	th.NoErr(NewUiKiosk().JawsRender(elem, &strings.Builder{}, nil))
+}
@@ -0,0 +1,76 @@
+package jaws
+
+import (
+	"html"
+	"io"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// MaskSetter may be implemented by the StringSetter of a UiMasked to
+// receive both the raw and formatted value. Otherwise JawsSetString is
+// called with the raw value.
+type MaskSetter interface {
+	JawsSetMasked(e *Element, raw, formatted string) error
+}
+
+// UiMasked is a text input with a Mask applied as the user types.
+// The value is validated against the Mask before the StringSetter is
+// called, and the browser only sends complete or empty values.
+//
+// The StringSetter gets and sets raw values, which are formatted using
+// the Mask when rendered.
+type UiMasked struct {
+	UiInputText
+	Mask Mask
+}
+
+func (ui *UiMasked) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.StringSetter)
+	attrs := append(ui.parseParams(e, params), `data-jaws-mask="`+html.EscapeString(string(ui.Mask))+`"`)
+	v := ui.Mask.Format(ui.JawsGetString(e))
+	ui.Last.Store(v)
+	e.redactInput("text")
+	return WriteHtmlInput(w, e.Jid(), "text", v, e.auditLabel(attrs)...)
+}
+
+func (ui *UiMasked) JawsUpdate(e *Element) {
+	if v := ui.Mask.Format(ui.JawsGetString(e)); ui.Last.Swap(v) != v {
+		e.SetValue(v)
+	}
+}
+
+func (ui *UiMasked) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	if wht == what.Input {
+		var raw, formatted string
+		if raw, formatted, err = ui.Mask.Parse(val); err == nil {
+			ui.Last.Store(formatted)
+			if ms, ok := ui.StringSetter.(MaskSetter); ok {
+				err = ms.JawsSetMasked(e, raw, formatted)
+			} else {
+				err = ui.StringSetter.JawsSetString(e, raw)
+			}
+		}
+		e.Dirty(ui.Tag)
+		if err != nil {
+			return
+		}
+		e.Handled()
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+func NewUiMasked(mask Mask, vp StringSetter) *UiMasked {
+	return &UiMasked{
+		UiInputText: UiInputText{
+			StringSetter: vp,
+		},
+		Mask: mask,
+	}
+}
+
+// Masked renders a text input formatted using mask as the user types.
+// The value is a StringSetter or string holding the raw value.
+func (rq RequestWriter) Masked(mask Mask, value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiMasked(mask, makeStringSetter(value)), params...)
+}
@@ -0,0 +1,56 @@
+package jaws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testMaskSetter struct {
+	*testSetter[string]
+	formatted string
+}
+
+func (ms *testMaskSetter) JawsSetMasked(e *Element, raw, formatted string) error {
+	ms.formatted = formatted
+	return ms.JawsSetString(e, raw)
+}
+
+func TestRequest_Masked(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ss := newTestSetter("5551234567")
+	th.NoErr(rq.Masked(MaskPhone, ss))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="text" value="(555) 123-4567" data-jaws-mask="(999) 999-9999">`)
+
+	e := rq.getElementByJid(1)
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "(555) 765-4321"))
+	th.Equal(ss.Get(), "5557654321")
+	th.True(errors.Is(rq.callAllEventHandlers(e.Jid(), what.Input, "(555) 765"), ErrMaskMismatch))
+	th.Equal(ss.Get(), "5557654321")
+	th.Equal(ss.SetCount(), 1)
+
+	ss.Set("5550000000")
+	e.Ui().JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.Value)
+	th.Equal(e.wsQueue[0].Data, "(555) 000-0000")
+}
+
+func TestUiMasked_MaskSetter(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ms := &testMaskSetter{testSetter: newTestSetter("")}
+	th.NoErr(rq.Masked(MaskCreditCard, ms))
+	e := rq.getElementByJid(1)
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "4111111111111111"))
+	th.Equal(ms.Get(), "4111111111111111")
+	th.Equal(ms.formatted, "4111 1111 1111 1111")
+}
@@ -0,0 +1,103 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"strconv"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// Paginator is implemented by paged components such as Pager.
+type Paginator interface {
+	// PageStatus returns the offset of the current page, the page size
+	// and the total number of items.
+	PageStatus(rq *Request) (offset, limit, count int)
+	// SetOffset moves to the page starting at offset, returning true if it changed.
+	SetOffset(offset int) (changed bool)
+}
+
+// UiPagerNav renders navigation buttons and the range of items shown for
+// a Paginator, such as "21–40 of 345", in a HTML nav element.
+type UiPagerNav struct {
+	UiHtml
+	Paginator
+	mu   deadlock.Mutex
+	last template.HTML
+}
+
+func NewUiPagerNav(p Paginator) *UiPagerNav {
+	return &UiPagerNav{Paginator: p}
+}
+
+func pagerButton(name, label, text string, disabled bool) string {
+	s := `<button type="button" name="` + name + `" aria-label="` + label + `"`
+	if disabled {
+		s += " disabled"
+	}
+	return s + ">" + text + "</button>"
+}
+
+func (ui *UiPagerNav) inner(rq *Request) template.HTML {
+	offset, limit, count := ui.PageStatus(rq)
+	first, last := 0, 0
+	if count > 0 {
+		first, last = offset+1, min(offset+limit, count)
+	}
+	atStart, atEnd := offset <= 0, offset+limit >= count
+	return template.HTML(pagerButton("first", "First page", "&laquo;", atStart) + // #nosec G203
+		pagerButton("prev", "Previous page", "&lsaquo;", atStart) +
+		`<span class="jaws-pager-status">` + strconv.Itoa(first) + "&ndash;" + strconv.Itoa(last) + " of " + strconv.Itoa(count) + `</span>` +
+		pagerButton("next", "Next page", "&rsaquo;", atEnd) +
+		pagerButton("last", "Last page", "&raquo;", atEnd))
+}
+
+func (ui *UiPagerNav) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.Paginator)
+	attrs := append(ui.parseParams(e, params), `class="jaws-pager"`)
+	inner := ui.inner(e.Request)
+	ui.mu.Lock()
+	ui.last = inner
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "nav", "", inner, attrs...)
+}
+
+func (ui *UiPagerNav) JawsUpdate(e *Element) {
+	inner := ui.inner(e.Request)
+	ui.mu.Lock()
+	changed := ui.last != inner
+	ui.last = inner
+	ui.mu.Unlock()
+	if changed {
+		e.SetInner(inner)
+	}
+}
+
+func (ui *UiPagerNav) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	if wht == what.Click {
+		offset, limit, count := ui.PageStatus(e.Request)
+		switch val {
+		case "first":
+			offset = 0
+		case "prev":
+			offset -= limit
+		case "next":
+			offset += limit
+		case "last":
+			offset = count
+		default:
+			return ui.UiHtml.JawsEvent(e, wht, val)
+		}
+		if ui.SetOffset(offset) {
+			e.Dirty(ui.Paginator)
+		}
+		e.Handled()
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+// PagerNav renders navigation buttons for a Paginator such as a Pager.
+func (rq RequestWriter) PagerNav(p Paginator, params ...interface{}) error {
+	return rq.UI(NewUiPagerNav(p), params...)
+}
@@ -0,0 +1,56 @@
+package jaws
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_PagerNav(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ds := &testDataSource{n: 25}
+	p := NewPager[int](ds, 10, func(i int) UI { return NewUiLi(makeHtmlGetter(strconv.Itoa(i))) })
+	th.NoErr(rq.PagerNav(p, `id="nav"`))
+	th.Equal(rq.BodyString(), `<nav id="Jid.1" id="nav" class="jaws-pager">`+
+		`<button type="button" name="first" aria-label="First page" disabled>&laquo;</button>`+
+		`<button type="button" name="prev" aria-label="Previous page" disabled>&lsaquo;</button>`+
+		`<span class="jaws-pager-status">1&ndash;10 of 25</span>`+
+		`<button type="button" name="next" aria-label="Next page">&rsaquo;</button>`+
+		`<button type="button" name="last" aria-label="Last page">&raquo;</button></nav>`)
+
+	e := rq.getElementByJid(1)
+	ui := e.Ui().(*UiPagerNav)
+	status := func() int {
+		offset, _, _ := p.PageStatus(rq.Request)
+		return offset
+	}
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "next"))
+	th.Equal(status(), 10)
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "last"))
+	th.Equal(status(), 20)
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.Inner)
+	th.Equal(string(ui.inner(rq.Request)), string(e.wsQueue[0].Data))
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "prev"))
+	th.Equal(status(), 10)
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "first"))
+	th.Equal(status(), 0)
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "first"))
+	th.Equal(rq.callAllEventHandlers(1, what.Click, "other"), nil)
+
+	ds.n = 0
+	th.Equal(string(ui.inner(rq.Request)), `<button type="button" name="first" aria-label="First page" disabled>&laquo;</button>`+
+		`<button type="button" name="prev" aria-label="Previous page" disabled>&lsaquo;</button>`+
+		`<span class="jaws-pager-status">0&ndash;0 of 0</span>`+
+		`<button type="button" name="next" aria-label="Next page" disabled>&rsaquo;</button>`+
+		`<button type="button" name="last" aria-label="Last page" disabled>&raquo;</button>`)
+}
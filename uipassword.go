@@ -1,25 +1,113 @@
 package jaws
 
 import (
+	"html"
 	"io"
+	"strconv"
+	"time"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
 )
 
+// DefaultPasswordDebounce is the default delay the browser waits after the
+// last keypress before sending a password to be scored.
+const DefaultPasswordDebounce = time.Millisecond * 300
+
+// UiPassword renders a HTML password input.
+//
+// If a PasswordScorer is given, a meter element showing the strength of
+// the password follows the input, and is updated as the user types.
+// If a PasswordReveal is given, a button toggling visibility follows.
+//
+// Since the Request holding the input is the only one that should know
+// the value, the value is never sent to the browser as the StringSetter
+// changes, except when it is cleared.
 type UiPassword struct {
 	UiInputText
+	Scorer   PasswordScorer // if not nil, scores the password for the strength meter
+	Reveal   string         // if not empty, label of the show/hide button
+	Debounce time.Duration  // delay before sending changes when Scorer is set, defaults to DefaultPasswordDebounce
+	mu       deadlock.Mutex
+	last     PasswordStrength
+}
+
+func (ui *UiPassword) strengthAttrs(ps PasswordStrength) []string {
+	return []string{
+		`data-jaws-strength="` + strconv.Itoa(ps.Score) + `"`,
+		`data-jaws-feedback="` + html.EscapeString(ps.Feedback) + `"`,
+	}
 }
 
-func (ui *UiPassword) JawsRender(e *Element, w io.Writer, params []interface{}) error {
-	return ui.renderStringInput(e, w, "password", params...)
+func (ui *UiPassword) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	var rest []interface{}
+	for _, p := range params {
+		switch data := p.(type) {
+		case PasswordScorer:
+			ui.Scorer = data
+		case func(string) PasswordStrength:
+			ui.Scorer = data
+		case PasswordReveal:
+			ui.Reveal = string(data)
+		default:
+			rest = append(rest, p)
+		}
+	}
+	var ps PasswordStrength
+	if ui.Scorer != nil {
+		ps = ui.Scorer(ui.JawsGetString(e))
+		ui.mu.Lock()
+		ui.last = ps
+		ui.mu.Unlock()
+		rest = append(rest, ui.strengthAttrs(ps))
+		if ui.Debounce > 0 {
+			rest = append(rest, `data-jaws-debounce="`+strconv.FormatInt(ui.Debounce.Milliseconds(), 10)+`"`)
+		}
+	}
+	err = ui.renderStringInput(e, w, "password", rest...)
+	if err == nil && ui.Scorer != nil {
+		_, err = io.WriteString(w, `<meter class="jaws-password-meter" min="0" max="4" low="2" high="3" optimum="4" value="`+
+			strconv.Itoa(ps.Score)+`" title="`+html.EscapeString(ps.Feedback)+`"></meter>`)
+	}
+	if err == nil && ui.Reveal != "" {
+		_, err = io.WriteString(w, `<button type="button" class="jaws-password-reveal" data-jaws-reveal="`+
+			e.Jid().String()+`" aria-pressed="false">`+html.EscapeString(ui.Reveal)+`</button>`)
+	}
+	return
+}
+
+func (ui *UiPassword) JawsUpdate(e *Element) {
+	if v := ui.JawsGetString(e); ui.Last.Swap(v) != v && v == "" {
+		e.SetValue(v)
+	}
+}
+
+func (ui *UiPassword) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	if wht == what.Input && ui.Scorer != nil {
+		ps := ui.Scorer(val)
+		ui.mu.Lock()
+		changed := ui.last != ps
+		ui.last = ps
+		ui.mu.Unlock()
+		if changed {
+			e.SetAttr("data-jaws-strength", strconv.Itoa(ps.Score))
+			e.SetAttr("data-jaws-feedback", ps.Feedback)
+		}
+	}
+	return ui.UiInputText.JawsEvent(e, wht, val)
 }
 
 func NewUiPassword(g StringSetter) *UiPassword {
 	return &UiPassword{
-		UiInputText{
+		UiInputText: UiInputText{
 			StringSetter: g,
 		},
+		Debounce: DefaultPasswordDebounce,
 	}
 }
 
+// Password renders a HTML password input. Pass a PasswordScorer to add a
+// strength meter, and a PasswordReveal to add a show/hide button.
 func (rq RequestWriter) Password(value interface{}, params ...interface{}) error {
 	return rq.UI(NewUiPassword(makeStringSetter(value)), params...)
 }
@@ -2,6 +2,8 @@ package jaws
 
 import (
 	"testing"
+
+	"github.com/linkdata/jaws/what"
 )
 
 func TestRequest_Password(t *testing.T) {
@@ -15,3 +17,53 @@ func TestRequest_Password(t *testing.T) {
 		t.Errorf("Request.Password() = %q, want %q", got, want)
 	}
 }
+
+func TestRequest_PasswordStrength(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ts := newTestSetter("")
+	scorer := func(pw string) PasswordStrength {
+		if len(pw) < 4 {
+			return PasswordStrength{Score: len(pw), Feedback: "<short>"}
+		}
+		return PasswordStrength{Score: 4}
+	}
+	th.NoErr(rq.Password(ts, scorer, PasswordReveal("Show"), `class="pw"`))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="password" class="pw" data-jaws-strength="0" data-jaws-feedback="&lt;short&gt;" data-jaws-debounce="300">`+
+		`<meter class="jaws-password-meter" min="0" max="4" low="2" high="3" optimum="4" value="0" title="&lt;short&gt;"></meter>`+
+		`<button type="button" class="jaws-password-reveal" data-jaws-reveal="Jid.1" aria-pressed="false">Show</button>`)
+
+	e := rq.getElementByJid(1)
+	ui := e.Ui().(*UiPassword)
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "ab"))
+	th.Equal(ts.Get(), "ab")
+	th.Equal(len(e.wsQueue), 2)
+	th.Equal(e.wsQueue[0], wsMsg{Jid: e.jid, What: what.SAttr, Data: "data-jaws-strength\n2"})
+	th.Equal(e.wsQueue[1], wsMsg{Jid: e.jid, What: what.SAttr, Data: "data-jaws-feedback\n<short>"})
+
+	e.wsQueue = nil
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "cd"))
+	th.Equal(len(e.wsQueue), 0)
+
+	ts.Set("secret")
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 0)
+	ts.Set("")
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.Value)
+	th.Equal(e.wsQueue[0].Data, "")
+}
+
+func TestRequest_PasswordReveal(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+	th.NoErr(rq.Password(newTestSetter(""), PasswordReveal("<Show>")))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="password">`+
+		`<button type="button" class="jaws-password-reveal" data-jaws-reveal="Jid.1" aria-pressed="false">&lt;Show&gt;</button>`)
+}
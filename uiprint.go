@@ -0,0 +1,55 @@
+package jaws
+
+import (
+	"bytes"
+	"html/template"
+	"io"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// UiPrint renders two variants of the same content, one shown on screen
+// and one shown when the page is printed.
+//
+// The variants are wrapped in div elements with the "jaws-screen" and "jaws-print"
+// classes, which are hidden using CSS media queries included by HeadHTML.
+type UiPrint struct {
+	Screen UI // shown on screen
+	Print  UI // shown when printing
+}
+
+func NewUiPrint(screen, print UI) *UiPrint {
+	return &UiPrint{Screen: screen, Print: print}
+}
+
+func (ui *UiPrint) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	attrs := append(parseParams(e, params), `class="jaws-print-swap"`)
+	var b bytes.Buffer
+	b.WriteString(`<div class="jaws-screen">`)
+	if err = ui.Screen.JawsRender(e, &b, nil); err == nil {
+		b.WriteString(`</div><div class="jaws-print">`)
+		if err = ui.Print.JawsRender(e, &b, nil); err == nil {
+			b.WriteString(`</div>`)
+			err = WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(b.String()), attrs...) // #nosec G203
+		}
+	}
+	return
+}
+
+func (ui *UiPrint) JawsUpdate(e *Element) {
+	ui.Screen.JawsUpdate(e)
+	ui.Print.JawsUpdate(e)
+}
+
+func (ui *UiPrint) JawsEvent(e *Element, wht what.What, val string) error {
+	return callEventHandler(ui.Screen, e, wht, val)
+}
+
+// PrintTemplate renders the templ template on screen, and the printTempl
+// template when the page is printed, both using jaws.With{Dot: dot} as data.
+//
+// This allows reports built as live pages to be printed cleanly. Since the
+// wrapping div element has the Jid, the templates should not use $.Jid.
+func (rq RequestWriter) PrintTemplate(templ, printTempl, dot interface{}, params ...interface{}) error {
+	return rq.UI(NewUiPrint(rq.rq.MakeTemplate(templ, dot), rq.rq.MakeTemplate(printTempl, dot)), params...)
+}
@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"html/template"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_PrintTemplate(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	screenTmpl := template.Must(template.New("screen").Parse(`<span>{{$.Dot}}</span>`))
+	printTmpl := template.Must(template.New("print").Parse(`<b>{{$.Dot}}</b>`))
+	th.NoErr(rq.PrintTemplate(screenTmpl, printTmpl, "report", "hidden"))
+	th.Equal(rq.BodyString(), `<div id="Jid.1" hidden class="jaws-print-swap">`+
+		`<div class="jaws-screen"><span>report</span></div>`+
+		`<div class="jaws-print"><b>report</b></div></div>`)
+
+	elem := rq.getElementByJid(1)
+	ui := elem.Ui().(*UiPrint)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+	th.Equal(ui.JawsEvent(elem, what.Click, "x"), ErrEventUnhandled)
+}
+
+func TestRequest_Print(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+
+	rq.Print()
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Print\t\t\"\"\n")
+	}
+}
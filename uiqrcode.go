@@ -0,0 +1,62 @@
+package jaws
+
+import (
+	"html"
+	"html/template"
+	"io"
+
+	"github.com/linkdata/deadlock"
+)
+
+// UiQRCode renders the string from a StringSetter as a QR code in an inline SVG image.
+type UiQRCode struct {
+	UiHtml
+	StringSetter
+	Level  QRLevel // error correction level, defaults to QRLevelM
+	Border int     // width of the light border in modules, defaults to 4
+	mu     deadlock.Mutex
+	last   string // last rendered inner HTML
+}
+
+func NewUiQRCode(g StringSetter) *UiQRCode {
+	return &UiQRCode{
+		StringSetter: g,
+		Level:        QRLevelM,
+		Border:       4,
+	}
+}
+
+func (ui *UiQRCode) inner(e *Element) string {
+	qr, err := NewQRCode([]byte(ui.JawsGetString(e)), ui.Level)
+	if err != nil {
+		return `<span class="jaws-qrcode-error">` + html.EscapeString(err.Error()) + `</span>`
+	}
+	return string(qr.AppendSVG(nil, ui.Border))
+}
+
+func (ui *UiQRCode) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.StringSetter)
+	attrs := append(ui.parseParams(e, params), `class="jaws-qrcode"`)
+	inner := ui.inner(e)
+	ui.mu.Lock()
+	ui.last = inner
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "div", "", template.HTML(inner), attrs...) // #nosec G203
+}
+
+func (ui *UiQRCode) JawsUpdate(e *Element) {
+	inner := ui.inner(e)
+	ui.mu.Lock()
+	changed := ui.last != inner
+	ui.last = inner
+	ui.mu.Unlock()
+	if changed {
+		e.SetInner(template.HTML(inner)) // #nosec G203
+	}
+}
+
+// QRCode renders a HTML div element containing the QR code for text,
+// which may be a string or a StringSetter.
+func (rq RequestWriter) QRCode(text interface{}, params ...interface{}) error {
+	return rq.UI(NewUiQRCode(makeStringSetter(text)), params...)
+}
@@ -0,0 +1,36 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_QRCode(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ts := newTestSetter("https://example.com/pair?code=1234")
+	th.NoErr(rq.QRCode(ts))
+	body := rq.BodyString()
+	th.True(strings.HasPrefix(body, `<div id="Jid.1" class="jaws-qrcode"><svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 37 37"`))
+	th.True(strings.HasSuffix(body, `</svg></div>`))
+
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(ts))
+	ui := elem.Ui().(*UiQRCode)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	ts.Set("changed")
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 1)
+	th.Equal(elem.wsQueue[0].What, what.Inner)
+	th.True(strings.HasPrefix(elem.wsQueue[0].Data, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 29 29"`))
+
+	ts.Set(strings.Repeat("x", 3000))
+	th.Equal(ui.inner(elem), `<span class="jaws-qrcode-error">data too long for QR code</span>`)
+}
@@ -0,0 +1,145 @@
+package jaws
+
+import (
+	"html"
+	"html/template"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/linkdata/deadlock"
+)
+
+// relTimeFormat is the format of the datetime attribute of UiRelativeTime.
+const relTimeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// UiRelativeTime renders the time from a TimeSetter relative to the current
+// time, such as "5 minutes ago" or "in 2 hours", in a HTML time element.
+//
+// The browser keeps the text current by itself, using the server time at
+// rendering as it's reference, so no WebSocket traffic is needed as time
+// passes. The Element is only updated if the time itself changes.
+type UiRelativeTime struct {
+	UiHtml
+	TimeSetter
+	countdown bool
+	mu        deadlock.Mutex
+	last      time.Time // last time rendered
+}
+
+// UiCountdown is like UiRelativeTime, but shows the time to or since
+// with a precision of seconds, such as "in 3m 12s".
+type UiCountdown struct {
+	UiRelativeTime
+}
+
+func NewUiRelativeTime(g TimeSetter) *UiRelativeTime {
+	return &UiRelativeTime{TimeSetter: g}
+}
+
+func NewUiCountdown(g TimeSetter) *UiCountdown {
+	return &UiCountdown{UiRelativeTime{TimeSetter: g, countdown: true}}
+}
+
+// relativeText returns the text for t relative to now. If countdown is
+// true, the text is precise to the second, otherwise it's in whole minutes,
+// hours or days. The Javascript function jawsDuration must match this.
+func relativeText(t, now time.Time, countdown bool) (text string) {
+	d := t.Sub(now)
+	s := int64(d / time.Second)
+	if s < 0 {
+		s = -s
+	}
+	if s == 0 {
+		if countdown {
+			return "0s"
+		}
+		return "just now"
+	}
+	itoa := func(n int64) string { return strconv.FormatInt(n, 10) }
+	if countdown {
+		switch {
+		case s >= 86400:
+			text = itoa(s/86400) + "d " + itoa(s/3600%24) + "h"
+		case s >= 3600:
+			text = itoa(s/3600) + "h " + itoa(s/60%60) + "m"
+		case s >= 60:
+			text = itoa(s/60) + "m " + itoa(s%60) + "s"
+		default:
+			text = itoa(s) + "s"
+		}
+	} else {
+		var n int64
+		var unit string
+		switch {
+		case s < 60:
+			return "just now"
+		case s < 3600:
+			n, unit = s/60, " minute"
+		case s < 86400:
+			n, unit = s/3600, " hour"
+		default:
+			n, unit = s/86400, " day"
+		}
+		text = itoa(n) + unit
+		if n != 1 {
+			text += "s"
+		}
+	}
+	if d > 0 {
+		return "in " + text
+	}
+	return text + " ago"
+}
+
+// timeAttrs returns the attributes the browser uses to keep the text current.
+func (ui *UiRelativeTime) timeAttrs(t, now time.Time) []string {
+	kind := "relative"
+	if ui.countdown {
+		kind = "countdown"
+	}
+	return []string{
+		`datetime="` + t.UTC().Format(relTimeFormat) + `"`,
+		`data-jaws-time="` + kind + `"`,
+		`data-jaws-now="` + strconv.FormatInt(now.UnixMilli(), 10) + `"`,
+	}
+}
+
+func (ui *UiRelativeTime) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.TimeSetter)
+	t := ui.JawsGetTime(e)
+	now := time.Now()
+	ui.mu.Lock()
+	ui.last = t
+	ui.mu.Unlock()
+	attrs := append(ui.parseParams(e, params), ui.timeAttrs(t, now)...)
+	return WriteHtmlInner(w, e.Jid(), "time", "", template.HTML(html.EscapeString(relativeText(t, now, ui.countdown))), attrs...) // #nosec G203
+}
+
+func (ui *UiRelativeTime) JawsUpdate(e *Element) {
+	t := ui.JawsGetTime(e)
+	ui.mu.Lock()
+	changed := !ui.last.Equal(t)
+	ui.last = t
+	ui.mu.Unlock()
+	if changed {
+		now := time.Now()
+		e.SetAttr("data-jaws-now", strconv.FormatInt(now.UnixMilli(), 10))
+		e.SetAttr("datetime", t.UTC().Format(relTimeFormat))
+		e.SetInner(template.HTML(html.EscapeString(relativeText(t, now, ui.countdown)))) // #nosec G203
+	}
+}
+
+// RelativeTime renders a HTML time element showing the time relative to
+// the current time, such as "5 minutes ago". The value may be a time.Time
+// or a TimeSetter.
+func (rq RequestWriter) RelativeTime(value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiRelativeTime(makeTimeSetter(value)), params...)
+}
+
+// Countdown renders a HTML time element showing the time to or since
+// the value with a precision of seconds, such as "in 3m 12s". The value
+// may be a time.Time or a TimeSetter.
+func (rq RequestWriter) Countdown(value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiCountdown(makeTimeSetter(value)), params...)
+}
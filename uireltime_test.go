@@ -0,0 +1,72 @@
+package jaws
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_relativeText(t *testing.T) {
+	th := newTestHelper(t)
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return now.Add(d) }
+
+	th.Equal(relativeText(now, now, false), "just now")
+	th.Equal(relativeText(at(59*time.Second), now, false), "just now")
+	th.Equal(relativeText(at(-time.Minute), now, false), "1 minute ago")
+	th.Equal(relativeText(at(5*time.Minute+30*time.Second), now, false), "in 5 minutes")
+	th.Equal(relativeText(at(-2*time.Hour), now, false), "2 hours ago")
+	th.Equal(relativeText(at(25*time.Hour), now, false), "in 1 day")
+	th.Equal(relativeText(at(-72*time.Hour), now, false), "3 days ago")
+
+	th.Equal(relativeText(at(500*time.Millisecond), now, true), "0s")
+	th.Equal(relativeText(at(12*time.Second), now, true), "in 12s")
+	th.Equal(relativeText(at(3*time.Minute+12*time.Second), now, true), "in 3m 12s")
+	th.Equal(relativeText(at(-(time.Hour+2*time.Minute+3*time.Second)), now, true), "1h 2m ago")
+	th.Equal(relativeText(at(50*time.Hour), now, true), "in 2d 2h")
+}
+
+func TestRequest_RelativeTime(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	when := time.Now().Add(-10 * time.Minute).Truncate(time.Millisecond)
+	ts := newTestSetter(when)
+	th.NoErr(rq.RelativeTime(ts, `class="when"`))
+	body := rq.BodyString()
+	th.True(strings.HasPrefix(body, `<time id="Jid.1" class="when" datetime="`+when.UTC().Format(relTimeFormat)+
+		`" data-jaws-time="relative" data-jaws-now="`))
+	th.True(strings.HasSuffix(body, `">10 minutes ago</time>`))
+
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(ts))
+	ui := elem.Ui().(*UiRelativeTime)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	when = time.Now().Add(50*time.Minute + 30*time.Second)
+	ts.Set(when)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 3)
+	th.Equal(elem.wsQueue[0].What, what.SAttr)
+	th.True(strings.HasPrefix(elem.wsQueue[0].Data, "data-jaws-now\n"))
+	th.Equal(elem.wsQueue[1].Data, "datetime\n"+when.UTC().Format(relTimeFormat))
+	th.Equal(elem.wsQueue[2].What, what.Inner)
+	th.Equal(elem.wsQueue[2].Data, "in 50 minutes")
+}
+
+func TestRequest_Countdown(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.NoErr(rq.Countdown(time.Now().Add(3*time.Minute + 12*time.Second + 500*time.Millisecond)))
+	body := rq.BodyString()
+	th.True(strings.Contains(body, ` data-jaws-time="countdown" `))
+	th.True(strings.HasSuffix(body, `">in 3m 12s</time>`))
+}
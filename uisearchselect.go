@@ -0,0 +1,194 @@
+package jaws
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/linkdata/deadlock"
+	"github.com/linkdata/jaws/what"
+)
+
+// DefaultSearchPageSize is the default number of options a UiSearchSelect
+// asks it's SearchSource for at a time.
+const DefaultSearchPageSize = 20
+
+// DefaultSearchDebounce is the default delay the browser waits after the
+// last keypress in a UiSearchSelect before sending the query.
+const DefaultSearchDebounce = time.Millisecond * 250
+
+// SearchOption is an option found by a SearchSource.
+type SearchOption struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+}
+
+// SearchSource provides the options for a UiSearchSelect.
+type SearchSource interface {
+	// JawsSearch returns at most limit options matching query, skipping
+	// the first offset matches, and whether more matches exist.
+	JawsSearch(e *Element, query string, offset, limit int) (opts []SearchOption, more bool, err error)
+	// JawsSearchLabel returns the label for key, or an error if
+	// key is not a valid option. The empty key has the empty label.
+	JawsSearchLabel(e *Element, key string) (label string, err error)
+}
+
+// UiSearchSelect renders a HTML search input where the options are found
+// by querying a SearchSource as the user types, for option sets too
+// large to send to the browser. The key of the chosen option is bound to
+// a StringSetter, while the input shows it's label.
+//
+// The options are sent as JSON in the data-jaws-results attribute of the
+// input, and rendered by the browser into the listbox following it.
+type UiSearchSelect struct {
+	UiHtml
+	StringSetter
+	Source   SearchSource
+	PageSize int           // number of options to fetch at a time, defaults to DefaultSearchPageSize
+	Debounce time.Duration // delay before sending queries, defaults to DefaultSearchDebounce
+	mu       deadlock.Mutex
+	query    string // current query
+	offset   int    // offset of the next page for the current query
+	lastKey  string
+}
+
+type searchResults struct {
+	Offset  int            `json:"offset"`
+	Options []SearchOption `json:"options"`
+	More    bool           `json:"more"`
+}
+
+func NewUiSearchSelect(src SearchSource, g StringSetter) *UiSearchSelect {
+	return &UiSearchSelect{
+		StringSetter: g,
+		Source:       src,
+		PageSize:     DefaultSearchPageSize,
+		Debounce:     DefaultSearchDebounce,
+	}
+}
+
+func (ui *UiSearchSelect) label(e *Element, key string) (label string, err error) {
+	if key != "" {
+		label, err = ui.Source.JawsSearchLabel(e, key)
+	}
+	return
+}
+
+func (ui *UiSearchSelect) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
+	ui.parseGetter(e, ui.StringSetter)
+	attrs := ui.parseParams(e, params)
+	key := ui.JawsGetString(e)
+	ui.mu.Lock()
+	ui.lastKey = key
+	ui.mu.Unlock()
+	var label string
+	if label, err = ui.label(e, key); err == nil {
+		attrs = append(attrs, `role="combobox"`, `autocomplete="off"`, `aria-autocomplete="list"`, `aria-expanded="false"`, "data-jaws-search")
+		if ui.Debounce > 0 {
+			attrs = append(attrs, `data-jaws-debounce="`+strconv.FormatInt(ui.Debounce.Milliseconds(), 10)+`"`)
+		}
+		if err = WriteHtmlInput(w, e.Jid(), "search", label, e.auditLabel(attrs)...); err == nil {
+			_, err = io.WriteString(w, `<ul class="jaws-search-results" role="listbox" hidden></ul>`)
+		}
+	}
+	return
+}
+
+func (ui *UiSearchSelect) JawsUpdate(e *Element) {
+	key := ui.JawsGetString(e)
+	ui.mu.Lock()
+	changed := ui.lastKey != key
+	ui.lastKey = key
+	ui.mu.Unlock()
+	if changed {
+		if label, err := ui.label(e, key); err == nil {
+			e.SetValue(label)
+		} else {
+			e.Request.Jaws.MustLog(err)
+		}
+	}
+}
+
+// search fetches the next page of options for the query and sends them.
+func (ui *UiSearchSelect) search(e *Element, query string, next bool) (err error) {
+	ui.mu.Lock()
+	if !next {
+		ui.query, ui.offset = query, 0
+	}
+	query, offset := ui.query, ui.offset
+	ui.mu.Unlock()
+	limit := ui.PageSize
+	if limit < 1 {
+		limit = DefaultSearchPageSize
+	}
+	res := searchResults{Offset: offset}
+	if res.Options, res.More, err = ui.Source.JawsSearch(e, query, offset, limit); err == nil {
+		ui.mu.Lock()
+		if ui.query == query && ui.offset == offset {
+			ui.offset += len(res.Options)
+		}
+		ui.mu.Unlock()
+		if res.Options == nil {
+			res.Options = []SearchOption{}
+		}
+		var b []byte
+		b, err = json.Marshal(res)
+		maybePanic(err)
+		e.SetAttr("data-jaws-results", string(b))
+	}
+	return
+}
+
+// choose sets the StringSetter to the key and the input to it's label.
+func (ui *UiSearchSelect) choose(e *Element, key string) (err error) {
+	var label string
+	if label, err = ui.label(e, key); err == nil {
+		if err = ui.StringSetter.JawsSetString(e, key); err == nil {
+			ui.mu.Lock()
+			ui.lastKey = key
+			ui.mu.Unlock()
+			e.SetValue(label)
+		}
+		e.Dirty(ui.Tag)
+	}
+	return
+}
+
+func (ui *UiSearchSelect) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	handled := true
+	switch wht {
+	case what.Input:
+		err = ui.search(e, val, false)
+	case what.Custom:
+		event, detail, _ := strings.Cut(val, "\t")
+		switch event {
+		case "more":
+			err = ui.search(e, "", true)
+		case "select":
+			var key string
+			if err = json.Unmarshal([]byte(detail), &key); err == nil {
+				err = ui.choose(e, key)
+			}
+		default:
+			handled = false
+		}
+	default:
+		handled = false
+	}
+	if err != nil {
+		return
+	}
+	if handled {
+		e.Handled()
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+// SearchSelect renders a HTML search input with options found by src as
+// the user types. The value is a StringSetter or string holding the key
+// of the chosen option.
+func (rq RequestWriter) SearchSelect(src SearchSource, value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiSearchSelect(src, makeStringSetter(value)), params...)
+}
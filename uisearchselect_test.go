@@ -0,0 +1,106 @@
+package jaws
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+var errNoSuchKey = errors.New("no such key")
+
+type testSearchSource struct{}
+
+func (testSearchSource) JawsSearch(e *Element, query string, offset, limit int) (opts []SearchOption, more bool, err error) {
+	if query == "fail" {
+		return nil, false, errors.New("search failed")
+	}
+	for i := 1; i <= 100; i++ {
+		label := "Item " + strconv.Itoa(i)
+		if strings.Contains(label, query) {
+			if offset > 0 {
+				offset--
+			} else if len(opts) < limit {
+				opts = append(opts, SearchOption{Key: strconv.Itoa(i), Label: label})
+			} else {
+				more = true
+				break
+			}
+		}
+	}
+	return
+}
+
+func (testSearchSource) JawsSearchLabel(e *Element, key string) (string, error) {
+	if n, err := strconv.Atoi(key); err == nil && n >= 1 && n <= 100 {
+		return "Item " + key, nil
+	}
+	return "", errNoSuchKey
+}
+
+func TestRequest_SearchSelect(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	ss := newTestSetter("42")
+	th.NoErr(rq.SearchSelect(testSearchSource{}, ss, `class="s"`))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="search" value="Item 42" class="s" role="combobox" autocomplete="off" aria-autocomplete="list" aria-expanded="false" data-jaws-search data-jaws-debounce="250">`+
+		`<ul class="jaws-search-results" role="listbox" hidden></ul>`)
+
+	e := rq.getElementByJid(1)
+	ui := e.Ui().(*UiSearchSelect)
+	ui.PageSize = 2
+
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "Item 1"))
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.SAttr)
+	th.Equal(e.wsQueue[0].Data, "data-jaws-results\n"+`{"offset":0,"options":[{"key":"1","label":"Item 1"},{"key":"10","label":"Item 10"}],"more":true}`)
+
+	e.wsQueue = nil
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Custom, "more\tnull"))
+	th.Equal(e.wsQueue[0].Data, "data-jaws-results\n"+`{"offset":2,"options":[{"key":"11","label":"Item 11"},{"key":"12","label":"Item 12"}],"more":true}`)
+
+	e.wsQueue = nil
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "Item 100"))
+	th.Equal(e.wsQueue[0].Data, "data-jaws-results\n"+`{"offset":0,"options":[{"key":"100","label":"Item 100"}],"more":false}`)
+
+	e.wsQueue = nil
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Input, "nothing"))
+	th.Equal(e.wsQueue[0].Data, "data-jaws-results\n"+`{"offset":0,"options":[],"more":false}`)
+	th.Equal(rq.callAllEventHandlers(e.Jid(), what.Input, "fail").Error(), "search failed")
+	th.Equal(ss.Get(), "42")
+
+	e.wsQueue = nil
+	th.NoErr(rq.callAllEventHandlers(e.Jid(), what.Custom, "select\t\"7\""))
+	th.Equal(ss.Get(), "7")
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].What, what.Value)
+	th.Equal(e.wsQueue[0].Data, "Item 7")
+
+	th.True(errors.Is(rq.callAllEventHandlers(e.Jid(), what.Custom, "select\t\"0\""), errNoSuchKey))
+	th.True(rq.callAllEventHandlers(e.Jid(), what.Custom, "select\tnope") != nil)
+	th.Equal(ss.Get(), "7")
+
+	e.wsQueue = nil
+	ss.Set("3")
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+	th.Equal(e.wsQueue[0].Data, "Item 3")
+	ui.JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 1)
+}
+
+func TestRequest_SearchSelectBadKey(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	th.True(errors.Is(rq.SearchSelect(testSearchSource{}, newTestSetter("x")), errNoSuchKey))
+	th.NoErr(rq.SearchSelect(testSearchSource{}, newTestSetter("")))
+	th.True(strings.Contains(rq.BodyString(), `<input id="Jid.2" type="search" role="combobox"`))
+}
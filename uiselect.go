@@ -34,6 +34,7 @@ func (ui *UiSelect) JawsEvent(e *Element, wht what.What, val string) (err error)
 		if err != nil {
 			return
 		}
+		e.Handled()
 	}
 	return ui.UiHtml.JawsEvent(e, wht, val)
 }
@@ -0,0 +1,114 @@
+package jaws
+
+import (
+	"html/template"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/linkdata/deadlock"
+)
+
+// UiSparkline renders the samples in a Series as a line in an inline SVG image,
+// for compact display of metrics in tables and cards.
+//
+// It is updated when the Series is dirtied, and only sends the new line if
+// it changed. Render it with the Delta parameter to send only the changed
+// part of the line.
+type UiSparkline struct {
+	UiHtml
+	*Series
+	Width  int  // width of the SVG view box, defaults to 100
+	Height int  // height of the SVG view box, defaults to 20
+	Rate   bool // if true, show the change between consecutive samples instead of the samples
+	mu     deadlock.Mutex
+	last   string // last rendered inner HTML
+}
+
+func NewUiSparkline(s *Series) *UiSparkline {
+	return &UiSparkline{
+		Series: s,
+		Width:  100,
+		Height: 20,
+	}
+}
+
+// sparklinePoints returns the SVG polyline points for vals scaled to fit
+// width and height, with the smallest value at the bottom.
+func sparklinePoints(vals []float64, width, height int) (b []byte) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, v := range vals {
+		if !math.IsNaN(v) && !math.IsInf(v, 0) {
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	step := float64(width)
+	if len(vals) > 1 {
+		step /= float64(len(vals) - 1)
+	}
+	for i, v := range vals {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		y := float64(height) / 2
+		if hi > lo {
+			y = float64(height) * (hi - v) / (hi - lo)
+		}
+		if len(b) > 0 {
+			b = append(b, ' ')
+		}
+		b = strconv.AppendFloat(b, math.Round(float64(i)*step*100)/100, 'f', -1, 64)
+		b = append(b, ',')
+		b = strconv.AppendFloat(b, math.Round(y*100)/100, 'f', -1, 64)
+	}
+	return
+}
+
+func (ui *UiSparkline) inner() string {
+	vals := ui.Values()
+	if ui.Rate {
+		for i := 1; i < len(vals); i++ {
+			vals[i-1] = vals[i] - vals[i-1]
+		}
+		if len(vals) > 0 {
+			vals = vals[:len(vals)-1]
+		}
+	}
+	b := append([]byte(nil), `<polyline fill="none" stroke="currentColor" vector-effect="non-scaling-stroke" points="`...)
+	b = append(b, sparklinePoints(vals, ui.Width, ui.Height)...)
+	b = append(b, `"></polyline>`...)
+	return string(b)
+}
+
+func (ui *UiSparkline) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.Series)
+	attrs := append(ui.parseParams(e, params),
+		`class="jaws-sparkline"`,
+		`xmlns="http://www.w3.org/2000/svg"`,
+		`viewBox="0 0 `+strconv.Itoa(ui.Width)+` `+strconv.Itoa(ui.Height)+`"`,
+		`width="`+strconv.Itoa(ui.Width)+`"`,
+		`height="`+strconv.Itoa(ui.Height)+`"`,
+		`preserveAspectRatio="none"`,
+	)
+	inner := ui.inner()
+	ui.mu.Lock()
+	ui.last = inner
+	ui.mu.Unlock()
+	return WriteHtmlInner(w, e.Jid(), "svg", "", template.HTML(inner), attrs...) // #nosec G203
+}
+
+func (ui *UiSparkline) JawsUpdate(e *Element) {
+	inner := ui.inner()
+	ui.mu.Lock()
+	changed := ui.last != inner
+	ui.last = inner
+	ui.mu.Unlock()
+	if changed {
+		e.SetInner(template.HTML(inner)) // #nosec G203
+	}
+}
+
+// Sparkline renders a SVG element showing the samples in the Series as a line.
+func (rq RequestWriter) Sparkline(s *Series, params ...interface{}) error {
+	return rq.UI(NewUiSparkline(s), params...)
+}
@@ -0,0 +1,50 @@
+package jaws
+
+import (
+	"html/template"
+	"math"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func Test_sparklinePoints(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(string(sparklinePoints(nil, 100, 20)), "")
+	th.Equal(string(sparklinePoints([]float64{5}, 100, 20)), "0,10")
+	th.Equal(string(sparklinePoints([]float64{1, 1}, 100, 20)), "0,10 100,10")
+	th.Equal(string(sparklinePoints([]float64{0, 10, 5}, 100, 20)), "0,20 50,0 100,10")
+	th.Equal(string(sparklinePoints([]float64{0, math.NaN(), 1, 2}, 30, 10)), "0,10 20,5 30,0")
+	th.Equal(string(sparklinePoints([]float64{0, 1, 2, 3}, 10, 3)), "0,3 3.33,2 6.67,1 10,0")
+}
+
+func TestRequest_Sparkline(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	s := NewSeries(4)
+	s.Add(1, 3)
+	th.NoErr(rq.Sparkline(s, `title="load"`))
+	th.Equal(rq.BodyHtml(), template.HTML(`<svg id="Jid.1" title="load" class="jaws-sparkline" xmlns="http://www.w3.org/2000/svg" `+
+		`viewBox="0 0 100 20" width="100" height="20" preserveAspectRatio="none">`+
+		`<polyline fill="none" stroke="currentColor" vector-effect="non-scaling-stroke" points="0,20 100,0"></polyline></svg>`))
+
+	elem := rq.getElementByJid(1)
+	th.True(elem.HasTag(s))
+	ui := elem.Ui().(*UiSparkline)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 0)
+
+	s.Add(2)
+	ui.JawsUpdate(elem)
+	th.Equal(len(elem.wsQueue), 1)
+	th.Equal(elem.wsQueue[0].What, what.Inner)
+	th.Equal(elem.wsQueue[0].Data, `<polyline fill="none" stroke="currentColor" vector-effect="non-scaling-stroke" points="0,20 50,0 100,10"></polyline>`)
+
+	ui.Rate = true
+	th.Equal(ui.inner(), `<polyline fill="none" stroke="currentColor" vector-effect="non-scaling-stroke" points="0,0 100,20"></polyline>`)
+	s.Clear()
+	th.Equal(ui.inner(), `<polyline fill="none" stroke="currentColor" vector-effect="non-scaling-stroke" points=""></polyline>`)
+}
@@ -9,10 +9,14 @@ type UiTextarea struct {
 	UiInputText
 }
 
-func (ui *UiTextarea) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+func (ui *UiTextarea) JawsRender(e *Element, w io.Writer, params []interface{}) (err error) {
 	ui.parseGetter(e, ui.StringSetter)
-	attrs := ui.parseParams(e, params)
-	return WriteHtmlInner(w, e.Jid(), "textarea", "", template.HTML(ui.JawsGetString(e)), attrs...) // #nosec G203
+	attrs := ui.parseParams(e, ui.extractMaxLength(params))
+	v := ui.JawsGetString(e)
+	if err = WriteHtmlInner(w, e.Jid(), "textarea", "", template.HTML(v), e.auditLabel(attrs)...); err == nil { // #nosec G203
+		err = ui.writeCounter(w, v)
+	}
+	return
 }
 
 func (ui *UiTextarea) JawsUpdate(e *Element) {
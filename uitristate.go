@@ -0,0 +1,77 @@
+package jaws
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// UiTriState renders a HTML checkbox bound to a TriStateSetter. When
+// Indeterminate, the checkbox has the data-jaws-indeterminate attribute
+// and the browser sets it's indeterminate property.
+//
+// Users can only check or uncheck the checkbox, so events always set
+// Checked or Unchecked.
+type UiTriState struct {
+	UiInput
+	TriStateSetter
+}
+
+func NewUiTriState(g TriStateSetter) *UiTriState {
+	return &UiTriState{TriStateSetter: g}
+}
+
+func (ui *UiTriState) JawsRender(e *Element, w io.Writer, params []interface{}) error {
+	ui.parseGetter(e, ui.TriStateSetter)
+	attrs := ui.parseParams(e, params)
+	v := ui.JawsGetTriState(e)
+	ui.Last.Store(v)
+	switch v {
+	case Checked:
+		attrs = append(attrs, "checked")
+	case Indeterminate:
+		attrs = append(attrs, "data-jaws-indeterminate")
+	}
+	return WriteHtmlInput(w, e.Jid(), "checkbox", "", e.auditLabel(attrs)...)
+}
+
+func (ui *UiTriState) JawsUpdate(e *Element) {
+	v := ui.JawsGetTriState(e)
+	if last := ui.Last.Swap(v); last != v {
+		if v == Indeterminate {
+			e.SetAttr("data-jaws-indeterminate", "")
+			return
+		}
+		if last == Indeterminate {
+			e.RemoveAttr("data-jaws-indeterminate")
+		}
+		e.SetValue(strconv.FormatBool(v == Checked))
+	}
+}
+
+func (ui *UiTriState) JawsEvent(e *Element, wht what.What, val string) (err error) {
+	if wht == what.Input {
+		var b bool
+		if val != "" {
+			if b, err = strconv.ParseBool(val); err != nil {
+				return
+			}
+		}
+		v := TriStateOf(&b)
+		ui.Last.Store(v)
+		err = ui.TriStateSetter.JawsSetTriState(e, v)
+		e.Dirty(ui.Tag)
+		if err != nil {
+			return
+		}
+		e.Handled()
+	}
+	return ui.UiHtml.JawsEvent(e, wht, val)
+}
+
+// TriState renders a HTML checkbox that may be indeterminate. The value
+// is a TriStateSetter, a TriState or a *bool where nil is Indeterminate.
+func (rq RequestWriter) TriState(value interface{}, params ...interface{}) error {
+	return rq.UI(NewUiTriState(makeTriStateSetter(value)), params...)
+}
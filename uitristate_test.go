@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+func TestRequest_TriState(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	nb := NewNullBool(nil)
+	th.NoErr(rq.TriState(nb, `class="c"`))
+	th.NoErr(rq.TriState(Checked))
+	th.NoErr(rq.TriState((*bool)(nil)))
+	th.Equal(rq.BodyString(), `<input id="Jid.1" type="checkbox" class="c" data-jaws-indeterminate>`+
+		`<input id="Jid.2" type="checkbox" checked>`+
+		`<input id="Jid.3" type="checkbox" data-jaws-indeterminate>`)
+
+	e := rq.getElementByJid(1)
+	th.NoErr(rq.callAllEventHandlers(1, what.Input, "true"))
+	th.Equal(nb.JawsGetTriState(e), Checked)
+	e.Ui().JawsUpdate(e)
+	th.Equal(len(e.wsQueue), 0)
+
+	th.NoErr(nb.JawsSetTriState(e, Indeterminate))
+	e.Ui().JawsUpdate(e)
+	th.Equal(e.wsQueue, []wsMsg{{Jid: 1, What: what.SAttr, Data: "data-jaws-indeterminate\n"}})
+
+	e.wsQueue = nil
+	th.NoErr(nb.JawsSetTriState(e, Unchecked))
+	e.Ui().JawsUpdate(e)
+	th.Equal(e.wsQueue, []wsMsg{
+		{Jid: 1, What: what.RAttr, Data: "data-jaws-indeterminate"},
+		{Jid: 1, What: what.Value, Data: "false"},
+	})
+
+	th.True(rq.callAllEventHandlers(1, what.Input, "maybe") != nil)
+	th.Equal(rq.callAllEventHandlers(2, what.Input, "false"), ErrValueNotSettable)
+}
@@ -12,28 +12,43 @@ const (
 	Reload   // Tells browser to reload the current URL
 	Redirect // Tells browser to load another URL
 	Alert    // Display (if using Bootstrap) an alert message
+	Print    // Open the browser print dialog
+	WakeLock // Request or release a screen wake lock
+	Announce // Announce text to screen readers
+	Prefs    // User preferences reported by the browser
+	Widget   // Custom element requesting it's widget to be rendered
 	Order    // Re-order a set of elements
+	Inert    // Make the page inert showing a banner, or not if the banner is empty
+	Mirror   // Ask the browser for the page HTML, or show the HTML of a mirrored page
+	Credit   // Flow control credits offered to or granted by the browser
 	// Element manipulation
-	Inner   // Set the elements inner HTML
-	Delete  // Delete the element
-	Replace // Replace the element with new HTML
-	Remove  // Remove child element
-	Insert  // Insert child element
-	Append  // Append child element
-	SAttr   // Set element attribute
-	RAttr   // Remove element attribute
-	SClass  // Set element class
-	RClass  // Remove element class
-	Value   // Set element value
+	Inner      // Set the elements inner HTML
+	Delete     // Delete the element
+	Replace    // Replace the element with new HTML
+	Remove     // Remove child element
+	Insert     // Insert child element
+	Append     // Append child element
+	SAttr      // Set element attribute
+	RAttr      // Remove element attribute
+	SClass     // Set element class
+	RClass     // Remove element class
+	Value      // Set element value
+	Fullscreen // Request or exit fullscreen for the element
+	Patch      // Patch the elements inner HTML
 	// Element input events
 	Input
 	Click
+	Paste   // Data pasted or dropped on the element
+	Capture // Data captured from camera or microphone
+	Custom  // Custom DOM event from a custom element
+	Change  // Input value committed, such as on blur or Enter
+	Key     // Key pressed on the element
 	// Testing
 	Hook // Calls event handler synchronously
 )
 
 func (w What) IsCommand() bool {
-	return w <= Order && w.IsValid()
+	return w <= Credit && w.IsValid()
 }
 
 func (w What) IsValid() bool {
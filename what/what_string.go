@@ -13,30 +13,46 @@ func _() {
 	_ = x[Reload-2]
 	_ = x[Redirect-3]
 	_ = x[Alert-4]
-	_ = x[Order-5]
-	_ = x[Inner-6]
-	_ = x[Delete-7]
-	_ = x[Replace-8]
-	_ = x[Remove-9]
-	_ = x[Insert-10]
-	_ = x[Append-11]
-	_ = x[SAttr-12]
-	_ = x[RAttr-13]
-	_ = x[SClass-14]
-	_ = x[RClass-15]
-	_ = x[Value-16]
-	_ = x[Input-17]
-	_ = x[Click-18]
-	_ = x[Hook-19]
+	_ = x[Print-5]
+	_ = x[WakeLock-6]
+	_ = x[Announce-7]
+	_ = x[Prefs-8]
+	_ = x[Widget-9]
+	_ = x[Order-10]
+	_ = x[Inert-11]
+	_ = x[Mirror-12]
+	_ = x[Credit-13]
+	_ = x[Inner-14]
+	_ = x[Delete-15]
+	_ = x[Replace-16]
+	_ = x[Remove-17]
+	_ = x[Insert-18]
+	_ = x[Append-19]
+	_ = x[SAttr-20]
+	_ = x[RAttr-21]
+	_ = x[SClass-22]
+	_ = x[RClass-23]
+	_ = x[Value-24]
+	_ = x[Fullscreen-25]
+	_ = x[Patch-26]
+	_ = x[Input-27]
+	_ = x[Click-28]
+	_ = x[Paste-29]
+	_ = x[Capture-30]
+	_ = x[Custom-31]
+	_ = x[Change-32]
+	_ = x[Key-33]
+	_ = x[Hook-34]
 }
 
-const _What_name = "invalidUpdateReloadRedirectAlertOrderInnerDeleteReplaceRemoveInsertAppendSAttrRAttrSClassRClassValueInputClickHook"
+const _What_name = "invalidUpdateReloadRedirectAlertPrintWakeLockAnnouncePrefsWidgetOrderInertMirrorCreditInnerDeleteReplaceRemoveInsertAppendSAttrRAttrSClassRClassValueFullscreenPatchInputClickPasteCaptureCustomChangeKeyHook"
 
-var _What_index = [...]uint8{0, 7, 13, 19, 27, 32, 37, 42, 48, 55, 61, 67, 73, 78, 83, 89, 95, 100, 105, 110, 114}
+var _What_index = [...]uint8{0, 7, 13, 19, 27, 32, 37, 45, 53, 58, 64, 69, 74, 80, 86, 91, 97, 104, 110, 116, 122, 127, 132, 138, 144, 149, 159, 164, 169, 174, 179, 186, 192, 198, 201, 205}
 
 func (i What) String() string {
-	if i >= What(len(_What_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_What_index)-1 {
 		return "What(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _What_name[_What_index[i]:_What_index[i+1]]
+	return _What_name[_What_index[idx]:_What_index[idx+1]]
 }
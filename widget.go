@@ -0,0 +1,120 @@
+package jaws
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/linkdata/jaws/what"
+)
+
+// WidgetFn creates the UI for a <jaws-widget> custom element, given the
+// element's attributes other than "name", "id" and "events".
+//
+// The returned params are used when rendering the UI, so WidgetFn decides
+// how the attributes map to params such as tags, attributes and handlers.
+type WidgetFn func(rq *Request, attrs map[string]string) (ui UI, params []interface{}, err error)
+
+// CustomHandler handles custom DOM events dispatched on a <jaws-widget>.
+//
+// The event name is the DOM event type, and detail is the JSON encoding
+// of the event's detail property.
+type CustomHandler interface {
+	JawsCustom(e *Element, event string, detail string) (err error)
+}
+
+type customHandlerWrapper struct{ CustomHandler }
+
+func (chw customHandlerWrapper) JawsEvent(e *Element, w what.What, v string) error {
+	if w == what.Custom {
+		return callCustomHandler(chw.CustomHandler, e, v)
+	}
+	return ErrEventUnhandled
+}
+
+func callCustomHandler(h CustomHandler, e *Element, val string) error {
+	event, detail, _ := strings.Cut(val, "\t")
+	return h.JawsCustom(e, event, detail)
+}
+
+// RegisterWidget makes fn render the <jaws-widget> custom elements with
+// the given name attribute, letting existing frontends such as SPAs embed
+// JaWS UI gradually. For example:
+//
+//	<jaws-widget name="counter" start="10" events="reset"></jaws-widget>
+//
+// Once the WebSocket connects, the custom element asks the server to render
+// the widget inside it, and does so again whenever it's attributes change.
+// DOM events with the names in the space-separated "events" attribute that
+// are dispatched on the custom element, typically CustomEvents from the
+// surrounding frontend, are sent to the first rendered Element and handled
+// by a CustomHandler.
+//
+// Registering a nil fn removes the widget.
+func (jw *Jaws) RegisterWidget(name string, fn WidgetFn) {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+	if fn == nil {
+		delete(jw.widgets, name)
+		return
+	}
+	if jw.widgets == nil {
+		jw.widgets = make(map[string]WidgetFn)
+	}
+	jw.widgets[name] = fn
+}
+
+func (jw *Jaws) getWidget(name string) (fn WidgetFn) {
+	jw.mu.RLock()
+	fn = jw.widgets[name]
+	jw.mu.RUnlock()
+	return
+}
+
+// handleWidget renders the widget requested by a <jaws-widget> custom element.
+// The data is the HTML id of the custom element, the widget name and the
+// JSON encoded attributes, separated by tabs.
+func (rq *Request) handleWidget(data string) (msgs []wsMsg) {
+	id, data, _ := strings.Cut(data, "\t")
+	name, data, _ := strings.Cut(data, "\t")
+	var attrs map[string]string
+	if id == "" || json.Unmarshal([]byte(data), &attrs) != nil {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: widget: %q", id+"\t"+name+"\t"+data))
+		return
+	}
+	fn := rq.Jaws.getWidget(name)
+	if fn == nil {
+		_ = rq.Jaws.Log(fmt.Errorf("jaws: widget %q not registered", name))
+		return
+	}
+	ui, params, err := fn(rq, attrs)
+	if err == nil {
+		var sb strings.Builder
+		if err = rq.NewElement(ui).Render(&sb, params); err == nil {
+			return append(msgs, wsMsg{
+				Data: id + "\t" + strconv.Quote(sb.String()),
+				Jid:  -1,
+				What: what.Inner,
+			})
+		}
+	}
+	var msg wsMsg
+	msg.FillAlert(rq.Jaws.Log(fmt.Errorf("jaws: widget %q: %w", name, err)))
+	return append(msgs, msg)
+}
+
+// WidgetAttrs returns the attributes as params in name order, for a WidgetFn
+// that renders them as HTML attributes of the UI.
+func WidgetAttrs(attrs map[string]string) (params []interface{}) {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		params = append(params, Attr(k, attrs[k]))
+	}
+	return
+}
@@ -0,0 +1,100 @@
+package jaws
+
+import (
+	"errors"
+	"html/template"
+	"strings"
+	"testing"
+
+	"github.com/linkdata/jaws/what"
+)
+
+type testJawsCustom struct {
+	event  string
+	detail string
+	calls  int
+}
+
+func (tjc *testJawsCustom) JawsCustom(e *Element, event, detail string) error {
+	tjc.event, tjc.detail = event, detail
+	tjc.calls++
+	return nil
+}
+
+var _ CustomHandler = (*testJawsCustom)(nil)
+
+func TestRequest_Widget(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	rq := newTestRequest()
+	defer rq.Close()
+
+	tjc := &testJawsCustom{}
+	rq.jw.RegisterWidget("greeting", func(rq *Request, attrs map[string]string) (UI, []interface{}, error) {
+		if attrs["who"] == "" {
+			return nil, nil, errors.New("who is missing")
+		}
+		return NewUiSpan(makeHtmlGetter(template.HTML("hello " + attrs["who"]))), append(WidgetAttrs(attrs), tjc), nil
+	})
+
+	rq.inCh <- wsMsg{What: what.Widget, Data: "w1\tgreeting\t{\"who\":\"world\",\"class\":\"big\"}"}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Inner\tw1\t\"<span id=\\\"Jid.1\\\" class=\\\"big\\\" who=\\\"world\\\">hello world</span>\"\n")
+	}
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Custom, "reset\t{\"to\":0}"))
+	th.Equal(tjc.calls, 1)
+	th.Equal(tjc.event, "reset")
+	th.Equal(tjc.detail, `{"to":0}`)
+
+	rq.inCh <- wsMsg{What: what.Widget, Data: "w2\tgreeting\t{}"}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case s := <-rq.outCh:
+		th.Equal(s, "Alert\t\t\"danger\\njaws: widget &#34;greeting&#34;: who is missing\"\n")
+	}
+
+	rq.inCh <- wsMsg{What: what.Widget, Data: "w3\tunknown\t{}"}
+	rq.inCh <- wsMsg{What: what.Widget, Data: "\tgreeting\t{}"}
+	rq.inCh <- wsMsg{What: what.Widget, Data: "w4\tgreeting\tnot json"}
+	rq.jw.RegisterWidget("greeting", nil)
+	rq.inCh <- wsMsg{What: what.Widget, Data: "w5\tgreeting\t{\"who\":\"you\"}"}
+	rq.Close()
+	<-rq.doneCh
+	for s := range rq.outCh {
+		t.Errorf("unexpected %q", s)
+	}
+	log := rq.jw.log.String()
+	th.True(strings.Contains(log, `jaws: widget "unknown" not registered`))
+	th.True(strings.Contains(log, `jaws: widget: "\tgreeting\t{}"`))
+	th.True(strings.Contains(log, `jaws: widget: "w4\tgreeting\tnot json"`))
+	th.True(strings.Contains(log, `jaws: widget "greeting" not registered`))
+}
+
+func Test_customHandlerWrapper_JawsEvent(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	tjc := &testJawsCustom{}
+	th.NoErr(rq.Div("inner", tjc))
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Click, "x"))
+	th.Equal(tjc.calls, 0)
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Custom, "picked\t[1,2]"))
+	th.Equal(tjc.event, "picked")
+	th.Equal(tjc.detail, "[1,2]")
+
+	th.NoErr(rq.callAllEventHandlers(1, what.Custom, "closed"))
+	th.Equal(tjc.event, "closed")
+	th.Equal(tjc.detail, "")
+	th.Equal(tjc.calls, 2)
+}
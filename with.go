@@ -4,9 +4,38 @@ import (
 	"html/template"
 )
 
+// With is the data templates are executed with. Besides the Element and
+// the Dot, it gives access to values most pages need without having
+// to pass them in the Dot:
+//
+//	{{with $.Session}}{{.Get "theme"}}{{end}}
+//	{{with $.Principal}}Signed in as {{.Name}}{{end}}
+//	<html lang="{{$.Locale}}"> {{$.T "Hello, %s!" $.Dot.Name}}
+//	{{if $.Flag "new-nav"}}...{{end}}
 type With struct {
 	*Element
 	RequestWriter
 	Dot   interface{}
 	Attrs template.HTMLAttr
+	tmpl  *template.Template // the template being executed, see Block
+}
+
+// Principal returns the authenticated principal, see Request.Principal.
+func (w With) Principal() interface{} {
+	return w.RequestWriter.rq.Principal()
+}
+
+// Locale returns the Request's locale, see Request.Locale.
+func (w With) Locale() string {
+	return w.RequestWriter.rq.Locale()
+}
+
+// T returns the translated text for key, see Request.Translate.
+func (w With) T(key string, args ...interface{}) string {
+	return w.RequestWriter.rq.Translate(key, args...)
+}
+
+// Flag returns true if the named feature flag is enabled, see Request.FlagEnabled.
+func (w With) Flag(name string) bool {
+	return w.RequestWriter.rq.FlagEnabled(name)
 }
@@ -0,0 +1,30 @@
+package jaws
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWith_Accessors(t *testing.T) {
+	th := newTestHelper(t)
+	nextJid = 0
+	tj := newTestJaws()
+	hr := httptest.NewRequest(http.MethodGet, "/", nil)
+	hr.Header.Set("Accept-Language", "fr-CA")
+	rq := tj.newRequest(hr)
+	defer rq.Close()
+
+	tj.Principal = PrincipalFunc(func(*Request) interface{} { return "alice" })
+	tj.Translator = TranslatorFunc(func(locale, key string, args ...interface{}) string {
+		return locale + "/" + key
+	})
+	fs := NewFlagSet(tj.Jaws)
+	fs.Set("beta", true)
+
+	tmpl := template.Must(template.New("with").Parse(
+		`{{$.Locale}} {{$.Principal}} {{$.T "hi"}} {{$.Flag "beta"}} {{$.Flag "gamma"}} {{if $.Session}}session{{else}}none{{end}}`))
+	th.NoErr(rq.UI(Template{Template: tmpl}))
+	th.Equal(rq.BodyString(), `fr-CA alice fr-CA/hi true false none`)
+}
@@ -2,61 +2,157 @@ package jaws
 
 import (
 	"context"
+	"errors"
 	"net/http"
-
-	"nhooyr.io/websocket"
+	"time"
 )
 
-func (rq *Request) startServe() (ok bool) {
+// ErrNoWebSocketTransport is returned when accepting a WebSocket connection
+// if built with the jaws_nonhooyr tag and Jaws.WebSocket isn't set.
+var ErrNoWebSocketTransport = errors.New("no WebSocket transport")
+
+// WebSocketConn is a WebSocket connection as used by JaWS.
+type WebSocketConn interface {
+	// Read returns the next text message, skipping any other message types.
+	Read(ctx context.Context) (msg []byte, err error)
+	// Write sends msg as a text message.
+	Write(ctx context.Context, msg []byte) error
+	// Close closes the connection normally, sending reason if it's not empty.
+	Close(reason string) error
+}
+
+// WebSocketAcceptOptions are the requirements for accepting a WebSocket connection.
+type WebSocketAcceptOptions struct {
+	Subprotocols    []string // subprotocols JaWS supports, the connection must use one of them
+	OriginPatterns  []string // host patterns for other origins allowed to connect, see path.Match
+	CompressMinSize int      // messages smaller than this are sent uncompressed, if negative compression is disabled
+	CompressContext bool     // if true, keep the compression context between messages
+	ReadLimit       int      // if positive, the largest message accepted, larger ones fail the connection
+}
+
+// WebSocketTransport accepts WebSocket connections, allowing another
+// WebSocket package than nhooyr.io/websocket to be used, see Jaws.WebSocket.
+//
+// Building with the jaws_nonhooyr tag leaves out nhooyr.io/websocket, in
+// which case Jaws.WebSocket must be set for browsers to connect.
+type WebSocketTransport interface {
+	// Accept upgrades the HTTP request to a WebSocket connection. If it
+	// fails, it must have written a HTTP error response.
+	Accept(w http.ResponseWriter, r *http.Request, opts WebSocketAcceptOptions) (WebSocketConn, error)
+}
+
+// webSocket returns the WebSocketTransport to use.
+func (jw *Jaws) webSocket() WebSocketTransport {
+	if jw.WebSocket != nil {
+		return jw.WebSocket
+	}
+	return defaultWebSocket()
+}
+
+// startServe marks the Request as running if it's been claimed and isn't
+// already running. If resumed is true, the browser has reconnected to it.
+func (rq *Request) startServe() (ok, resumed bool) {
 	rq.mu.Lock()
 	if ok = !rq.running && rq.claimed; ok {
 		rq.running = true
+		resumed = !rq.lost.IsZero()
+		rq.lost = time.Time{}
 	}
 	rq.mu.Unlock()
 	return
 }
 
+// stopServe ends the Request, unless the connection was lost and the
+// browser may reconnect to it, see Jaws.ReconnectWindow.
 func (rq *Request) stopServe() {
-	rq.cancel(nil)
-	rq.Jaws.recycle(rq)
+	if rq.suspend() {
+		rq.startParking()
+	} else {
+		rq.releaseTab()
+		rq.cancel(nil)
+		rq.Jaws.recycle(rq)
+	}
 }
 
 // ServeHTTP implements http.HanderFunc.
 //
 // Requires UseRequest() have been successfully called for the Request.
+// If a TokenValidator is set, the bearer token must be valid.
 func (rq *Request) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	if rq.startServe() {
+	if ok, resumed := rq.startServe(); ok {
 		defer rq.stopServe()
-		ws, err := websocket.Accept(w, r, nil)
+		counters := &wsCounters{total: &rq.Jaws.wsTotal}
+		ws, err := rq.Jaws.webSocket().Accept(counters.wrap(w), r, WebSocketAcceptOptions{
+			Subprotocols:    []string{WebSocketProtocol},
+			OriginPatterns:  rq.originPatterns(),
+			CompressMinSize: rq.Jaws.compressMinSize(),
+			CompressContext: rq.Jaws.CompressContext,
+			ReadLimit:       rq.maxMessageSize(),
+		})
 		if err == nil {
-			if err = rq.onConnect(); err == nil {
+			pinger, _ := ws.(WebSocketPinger)
+			rq.setCompression(w.Header(), counters)
+			fc := rq.flowControl()
+			ws = limitFlow(rq.limitSends(countingWebSocketConn{WebSocketConn: ws, counters: counters}), fc)
+			if err = rq.checkProtocol(r); err == nil {
+				err = rq.authenticate(r)
+			}
+			if err == nil {
+				err = rq.onConnect()
+			}
+			if err == nil {
+				if resumed {
+					rq.stopParking()
+				}
 				incomingMsgCh := make(chan wsMsg)
 				broadcastMsgCh := rq.Jaws.subscribe(rq, 4+len(rq.elems)*4)
 				outboundCh := make(chan string, cap(broadcastMsgCh))
-				go wsReader(rq.ctx, rq.cancelFn, rq.Jaws.Done(), incomingMsgCh, ws) // closes incomingMsgCh
-				go wsWriter(rq.ctx, rq.cancelFn, rq.Jaws.Done(), outboundCh, ws)    // calls ws.Close()
-				rq.process(broadcastMsgCh, incomingMsgCh, outboundCh)               // unsubscribes broadcastMsgCh, closes outboundMsgCh
+				rq.setOutbound(outboundCh)
+				rq.markSeen()
+				if fc != nil {
+					rq.setFlow(fc)
+					rq.wsSend(outboundCh, fc.offer())
+				}
+				rq.checkAssetVersion()
+				rq.checkTabs(resumed)
+				if resumed {
+					rq.resync(outboundCh)
+				}
+				if interval := rq.Jaws.PingInterval; interval > 0 && pinger != nil {
+					go rq.wsPinger(rq.ctx, rq.cancelFn, rq.Jaws.Done(), pinger, interval, rq.Jaws.pingTimeout())
+				}
+				go wsReader(rq.ctx, rq.cancelFn, rq.Jaws.Done(), incomingMsgCh, rq.readLimits(), ws) // closes incomingMsgCh
+				go wsWriter(rq.ctx, rq.cancelFn, rq.Jaws.Done(), outboundCh, rq.flushWindow(), ws)   // calls ws.Close()
+				rq.process(broadcastMsgCh, incomingMsgCh, outboundCh)                                // unsubscribes broadcastMsgCh, closes outboundMsgCh
+				rq.setOutbound(nil)
+				rq.setFlow(nil)
+				rq.parkUnsent(outboundCh)
 			} else {
-				defer ws.Close(websocket.StatusNormalClosure, err.Error())
-				var msg wsMsg
-				msg.FillAlert(rq.Jaws.Log(err))
-				_ = ws.Write(r.Context(), websocket.MessageText, msg.Append(nil))
+				defer ws.Close(err.Error())
+				msg := rq.connectErrorMsg(err)
+				_ = ws.Write(r.Context(), msg.Append(nil))
 			}
 		}
 		rq.cancel(err)
 	}
 }
 
-// wsReader reads websocket text messages, parses them and sends them on incomingMsgCh.
+// wsReader reads websocket text messages, parses them and sends them on incomingMsgCh,
+// applying the limits in rl.
 //
 // Closes incomingMsgCh on exit.
-func wsReader(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-chan struct{}, incomingMsgCh chan<- wsMsg, ws *websocket.Conn) {
-	var typ websocket.MessageType
+func wsReader(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-chan struct{}, incomingMsgCh chan<- wsMsg, rl readLimits, ws WebSocketConn) {
 	var txt []byte
 	var err error
 	defer close(incomingMsgCh)
 	for err == nil {
-		if typ, txt, err = ws.Read(ctx); typ == websocket.MessageText {
+		if !rl.wait(ctx, jawsDoneCh) {
+			return
+		}
+		if txt, err = ws.Read(ctx); err == nil {
+			if err = rl.checkSize(txt); err != nil {
+				break
+			}
 			if msg, ok := wsParse(txt); ok {
 				select {
 				case <-ctx.Done():
@@ -74,10 +170,12 @@ func wsReader(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-cha
 }
 
 // wsWriter reads JaWS messages from outboundMsgCh, formats them and writes them to the websocket.
+// Messages already queued, and those queued within flushInterval of the
+// first, are written as a single WebSocket message.
 //
 // Closes the websocket on exit.
-func wsWriter(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-chan struct{}, outboundCh <-chan string, ws *websocket.Conn) {
-	defer ws.Close(websocket.StatusNormalClosure, "")
+func wsWriter(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-chan struct{}, outboundCh <-chan string, fw flushWindow, ws WebSocketConn) {
+	defer ws.Close("")
 	var err error
 	for err == nil {
 		select {
@@ -89,7 +187,13 @@ func wsWriter(ctx context.Context, ccf context.CancelCauseFunc, jawsDoneCh <-cha
 			if !ok {
 				return
 			}
-			err = ws.Write(ctx, websocket.MessageText, []byte(msg))
+			msg, ok = wsBatch(ctx, jawsDoneCh, outboundCh, fw.interval(), msg)
+			start := time.Now()
+			err = ws.Write(ctx, []byte(wsCoalesce(msg)))
+			fw.observe(time.Since(start))
+			if !ok {
+				return
+			}
 		}
 	}
 	if ccf != nil {
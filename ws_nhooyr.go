@@ -0,0 +1,66 @@
+//go:build !jaws_nonhooyr
+
+package jaws
+
+import (
+	"context"
+	"net/http"
+
+	"nhooyr.io/websocket"
+)
+
+// defaultWebSocket returns the WebSocketTransport used if Jaws.WebSocket isn't set.
+func defaultWebSocket() WebSocketTransport {
+	return nhooyrTransport{}
+}
+
+// nhooyrTransport is the WebSocketTransport using nhooyr.io/websocket.
+type nhooyrTransport struct{}
+
+func (nhooyrTransport) Accept(w http.ResponseWriter, r *http.Request, opts WebSocketAcceptOptions) (WebSocketConn, error) {
+	mode := websocket.CompressionNoContextTakeover
+	if opts.CompressContext {
+		mode = websocket.CompressionContextTakeover
+	}
+	if opts.CompressMinSize < 0 {
+		mode = websocket.CompressionDisabled
+	}
+	ws, err := websocket.Accept(w, r, &websocket.AcceptOptions{
+		Subprotocols:         opts.Subprotocols,
+		OriginPatterns:       opts.OriginPatterns,
+		CompressionMode:      mode,
+		CompressionThreshold: opts.CompressMinSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.ReadLimit > 0 {
+		ws.SetReadLimit(int64(opts.ReadLimit))
+	}
+	return nhooyrConn{ws}, nil
+}
+
+// nhooyrConn adapts a nhooyr.io/websocket connection to WebSocketConn.
+type nhooyrConn struct{ ws *websocket.Conn }
+
+func (c nhooyrConn) Read(ctx context.Context) (msg []byte, err error) {
+	var typ websocket.MessageType
+	for err == nil {
+		if typ, msg, err = c.ws.Read(ctx); typ == websocket.MessageText {
+			break
+		}
+	}
+	return
+}
+
+func (c nhooyrConn) Write(ctx context.Context, msg []byte) error {
+	return c.ws.Write(ctx, websocket.MessageText, msg)
+}
+
+func (c nhooyrConn) Close(reason string) error {
+	return c.ws.Close(websocket.StatusNormalClosure, reason)
+}
+
+func (c nhooyrConn) Ping(ctx context.Context) error {
+	return c.ws.Ping(ctx)
+}
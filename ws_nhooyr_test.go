@@ -0,0 +1,824 @@
+//go:build !jaws_nonhooyr
+
+package jaws
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/linkdata/jaws/what"
+	"nhooyr.io/websocket"
+)
+
+// testUpgradeStatus is the status of a plain HTTP request for a WebSocket.
+const testUpgradeStatus = http.StatusUpgradeRequired
+
+func TestReader_RespectsContextDone(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	msg := wsMsg{Jid: Jid(1234), What: what.Input}
+	doneCh := make(chan struct{})
+	inCh := make(chan wsMsg)
+	client, server := Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
+	defer cancel()
+
+	go func() {
+		defer close(doneCh)
+		wsReader(ts.ctx, nil, ts.jw.Done(), inCh, readLimits{}, nhooyrConn{server})
+	}()
+
+	client.Write(ctx, websocket.MessageText, []byte(msg.Format()))
+
+	// wsReader should now be blocked trying to send the decoded message
+	select {
+	case <-doneCh:
+		t.Error("did not block")
+	case <-time.NewTimer(time.Millisecond).C:
+	}
+
+	ts.cancel()
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+}
+
+func TestReader_RespectsJawsDone(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	inCh := make(chan wsMsg)
+	client, server := Pipe()
+
+	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
+	defer cancel()
+
+	go func() {
+		defer close(doneCh)
+		wsReader(ts.ctx, nil, ts.jw.Done(), inCh, readLimits{}, nhooyrConn{server})
+	}()
+
+	ts.jw.Close()
+	msg := wsMsg{Jid: Jid(1234), What: what.Input}
+	err := client.Write(ctx, websocket.MessageText, []byte(msg.Format()))
+	if err != nil {
+		t.Error(err)
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+}
+
+func TestWriter_SendsThePayload(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	outCh := make(chan string)
+	defer close(outCh)
+	client, server := Pipe()
+
+	go wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, flushWindow{}, nhooyrConn{server})
+
+	var mt websocket.MessageType
+	var b []byte
+	var err error
+	doneCh := make(chan struct{})
+	go func() {
+		defer close(doneCh)
+		mt, b, err = client.Read(ts.ctx)
+		ts.cancel()
+	}()
+
+	msg := wsMsg{Jid: Jid(1234)}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case outCh <- msg.Format():
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+
+	if err != nil {
+		t.Error(err)
+	}
+	if mt != websocket.MessageText {
+		t.Error(mt)
+	}
+	if string(b) != msg.Format() {
+		t.Error(string(b))
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-client.CloseRead(ts.ctx).Done():
+	}
+}
+
+func TestWriter_RespectsContext(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	outCh := make(chan string)
+	defer close(outCh)
+	client, server := Pipe()
+	client.CloseRead(context.Background())
+
+	go func() {
+		defer close(doneCh)
+		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, flushWindow{}, nhooyrConn{server})
+	}()
+
+	ts.cancel()
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+		return
+	}
+}
+
+func TestWriter_RespectsJawsDone(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	outCh := make(chan string)
+	defer close(outCh)
+	client, server := Pipe()
+	client.CloseRead(ts.ctx)
+
+	go func() {
+		defer close(doneCh)
+		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, flushWindow{}, nhooyrConn{server})
+	}()
+
+	ts.jw.Close()
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+}
+
+func TestWriter_RespectsOutboundClosed(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	outCh := make(chan string)
+	client, server := Pipe()
+	client.CloseRead(ts.ctx)
+
+	go func() {
+		defer close(doneCh)
+		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, flushWindow{}, nhooyrConn{server})
+	}()
+
+	close(outCh)
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+
+	if err := ts.rq.Context().Err(); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestWriter_ReportsError(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	outCh := make(chan string)
+	client, server := Pipe()
+	client.CloseRead(ts.ctx)
+	server.Close(websocket.StatusNormalClosure, "")
+
+	go func() {
+		defer close(doneCh)
+		wsWriter(ts.rq.ctx, ts.rq.cancelFn, ts.jw.Done(), outCh, flushWindow{}, nhooyrConn{server})
+	}()
+
+	msg := wsMsg{Jid: Jid(1234)}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case outCh <- msg.Format():
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+
+	err := context.Cause(ts.rq.Context())
+	if !strings.Contains(err.Error(), "WebSocket closed") {
+		t.Error(err)
+	}
+}
+
+func TestReader_ReportsError(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	doneCh := make(chan struct{})
+	inCh := make(chan wsMsg)
+	client, server := Pipe()
+	client.CloseRead(ts.ctx)
+	server.Close(websocket.StatusNormalClosure, "")
+
+	go func() {
+		defer close(doneCh)
+		wsReader(ts.rq.ctx, ts.rq.cancelFn, ts.jw.Done(), inCh, readLimits{}, nhooyrConn{server})
+	}()
+
+	msg := wsMsg{Jid: Jid(1234), What: what.Input}
+	err := client.Write(ts.ctx, websocket.MessageText, []byte(msg.Format()))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-doneCh:
+	}
+
+	err = context.Cause(ts.rq.Context())
+	if !strings.Contains(err.Error(), "WebSocket closed") {
+		t.Error(err)
+	}
+}
+
+// testDialOptions returns opts, or new DialOptions if nil, offering
+// the subprotocols jaws.js does.
+func testDialOptions(opts *websocket.DialOptions) *websocket.DialOptions {
+	if opts == nil {
+		opts = &websocket.DialOptions{}
+	}
+	if len(opts.Subprotocols) == 0 {
+		opts.Subprotocols = []string{WebSocketProtocol}
+	}
+	opts.Subprotocols = append(opts.Subprotocols, WebSocketProtocol+".v"+strconv.Itoa(ProtocolVersion))
+	return opts
+}
+
+// adapted from nhooyr.io/websocket/internal/test/wstest.Pipe
+
+func Pipe() (clientConn, serverConn *websocket.Conn) {
+	dialOpts := &websocket.DialOptions{
+		HTTPClient: &http.Client{
+			Transport: fakeTransport{
+				h: func(w http.ResponseWriter, r *http.Request) {
+					serverConn, _ = websocket.Accept(w, r, nil)
+				},
+			},
+		},
+	}
+
+	clientConn, _, _ = websocket.Dial(context.Background(), "ws://localhost", dialOpts)
+	return clientConn, serverConn
+}
+
+type fakeTransport struct {
+	h http.HandlerFunc
+}
+
+func (t fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	clientConn, serverConn := net.Pipe()
+
+	hj := testHijacker{
+		ResponseRecorder: httptest.NewRecorder(),
+		serverConn:       serverConn,
+	}
+
+	t.h.ServeHTTP(hj, r)
+
+	resp := hj.ResponseRecorder.Result()
+	if resp.StatusCode == http.StatusSwitchingProtocols {
+		resp.Body = clientConn
+	}
+	return resp, nil
+}
+
+type testHijacker struct {
+	*httptest.ResponseRecorder
+	serverConn net.Conn
+}
+
+var _ http.Hijacker = testHijacker{}
+
+func (hj testHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	return hj.serverConn, bufio.NewReadWriter(bufio.NewReader(hj.serverConn), bufio.NewWriter(hj.serverConn)), nil
+}
+
+func testCompression(t *testing.T, minSize int, text string) (c Compression, stats CompressionStats) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	go ts.jw.Serve()
+	ts.jw.CompressMinSize = minSize
+
+	conn, _, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	th.NoErr(err)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-ts.connectedCh:
+	}
+
+	ts.jw.Alert("info", text)
+	_, b, err := conn.Read(ts.ctx)
+	th.NoErr(err)
+	th.True(strings.Contains(string(b), text))
+
+	for ts.rq.Compression().Messages == 0 {
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	return ts.rq.Compression(), ts.jw.CompressionStats()
+}
+
+func TestWS_Compression(t *testing.T) {
+	th := newTestHelper(t)
+	text := strings.Repeat("compressible ", 100)
+
+	c, stats := testCompression(t, 0, text)
+	th.Equal(c.Enabled, true)
+	th.Equal(c.ContextTakeover, false)
+	th.Equal(c.MinSize, DefaultCompressMinSize)
+	th.Equal(c.Messages, uint64(1))
+	th.True(c.Bytes > uint64(len(text)))
+	th.True(c.Ratio() < 0.5)
+	th.Equal(stats.Connections, uint64(1))
+	th.Equal(stats.Compressed, uint64(1))
+	th.Equal(stats.WireStats, c.WireStats)
+
+	c, _ = testCompression(t, len(text)*2, text)
+	th.Equal(c.Enabled, true)
+	th.True(c.Ratio() > 1)
+
+	c, stats = testCompression(t, -1, text)
+	th.Equal(c.Enabled, false)
+	th.True(c.Ratio() > 1)
+	th.Equal(stats.Compressed, uint64(0))
+}
+
+func TestWS_ParkedAlert(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	go ts.jw.Serve()
+	ts.jw.ReconnectWindow = time.Minute
+	ts.jw.ParkWindow = time.Minute
+	ts.rq.SetConnectFn(nil)
+	th.True(ts.rq.Session() == ts.sess)
+
+	conn, _, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	th.NoErr(err)
+	conn.Close(websocket.StatusGoingAway, "")
+
+	waitFor := func(cond func() bool) {
+		for !cond() {
+			select {
+			case <-th.C:
+				th.Timeout()
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}
+	waitFor(func() bool {
+		ts.rq.mu.RLock()
+		defer ts.rq.mu.RUnlock()
+		return !ts.rq.lost.IsZero() && ts.rq.parkStop != nil
+	})
+
+	ts.jw.Alert("info", "while away")
+	ts.jw.Broadcast(Message{What: what.Inner, Dest: Tag("nothing"), Data: "not parked"})
+	waitFor(func() bool {
+		ts.sess.mu.RLock()
+		defer ts.sess.mu.RUnlock()
+		return len(ts.sess.parked) > 0
+	})
+
+	th.Equal(ts.jw.UseRequest(ts.rq.JawsKey, ts.hr), ts.rq)
+	conn, _, err = websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	th.NoErr(err)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	ctx, cancel := context.WithTimeout(ts.ctx, testTimeout)
+	defer cancel()
+	_, b, err := conn.Read(ctx)
+	th.NoErr(err)
+	msg := wsMsg{What: what.Alert, Data: "info\nwhile away"}
+	th.Equal(string(b), msg.Format())
+
+	ts.rq.mu.RLock()
+	th.Equal(ts.rq.parkStop, (chan struct{})(nil))
+	ts.rq.mu.RUnlock()
+	th.Equal(ts.sess.unpark(ts.rq.JawsKey), "")
+	th.True(!strings.Contains(string(b), "not parked"))
+}
+
+func TestWS_Reconnect(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	go ts.jw.Serve()
+	ts.jw.ReconnectWindow = time.Minute
+	ts.rq.SetConnectFn(nil)
+
+	ui := &testUi{updateFn: func(e *Element) { e.SetInner("resynced") }}
+	elem := ts.rq.NewElement(ui)
+
+	conn, _, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	th.NoErr(err)
+	conn.Close(websocket.StatusGoingAway, "")
+
+	for {
+		ts.rq.mu.RLock()
+		lost := !ts.rq.lost.IsZero()
+		ts.rq.mu.RUnlock()
+		if lost {
+			break
+		}
+		select {
+		case <-th.C:
+			th.Timeout()
+		case <-time.After(time.Millisecond):
+		}
+	}
+	th.Equal(ts.jw.RequestCount(), 1)
+	th.Equal(ts.jw.UseRequest(ts.rq.JawsKey, ts.hr), ts.rq)
+
+	conn, _, err = websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	ctx, cancel := context.WithTimeout(ts.ctx, testTimeout)
+	defer cancel()
+	_, b, err := conn.Read(ctx)
+	th.NoErr(err)
+	msg := wsMsg{Jid: elem.Jid(), What: what.Inner, Data: "resynced"}
+	th.Equal(string(b), msg.Format())
+	th.Equal(atomic.LoadInt32(&ui.updateCalled), int32(1))
+}
+
+func TestWS_RequestOptionsTokenValidator(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	ts.rq.opts = &RequestOptions{
+		TokenValidator: TokenValidatorFunc(func(rq *Request, token string) error {
+			return errors.New("admin token required")
+		}),
+	}
+
+	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	th.NoErr(err)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	th.Equal(resp.StatusCode, http.StatusSwitchingProtocols)
+	_, b, err := conn.Read(ts.ctx)
+	th.NoErr(err)
+	var m wsMsg
+	m.FillAlert(errors.New("admin token required"))
+	th.Equal(string(b), string(m.Append(nil)))
+}
+
+func TestWS_RequestOptionsOriginPatterns(t *testing.T) {
+	th := newTestHelper(t)
+	dial := func(patterns []string) int {
+		ts := newTestServer()
+		defer ts.Close()
+		ts.rq.opts = &RequestOptions{OriginPatterns: patterns}
+		hdr := http.Header{}
+		hdr.Set("Origin", "https://app.example.com")
+		conn, resp, _ := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(&websocket.DialOptions{HTTPHeader: hdr}))
+		if conn != nil {
+			conn.Close(websocket.StatusNormalClosure, "")
+		}
+		return resp.StatusCode
+	}
+	th.Equal(dial(nil), http.StatusForbidden)
+	th.Equal(dial([]string{"*.example.com"}), http.StatusSwitchingProtocols)
+}
+
+func TestSession_Delete(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	go ts.jw.ServeWithTimeout(time.Second)
+
+	// the test session is there
+	sl := ts.jw.Sessions()
+	if x := len(sl); x != 1 {
+		t.Fatal(x)
+	}
+	if x := sl[0]; x != ts.sess {
+		t.Fatal(x)
+	}
+
+	// session cookie seems ok
+	cookie1 := &ts.sess.cookie
+	if cookie1 != nil {
+		if x := cookie1.Name; x != ts.jw.CookieName {
+			t.Error(x)
+		}
+	} else {
+		t.Fatal(cookie1)
+	}
+
+	// trying to get the session from another IP fails
+	hr2 := httptest.NewRequest("GET", "/", nil)
+	hr2.AddCookie(&ts.sess.cookie)
+	hr2.RemoteAddr = "10.5.6.7:89"
+	sess := ts.jw.GetSession(hr2)
+	if x := sess; x != nil {
+		t.Error(x)
+	}
+
+	// accessing from same IP but other port works
+	host, port, _ := net.SplitHostPort(ts.hr.RemoteAddr)
+	if port == "1" {
+		port = "2"
+	} else {
+		port = "1"
+	}
+	hr2.RemoteAddr = net.JoinHostPort(host, port)
+	sess = ts.jw.GetSession(hr2)
+	if x := sess; x != ts.sess {
+		t.Error(x)
+	}
+
+	rq2 := ts.jw.NewRequest(hr2)
+	if x := rq2.Session(); x != ts.sess {
+		t.Error(x)
+	}
+
+	// session should now have both requests listed
+	rl := sess.Requests()
+	if len(rl) != 2 {
+		t.Error(len(rl))
+	}
+	if !slices.Contains(rl, ts.rq) {
+		t.Errorf("%v missing from %v", ts.rq, rl)
+	}
+	if !slices.Contains(rl, rq2) {
+		t.Errorf("%v missing from %v", rq2, rl)
+	}
+
+	ts.rq.Register("byebye", func(e *Element, evt what.What, val string) error {
+		sess2 := ts.jw.GetSession(e.Request.Initial)
+		if x := sess2; x != ts.sess {
+			t.Error(x)
+		}
+		if x := sess2.cookie.MaxAge; x < 0 {
+			t.Error(x)
+		}
+
+		cookie2 := sess2.Close()
+		if x := cookie2; x == nil {
+			t.Fatal(x)
+		}
+		if x := cookie2.MaxAge; x != -1 {
+			t.Error(x)
+		}
+		if x := cookie2.Expires.IsZero(); !x {
+			t.Error(x)
+		}
+		if x := cookie2.Name; x != cookie1.Name {
+			t.Error(x)
+		}
+		if x := cookie2.Value; x != cookie1.Value {
+			t.Error(x)
+		}
+		return nil
+	})
+
+	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x := resp.StatusCode; x != http.StatusSwitchingProtocols {
+		t.Error(x)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	msg := wsMsg{Jid: jidForTag(ts.rq, Tag("byebye")), What: what.Input}
+	ctx, cancel := context.WithCancel(ts.ctx)
+	defer cancel()
+
+	err = conn.Write(ctx, websocket.MessageText, msg.Append(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if x := ctx.Err(); x != nil {
+		t.Fatal(x)
+	}
+	if x := ts.ctx.Err(); x != nil {
+		t.Fatal(x)
+	}
+
+	type readResult struct {
+		mt  websocket.MessageType
+		b   []byte
+		err error
+	}
+
+	resultChan := make(chan readResult)
+
+	go func() {
+		var rr readResult
+		defer close(resultChan)
+		rr.mt, rr.b, rr.err = conn.Read(ctx)
+		resultChan <- rr
+	}()
+
+	if x := ts.ctx.Err(); x != nil {
+		t.Fatal(x)
+	}
+
+	select {
+	case <-th.C:
+		th.Timeout()
+	case rr, ok := <-resultChan:
+		if ok {
+			if x := rr.err; x != nil {
+				t.Fatal(x)
+			}
+			if x := ctx.Err(); x != nil {
+				t.Fatal(x)
+			}
+			if x := ts.ctx.Err(); x != nil {
+				t.Fatal(x)
+			}
+			if x := sess.cookie.MaxAge; x != -1 {
+				t.Error(x)
+			}
+			if x := rr.mt; x != websocket.MessageText {
+				t.Error(x)
+			}
+			if x := string(rr.b); x != "Reload\t\t\"\"\n" {
+				t.Error(x)
+			}
+		} else {
+			t.Error("resultChan closed")
+		}
+	}
+}
+
+func TestWS_ConnectFnFails(t *testing.T) {
+	const nope = "nope"
+	ts := newTestServer()
+	defer ts.Close()
+	ts.rq.SetConnectFn(func(_ *Request) error { return errors.New(nope) })
+
+	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	if conn != nil {
+		defer conn.Close(websocket.StatusNormalClosure, "")
+	}
+	if err != nil {
+		t.Error(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Error(resp.StatusCode)
+	}
+	mt, b, err := conn.Read(ts.ctx)
+	if err != nil {
+		t.Error(err)
+	}
+	if mt != websocket.MessageText {
+		t.Error(mt)
+	}
+	if !strings.Contains(string(b), nope) {
+		t.Error(string(b))
+	}
+}
+
+func TestWS_NormalExchange(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+
+	fooError := errors.New("this foo failed")
+
+	gotCallCh := make(chan struct{})
+
+	ts.rq.Register(("foo"), func(e *Element, evt what.What, val string) error {
+		close(gotCallCh)
+		return fooError
+	})
+
+	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Error(resp.StatusCode)
+	}
+	defer conn.Close(websocket.StatusNormalClosure, "")
+
+	msg := wsMsg{Jid: jidForTag(ts.rq, Tag("foo")), What: what.Input}
+	ctx, cancel := context.WithTimeout(ts.ctx, testTimeout)
+	defer cancel()
+
+	err = conn.Write(ctx, websocket.MessageText, msg.Append(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-th.C:
+		th.Timeout()
+	case <-gotCallCh:
+	}
+
+	mt, b, err := conn.Read(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mt != websocket.MessageText {
+		t.Error(mt)
+	}
+	var m2 wsMsg
+	m2.FillAlert(fooError)
+	if !bytes.Equal(b, m2.Append(nil)) {
+		t.Error(b)
+	}
+}
+
+func TestWS_TokenValidator(t *testing.T) {
+	th := newTestHelper(t)
+	ts := newTestServer()
+	defer ts.Close()
+	errDenied := errors.New("access denied")
+	tokenCh := make(chan string, 2)
+	ts.jw.TokenValidator = TokenValidatorFunc(func(rq *Request, token string) error {
+		tokenCh <- token
+		if token != "good" {
+			return errDenied
+		}
+		return nil
+	})
+
+	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), testDialOptions(&websocket.DialOptions{
+		Subprotocols: []string{WebSocketProtocol, WebSocketTokenPrefix + "bad"},
+	}))
+	th.NoErr(err)
+	defer conn.Close(websocket.StatusNormalClosure, "")
+	th.Equal(resp.StatusCode, http.StatusSwitchingProtocols)
+	th.Equal(conn.Subprotocol(), WebSocketProtocol)
+	th.Equal(<-tokenCh, "bad")
+	_, b, err := conn.Read(ts.ctx)
+	th.NoErr(err)
+	var m wsMsg
+	m.FillAlert(errDenied)
+	th.Equal(string(b), string(m.Append(nil)))
+	_, _, err = conn.Read(ts.ctx)
+	th.True(err != nil)
+	th.True(strings.Contains(err.Error(), errDenied.Error()))
+}
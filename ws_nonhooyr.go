@@ -0,0 +1,20 @@
+//go:build jaws_nonhooyr
+
+package jaws
+
+import (
+	"net/http"
+)
+
+// defaultWebSocket returns the WebSocketTransport used if Jaws.WebSocket isn't set.
+func defaultWebSocket() WebSocketTransport {
+	return noWebSocket{}
+}
+
+// noWebSocket is the WebSocketTransport used when built without nhooyr.io/websocket.
+type noWebSocket struct{}
+
+func (noWebSocket) Accept(w http.ResponseWriter, r *http.Request, opts WebSocketAcceptOptions) (WebSocketConn, error) {
+	http.Error(w, ErrNoWebSocketTransport.Error(), http.StatusNotImplemented)
+	return nil, ErrNoWebSocketTransport
+}
@@ -0,0 +1,23 @@
+//go:build jaws_nonhooyr
+
+package jaws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// testUpgradeStatus is the status of a plain HTTP request for a WebSocket.
+const testUpgradeStatus = http.StatusNotImplemented
+
+func TestJaws_noWebSocket(t *testing.T) {
+	th := newTestHelper(t)
+	jw := New()
+	defer jw.Close()
+	rr := httptest.NewRecorder()
+	ws, err := jw.webSocket().Accept(rr, httptest.NewRequest(http.MethodGet, "/jaws/x", nil), WebSocketAcceptOptions{})
+	th.Equal(ws, nil)
+	th.Equal(err, ErrNoWebSocketTransport)
+	th.Equal(rr.Code, http.StatusNotImplemented)
+}
@@ -1,19 +1,17 @@
 package jaws
 
 import (
-	"bufio"
-	"bytes"
 	"context"
 	"errors"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/linkdata/jaws/what"
-	"nhooyr.io/websocket"
 )
 
 type testServer struct {
@@ -94,401 +92,96 @@ func TestWS_UpgradeRequired(t *testing.T) {
 	jw.UseRequest(rq.JawsKey, hr)
 	req := httptest.NewRequest("", "/jaws/"+rq.JawsKeyString(), nil)
 	rq.ServeHTTP(w, req)
-	if w.Code != http.StatusUpgradeRequired {
+	if w.Code != testUpgradeStatus {
 		t.Error(w.Code)
 	}
 }
 
-func TestWS_ConnectFnFails(t *testing.T) {
-	const nope = "nope"
-	ts := newTestServer()
-	defer ts.Close()
-	ts.rq.SetConnectFn(func(_ *Request) error { return errors.New(nope) })
-
-	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), nil)
-	if conn != nil {
-		defer conn.Close(websocket.StatusNormalClosure, "")
-	}
-	if err != nil {
-		t.Error(err)
-	}
-	if resp.StatusCode != http.StatusSwitchingProtocols {
-		t.Error(resp.StatusCode)
-	}
-	mt, b, err := conn.Read(ts.ctx)
-	if err != nil {
-		t.Error(err)
-	}
-	if mt != websocket.MessageText {
-		t.Error(mt)
-	}
-	if !strings.Contains(string(b), nope) {
-		t.Error(string(b))
-	}
+type testWebSocketConn struct {
+	inCh     chan []byte
+	outCh    chan []byte
+	closedCh chan struct{}
+	once     sync.Once
 }
 
-func TestWS_NormalExchange(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	fooError := errors.New("this foo failed")
-
-	gotCallCh := make(chan struct{})
-
-	ts.rq.Register(("foo"), func(e *Element, evt what.What, val string) error {
-		close(gotCallCh)
-		return fooError
-	})
-
-	conn, resp, err := websocket.Dial(ts.ctx, ts.Url(), nil)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if resp.StatusCode != http.StatusSwitchingProtocols {
-		t.Error(resp.StatusCode)
-	}
-	defer conn.Close(websocket.StatusNormalClosure, "")
-
-	msg := wsMsg{Jid: jidForTag(ts.rq, Tag("foo")), What: what.Input}
-	ctx, cancel := context.WithTimeout(ts.ctx, testTimeout)
-	defer cancel()
-
-	err = conn.Write(ctx, websocket.MessageText, msg.Append(nil))
-	if err != nil {
-		t.Fatal(err)
-	}
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-gotCallCh:
-	}
-
-	mt, b, err := conn.Read(ctx)
-	if err != nil {
-		t.Fatal(err)
-	}
-	if mt != websocket.MessageText {
-		t.Error(mt)
-	}
-	var m2 wsMsg
-	m2.FillAlert(fooError)
-	if !bytes.Equal(b, m2.Append(nil)) {
-		t.Error(b)
-	}
-}
-
-func TestReader_RespectsContextDone(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	msg := wsMsg{Jid: Jid(1234), What: what.Input}
-	doneCh := make(chan struct{})
-	inCh := make(chan wsMsg)
-	client, server := Pipe()
-
-	ctx, cancel := context.WithTimeout(context.Background(), time.Second*3)
-	defer cancel()
-
-	go func() {
-		defer close(doneCh)
-		wsReader(ts.ctx, nil, ts.jw.Done(), inCh, server)
-	}()
-
-	client.Write(ctx, websocket.MessageText, []byte(msg.Format()))
-
-	// wsReader should now be blocked trying to send the decoded message
-	select {
-	case <-doneCh:
-		t.Error("did not block")
-	case <-time.NewTimer(time.Millisecond).C:
-	}
-
-	ts.cancel()
-
+func (c *testWebSocketConn) Read(ctx context.Context) ([]byte, error) {
 	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closedCh:
+		return nil, net.ErrClosed
+	case msg := <-c.inCh:
+		return msg, nil
 	}
 }
 
-func TestReader_RespectsJawsDone(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	doneCh := make(chan struct{})
-	inCh := make(chan wsMsg)
-	client, server := Pipe()
-
-	ctx, cancel := context.WithTimeout(context.Background(), testTimeout)
-	defer cancel()
-
-	go func() {
-		defer close(doneCh)
-		wsReader(ts.ctx, nil, ts.jw.Done(), inCh, server)
-	}()
-
-	ts.jw.Close()
-	msg := wsMsg{Jid: Jid(1234), What: what.Input}
-	err := client.Write(ctx, websocket.MessageText, []byte(msg.Format()))
-	if err != nil {
-		t.Error(err)
-	}
-
+func (c *testWebSocketConn) Write(ctx context.Context, msg []byte) error {
 	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-c.closedCh:
+		return net.ErrClosed
+	case c.outCh <- msg:
+		return nil
 	}
 }
 
-func TestWriter_SendsThePayload(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	outCh := make(chan string)
-	defer close(outCh)
-	client, server := Pipe()
-
-	go wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, server)
-
-	var mt websocket.MessageType
-	var b []byte
-	var err error
-	doneCh := make(chan struct{})
-	go func() {
-		defer close(doneCh)
-		mt, b, err = client.Read(ts.ctx)
-		ts.cancel()
-	}()
-
-	msg := wsMsg{Jid: Jid(1234)}
-	select {
-	case <-th.C:
-		th.Timeout()
-	case outCh <- msg.Format():
-	}
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
-	}
-
-	if err != nil {
-		t.Error(err)
-	}
-	if mt != websocket.MessageText {
-		t.Error(mt)
-	}
-	if string(b) != msg.Format() {
-		t.Error(string(b))
-	}
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-client.CloseRead(ts.ctx).Done():
-	}
+func (c *testWebSocketConn) Close(reason string) error {
+	c.once.Do(func() { close(c.closedCh) })
+	return nil
 }
 
-func TestWriter_RespectsContext(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	doneCh := make(chan struct{})
-	outCh := make(chan string)
-	defer close(outCh)
-	client, server := Pipe()
-	client.CloseRead(context.Background())
-
-	go func() {
-		defer close(doneCh)
-		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, server)
-	}()
-
-	ts.cancel()
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
-		return
-	}
+type testWebSocketTransport struct {
+	opts WebSocketAcceptOptions
+	conn *testWebSocketConn
 }
 
-func TestWriter_RespectsJawsDone(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	doneCh := make(chan struct{})
-	outCh := make(chan string)
-	defer close(outCh)
-	client, server := Pipe()
-	client.CloseRead(ts.ctx)
-
-	go func() {
-		defer close(doneCh)
-		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, server)
-	}()
-
-	ts.jw.Close()
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
-	}
+func (t *testWebSocketTransport) Accept(w http.ResponseWriter, r *http.Request, opts WebSocketAcceptOptions) (WebSocketConn, error) {
+	t.opts = opts
+	return t.conn, nil
 }
 
-func TestWriter_RespectsOutboundClosed(t *testing.T) {
+func TestWS_CustomTransport(t *testing.T) {
 	th := newTestHelper(t)
 	ts := newTestServer()
 	defer ts.Close()
 
-	doneCh := make(chan struct{})
-	outCh := make(chan string)
-	client, server := Pipe()
-	client.CloseRead(ts.ctx)
-
-	go func() {
-		defer close(doneCh)
-		wsWriter(ts.ctx, nil, ts.jw.Done(), outCh, server)
-	}()
-
-	close(outCh)
-
-	select {
-	case <-th.C:
-		th.Timeout()
-	case <-doneCh:
-	}
-
-	if err := ts.rq.Context().Err(); err != nil {
-		t.Error(err)
-	}
-}
+	conn := &testWebSocketConn{inCh: make(chan []byte), outCh: make(chan []byte), closedCh: make(chan struct{})}
+	tr := &testWebSocketTransport{conn: conn}
+	ts.jw.WebSocket = tr
 
-func TestWriter_ReportsError(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
+	fooError := errors.New("this foo failed")
+	ts.rq.Register(("foo"), func(e *Element, evt what.What, val string) error {
+		return fooError
+	})
 
 	doneCh := make(chan struct{})
-	outCh := make(chan string)
-	client, server := Pipe()
-	client.CloseRead(ts.ctx)
-	server.Close(websocket.StatusNormalClosure, "")
-
 	go func() {
 		defer close(doneCh)
-		wsWriter(ts.rq.ctx, ts.rq.cancelFn, ts.jw.Done(), outCh, server)
+		ts.rq.ServeHTTP(httptest.NewRecorder(), newProtocolRequest(http.MethodGet, ts.Path(), nil))
 	}()
 
-	msg := wsMsg{Jid: Jid(1234)}
+	msg := wsMsg{Jid: jidForTag(ts.rq, Tag("foo")), What: what.Input}
 	select {
 	case <-th.C:
 		th.Timeout()
-	case outCh <- msg.Format():
+	case conn.inCh <- msg.Append(nil):
 	}
 
+	var m2 wsMsg
+	m2.FillAlert(fooError)
 	select {
 	case <-th.C:
 		th.Timeout()
-	case <-doneCh:
-	}
-
-	err := context.Cause(ts.rq.Context())
-	if !strings.Contains(err.Error(), "WebSocket closed") {
-		t.Error(err)
-	}
-}
-
-func TestReader_ReportsError(t *testing.T) {
-	th := newTestHelper(t)
-	ts := newTestServer()
-	defer ts.Close()
-
-	doneCh := make(chan struct{})
-	inCh := make(chan wsMsg)
-	client, server := Pipe()
-	client.CloseRead(ts.ctx)
-	server.Close(websocket.StatusNormalClosure, "")
-
-	go func() {
-		defer close(doneCh)
-		wsReader(ts.rq.ctx, ts.rq.cancelFn, ts.jw.Done(), inCh, server)
-	}()
-
-	msg := wsMsg{Jid: Jid(1234), What: what.Input}
-	err := client.Write(ts.ctx, websocket.MessageText, []byte(msg.Format()))
-	if err == nil {
-		t.Fatal("expected error")
+	case b := <-conn.outCh:
+		th.Equal(string(b), m2.Format())
 	}
+	th.Equal(tr.opts.Subprotocols, []string{WebSocketProtocol})
 
+	conn.Close("")
 	select {
 	case <-th.C:
 		th.Timeout()
 	case <-doneCh:
 	}
-
-	err = context.Cause(ts.rq.Context())
-	if !strings.Contains(err.Error(), "WebSocket closed") {
-		t.Error(err)
-	}
-}
-
-// adapted from nhooyr.io/websocket/internal/test/wstest.Pipe
-
-func Pipe() (clientConn, serverConn *websocket.Conn) {
-	dialOpts := &websocket.DialOptions{
-		HTTPClient: &http.Client{
-			Transport: fakeTransport{
-				h: func(w http.ResponseWriter, r *http.Request) {
-					serverConn, _ = websocket.Accept(w, r, nil)
-				},
-			},
-		},
-	}
-
-	clientConn, _, _ = websocket.Dial(context.Background(), "ws://localhost", dialOpts)
-	return clientConn, serverConn
-}
-
-type fakeTransport struct {
-	h http.HandlerFunc
-}
-
-func (t fakeTransport) RoundTrip(r *http.Request) (*http.Response, error) {
-	clientConn, serverConn := net.Pipe()
-
-	hj := testHijacker{
-		ResponseRecorder: httptest.NewRecorder(),
-		serverConn:       serverConn,
-	}
-
-	t.h.ServeHTTP(hj, r)
-
-	resp := hj.ResponseRecorder.Result()
-	if resp.StatusCode == http.StatusSwitchingProtocols {
-		resp.Body = clientConn
-	}
-	return resp, nil
-}
-
-type testHijacker struct {
-	*httptest.ResponseRecorder
-	serverConn net.Conn
-}
-
-var _ http.Hijacker = testHijacker{}
-
-func (hj testHijacker) Hijack() (net.Conn, *bufio.ReadWriter, error) {
-	return hj.serverConn, bufio.NewReadWriter(bufio.NewReader(hj.serverConn), bufio.NewWriter(hj.serverConn)), nil
 }
@@ -0,0 +1,56 @@
+package jaws
+
+import (
+	"net/http"
+	"strings"
+)
+
+// WebSocketProtocol is the WebSocket subprotocol selected by JaWS.
+const WebSocketProtocol = "jaws"
+
+// WebSocketTokenPrefix prefixes a bearer token sent as a WebSocket subprotocol.
+//
+// Browsers can't set headers on WebSocket connections, so if the page sets
+// the Javascript variable jawsToken, the JaWS script sends it as the subprotocol
+// "jaws.bearer.<token>" in addition to "jaws". The token must then only contain
+// characters allowed in HTTP tokens, such as those in base64url encoding.
+const WebSocketTokenPrefix = WebSocketProtocol + ".bearer."
+
+// TokenValidator validates the bearer token sent when a Request's WebSocket connects.
+//
+// If it returns an error, the error message is shown as an alert and the connection is closed.
+type TokenValidator interface {
+	JawsValidateToken(rq *Request, token string) error
+}
+
+// TokenValidatorFunc adapts an ordinary function to the TokenValidator interface.
+type TokenValidatorFunc func(rq *Request, token string) error
+
+func (fn TokenValidatorFunc) JawsValidateToken(rq *Request, token string) error {
+	return fn(rq, token)
+}
+
+// bearerToken returns the bearer token from the Authorization header, or failing
+// that from the Sec-WebSocket-Protocol header, or an empty string.
+func bearerToken(r *http.Request) string {
+	if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+	for _, line := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, proto := range strings.Split(line, ",") {
+			if token, ok := strings.CutPrefix(strings.TrimSpace(proto), WebSocketTokenPrefix); ok {
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// authenticate calls the TokenValidator, if any, with the bearer token from r.
+// The token is passed as-is, and may be empty if none was sent.
+func (rq *Request) authenticate(r *http.Request) (err error) {
+	if tv := rq.tokenValidator(); tv != nil {
+		err = tv.JawsValidateToken(rq, bearerToken(r))
+	}
+	return
+}
@@ -0,0 +1,43 @@
+package jaws
+
+import (
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_bearerToken(t *testing.T) {
+	th := newTestHelper(t)
+	hr := httptest.NewRequest("GET", "/", nil)
+	th.Equal(bearerToken(hr), "")
+	hr.Header.Set("Sec-WebSocket-Protocol", "jaws, jaws.bearer.abc-123")
+	th.Equal(bearerToken(hr), "abc-123")
+	hr.Header.Set("Authorization", "bearer  xyz ")
+	th.Equal(bearerToken(hr), "xyz")
+	hr.Header.Set("Authorization", "Basic dXNlcjpwYXNz")
+	th.Equal(bearerToken(hr), "abc-123")
+}
+
+func TestRequest_authenticate(t *testing.T) {
+	th := newTestHelper(t)
+	tj := newTestJaws()
+	defer tj.Close()
+	rq := tj.newRequest(nil)
+	defer rq.Close()
+
+	hr := httptest.NewRequest("GET", "/", nil)
+	th.NoErr(rq.authenticate(hr))
+
+	var gotRq *Request
+	tj.TokenValidator = TokenValidatorFunc(func(rq *Request, token string) error {
+		gotRq = rq
+		if token == "" {
+			return errors.New("missing token")
+		}
+		return nil
+	})
+	th.Equal(rq.authenticate(hr).Error(), "missing token")
+	th.Equal(gotRq, rq.Request)
+	hr.Header.Set("Authorization", "Bearer t0k3n")
+	th.NoErr(rq.authenticate(hr))
+}
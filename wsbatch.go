@@ -0,0 +1,117 @@
+package jaws
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/linkdata/jaws/jid"
+	"github.com/linkdata/jaws/what"
+)
+
+// wsMaxBatchSize is the size above which wsBatch stops collecting messages.
+const wsMaxBatchSize = 64 * 1024
+
+// wsBatch appends the messages queued on outboundCh to msg, waiting up to
+// flushInterval for more. Returns false if outboundCh was closed.
+func wsBatch(ctx context.Context, jawsDoneCh <-chan struct{}, outboundCh <-chan string, flushInterval time.Duration, msg string) (string, bool) {
+	var sb strings.Builder
+	sb.WriteString(msg)
+	var timeoutCh <-chan time.Time
+	if flushInterval > 0 {
+		t := time.NewTimer(flushInterval)
+		defer t.Stop()
+		timeoutCh = t.C
+	}
+	for sb.Len() < wsMaxBatchSize {
+		if timeoutCh == nil {
+			select {
+			case m, ok := <-outboundCh:
+				if !ok {
+					return sb.String(), false
+				}
+				sb.WriteString(m)
+				continue
+			default:
+				return sb.String(), true
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return sb.String(), true
+		case <-jawsDoneCh:
+			return sb.String(), true
+		case <-timeoutCh:
+			return sb.String(), true
+		case m, ok := <-outboundCh:
+			if !ok {
+				return sb.String(), false
+			}
+			sb.WriteString(m)
+		}
+	}
+	return sb.String(), true
+}
+
+// flushWindow is how long wsWriter collects messages for one WebSocket
+// write. If maximum is greater than minimum it adapts to the connection,
+// being twice the average time writes take, so slow clients get fewer and
+// larger messages while fast ones are updated without delay.
+type flushWindow struct {
+	minimum time.Duration
+	maximum time.Duration
+	avg     time.Duration // moving average of how long writes take
+	stats   *sendCounters // if not nil, updated with the write latency and window
+}
+
+// flushWindow returns the flushWindow for the Request's WebSocket writer.
+func (rq *Request) flushWindow() flushWindow {
+	return flushWindow{minimum: rq.Jaws.FlushInterval, maximum: rq.Jaws.FlushMax, stats: &rq.sendCount}
+}
+
+func (fw *flushWindow) interval() time.Duration {
+	if fw.maximum > fw.minimum {
+		return min(max(2*fw.avg, fw.minimum), fw.maximum)
+	}
+	return fw.minimum
+}
+
+// observe updates the average with the duration of a write.
+func (fw *flushWindow) observe(d time.Duration) {
+	fw.avg += (d - fw.avg) / 4
+	if fw.stats != nil {
+		fw.stats.writeLatency.Store(int64(fw.avg))
+		fw.stats.flushInterval.Store(int64(fw.interval()))
+	}
+}
+
+// wsCoalesce removes Inner and Value messages from the newline terminated
+// messages in s that are followed by another of the same kind for the same
+// Jid, with no other messages for that Jid in between. Only the latest
+// update survives, since it overwrites the earlier ones in the browser.
+func wsCoalesce(s string) string {
+	if strings.Count(s, "\n") < 2 {
+		return s
+	}
+	lines := strings.SplitAfter(s, "\n")
+	last := map[string]int{} // index of the last message for each Jid
+	removed := false
+	for i, line := range lines {
+		wht, rest, _ := strings.Cut(line, "\t")
+		id, _, found := strings.Cut(rest, "\t")
+		if !found || !strings.HasPrefix(id, jid.Prefix) {
+			continue
+		}
+		if wht == what.Inner.String() || wht == what.Value.String() {
+			if prev, ok := last[id]; ok && strings.HasPrefix(lines[prev], wht+"\t") {
+				lines[prev] = ""
+				removed = true
+			}
+		}
+		last[id] = i
+	}
+	if !removed {
+		return s
+	}
+	return strings.Join(lines, "")
+}
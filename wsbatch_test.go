@@ -0,0 +1,76 @@
+package jaws
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func Test_wsCoalesce(t *testing.T) {
+	th := newTestHelper(t)
+	th.Equal(wsCoalesce(""), "")
+	th.Equal(wsCoalesce("Inner\tJid.1\t\"a\"\n"), "Inner\tJid.1\t\"a\"\n")
+	th.Equal(wsCoalesce("Inner\tJid.1\t\"a\"\nValue\tJid.2\t\"x\"\nInner\tJid.1\t\"b\"\n"),
+		"Value\tJid.2\t\"x\"\nInner\tJid.1\t\"b\"\n")
+	th.Equal(wsCoalesce("Value\tJid.1\t\"a\"\nValue\tJid.1\t\"b\"\nValue\tJid.1\t\"c\"\n"),
+		"Value\tJid.1\t\"c\"\n")
+	// other messages for the same Jid in between prevent coalescing
+	th.Equal(wsCoalesce("Inner\tJid.1\t\"a\"\nSAttr\tJid.1\t\"x\\ny\"\nInner\tJid.1\t\"b\"\n"),
+		"Inner\tJid.1\t\"a\"\nSAttr\tJid.1\t\"x\\ny\"\nInner\tJid.1\t\"b\"\n")
+	// different kinds aren't coalesced
+	th.Equal(wsCoalesce("Inner\tJid.1\t\"a\"\nValue\tJid.1\t\"b\"\n"), "Inner\tJid.1\t\"a\"\nValue\tJid.1\t\"b\"\n")
+	// messages not addressed to a Jid aren't coalesced
+	th.Equal(wsCoalesce("Inner\tfoo\t\"a\"\nInner\tfoo\t\"b\"\n"), "Inner\tfoo\t\"a\"\nInner\tfoo\t\"b\"\n")
+}
+
+func Test_wsBatch(t *testing.T) {
+	th := newTestHelper(t)
+	ctx := context.Background()
+	outCh := make(chan string, 4)
+
+	outCh <- "b\n"
+	outCh <- "c\n"
+	msg, ok := wsBatch(ctx, nil, outCh, 0, "a\n")
+	th.Equal(msg, "a\nb\nc\n")
+	th.True(ok)
+
+	go func() {
+		time.Sleep(time.Millisecond * 10)
+		outCh <- "e\n"
+	}()
+	msg, ok = wsBatch(ctx, nil, outCh, time.Millisecond*50, "d\n")
+	th.Equal(msg, "d\ne\n")
+	th.True(ok)
+
+	outCh <- "g\n"
+	close(outCh)
+	msg, ok = wsBatch(ctx, nil, outCh, time.Second, "f\n")
+	th.Equal(msg, "f\ng\n")
+	th.Equal(ok, false)
+}
+
+func Test_flushWindow(t *testing.T) {
+	th := newTestHelper(t)
+	var stats sendCounters
+	fw := flushWindow{minimum: time.Millisecond * 10, maximum: time.Millisecond * 100, stats: &stats}
+	th.Equal(fw.interval(), time.Millisecond*10)
+	for i := 0; i < 100; i++ {
+		fw.observe(time.Millisecond * 40)
+	}
+	near := func(got, want time.Duration) bool { return got > want-time.Microsecond && got <= want }
+	th.True(near(fw.interval(), time.Millisecond*80))
+	th.True(near(time.Duration(stats.writeLatency.Load()), time.Millisecond*40))
+	th.True(near(time.Duration(stats.flushInterval.Load()), time.Millisecond*80))
+	for i := 0; i < 100; i++ {
+		fw.observe(time.Second)
+	}
+	th.Equal(fw.interval(), time.Millisecond*100)
+	for i := 0; i < 100; i++ {
+		fw.observe(0)
+	}
+	th.Equal(fw.interval(), time.Millisecond*10)
+
+	fw = flushWindow{minimum: time.Millisecond * 10}
+	fw.observe(time.Second)
+	th.Equal(fw.interval(), time.Millisecond*10)
+}